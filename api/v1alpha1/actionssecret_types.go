@@ -0,0 +1,160 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// ActionsSecretSpec defines the desired state of ActionsSecret
+type ActionsSecretSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	//+kubebuilder:validation:MinLength=1
+	//+kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+
+	// The name of the secret. Can only contain alphanumeric characters or underscores and cannot start with a number.
+	Name string `json:"name"`
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The organization that owns the secret, or the owner of the repository for repository and environment secrets.
+	Owner string `json:"owner"`
+
+	// The repository the secret belongs to. If unset, the secret is an organization secret.
+	// +optional
+	RepositoryName *string `json:"repositoryName,omitempty"`
+
+	// The environment the secret belongs to. Requires repositoryName.
+	// +optional
+	EnvironmentName *string `json:"environmentName,omitempty"`
+
+	// The key of a Kubernetes Secret in the same namespace holding the secret value. A new value
+	// is uploaded immediately if the Secret is labeled github.github-operator.eczy.io/watch: "true",
+	// and at the next requeue interval otherwise.
+	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef"`
+
+	// Which repositories in the organization can access an organization secret. Only applies to organization secrets.
+	// Can be one of: all, private, selected.
+	// Default: private
+	// +optional
+	Visibility *ActionsVisibility `json:"visibility,omitempty"`
+
+	// Names of the repositories that can access an organization secret with selected visibility.
+	// +optional
+	SelectedRepositories []string `json:"selectedRepositories,omitempty"`
+
+	// What happens to the GitHub resource when this resource is deleted. Can be one of: Delete, Orphan.
+	// Defaults to Delete if the operator runs with --delete-on-resource-deletion, otherwise Orphan.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// How much control the operator takes over the GitHub resource. Can be one of: Observe, Adopt, Full.
+	// Default: Full
+	// +optional
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// The cluster-scoped GitHubProvider whose credentials are used for the GitHub resource.
+	// Defaults to the credentials the operator was started with.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
+}
+
+// ActionsSecretStatus defines the observed state of ActionsSecret
+type ActionsSecretStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+	LastUpdateTimestamp *metav1.Time `json:"lastUpdateTimestamp,omitempty"`
+
+	Name                 *string            `json:"name,omitempty"`
+	Owner                *string            `json:"owner,omitempty"`
+	RepositoryName       *string            `json:"repositoryName,omitempty"`
+	EnvironmentName      *string            `json:"environmentName,omitempty"`
+	Visibility           *ActionsVisibility `json:"visibility,omitempty"`
+	SelectedRepositories []string           `json:"selectedRepositories,omitempty"`
+	CreatedAt            *metav1.Time       `json:"createdAt,omitempty"`
+	UpdatedAt            *metav1.Time       `json:"updatedAt,omitempty"`
+
+	// HMAC-SHA256 of the last applied secret value, keyed on the UID of the referenced
+	// Kubernetes Secret. GitHub never returns secret values, so this is used to detect
+	// changes to the referenced Secret.
+	ValueHash *string `json:"valueHash,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Fields of the GitHub resource that differed from the spec when last reconciled,
+	// including differences that were corrected.
+	// +optional
+	// +listType=map
+	// +listMapKey=field
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GitHub mutations computed by the last reconcile but not applied, e.g. in dry-run mode
+	// or under the Observe management policy.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ActionsSecret is the Schema for the actionssecrets API
+type ActionsSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ActionsSecretSpec   `json:"spec,omitempty"`
+	Status ActionsSecretStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ActionsSecretList contains a list of ActionsSecret
+type ActionsSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ActionsSecret `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ActionsSecret{}, &ActionsSecretList{})
+}
+
+// +kubebuilder:validation:Enum=all;private;selected
+type ActionsVisibility string
+
+const (
+	ActionsVisibilityAll      ActionsVisibility = "all"
+	ActionsVisibilityPrivate  ActionsVisibility = "private"
+	ActionsVisibilitySelected ActionsVisibility = "selected"
+)
@@ -31,12 +31,21 @@ type BranchProtectionSpec struct {
 	//+kubebuilder:validation:MinLength=1
 
 	// The owner of the repository associated with this branch protection rule.
-	RepositoryOwner string `json:"repositoryOwner"`
+	// Required unless repositoryRef is set.
+	// +optional
+	RepositoryOwner string `json:"repositoryOwner,omitempty"`
 
 	//+kubebuilder:validation:MinLength=1
 
 	// The repository associated with this branch protection rule.
-	RepositoryName string `json:"repositoryName"`
+	// Required unless repositoryRef is set.
+	// +optional
+	RepositoryName string `json:"repositoryName,omitempty"`
+
+	// Repository resource whose repository this branch protection rule applies to.
+	// Takes precedence over repositoryOwner and repositoryName.
+	// +optional
+	RepositoryRef *ResourceReference `json:"repositoryRef,omitempty"`
 
 	//+kubebuilder:validation:MinLength=1
 
@@ -67,6 +76,10 @@ type BranchProtectionSpec struct {
 	// +optional
 	BypassForcePushTeams []string `json:"bypassForcePushTeams,omitempty"`
 
+	// Team resources able to force push for this branch protection rule, in addition to bypassForcePushTeams.
+	// +optional
+	BypassForcePushTeamRefs []ResourceReference `json:"bypassForcePushTeamRefs,omitempty"`
+
 	// A list of users able to bypass PRs for this branch protection rule.
 	// +optional
 	BypassPullRequestUsers []string `json:"bypassPullRequestUsers,omitempty"`
@@ -79,6 +92,10 @@ type BranchProtectionSpec struct {
 	// +optional
 	BypassPullRequestTeams []string `json:"bypassPullRequestTeams,omitempty"`
 
+	// Team resources able to bypass PRs for this branch protection rule, in addition to bypassPullRequestTeams.
+	// +optional
+	BypassPullRequestTeamRefs []ResourceReference `json:"bypassPullRequestTeamRefs,omitempty"`
+
 	// Will new commits pushed to matching branches dismiss pull request review approvals.
 	// +optional
 	DismissesStaleReviews *bool `json:"dismissesStaleReviews,omitempty"`
@@ -107,6 +124,10 @@ type BranchProtectionSpec struct {
 	// +optional
 	PushAllowanceTeams []string `json:"pushAllowanceTeams,omitempty"`
 
+	// Team resources with push allowances for this branch protection rule, in addition to pushAllowanceTeams.
+	// +optional
+	PushAllowanceTeamRefs []ResourceReference `json:"pushAllowanceTeamRefs,omitempty"`
+
 	// Whether the most recent push must be approved by someone other than the person who pushed it.
 	// +optional
 	RequireLastPushApproval *bool `json:"requireLastPushApproval,omitempty"`
@@ -178,6 +199,25 @@ type BranchProtectionSpec struct {
 	// A list of team review dismissal allowances for this branch protection rule.
 	// +optional
 	ReviewDismissalTeams []string `json:"reviewDismissalTeams,omitempty"`
+
+	// Team resources with review dismissal allowances for this branch protection rule, in addition to reviewDismissalTeams.
+	// +optional
+	ReviewDismissalTeamRefs []ResourceReference `json:"reviewDismissalTeamRefs,omitempty"`
+
+	// What happens to the GitHub resource when this resource is deleted. Can be one of: Delete, Orphan.
+	// Defaults to Delete if the operator runs with --delete-on-resource-deletion, otherwise Orphan.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// How much control the operator takes over the GitHub resource. Can be one of: Observe, Adopt, Full.
+	// Default: Full
+	// +optional
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// The cluster-scoped GitHubProvider whose credentials are used for the GitHub resource.
+	// Defaults to the credentials the operator was started with.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
 }
 
 // BranchProtectionStatus defines the observed state of BranchProtection
@@ -225,12 +265,38 @@ type BranchProtectionStatus struct {
 	ReviewDismissalUsers           []string              `json:"reviewDismissalUsers,omitempty"`
 	ReviewDismissalApps            []string              `json:"reviewDismissalApps,omitempty"`
 	ReviewDismissalTeams           []string              `json:"reviewDismissalTeams,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Fields of the GitHub resource that differed from the spec when last reconciled,
+	// including differences that were corrected.
+	// +optional
+	// +listType=map
+	// +listMapKey=field
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GitHub mutations computed by the last reconcile but not applied, e.g. in dry-run mode
+	// or under the Observe management policy.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
-// BranchProtection is the Schema for the branchprotections API
+// BranchProtection is the Schema for the branchprotections API. A BranchProtection whose repository
+// is managed by a Repository in the same namespace is owned by that Repository and deleted along with it.
 type BranchProtection struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types reported in the status of every resource.
+const (
+	// ConditionTypeReady indicates that the GitHub resource exists and matches the spec.
+	ConditionTypeReady = "Ready"
+	// ConditionTypeSynced indicates that the last reconcile of the resource succeeded.
+	ConditionTypeSynced = "Synced"
+	// ConditionTypeDegraded indicates that the resource could not be reconciled.
+	ConditionTypeDegraded = "Degraded"
+	// ConditionTypeDrifted indicates that the GitHub resource differed from the spec when last reconciled.
+	ConditionTypeDrifted = "Drifted"
+)
+
+// Condition reasons reported in the status of every resource.
+const (
+	ConditionReasonReconcileSuccess = "ReconcileSuccess"
+	ConditionReasonReconcileError   = "ReconcileError"
+	// ConditionReasonChangesNotApplied indicates that the GitHub resource differs from the spec
+	// but the differences were not written, e.g. in dry-run mode or under the Observe management policy.
+	ConditionReasonChangesNotApplied = "ChangesNotApplied"
+	// ConditionReasonDependencyNotReady indicates that a resource referenced in the spec doesn't exist
+	// or hasn't been reconciled yet. The resource is reconciled again once it is.
+	ConditionReasonDependencyNotReady = "DependencyNotReady"
+	// ConditionReasonDriftDetected indicates that fields of the GitHub resource differed from the spec.
+	ConditionReasonDriftDetected = "DriftDetected"
+	// ConditionReasonNoDrift indicates that the GitHub resource matched the spec.
+	ConditionReasonNoDrift = "NoDrift"
+)
+
+// FieldDrift describes a field of a GitHub resource that differs from the spec.
+type FieldDrift struct {
+	// The differing field, named after the spec field.
+	Field string `json:"field"`
+
+	// The value declared in the spec.
+	// +optional
+	Desired string `json:"desired,omitempty"`
+
+	// The value observed on GitHub.
+	// +optional
+	Observed string `json:"observed,omitempty"`
+
+	// When the difference was first detected.
+	DetectedAt metav1.Time `json:"detectedAt"`
+}
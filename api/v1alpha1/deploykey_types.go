@@ -0,0 +1,148 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// DeployKeySpec defines the desired state of DeployKey
+type DeployKeySpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The owner of the repository.
+	RepositoryOwner string `json:"repositoryOwner"`
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The name of the repository.
+	RepositoryName string `json:"repositoryName"`
+
+	//+kubebuilder:validation:MinLength=1
+
+	// A name for the key.
+	Title string `json:"title"`
+
+	// If true, the key will only be able to read repository contents. Otherwise, the key will be able to read and write.
+	// Default: true
+	// +optional
+	ReadOnly *bool `json:"readOnly,omitempty"`
+
+	// The key of a Kubernetes Secret in the same namespace holding the public key to register.
+	// If unset, an ed25519 key pair is generated and written to privateKeySecretName.
+	// +optional
+	PublicKeySecretRef *corev1.SecretKeySelector `json:"publicKeySecretRef,omitempty"`
+
+	// The name of the Kubernetes Secret the generated key pair is written to. The Secret is
+	// owned by the DeployKey and holds the keys ssh-privatekey and ssh-publickey. An existing
+	// Secret that isn't controlled by the DeployKey is never overwritten.
+	// Default: <metadata.name>-deploy-key
+	// +optional
+	PrivateKeySecretName *string `json:"privateKeySecretName,omitempty"`
+
+	// What happens to the GitHub resource when this resource is deleted. Can be one of: Delete, Orphan.
+	// Defaults to Delete if the operator runs with --delete-on-resource-deletion, otherwise Orphan.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// How much control the operator takes over the GitHub resource. Can be one of: Observe, Adopt, Full.
+	// Default: Full
+	// +optional
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// The cluster-scoped GitHubProvider whose credentials are used for the GitHub resource.
+	// Defaults to the credentials the operator was started with.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
+}
+
+// DeployKeyStatus defines the observed state of DeployKey
+type DeployKeyStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+	LastUpdateTimestamp *metav1.Time `json:"lastUpdateTimestamp,omitempty"`
+
+	Id              *int64       `json:"id,omitempty"`
+	RepositoryOwner *string      `json:"repositoryOwner,omitempty"`
+	RepositoryName  *string      `json:"repositoryName,omitempty"`
+	Title           *string      `json:"title,omitempty"`
+	ReadOnly        *bool        `json:"readOnly,omitempty"`
+	Key             *string      `json:"key,omitempty"`
+	Verified        *bool        `json:"verified,omitempty"`
+	CreatedAt       *metav1.Time `json:"createdAt,omitempty"`
+	LastUsed        *metav1.Time `json:"lastUsed,omitempty"`
+
+	// The value of the rotation annotation that was last acted on.
+	Rotation *string `json:"rotation,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Fields of the GitHub resource that differed from the spec when last reconciled,
+	// including differences that were corrected.
+	// +optional
+	// +listType=map
+	// +listMapKey=field
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GitHub mutations computed by the last reconcile but not applied, e.g. in dry-run mode
+	// or under the Observe management policy.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DeployKey is the Schema for the deploykeys API
+type DeployKey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DeployKeySpec   `json:"spec,omitempty"`
+	Status DeployKeyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DeployKeyList contains a list of DeployKey
+type DeployKeyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DeployKey `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DeployKey{}, &DeployKeyList{})
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// EnvironmentSpec defines the desired state of Environment
+type EnvironmentSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The owner of the repository associated with this environment.
+	RepositoryOwner string `json:"repositoryOwner"`
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The repository associated with this environment.
+	RepositoryName string `json:"repositoryName"`
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The name of the environment.
+	Name string `json:"name"`
+
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=43200
+
+	// The amount of time to delay a job after the job is initially triggered. The time (in minutes) must be an integer between 0 and 43,200 (30 days).
+	// +optional
+	WaitTimer *int `json:"waitTimer,omitempty"`
+
+	// Whether or not a user who created the job is prevented from approving their own job.
+	// +optional
+	PreventSelfReview *bool `json:"preventSelfReview,omitempty"`
+
+	// Whether or not to allow repository administrators to bypass the protection rules set for this environment.
+	// Default: true
+	// +optional
+	CanAdminsBypass *bool `json:"canAdminsBypass,omitempty"`
+
+	// Logins of users that may review jobs that reference the environment. Up to six users and teams in total may be listed.
+	// +optional
+	RequiredReviewerUsers []string `json:"requiredReviewerUsers,omitempty"`
+
+	// Slugs of teams in the repository owner's organization that may review jobs that reference the environment.
+	// +optional
+	RequiredReviewerTeams []string `json:"requiredReviewerTeams,omitempty"`
+
+	// The type of deployment branch policy for this environment. If unset, all branches can deploy.
+	// +optional
+	DeploymentBranchPolicy *DeploymentBranchPolicy `json:"deploymentBranchPolicy,omitempty"`
+
+	// What happens to the GitHub resource when this resource is deleted. Can be one of: Delete, Orphan.
+	// Defaults to Delete if the operator runs with --delete-on-resource-deletion, otherwise Orphan.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// How much control the operator takes over the GitHub resource. Can be one of: Observe, Adopt, Full.
+	// Default: Full
+	// +optional
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// The cluster-scoped GitHubProvider whose credentials are used for the GitHub resource.
+	// Defaults to the credentials the operator was started with.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
+}
+
+// EnvironmentStatus defines the observed state of Environment
+type EnvironmentStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+	LastUpdateTimestamp *metav1.Time `json:"lastUpdateTimestamp,omitempty"`
+
+	Id                     *int64                  `json:"id,omitempty"`
+	NodeId                 *string                 `json:"nodeId,omitempty"`
+	RepositoryOwner        *string                 `json:"repositoryOwner,omitempty"`
+	RepositoryName         *string                 `json:"repositoryName,omitempty"`
+	Name                   *string                 `json:"name,omitempty"`
+	WaitTimer              *int                    `json:"waitTimer,omitempty"`
+	PreventSelfReview      *bool                   `json:"preventSelfReview,omitempty"`
+	CanAdminsBypass        *bool                   `json:"canAdminsBypass,omitempty"`
+	RequiredReviewerUsers  []string                `json:"requiredReviewerUsers,omitempty"`
+	RequiredReviewerTeams  []string                `json:"requiredReviewerTeams,omitempty"`
+	DeploymentBranchPolicy *DeploymentBranchPolicy `json:"deploymentBranchPolicy,omitempty"`
+	CreatedAt              *metav1.Time            `json:"createdAt,omitempty"`
+	UpdatedAt              *metav1.Time            `json:"updatedAt,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Fields of the GitHub resource that differed from the spec when last reconciled,
+	// including differences that were corrected.
+	// +optional
+	// +listType=map
+	// +listMapKey=field
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GitHub mutations computed by the last reconcile but not applied, e.g. in dry-run mode
+	// or under the Observe management policy.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Environment is the Schema for the environments API
+type Environment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EnvironmentSpec   `json:"spec,omitempty"`
+	Status EnvironmentStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EnvironmentList contains a list of Environment
+type EnvironmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Environment `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Environment{}, &EnvironmentList{})
+}
+
+type DeploymentBranchPolicy struct {
+	// Whether only branches with branch protection rules can deploy to this environment.
+	// Cannot be combined with branch or tag patterns.
+	// +optional
+	ProtectedBranches bool `json:"protectedBranches,omitempty"`
+
+	// Name patterns that branches must match in order to deploy to this environment.
+	// +optional
+	BranchPatterns []string `json:"branchPatterns,omitempty"`
+
+	// Name patterns that tags must match in order to deploy to this environment.
+	// +optional
+	TagPatterns []string `json:"tagPatterns,omitempty"`
+}
+
+func (p *DeploymentBranchPolicy) GetBranchPatterns() []string {
+	if p == nil {
+		return nil
+	}
+	return p.BranchPatterns
+}
+
+func (p *DeploymentBranchPolicy) GetTagPatterns() []string {
+	if p == nil {
+		return nil
+	}
+	return p.TagPatterns
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GitHubProviderSpec defines the desired state of GitHubProvider
+type GitHubProviderSpec struct {
+	// The Kubernetes Secret holding the credentials. It must contain either the key token, or the
+	// keys appId, installationId and privateKey to authenticate as a GitHub App installation.
+	// Without installationId, each resource uses the App's installation on the account that owns it.
+	CredentialsSecretRef SecretReference `json:"credentialsSecretRef"`
+
+	// REST API URL of a GitHub Enterprise Server instance, e.g. https://github.example.com/api/v3/.
+	// Defaults to the GitHub instance the operator is configured with.
+	// +optional
+	BaseURL *string `json:"baseURL,omitempty"`
+
+	// Upload URL of a GitHub Enterprise Server instance. Defaults to the upload URL of the instance at baseURL.
+	// +optional
+	UploadURL *string `json:"uploadURL,omitempty"`
+
+	// GraphQL API URL of a GitHub Enterprise Server instance. Defaults to the GraphQL URL of the instance at baseURL.
+	// +optional
+	GraphQLURL *string `json:"graphqlURL,omitempty"`
+
+	// PEM encoded CA certificates to trust when connecting to GitHub, in addition to the system roots.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+}
+
+// SecretReference refers to a Kubernetes Secret in any namespace.
+type SecretReference struct {
+	//+kubebuilder:validation:MinLength=1
+
+	// The name of the Secret.
+	Name string `json:"name"`
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The namespace of the Secret.
+	Namespace string `json:"namespace"`
+}
+
+// ProviderReference refers to the GitHubProvider whose credentials are used for a resource.
+type ProviderReference struct {
+	//+kubebuilder:validation:MinLength=1
+
+	// The name of the GitHubProvider.
+	Name string `json:"name"`
+}
+
+// GitHubProviderStatus defines the observed state of GitHubProvider
+type GitHubProviderStatus struct {
+	// How the provider authenticates with GitHub. One of: Token, App.
+	// +optional
+	AuthType *string `json:"authType,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Auth",type=string,JSONPath=`.status.authType`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// GitHubProvider is the Schema for the githubproviders API. It holds GitHub credentials that
+// resources in any namespace can use through their providerRef.
+type GitHubProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GitHubProviderSpec   `json:"spec,omitempty"`
+	Status GitHubProviderStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GitHubProviderList contains a list of GitHubProvider
+type GitHubProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitHubProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitHubProvider{}, &GitHubProviderList{})
+}
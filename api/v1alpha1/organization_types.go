@@ -142,6 +142,20 @@ type OrganizationSpec struct {
 	// Whether secret scanning push protection is automatically enabled for new repositories.
 	// +optional
 	SecretScanningPushProtectionEnabledForNewRepositories *bool `json:"secretScanningPushProtectionEnabledForNewRepositories,omitempty"`
+
+	// Members and owners of the organization. If unset, organization membership is not managed.
+	// +optional
+	Membership *OrganizationMembership `json:"membership,omitempty"`
+
+	// How much control the operator takes over the GitHub resource. Can be one of: Observe, Adopt, Full.
+	// Default: Full
+	// +optional
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// The cluster-scoped GitHubProvider whose credentials are used for the GitHub resource.
+	// Defaults to the credentials the operator was started with.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
 }
 
 // OrganizationStatus defines the observed state of Organization
@@ -178,10 +192,39 @@ type OrganizationStatus struct {
 	DependencyGraphEnabledForNewRepositories              *bool                        `json:"dependencyFraphEnabledForNewRepositories,omitempty"`
 	SecretScanningEnabledForNewRepositories               *bool                        `json:"secretScanningEnabledForNewRepositories,omitempty"`
 	SecretScanningPushProtectionEnabledForNewRepositories *bool                        `json:"secretScanningPushProtectionEnabledForNewRepositories,omitempty"`
+
+	Members     []string                 `json:"members,omitempty"`
+	Admins      []string                 `json:"admins,omitempty"`
+	Invitations []OrganizationInvitation `json:"invitations,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Fields of the GitHub resource that differed from the spec when last reconciled,
+	// including differences that were corrected.
+	// +optional
+	// +listType=map
+	// +listMapKey=field
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GitHub mutations computed by the last reconcile but not applied, e.g. in dry-run mode
+	// or under the Observe management policy.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Organization is the Schema for the organizations API
 type Organization struct {
@@ -214,3 +257,37 @@ const (
 	DefaultRepositoryPermissionNone  DefaultRepositoryPermission = "none"
 	DefaultRepositoryPermissionAdmin DefaultRepositoryPermission = "admin"
 )
+
+type OrganizationMembership struct {
+	// Logins of users that should be members of the organization. Users that are not
+	// yet members are invited.
+	// +optional
+	Members []string `json:"members,omitempty"`
+
+	// Logins of users that should be owners of the organization.
+	// Users listed in both members and admins are made owners.
+	// +optional
+	Admins []string `json:"admins,omitempty"`
+
+	// Whether to remove members and cancel invitations of users not listed in members or admins.
+	// The user the operator authenticates as is never removed.
+	// Default: false
+	// +optional
+	Prune *bool `json:"prune,omitempty"`
+}
+
+type OrganizationInvitation struct {
+	Id           int64        `json:"id"`
+	Login        *string      `json:"login,omitempty"`
+	Email        *string      `json:"email,omitempty"`
+	Role         string       `json:"role"`
+	State        string       `json:"state"`
+	CreatedAt    *metav1.Time `json:"createdAt,omitempty"`
+	FailedAt     *metav1.Time `json:"failedAt,omitempty"`
+	FailedReason *string      `json:"failedReason,omitempty"`
+}
+
+const (
+	OrganizationInvitationStatePending = "pending"
+	OrganizationInvitationStateFailed  = "failed"
+)
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// What happens to a GitHub resource when the Kubernetes resource managing it is deleted.
+// +kubebuilder:validation:Enum=Delete;Orphan
+type DeletionPolicy string
+
+const (
+	// The GitHub resource is deleted.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// The GitHub resource is left in place.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// What happens to a GitHub repository when the Repository managing it is deleted.
+// +kubebuilder:validation:Enum=Delete;Orphan;Archive
+type RepositoryDeletionPolicy string
+
+const (
+	// The repository is deleted.
+	RepositoryDeletionPolicyDelete RepositoryDeletionPolicy = "Delete"
+	// The repository is left in place.
+	RepositoryDeletionPolicyOrphan RepositoryDeletionPolicy = "Orphan"
+	// The repository is renamed with a tombstone suffix and archived.
+	RepositoryDeletionPolicyArchive RepositoryDeletionPolicy = "Archive"
+)
+
+// How much control the operator takes over a GitHub resource.
+// +kubebuilder:validation:Enum=Observe;Adopt;Full
+type ManagementPolicy string
+
+const (
+	// Status is populated from GitHub and differences from the spec are reported, but nothing is written to GitHub.
+	ManagementPolicyObserve ManagementPolicy = "Observe"
+	// An existing GitHub resource is updated to match the spec, but a missing one is never created.
+	ManagementPolicyAdopt ManagementPolicy = "Adopt"
+	// The GitHub resource is created if missing and updated to match the spec.
+	ManagementPolicyFull ManagementPolicy = "Full"
+)
+
+// What happens when a repository's owner differs from the owner in its spec.
+// +kubebuilder:validation:Enum=Deny;Allow
+type TransferPolicy string
+
+const (
+	// The repository is left with its current owner and the mismatch is reported as an error.
+	TransferPolicyDeny TransferPolicy = "Deny"
+	// The repository is transferred to the owner in the spec.
+	TransferPolicyAllow TransferPolicy = "Allow"
+)
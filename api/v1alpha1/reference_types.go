@@ -0,0 +1,27 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// ResourceReference refers to another resource managed by the operator in the same namespace.
+// Fields given as references are resolved through the status of the referenced resource, so
+// they resolve once it has been reconciled.
+type ResourceReference struct {
+	//+kubebuilder:validation:MinLength=1
+
+	// The name of the resource.
+	Name string `json:"name"`
+}
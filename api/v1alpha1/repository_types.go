@@ -169,6 +169,47 @@ type RepositorySpec struct {
 	// [Managing security managers in your organization]: https://docs.github.com/en/organizations/managing-peoples-access-to-your-organization-with-roles/managing-security-managers-in-your-organization
 	// +optional
 	SecurityAndAnalysis *SecurityAndAnalysis `json:"securitAandAnalysis,omitempty"`
+
+	// Permissions to grant to direct collaborators, keyed by GitHub login.
+	// Users that are not organization members are sent an invitation.
+	// If unset and collaborators aren't pruned, collaborators are not managed.
+	// +optional
+	Collaborators map[string]RepositoryPermission `json:"collaborators,omitempty"`
+
+	// If true, direct collaborators and pending invitations not listed in collaborators are removed.
+	// Pruning without collaborators removes all direct collaborators.
+	// +optional
+	PruneCollaborators *bool `json:"pruneCollaborators,omitempty"`
+
+	// Issue labels to create or update on the repository.
+	// If unset and labels aren't pruned, labels are not managed.
+	// +optional
+	Labels []RepositoryLabel `json:"labels,omitempty"`
+
+	// If true, labels that are not listed in labels are deleted.
+	// Pruning without labels deletes all labels.
+	// +optional
+	PruneLabels *bool `json:"pruneLabels,omitempty"`
+
+	// What happens to the GitHub resource when this resource is deleted. Can be one of: Delete, Orphan, Archive.
+	// Defaults to Delete if the operator runs with --delete-on-resource-deletion, otherwise Orphan.
+	// +optional
+	DeletionPolicy *RepositoryDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// How much control the operator takes over the GitHub resource. Can be one of: Observe, Adopt, Full.
+	// Default: Full
+	// +optional
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// Whether the repository is transferred when owner is changed. Can be one of: Deny, Allow.
+	// Default: Deny
+	// +optional
+	TransferPolicy *TransferPolicy `json:"transferPolicy,omitempty"`
+
+	// The cluster-scoped GitHubProvider whose credentials are used for the GitHub resource.
+	// Defaults to the credentials the operator was started with.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
 }
 
 // RepositoryStatus defines the observed state of Repository
@@ -211,6 +252,10 @@ type RepositoryStatus struct {
 	Visibility               *string                   `json:"visibility,omitempty"`
 	SecurityAndAnalysis      *SecurityAndAnalysis      `json:"securityAndAnalysis,omitempty"`
 
+	Collaborators        map[string]RepositoryPermission `json:"collaborators,omitempty"`
+	PendingCollaborators map[string]RepositoryPermission `json:"pendingCollaborators,omitempty"`
+	Labels               []string                        `json:"labels,omitempty"`
+
 	ParentName                    *string `json:"parentName,omitempty"`
 	ParentId                      *int64  `json:"parentId,omitempty"`
 	ParentNodeId                  *string `json:"parentNodeId,omitempty"`
@@ -224,10 +269,35 @@ type RepositoryStatus struct {
 	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
 	PushedAt  *metav1.Time `json:"pushedAt,omitempty"`
 	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Fields of the GitHub resource that differed from the spec when last reconciled,
+	// including differences that were corrected.
+	// +optional
+	// +listType=map
+	// +listMapKey=field
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GitHub mutations computed by the last reconcile but not applied, e.g. in dry-run mode
+	// or under the Observe management policy.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Repository is the Schema for the repositories API
 type Repository struct {
@@ -251,6 +321,27 @@ func init() {
 	SchemeBuilder.Register(&Repository{}, &RepositoryList{})
 }
 
+type RepositoryLabel struct {
+	//+kubebuilder:validation:MinLength=1
+
+	// The name of the label.
+	Name string `json:"name"`
+
+	//+kubebuilder:validation:Pattern=`^[0-9a-fA-F]{6}$`
+
+	// The hexadecimal color code for the label, without the leading #.
+	Color string `json:"color"`
+
+	// A short description of the label.
+	// +optional
+	Description *string `json:"description,omitempty"`
+
+	// Former names of the label. An existing label with one of these names is
+	// renamed rather than replaced, so issues and pull requests keep the label.
+	// +optional
+	PreviousNames []string `json:"previousNames,omitempty"`
+}
+
 type SecurityAndAnalysisFeature struct {
 	// Can be enabled or disabled.
 	Status string `json:"status"`
@@ -0,0 +1,348 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// RulesetSpec defines the desired state of Ruleset
+type RulesetSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The name of the ruleset.
+	Name string `json:"name"`
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The organization that owns the ruleset, or the owner of the repository for repository rulesets. The name is not case sensitive.
+	Owner string `json:"owner"`
+
+	// The repository the ruleset applies to. If unset, the ruleset is an organization ruleset.
+	// +optional
+	RepositoryName *string `json:"repositoryName,omitempty"`
+
+	// The target of the ruleset. Can be one of: branch, tag, push.
+	// Default: branch
+	// +optional
+	Target *RulesetTarget `json:"target,omitempty"`
+
+	// The enforcement level of the ruleset. evaluate allows admins to test rules before enforcing them (GitHub Enterprise only).
+	// Can be one of: active, evaluate, disabled.
+	Enforcement RulesetEnforcement `json:"enforcement"`
+
+	// The actors that can bypass the rules in this ruleset.
+	// +optional
+	BypassActors []RulesetBypassActor `json:"bypassActors,omitempty"`
+
+	// Ref name patterns to include. Accepts fnmatch patterns as well as ~DEFAULT_BRANCH and ~ALL.
+	// +optional
+	IncludeRefs []string `json:"includeRefs,omitempty"`
+
+	// Ref name patterns to exclude.
+	// +optional
+	ExcludeRefs []string `json:"excludeRefs,omitempty"`
+
+	// Repository name patterns to include. Only applies to organization rulesets. Accepts fnmatch patterns as well as ~ALL.
+	// +optional
+	IncludeRepositories []string `json:"includeRepositories,omitempty"`
+
+	// Repository name patterns to exclude. Only applies to organization rulesets.
+	// +optional
+	ExcludeRepositories []string `json:"excludeRepositories,omitempty"`
+
+	// The rules to enforce on matching refs.
+	// +optional
+	Rules *RulesetRules `json:"rules,omitempty"`
+
+	// What happens to the GitHub resource when this resource is deleted. Can be one of: Delete, Orphan.
+	// Defaults to Delete if the operator runs with --delete-on-resource-deletion, otherwise Orphan.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// How much control the operator takes over the GitHub resource. Can be one of: Observe, Adopt, Full.
+	// Default: Full
+	// +optional
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// The cluster-scoped GitHubProvider whose credentials are used for the GitHub resource.
+	// Defaults to the credentials the operator was started with.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
+}
+
+// RulesetStatus defines the observed state of Ruleset
+type RulesetStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+	LastUpdateTimestamp *metav1.Time `json:"lastUpdateTimestamp,omitempty"`
+
+	Id             *int64              `json:"id,omitempty"`
+	NodeId         *string             `json:"nodeId,omitempty"`
+	Name           *string             `json:"name,omitempty"`
+	Owner          *string             `json:"owner,omitempty"`
+	RepositoryName *string             `json:"repositoryName,omitempty"`
+	SourceType     *string             `json:"sourceType,omitempty"`
+	Target         *RulesetTarget      `json:"target,omitempty"`
+	Enforcement    *RulesetEnforcement `json:"enforcement,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Fields of the GitHub resource that differed from the spec when last reconciled,
+	// including differences that were corrected.
+	// +optional
+	// +listType=map
+	// +listMapKey=field
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GitHub mutations computed by the last reconcile but not applied, e.g. in dry-run mode
+	// or under the Observe management policy.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Ruleset is the Schema for the rulesets API
+type Ruleset struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RulesetSpec   `json:"spec,omitempty"`
+	Status RulesetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RulesetList contains a list of Ruleset
+type RulesetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Ruleset `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Ruleset{}, &RulesetList{})
+}
+
+type RulesetBypassActor struct {
+	// The ID of the actor that can bypass the ruleset. Ignored for OrganizationAdmin.
+	// For RepositoryRole, 1 is the maintain role, 2 is the write role and 5 is the admin role.
+	// +optional
+	ActorId *int64 `json:"actorId,omitempty"`
+
+	// Team resource whose team can bypass the ruleset. Only applies to the Team actor type.
+	// Takes precedence over actorId.
+	// +optional
+	TeamRef *ResourceReference `json:"teamRef,omitempty"`
+
+	// The type of actor that can bypass the ruleset.
+	// Can be one of: RepositoryRole, Team, Integration, OrganizationAdmin.
+	ActorType RulesetActorType `json:"actorType"`
+
+	// When the actor can bypass the ruleset. pull_request means that an actor can only bypass rules on pull requests.
+	// Can be one of: always, pull_request.
+	// Default: always
+	// +optional
+	BypassMode *RulesetBypassMode `json:"bypassMode,omitempty"`
+}
+
+type RulesetRules struct {
+	// Only allow users with bypass permission to create matching refs.
+	// +optional
+	Creation *bool `json:"creation,omitempty"`
+
+	// Only allow users with bypass permission to update matching refs.
+	// +optional
+	Update *bool `json:"update,omitempty"`
+
+	// Whether users can pull changes from upstream when the update rule is enabled.
+	// +optional
+	UpdateAllowsFetchAndMerge *bool `json:"updateAllowsFetchAndMerge,omitempty"`
+
+	// Only allow users with bypass permissions to delete matching refs.
+	// +optional
+	Deletion *bool `json:"deletion,omitempty"`
+
+	// Prevent merge commits from being pushed to matching refs.
+	// +optional
+	RequiredLinearHistory *bool `json:"requiredLinearHistory,omitempty"`
+
+	// Commits pushed to matching refs must have verified signatures.
+	// +optional
+	RequiredSignatures *bool `json:"requiredSignatures,omitempty"`
+
+	// Prevent users with push access from force pushing to refs.
+	// +optional
+	NonFastForward *bool `json:"nonFastForward,omitempty"`
+
+	// Environments that must be successfully deployed to before refs can be pushed into a ref that matches this rule.
+	// +optional
+	RequiredDeploymentEnvironments []string `json:"requiredDeploymentEnvironments,omitempty"`
+
+	// Require all commits be made to a non-target branch and submitted via a pull request before they can be merged.
+	// +optional
+	PullRequest *RulesetPullRequestRule `json:"pullRequest,omitempty"`
+
+	// Choose which status checks must pass before the ref is updated.
+	// +optional
+	RequiredStatusChecks *RulesetRequiredStatusChecksRule `json:"requiredStatusChecks,omitempty"`
+
+	// Parameters to be used for the commit_message_pattern rule.
+	// +optional
+	CommitMessagePattern *RulesetPatternRule `json:"commitMessagePattern,omitempty"`
+
+	// Parameters to be used for the commit_author_email_pattern rule.
+	// +optional
+	CommitAuthorEmailPattern *RulesetPatternRule `json:"commitAuthorEmailPattern,omitempty"`
+
+	// Parameters to be used for the committer_email_pattern rule.
+	// +optional
+	CommitterEmailPattern *RulesetPatternRule `json:"committerEmailPattern,omitempty"`
+
+	// Parameters to be used for the branch_name_pattern rule.
+	// +optional
+	BranchNamePattern *RulesetPatternRule `json:"branchNamePattern,omitempty"`
+
+	// Parameters to be used for the tag_name_pattern rule.
+	// +optional
+	TagNamePattern *RulesetPatternRule `json:"tagNamePattern,omitempty"`
+}
+
+type RulesetPullRequestRule struct {
+	// New, reviewable commits pushed will dismiss previous pull request review approvals.
+	// +optional
+	DismissStaleReviewsOnPush bool `json:"dismissStaleReviewsOnPush,omitempty"`
+
+	// Require an approving review in pull requests that modify files that have a designated code owner.
+	// +optional
+	RequireCodeOwnerReview bool `json:"requireCodeOwnerReview,omitempty"`
+
+	// Whether the most recent reviewable push must be approved by someone other than the person who pushed it.
+	// +optional
+	RequireLastPushApproval bool `json:"requireLastPushApproval,omitempty"`
+
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=10
+
+	// The number of approving reviews that are required before a pull request can be merged.
+	// +optional
+	RequiredApprovingReviewCount int `json:"requiredApprovingReviewCount,omitempty"`
+
+	// All conversations on code must be resolved before a pull request can be merged.
+	// +optional
+	RequiredReviewThreadResolution bool `json:"requiredReviewThreadResolution,omitempty"`
+}
+
+type RulesetRequiredStatusChecksRule struct {
+	// Status checks that are required.
+	RequiredStatusChecks []RulesetStatusCheck `json:"requiredStatusChecks"`
+
+	// Whether pull requests targeting a matching branch must be tested with the latest code.
+	// +optional
+	StrictRequiredStatusChecksPolicy bool `json:"strictRequiredStatusChecksPolicy,omitempty"`
+}
+
+type RulesetStatusCheck struct {
+	// The status check context name that must be present on the commit.
+	Context string `json:"context"`
+
+	// The optional integration ID that this status check must originate from.
+	// +optional
+	IntegrationId *int64 `json:"integrationId,omitempty"`
+}
+
+type RulesetPatternRule struct {
+	// How this rule will appear to users.
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// If true, the rule will fail if the pattern matches.
+	// +optional
+	Negate *bool `json:"negate,omitempty"`
+
+	// The operator to use for matching.
+	// Can be one of: starts_with, ends_with, contains, regex.
+	Operator RulesetPatternOperator `json:"operator"`
+
+	// The pattern to match with.
+	Pattern string `json:"pattern"`
+}
+
+// +kubebuilder:validation:Enum=branch;tag;push
+type RulesetTarget string
+
+const (
+	RulesetTargetBranch RulesetTarget = "branch"
+	RulesetTargetTag    RulesetTarget = "tag"
+	RulesetTargetPush   RulesetTarget = "push"
+)
+
+// +kubebuilder:validation:Enum=active;evaluate;disabled
+type RulesetEnforcement string
+
+const (
+	RulesetEnforcementActive   RulesetEnforcement = "active"
+	RulesetEnforcementEvaluate RulesetEnforcement = "evaluate"
+	RulesetEnforcementDisabled RulesetEnforcement = "disabled"
+)
+
+// +kubebuilder:validation:Enum=RepositoryRole;Team;Integration;OrganizationAdmin
+type RulesetActorType string
+
+const (
+	RulesetActorTypeRepositoryRole    RulesetActorType = "RepositoryRole"
+	RulesetActorTypeTeam              RulesetActorType = "Team"
+	RulesetActorTypeIntegration       RulesetActorType = "Integration"
+	RulesetActorTypeOrganizationAdmin RulesetActorType = "OrganizationAdmin"
+)
+
+// +kubebuilder:validation:Enum=always;pull_request
+type RulesetBypassMode string
+
+const (
+	RulesetBypassModeAlways      RulesetBypassMode = "always"
+	RulesetBypassModePullRequest RulesetBypassMode = "pull_request"
+)
+
+// +kubebuilder:validation:Enum=starts_with;ends_with;contains;regex
+type RulesetPatternOperator string
+
+const (
+	RulesetPatternOperatorStartsWith RulesetPatternOperator = "starts_with"
+	RulesetPatternOperatorEndsWith   RulesetPatternOperator = "ends_with"
+	RulesetPatternOperatorContains   RulesetPatternOperator = "contains"
+	RulesetPatternOperatorRegex      RulesetPatternOperator = "regex"
+)
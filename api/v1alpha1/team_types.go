@@ -54,9 +54,49 @@ type TeamSpec struct {
 	// +optional
 	ParentTeamId *int64 `json:"parentTeamId,omitempty"`
 
+	// Team resource to set as the parent of this team. Takes precedence over parentTeamId.
+	// +optional
+	ParentTeamRef *ResourceReference `json:"parentTeamRef,omitempty"`
+
 	// Repository permissions to assign to this team
 	// +optional
 	Repositories map[string]RepositoryPermission `json:"repositories,omitempty"`
+
+	// Repository permissions to assign to this team, keyed by the name of a Repository resource.
+	// The repositories must belong to the team's organization. Takes precedence over repositories.
+	// +optional
+	RepositoryRefs map[string]RepositoryPermission `json:"repositoryRefs,omitempty"`
+
+	// Logins of users that should be members of the team. Users that aren't members of the
+	// organization yet are invited.
+	// +optional
+	Members []string `json:"members,omitempty"`
+
+	// Logins of users that should be maintainers of the team.
+	// Users listed in both members and maintainers are made maintainers.
+	// +optional
+	Maintainers []string `json:"maintainers,omitempty"`
+
+	// Whether to remove members and cancel invitations of users not listed in members or maintainers.
+	// Pruning without members or maintainers removes everyone from the team.
+	// Default: false
+	// +optional
+	PruneMembers *bool `json:"pruneMembers,omitempty"`
+
+	// What happens to the GitHub resource when this resource is deleted. Can be one of: Delete, Orphan.
+	// Defaults to Delete if the operator runs with --delete-on-resource-deletion, otherwise Orphan.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// How much control the operator takes over the GitHub resource. Can be one of: Observe, Adopt, Full.
+	// Default: Full
+	// +optional
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// The cluster-scoped GitHubProvider whose credentials are used for the GitHub resource.
+	// Defaults to the credentials the operator was started with.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
 }
 
 // TeamStatus defines the observed state of Team
@@ -77,10 +117,38 @@ type TeamStatus struct {
 	ParentTeamId        *int64                          `json:"parentTeamId,omitempty"`
 	ParentTeamSlug      *string                         `json:"parentTeamSlug,omitempty"`
 	Repositories        map[string]RepositoryPermission `json:"repositories,omitempty"`
+	Members             []string                        `json:"members,omitempty"`
+	Maintainers         []string                        `json:"maintainers,omitempty"`
+	PendingInvitations  []string                        `json:"pendingInvitations,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Fields of the GitHub resource that differed from the spec when last reconciled,
+	// including differences that were corrected.
+	// +optional
+	// +listType=map
+	// +listMapKey=field
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GitHub mutations computed by the last reconcile but not applied, e.g. in dry-run mode
+	// or under the Observe management policy.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Team is the Schema for the teams API
 type Team struct {
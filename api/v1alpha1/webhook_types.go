@@ -0,0 +1,171 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// WebhookSpec defines the desired state of Webhook
+type WebhookSpec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The organization that owns the webhook, or the owner of the repository for repository webhooks.
+	Owner string `json:"owner"`
+
+	// The repository the webhook belongs to. If unset, the webhook is an organization webhook.
+	// +optional
+	RepositoryName *string `json:"repositoryName,omitempty"`
+
+	//+kubebuilder:validation:MinLength=1
+
+	// The URL to which the payloads will be delivered.
+	URL string `json:"url"`
+
+	// The media type used to serialize the payloads. Can be one of: json, form.
+	// Default: form
+	// +optional
+	ContentType *WebhookContentType `json:"contentType,omitempty"`
+
+	// Determines what events the hook is triggered for.
+	// Default: [push]
+	// +optional
+	Events []string `json:"events,omitempty"`
+
+	// Determines if notifications are sent when the webhook is triggered.
+	// Default: true
+	// +optional
+	Active *bool `json:"active,omitempty"`
+
+	// Determines whether the SSL certificate of the host for url will be verified when delivering payloads.
+	// +optional
+	InsecureSSL *bool `json:"insecureSsl,omitempty"`
+
+	// The key of a Kubernetes Secret in the same namespace holding the webhook secret, used to sign payloads.
+	// If unset, the secret of the webhook is not managed.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// What happens to the GitHub resource when this resource is deleted. Can be one of: Delete, Orphan.
+	// Defaults to Delete if the operator runs with --delete-on-resource-deletion, otherwise Orphan.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// How much control the operator takes over the GitHub resource. Can be one of: Observe, Adopt, Full.
+	// Default: Full
+	// +optional
+	ManagementPolicy *ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// The cluster-scoped GitHubProvider whose credentials are used for the GitHub resource.
+	// Defaults to the credentials the operator was started with.
+	// +optional
+	ProviderRef *ProviderReference `json:"providerRef,omitempty"`
+}
+
+// WebhookStatus defines the observed state of Webhook
+type WebhookStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+	LastUpdateTimestamp *metav1.Time `json:"lastUpdateTimestamp,omitempty"`
+
+	Id             *int64              `json:"id,omitempty"`
+	Owner          *string             `json:"owner,omitempty"`
+	RepositoryName *string             `json:"repositoryName,omitempty"`
+	URL            *string             `json:"url,omitempty"`
+	ContentType    *WebhookContentType `json:"contentType,omitempty"`
+	Events         []string            `json:"events,omitempty"`
+	Active         *bool               `json:"active,omitempty"`
+	InsecureSSL    *bool               `json:"insecureSsl,omitempty"`
+	CreatedAt      *metav1.Time        `json:"createdAt,omitempty"`
+	UpdatedAt      *metav1.Time        `json:"updatedAt,omitempty"`
+	LastDelivery   *WebhookDelivery    `json:"lastDelivery,omitempty"`
+
+	// Conditions describe the current state of the resource.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The generation of the spec that was last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Fields of the GitHub resource that differed from the spec when last reconciled,
+	// including differences that were corrected.
+	// +optional
+	// +listType=map
+	// +listMapKey=field
+	Drift []FieldDrift `json:"drift,omitempty"`
+
+	// GitHub mutations computed by the last reconcile but not applied, e.g. in dry-run mode
+	// or under the Observe management policy.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
+}
+
+// WebhookDelivery is the result of the most recent delivery of a webhook
+type WebhookDelivery struct {
+	Id          *int64       `json:"id,omitempty"`
+	Event       *string      `json:"event,omitempty"`
+	Action      *string      `json:"action,omitempty"`
+	Status      *string      `json:"status,omitempty"`
+	StatusCode  *int         `json:"statusCode,omitempty"`
+	DeliveredAt *metav1.Time `json:"deliveredAt,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Drifted",type=string,JSONPath=`.status.conditions[?(@.type=="Drifted")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Webhook is the Schema for the webhooks API
+type Webhook struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WebhookSpec   `json:"spec,omitempty"`
+	Status WebhookStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// WebhookList contains a list of Webhook
+type WebhookList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Webhook `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Webhook{}, &WebhookList{})
+}
+
+// +kubebuilder:validation:Enum=json;form
+type WebhookContentType string
+
+const (
+	WebhookContentTypeJson WebhookContentType = "json"
+	WebhookContentTypeForm WebhookContentType = "form"
+)
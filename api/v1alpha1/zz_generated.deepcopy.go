@@ -21,11 +21,13 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BranchProtection) DeepCopyInto(out *BranchProtection) {
+func (in *ActionsSecret) DeepCopyInto(out *ActionsSecret) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -33,18 +35,18 @@ func (in *BranchProtection) DeepCopyInto(out *BranchProtection) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtection.
-func (in *BranchProtection) DeepCopy() *BranchProtection {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsSecret.
+func (in *ActionsSecret) DeepCopy() *ActionsSecret {
 	if in == nil {
 		return nil
 	}
-	out := new(BranchProtection)
+	out := new(ActionsSecret)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *BranchProtection) DeepCopyObject() runtime.Object {
+func (in *ActionsSecret) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -52,31 +54,31 @@ func (in *BranchProtection) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BranchProtectionList) DeepCopyInto(out *BranchProtectionList) {
+func (in *ActionsSecretList) DeepCopyInto(out *ActionsSecretList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]BranchProtection, len(*in))
+		*out = make([]ActionsSecret, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtectionList.
-func (in *BranchProtectionList) DeepCopy() *BranchProtectionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsSecretList.
+func (in *ActionsSecretList) DeepCopy() *ActionsSecretList {
 	if in == nil {
 		return nil
 	}
-	out := new(BranchProtectionList)
+	out := new(ActionsSecretList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *BranchProtectionList) DeepCopyObject() runtime.Object {
+func (in *ActionsSecretList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -84,211 +86,260 @@ func (in *BranchProtectionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BranchProtectionSpec) DeepCopyInto(out *BranchProtectionSpec) {
+func (in *ActionsSecretSpec) DeepCopyInto(out *ActionsSecretSpec) {
 	*out = *in
-	if in.AllowsDeletions != nil {
-		in, out := &in.AllowsDeletions, &out.AllowsDeletions
-		*out = new(bool)
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
+		*out = new(string)
 		**out = **in
 	}
-	if in.AllowsForcePushes != nil {
-		in, out := &in.AllowsForcePushes, &out.AllowsForcePushes
-		*out = new(bool)
+	if in.EnvironmentName != nil {
+		in, out := &in.EnvironmentName, &out.EnvironmentName
+		*out = new(string)
 		**out = **in
 	}
-	if in.BlocksCreations != nil {
-		in, out := &in.BlocksCreations, &out.BlocksCreations
-		*out = new(bool)
+	in.SecretKeyRef.DeepCopyInto(&out.SecretKeyRef)
+	if in.Visibility != nil {
+		in, out := &in.Visibility, &out.Visibility
+		*out = new(ActionsVisibility)
 		**out = **in
 	}
-	if in.BypassForcePushUsers != nil {
-		in, out := &in.BypassForcePushUsers, &out.BypassForcePushUsers
+	if in.SelectedRepositories != nil {
+		in, out := &in.SelectedRepositories, &out.SelectedRepositories
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.BypassForcePushApps != nil {
-		in, out := &in.BypassForcePushApps, &out.BypassForcePushApps
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		**out = **in
 	}
-	if in.BypassForcePushTeams != nil {
-		in, out := &in.BypassForcePushTeams, &out.BypassForcePushTeams
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
 	}
-	if in.BypassPullRequestUsers != nil {
-		in, out := &in.BypassPullRequestUsers, &out.BypassPullRequestUsers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
 	}
-	if in.BypassPullRequestApps != nil {
-		in, out := &in.BypassPullRequestApps, &out.BypassPullRequestApps
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsSecretSpec.
+func (in *ActionsSecretSpec) DeepCopy() *ActionsSecretSpec {
+	if in == nil {
+		return nil
 	}
-	if in.BypassPullRequestTeams != nil {
-		in, out := &in.BypassPullRequestTeams, &out.BypassPullRequestTeams
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out := new(ActionsSecretSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionsSecretStatus) DeepCopyInto(out *ActionsSecretStatus) {
+	*out = *in
+	if in.LastUpdateTimestamp != nil {
+		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
+		*out = (*in).DeepCopy()
 	}
-	if in.DismissesStaleReviews != nil {
-		in, out := &in.DismissesStaleReviews, &out.DismissesStaleReviews
-		*out = new(bool)
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
 		**out = **in
 	}
-	if in.IsAdminEnforced != nil {
-		in, out := &in.IsAdminEnforced, &out.IsAdminEnforced
-		*out = new(bool)
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(string)
 		**out = **in
 	}
-	if in.LockAllowsFetchAndMerge != nil {
-		in, out := &in.LockAllowsFetchAndMerge, &out.LockAllowsFetchAndMerge
-		*out = new(bool)
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
+		*out = new(string)
 		**out = **in
 	}
-	if in.LockBranch != nil {
-		in, out := &in.LockBranch, &out.LockBranch
-		*out = new(bool)
+	if in.EnvironmentName != nil {
+		in, out := &in.EnvironmentName, &out.EnvironmentName
+		*out = new(string)
 		**out = **in
 	}
-	if in.PushAllowanceUsers != nil {
-		in, out := &in.PushAllowanceUsers, &out.PushAllowanceUsers
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Visibility != nil {
+		in, out := &in.Visibility, &out.Visibility
+		*out = new(ActionsVisibility)
+		**out = **in
 	}
-	if in.PushAllowanceApps != nil {
-		in, out := &in.PushAllowanceApps, &out.PushAllowanceApps
+	if in.SelectedRepositories != nil {
+		in, out := &in.SelectedRepositories, &out.SelectedRepositories
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.PushAllowanceTeams != nil {
-		in, out := &in.PushAllowanceTeams, &out.PushAllowanceTeams
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
 	}
-	if in.RequireLastPushApproval != nil {
-		in, out := &in.RequireLastPushApproval, &out.RequireLastPushApproval
-		*out = new(bool)
-		**out = **in
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
 	}
-	if in.RequiredApprovingReviewCount != nil {
-		in, out := &in.RequiredApprovingReviewCount, &out.RequiredApprovingReviewCount
-		*out = new(int)
+	if in.ValueHash != nil {
+		in, out := &in.ValueHash, &out.ValueHash
+		*out = new(string)
 		**out = **in
 	}
-	if in.RequiredDeploymentEnvironments != nil {
-		in, out := &in.RequiredDeploymentEnvironments, &out.RequiredDeploymentEnvironments
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.RequiredStatusCheckContexts != nil {
-		in, out := &in.RequiredStatusCheckContexts, &out.RequiredStatusCheckContexts
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.RequiredStatusChecks != nil {
-		in, out := &in.RequiredStatusChecks, &out.RequiredStatusChecks
-		*out = make([]RequiredStatusCheck, len(*in))
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.RequiresApprovingReviews != nil {
-		in, out := &in.RequiresApprovingReviews, &out.RequiresApprovingReviews
-		*out = new(bool)
-		**out = **in
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.RequiresCodeOwnerReviews != nil {
-		in, out := &in.RequiresCodeOwnerReviews, &out.RequiresCodeOwnerReviews
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsSecretStatus.
+func (in *ActionsSecretStatus) DeepCopy() *ActionsSecretStatus {
+	if in == nil {
+		return nil
 	}
-	if in.RequiresCommitSignatures != nil {
-		in, out := &in.RequiresCommitSignatures, &out.RequiresCommitSignatures
-		*out = new(bool)
-		**out = **in
+	out := new(ActionsSecretStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionsVariable) DeepCopyInto(out *ActionsVariable) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsVariable.
+func (in *ActionsVariable) DeepCopy() *ActionsVariable {
+	if in == nil {
+		return nil
 	}
-	if in.RequiresConversationResolution != nil {
-		in, out := &in.RequiresConversationResolution, &out.RequiresConversationResolution
-		*out = new(bool)
-		**out = **in
+	out := new(ActionsVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActionsVariable) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.RequiresDeployments != nil {
-		in, out := &in.RequiresDeployments, &out.RequiresDeployments
-		*out = new(bool)
-		**out = **in
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionsVariableList) DeepCopyInto(out *ActionsVariableList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ActionsVariable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.RequiresLinearHistory != nil {
-		in, out := &in.RequiresLinearHistory, &out.RequiresLinearHistory
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsVariableList.
+func (in *ActionsVariableList) DeepCopy() *ActionsVariableList {
+	if in == nil {
+		return nil
 	}
-	if in.RequiresStatusChecks != nil {
-		in, out := &in.RequiresStatusChecks, &out.RequiresStatusChecks
-		*out = new(bool)
-		**out = **in
+	out := new(ActionsVariableList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ActionsVariableList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	if in.RequiresStrictStatusChecks != nil {
-		in, out := &in.RequiresStrictStatusChecks, &out.RequiresStrictStatusChecks
-		*out = new(bool)
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActionsVariableSpec) DeepCopyInto(out *ActionsVariableSpec) {
+	*out = *in
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
+		*out = new(string)
 		**out = **in
 	}
-	if in.RestrictsPushes != nil {
-		in, out := &in.RestrictsPushes, &out.RestrictsPushes
-		*out = new(bool)
+	if in.EnvironmentName != nil {
+		in, out := &in.EnvironmentName, &out.EnvironmentName
+		*out = new(string)
 		**out = **in
 	}
-	if in.RestrictsReviewDismissals != nil {
-		in, out := &in.RestrictsReviewDismissals, &out.RestrictsReviewDismissals
-		*out = new(bool)
+	if in.Visibility != nil {
+		in, out := &in.Visibility, &out.Visibility
+		*out = new(ActionsVisibility)
 		**out = **in
 	}
-	if in.ReviewDismissalUsers != nil {
-		in, out := &in.ReviewDismissalUsers, &out.ReviewDismissalUsers
+	if in.SelectedRepositories != nil {
+		in, out := &in.SelectedRepositories, &out.SelectedRepositories
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.ReviewDismissalApps != nil {
-		in, out := &in.ReviewDismissalApps, &out.ReviewDismissalApps
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		**out = **in
 	}
-	if in.ReviewDismissalTeams != nil {
-		in, out := &in.ReviewDismissalTeams, &out.ReviewDismissalTeams
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
+	}
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtectionSpec.
-func (in *BranchProtectionSpec) DeepCopy() *BranchProtectionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsVariableSpec.
+func (in *ActionsVariableSpec) DeepCopy() *ActionsVariableSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(BranchProtectionSpec)
+	out := new(ActionsVariableSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BranchProtectionStatus) DeepCopyInto(out *BranchProtectionStatus) {
+func (in *ActionsVariableStatus) DeepCopyInto(out *ActionsVariableStatus) {
 	*out = *in
 	if in.LastUpdateTimestamp != nil {
 		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
 		*out = (*in).DeepCopy()
 	}
-	if in.NodeId != nil {
-		in, out := &in.NodeId, &out.NodeId
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
 		*out = new(string)
 		**out = **in
 	}
-	if in.RepositoryNodeId != nil {
-		in, out := &in.RepositoryNodeId, &out.RepositoryNodeId
-		*out = new(string)
-		**out = **in
-	}
-	if in.RepositoryOwner != nil {
-		in, out := &in.RepositoryOwner, &out.RepositoryOwner
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
 		*out = new(string)
 		**out = **in
 	}
@@ -297,11 +348,132 @@ func (in *BranchProtectionStatus) DeepCopyInto(out *BranchProtectionStatus) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.Pattern != nil {
-		in, out := &in.Pattern, &out.Pattern
+	if in.EnvironmentName != nil {
+		in, out := &in.EnvironmentName, &out.EnvironmentName
 		*out = new(string)
 		**out = **in
 	}
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	if in.Visibility != nil {
+		in, out := &in.Visibility, &out.Visibility
+		*out = new(ActionsVisibility)
+		**out = **in
+	}
+	if in.SelectedRepositories != nil {
+		in, out := &in.SelectedRepositories, &out.SelectedRepositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActionsVariableStatus.
+func (in *ActionsVariableStatus) DeepCopy() *ActionsVariableStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActionsVariableStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BranchProtection) DeepCopyInto(out *BranchProtection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtection.
+func (in *BranchProtection) DeepCopy() *BranchProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(BranchProtection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BranchProtection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BranchProtectionList) DeepCopyInto(out *BranchProtectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BranchProtection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtectionList.
+func (in *BranchProtectionList) DeepCopy() *BranchProtectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(BranchProtectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BranchProtectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BranchProtectionSpec) DeepCopyInto(out *BranchProtectionSpec) {
+	*out = *in
+	if in.RepositoryRef != nil {
+		in, out := &in.RepositoryRef, &out.RepositoryRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.AllowsDeletions != nil {
 		in, out := &in.AllowsDeletions, &out.AllowsDeletions
 		*out = new(bool)
@@ -332,6 +504,11 @@ func (in *BranchProtectionStatus) DeepCopyInto(out *BranchProtectionStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.BypassForcePushTeamRefs != nil {
+		in, out := &in.BypassForcePushTeamRefs, &out.BypassForcePushTeamRefs
+		*out = make([]ResourceReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.BypassPullRequestUsers != nil {
 		in, out := &in.BypassPullRequestUsers, &out.BypassPullRequestUsers
 		*out = make([]string, len(*in))
@@ -347,6 +524,11 @@ func (in *BranchProtectionStatus) DeepCopyInto(out *BranchProtectionStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.BypassPullRequestTeamRefs != nil {
+		in, out := &in.BypassPullRequestTeamRefs, &out.BypassPullRequestTeamRefs
+		*out = make([]ResourceReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.DismissesStaleReviews != nil {
 		in, out := &in.DismissesStaleReviews, &out.DismissesStaleReviews
 		*out = new(bool)
@@ -382,6 +564,11 @@ func (in *BranchProtectionStatus) DeepCopyInto(out *BranchProtectionStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PushAllowanceTeamRefs != nil {
+		in, out := &in.PushAllowanceTeamRefs, &out.PushAllowanceTeamRefs
+		*out = make([]ResourceReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.RequireLastPushApproval != nil {
 		in, out := &in.RequireLastPushApproval, &out.RequireLastPushApproval
 		*out = new(bool)
@@ -474,363 +661,275 @@ func (in *BranchProtectionStatus) DeepCopyInto(out *BranchProtectionStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtectionStatus.
-func (in *BranchProtectionStatus) DeepCopy() *BranchProtectionStatus {
-	if in == nil {
-		return nil
+	if in.ReviewDismissalTeamRefs != nil {
+		in, out := &in.ReviewDismissalTeamRefs, &out.ReviewDismissalTeamRefs
+		*out = make([]ResourceReference, len(*in))
+		copy(*out, *in)
 	}
-	out := new(BranchProtectionStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Organization) DeepCopyInto(out *Organization) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Organization.
-func (in *Organization) DeepCopy() *Organization {
-	if in == nil {
-		return nil
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		**out = **in
 	}
-	out := new(Organization)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Organization) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
 	}
-	return nil
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OrganizationList) DeepCopyInto(out *OrganizationList) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Organization, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationList.
-func (in *OrganizationList) DeepCopy() *OrganizationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtectionSpec.
+func (in *BranchProtectionSpec) DeepCopy() *BranchProtectionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OrganizationList)
+	out := new(BranchProtectionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OrganizationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OrganizationSpec) DeepCopyInto(out *OrganizationSpec) {
+func (in *BranchProtectionStatus) DeepCopyInto(out *BranchProtectionStatus) {
 	*out = *in
-	if in.Name != nil {
-		in, out := &in.Name, &out.Name
-		*out = new(string)
-		**out = **in
-	}
-	if in.BillingEmail != nil {
-		in, out := &in.BillingEmail, &out.BillingEmail
-		*out = new(string)
-		**out = **in
+	if in.LastUpdateTimestamp != nil {
+		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
+		*out = (*in).DeepCopy()
 	}
-	if in.Company != nil {
-		in, out := &in.Company, &out.Company
+	if in.NodeId != nil {
+		in, out := &in.NodeId, &out.NodeId
 		*out = new(string)
 		**out = **in
 	}
-	if in.Email != nil {
-		in, out := &in.Email, &out.Email
+	if in.RepositoryNodeId != nil {
+		in, out := &in.RepositoryNodeId, &out.RepositoryNodeId
 		*out = new(string)
 		**out = **in
 	}
-	if in.TwitterUsername != nil {
-		in, out := &in.TwitterUsername, &out.TwitterUsername
+	if in.RepositoryOwner != nil {
+		in, out := &in.RepositoryOwner, &out.RepositoryOwner
 		*out = new(string)
 		**out = **in
 	}
-	if in.Location != nil {
-		in, out := &in.Location, &out.Location
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
 		*out = new(string)
 		**out = **in
 	}
-	if in.Description != nil {
-		in, out := &in.Description, &out.Description
+	if in.Pattern != nil {
+		in, out := &in.Pattern, &out.Pattern
 		*out = new(string)
 		**out = **in
 	}
-	if in.HasOrganizationProjects != nil {
-		in, out := &in.HasOrganizationProjects, &out.HasOrganizationProjects
+	if in.AllowsDeletions != nil {
+		in, out := &in.AllowsDeletions, &out.AllowsDeletions
 		*out = new(bool)
 		**out = **in
 	}
-	if in.HasRepositoryProjects != nil {
-		in, out := &in.HasRepositoryProjects, &out.HasRepositoryProjects
+	if in.AllowsForcePushes != nil {
+		in, out := &in.AllowsForcePushes, &out.AllowsForcePushes
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DefaultRepositoryPermission != nil {
-		in, out := &in.DefaultRepositoryPermission, &out.DefaultRepositoryPermission
-		*out = new(DefaultRepositoryPermission)
-		**out = **in
-	}
-	if in.MembersCanCreateRepositories != nil {
-		in, out := &in.MembersCanCreateRepositories, &out.MembersCanCreateRepositories
+	if in.BlocksCreations != nil {
+		in, out := &in.BlocksCreations, &out.BlocksCreations
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MembersCanCreateInternalRepositories != nil {
-		in, out := &in.MembersCanCreateInternalRepositories, &out.MembersCanCreateInternalRepositories
-		*out = new(bool)
-		**out = **in
+	if in.BypassForcePushUsers != nil {
+		in, out := &in.BypassForcePushUsers, &out.BypassForcePushUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.MembersCanCreatePrivateRepositories != nil {
-		in, out := &in.MembersCanCreatePrivateRepositories, &out.MembersCanCreatePrivateRepositories
-		*out = new(bool)
-		**out = **in
+	if in.BypassForcePushApps != nil {
+		in, out := &in.BypassForcePushApps, &out.BypassForcePushApps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.MembersCanCreatePublicRepositories != nil {
-		in, out := &in.MembersCanCreatePublicRepositories, &out.MembersCanCreatePublicRepositories
-		*out = new(bool)
-		**out = **in
+	if in.BypassForcePushTeams != nil {
+		in, out := &in.BypassForcePushTeams, &out.BypassForcePushTeams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.MembersCanCreatePages != nil {
-		in, out := &in.MembersCanCreatePages, &out.MembersCanCreatePages
-		*out = new(bool)
-		**out = **in
+	if in.BypassPullRequestUsers != nil {
+		in, out := &in.BypassPullRequestUsers, &out.BypassPullRequestUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.MembersCanCreatePublicPages != nil {
-		in, out := &in.MembersCanCreatePublicPages, &out.MembersCanCreatePublicPages
-		*out = new(bool)
-		**out = **in
+	if in.BypassPullRequestApps != nil {
+		in, out := &in.BypassPullRequestApps, &out.BypassPullRequestApps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.MembersCanCreatePrivatePages != nil {
-		in, out := &in.MembersCanCreatePrivatePages, &out.MembersCanCreatePrivatePages
-		*out = new(bool)
-		**out = **in
+	if in.BypassPullRequestTeams != nil {
+		in, out := &in.BypassPullRequestTeams, &out.BypassPullRequestTeams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.MembersCanForkPrivateRepositories != nil {
-		in, out := &in.MembersCanForkPrivateRepositories, &out.MembersCanForkPrivateRepositories
+	if in.DismissesStaleReviews != nil {
+		in, out := &in.DismissesStaleReviews, &out.DismissesStaleReviews
 		*out = new(bool)
 		**out = **in
 	}
-	if in.WebCommitSignoffRequired != nil {
-		in, out := &in.WebCommitSignoffRequired, &out.WebCommitSignoffRequired
+	if in.IsAdminEnforced != nil {
+		in, out := &in.IsAdminEnforced, &out.IsAdminEnforced
 		*out = new(bool)
 		**out = **in
 	}
-	if in.Blog != nil {
-		in, out := &in.Blog, &out.Blog
-		*out = new(string)
-		**out = **in
-	}
-	if in.AdvancedSecurityEnabledForNewRepositories != nil {
-		in, out := &in.AdvancedSecurityEnabledForNewRepositories, &out.AdvancedSecurityEnabledForNewRepositories
+	if in.LockAllowsFetchAndMerge != nil {
+		in, out := &in.LockAllowsFetchAndMerge, &out.LockAllowsFetchAndMerge
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DependabotAlertsEnabledForNewRepositories != nil {
-		in, out := &in.DependabotAlertsEnabledForNewRepositories, &out.DependabotAlertsEnabledForNewRepositories
+	if in.LockBranch != nil {
+		in, out := &in.LockBranch, &out.LockBranch
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DependabotSecurityUpdatesEnabledForNewRepositories != nil {
-		in, out := &in.DependabotSecurityUpdatesEnabledForNewRepositories, &out.DependabotSecurityUpdatesEnabledForNewRepositories
-		*out = new(bool)
-		**out = **in
+	if in.PushAllowanceUsers != nil {
+		in, out := &in.PushAllowanceUsers, &out.PushAllowanceUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.DependencyGraphEnabledForNewRepositories != nil {
-		in, out := &in.DependencyGraphEnabledForNewRepositories, &out.DependencyGraphEnabledForNewRepositories
-		*out = new(bool)
-		**out = **in
+	if in.PushAllowanceApps != nil {
+		in, out := &in.PushAllowanceApps, &out.PushAllowanceApps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.SecretScanningEnabledForNewRepositories != nil {
-		in, out := &in.SecretScanningEnabledForNewRepositories, &out.SecretScanningEnabledForNewRepositories
-		*out = new(bool)
-		**out = **in
+	if in.PushAllowanceTeams != nil {
+		in, out := &in.PushAllowanceTeams, &out.PushAllowanceTeams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.SecretScanningPushProtectionEnabledForNewRepositories != nil {
-		in, out := &in.SecretScanningPushProtectionEnabledForNewRepositories, &out.SecretScanningPushProtectionEnabledForNewRepositories
+	if in.RequireLastPushApproval != nil {
+		in, out := &in.RequireLastPushApproval, &out.RequireLastPushApproval
 		*out = new(bool)
 		**out = **in
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationSpec.
-func (in *OrganizationSpec) DeepCopy() *OrganizationSpec {
-	if in == nil {
-		return nil
-	}
-	out := new(OrganizationSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OrganizationStatus) DeepCopyInto(out *OrganizationStatus) {
-	*out = *in
-	if in.Login != nil {
-		in, out := &in.Login, &out.Login
-		*out = new(string)
-		**out = **in
-	}
-	if in.NodeId != nil {
-		in, out := &in.NodeId, &out.NodeId
-		*out = new(string)
-		**out = **in
-	}
-	if in.LastUpdateTimestamp != nil {
-		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
-		*out = (*in).DeepCopy()
-	}
-	if in.TwitterUsername != nil {
-		in, out := &in.TwitterUsername, &out.TwitterUsername
-		*out = new(string)
+	if in.RequiredApprovingReviewCount != nil {
+		in, out := &in.RequiredApprovingReviewCount, &out.RequiredApprovingReviewCount
+		*out = new(int)
 		**out = **in
 	}
-	if in.Location != nil {
-		in, out := &in.Location, &out.Location
-		*out = new(string)
-		**out = **in
+	if in.RequiredDeploymentEnvironments != nil {
+		in, out := &in.RequiredDeploymentEnvironments, &out.RequiredDeploymentEnvironments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Description != nil {
-		in, out := &in.Description, &out.Description
-		*out = new(string)
-		**out = **in
+	if in.RequiredStatusCheckContexts != nil {
+		in, out := &in.RequiredStatusCheckContexts, &out.RequiredStatusCheckContexts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.HasOrganizationProjects != nil {
-		in, out := &in.HasOrganizationProjects, &out.HasOrganizationProjects
-		*out = new(bool)
-		**out = **in
+	if in.RequiredStatusChecks != nil {
+		in, out := &in.RequiredStatusChecks, &out.RequiredStatusChecks
+		*out = make([]RequiredStatusCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.HasRepositoryProjects != nil {
-		in, out := &in.HasRepositoryProjects, &out.HasRepositoryProjects
+	if in.RequiresApprovingReviews != nil {
+		in, out := &in.RequiresApprovingReviews, &out.RequiresApprovingReviews
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DefaultRepositoryPermission != nil {
-		in, out := &in.DefaultRepositoryPermission, &out.DefaultRepositoryPermission
-		*out = new(DefaultRepositoryPermission)
-		**out = **in
-	}
-	if in.MembersCanCreateRepositories != nil {
-		in, out := &in.MembersCanCreateRepositories, &out.MembersCanCreateRepositories
+	if in.RequiresCodeOwnerReviews != nil {
+		in, out := &in.RequiresCodeOwnerReviews, &out.RequiresCodeOwnerReviews
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MembersCanCreateInternalRepositories != nil {
-		in, out := &in.MembersCanCreateInternalRepositories, &out.MembersCanCreateInternalRepositories
+	if in.RequiresCommitSignatures != nil {
+		in, out := &in.RequiresCommitSignatures, &out.RequiresCommitSignatures
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MembersCanCreatePrivateRepositories != nil {
-		in, out := &in.MembersCanCreatePrivateRepositories, &out.MembersCanCreatePrivateRepositories
+	if in.RequiresConversationResolution != nil {
+		in, out := &in.RequiresConversationResolution, &out.RequiresConversationResolution
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MembersCanCreatePublicRepositories != nil {
-		in, out := &in.MembersCanCreatePublicRepositories, &out.MembersCanCreatePublicRepositories
+	if in.RequiresDeployments != nil {
+		in, out := &in.RequiresDeployments, &out.RequiresDeployments
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MembersCanCreatePages != nil {
-		in, out := &in.MembersCanCreatePages, &out.MembersCanCreatePages
+	if in.RequiresLinearHistory != nil {
+		in, out := &in.RequiresLinearHistory, &out.RequiresLinearHistory
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MembersCanCreatePublicPages != nil {
-		in, out := &in.MembersCanCreatePublicPages, &out.MembersCanCreatePublicPages
+	if in.RequiresStatusChecks != nil {
+		in, out := &in.RequiresStatusChecks, &out.RequiresStatusChecks
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MembersCanCreatePrivatePages != nil {
-		in, out := &in.MembersCanCreatePrivatePages, &out.MembersCanCreatePrivatePages
+	if in.RequiresStrictStatusChecks != nil {
+		in, out := &in.RequiresStrictStatusChecks, &out.RequiresStrictStatusChecks
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MembersCanForkPrivateRepositories != nil {
-		in, out := &in.MembersCanForkPrivateRepositories, &out.MembersCanForkPrivateRepositories
+	if in.RestrictsPushes != nil {
+		in, out := &in.RestrictsPushes, &out.RestrictsPushes
 		*out = new(bool)
 		**out = **in
 	}
-	if in.WebCommitSignoffRequired != nil {
-		in, out := &in.WebCommitSignoffRequired, &out.WebCommitSignoffRequired
+	if in.RestrictsReviewDismissals != nil {
+		in, out := &in.RestrictsReviewDismissals, &out.RestrictsReviewDismissals
 		*out = new(bool)
 		**out = **in
 	}
-	if in.Blog != nil {
-		in, out := &in.Blog, &out.Blog
-		*out = new(string)
-		**out = **in
-	}
-	if in.AdvancedSecurityEnabledForNewRepositories != nil {
-		in, out := &in.AdvancedSecurityEnabledForNewRepositories, &out.AdvancedSecurityEnabledForNewRepositories
-		*out = new(bool)
-		**out = **in
+	if in.ReviewDismissalUsers != nil {
+		in, out := &in.ReviewDismissalUsers, &out.ReviewDismissalUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.DependabotAlertsEnabledForNewRepositories != nil {
-		in, out := &in.DependabotAlertsEnabledForNewRepositories, &out.DependabotAlertsEnabledForNewRepositories
-		*out = new(bool)
-		**out = **in
+	if in.ReviewDismissalApps != nil {
+		in, out := &in.ReviewDismissalApps, &out.ReviewDismissalApps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.DependabotSecurityUpdatesEnabledForNewRepositories != nil {
-		in, out := &in.DependabotSecurityUpdatesEnabledForNewRepositories, &out.DependabotSecurityUpdatesEnabledForNewRepositories
-		*out = new(bool)
-		**out = **in
+	if in.ReviewDismissalTeams != nil {
+		in, out := &in.ReviewDismissalTeams, &out.ReviewDismissalTeams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.DependencyGraphEnabledForNewRepositories != nil {
-		in, out := &in.DependencyGraphEnabledForNewRepositories, &out.DependencyGraphEnabledForNewRepositories
-		*out = new(bool)
-		**out = **in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.SecretScanningEnabledForNewRepositories != nil {
-		in, out := &in.SecretScanningEnabledForNewRepositories, &out.SecretScanningEnabledForNewRepositories
-		*out = new(bool)
-		**out = **in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.SecretScanningPushProtectionEnabledForNewRepositories != nil {
-		in, out := &in.SecretScanningPushProtectionEnabledForNewRepositories, &out.SecretScanningPushProtectionEnabledForNewRepositories
-		*out = new(bool)
-		**out = **in
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationStatus.
-func (in *OrganizationStatus) DeepCopy() *OrganizationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BranchProtectionStatus.
+func (in *BranchProtectionStatus) DeepCopy() *BranchProtectionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OrganizationStatus)
+	out := new(BranchProtectionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Repository) DeepCopyInto(out *Repository) {
+func (in *DeployKey) DeepCopyInto(out *DeployKey) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -838,18 +937,18 @@ func (in *Repository) DeepCopyInto(out *Repository) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Repository.
-func (in *Repository) DeepCopy() *Repository {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployKey.
+func (in *DeployKey) DeepCopy() *DeployKey {
 	if in == nil {
 		return nil
 	}
-	out := new(Repository)
+	out := new(DeployKey)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Repository) DeepCopyObject() runtime.Object {
+func (in *DeployKey) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -857,31 +956,31 @@ func (in *Repository) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryList) DeepCopyInto(out *RepositoryList) {
+func (in *DeployKeyList) DeepCopyInto(out *DeployKeyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Repository, len(*in))
+		*out = make([]DeployKey, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryList.
-func (in *RepositoryList) DeepCopy() *RepositoryList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployKeyList.
+func (in *DeployKeyList) DeepCopy() *DeployKeyList {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryList)
+	out := new(DeployKeyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RepositoryList) DeepCopyObject() runtime.Object {
+func (in *DeployKeyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -889,29 +988,1332 @@ func (in *RepositoryList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositorySpec) DeepCopyInto(out *RepositorySpec) {
+func (in *DeployKeySpec) DeepCopyInto(out *DeployKeySpec) {
 	*out = *in
-	if in.Description != nil {
-		in, out := &in.Description, &out.Description
-		*out = new(string)
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Homepage != nil {
-		in, out := &in.Homepage, &out.Homepage
+	if in.PublicKeySecretRef != nil {
+		in, out := &in.PublicKeySecretRef, &out.PublicKeySecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrivateKeySecretName != nil {
+		in, out := &in.PrivateKeySecretName, &out.PrivateKeySecretName
 		*out = new(string)
 		**out = **in
 	}
-	if in.DefaultBranch != nil {
-		in, out := &in.DefaultBranch, &out.DefaultBranch
-		*out = new(string)
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
 		**out = **in
 	}
-	if in.TemplateOwner != nil {
-		in, out := &in.TemplateOwner, &out.TemplateOwner
-		*out = new(string)
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
 		**out = **in
 	}
-	if in.TemplateRepository != nil {
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployKeySpec.
+func (in *DeployKeySpec) DeepCopy() *DeployKeySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployKeySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployKeyStatus) DeepCopyInto(out *DeployKeyStatus) {
+	*out = *in
+	if in.LastUpdateTimestamp != nil {
+		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Id != nil {
+		in, out := &in.Id, &out.Id
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RepositoryOwner != nil {
+		in, out := &in.RepositoryOwner, &out.RepositoryOwner
+		*out = new(string)
+		**out = **in
+	}
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Title != nil {
+		in, out := &in.Title, &out.Title
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(string)
+		**out = **in
+	}
+	if in.Verified != nil {
+		in, out := &in.Verified, &out.Verified
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUsed != nil {
+		in, out := &in.LastUsed, &out.LastUsed
+		*out = (*in).DeepCopy()
+	}
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployKeyStatus.
+func (in *DeployKeyStatus) DeepCopy() *DeployKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentBranchPolicy) DeepCopyInto(out *DeploymentBranchPolicy) {
+	*out = *in
+	if in.BranchPatterns != nil {
+		in, out := &in.BranchPatterns, &out.BranchPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TagPatterns != nil {
+		in, out := &in.TagPatterns, &out.TagPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentBranchPolicy.
+func (in *DeploymentBranchPolicy) DeepCopy() *DeploymentBranchPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentBranchPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Environment) DeepCopyInto(out *Environment) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Environment.
+func (in *Environment) DeepCopy() *Environment {
+	if in == nil {
+		return nil
+	}
+	out := new(Environment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Environment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentList) DeepCopyInto(out *EnvironmentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Environment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentList.
+func (in *EnvironmentList) DeepCopy() *EnvironmentList {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvironmentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
+	*out = *in
+	if in.WaitTimer != nil {
+		in, out := &in.WaitTimer, &out.WaitTimer
+		*out = new(int)
+		**out = **in
+	}
+	if in.PreventSelfReview != nil {
+		in, out := &in.PreventSelfReview, &out.PreventSelfReview
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CanAdminsBypass != nil {
+		in, out := &in.CanAdminsBypass, &out.CanAdminsBypass
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RequiredReviewerUsers != nil {
+		in, out := &in.RequiredReviewerUsers, &out.RequiredReviewerUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredReviewerTeams != nil {
+		in, out := &in.RequiredReviewerTeams, &out.RequiredReviewerTeams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeploymentBranchPolicy != nil {
+		in, out := &in.DeploymentBranchPolicy, &out.DeploymentBranchPolicy
+		*out = new(DeploymentBranchPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		**out = **in
+	}
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
+	}
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentSpec.
+func (in *EnvironmentSpec) DeepCopy() *EnvironmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentStatus) DeepCopyInto(out *EnvironmentStatus) {
+	*out = *in
+	if in.LastUpdateTimestamp != nil {
+		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Id != nil {
+		in, out := &in.Id, &out.Id
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NodeId != nil {
+		in, out := &in.NodeId, &out.NodeId
+		*out = new(string)
+		**out = **in
+	}
+	if in.RepositoryOwner != nil {
+		in, out := &in.RepositoryOwner, &out.RepositoryOwner
+		*out = new(string)
+		**out = **in
+	}
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.WaitTimer != nil {
+		in, out := &in.WaitTimer, &out.WaitTimer
+		*out = new(int)
+		**out = **in
+	}
+	if in.PreventSelfReview != nil {
+		in, out := &in.PreventSelfReview, &out.PreventSelfReview
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CanAdminsBypass != nil {
+		in, out := &in.CanAdminsBypass, &out.CanAdminsBypass
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RequiredReviewerUsers != nil {
+		in, out := &in.RequiredReviewerUsers, &out.RequiredReviewerUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredReviewerTeams != nil {
+		in, out := &in.RequiredReviewerTeams, &out.RequiredReviewerTeams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeploymentBranchPolicy != nil {
+		in, out := &in.DeploymentBranchPolicy, &out.DeploymentBranchPolicy
+		*out = new(DeploymentBranchPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentStatus.
+func (in *EnvironmentStatus) DeepCopy() *EnvironmentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldDrift) DeepCopyInto(out *FieldDrift) {
+	*out = *in
+	in.DetectedAt.DeepCopyInto(&out.DetectedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldDrift.
+func (in *FieldDrift) DeepCopy() *FieldDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubProvider) DeepCopyInto(out *GitHubProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubProvider.
+func (in *GitHubProvider) DeepCopy() *GitHubProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitHubProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubProviderList) DeepCopyInto(out *GitHubProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GitHubProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubProviderList.
+func (in *GitHubProviderList) DeepCopy() *GitHubProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitHubProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubProviderSpec) DeepCopyInto(out *GitHubProviderSpec) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+	if in.BaseURL != nil {
+		in, out := &in.BaseURL, &out.BaseURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.UploadURL != nil {
+		in, out := &in.UploadURL, &out.UploadURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.GraphQLURL != nil {
+		in, out := &in.GraphQLURL, &out.GraphQLURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubProviderSpec.
+func (in *GitHubProviderSpec) DeepCopy() *GitHubProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubProviderStatus) DeepCopyInto(out *GitHubProviderStatus) {
+	*out = *in
+	if in.AuthType != nil {
+		in, out := &in.AuthType, &out.AuthType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubProviderStatus.
+func (in *GitHubProviderStatus) DeepCopy() *GitHubProviderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubProviderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Organization) DeepCopyInto(out *Organization) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Organization.
+func (in *Organization) DeepCopy() *Organization {
+	if in == nil {
+		return nil
+	}
+	out := new(Organization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Organization) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationInvitation) DeepCopyInto(out *OrganizationInvitation) {
+	*out = *in
+	if in.Login != nil {
+		in, out := &in.Login, &out.Login
+		*out = new(string)
+		**out = **in
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(string)
+		**out = **in
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedAt != nil {
+		in, out := &in.FailedAt, &out.FailedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedReason != nil {
+		in, out := &in.FailedReason, &out.FailedReason
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationInvitation.
+func (in *OrganizationInvitation) DeepCopy() *OrganizationInvitation {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationInvitation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationList) DeepCopyInto(out *OrganizationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Organization, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationList.
+func (in *OrganizationList) DeepCopy() *OrganizationList {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OrganizationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationMembership) DeepCopyInto(out *OrganizationMembership) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Admins != nil {
+		in, out := &in.Admins, &out.Admins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Prune != nil {
+		in, out := &in.Prune, &out.Prune
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationMembership.
+func (in *OrganizationMembership) DeepCopy() *OrganizationMembership {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationMembership)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationSpec) DeepCopyInto(out *OrganizationSpec) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.BillingEmail != nil {
+		in, out := &in.BillingEmail, &out.BillingEmail
+		*out = new(string)
+		**out = **in
+	}
+	if in.Company != nil {
+		in, out := &in.Company, &out.Company
+		*out = new(string)
+		**out = **in
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(string)
+		**out = **in
+	}
+	if in.TwitterUsername != nil {
+		in, out := &in.TwitterUsername, &out.TwitterUsername
+		*out = new(string)
+		**out = **in
+	}
+	if in.Location != nil {
+		in, out := &in.Location, &out.Location
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.HasOrganizationProjects != nil {
+		in, out := &in.HasOrganizationProjects, &out.HasOrganizationProjects
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HasRepositoryProjects != nil {
+		in, out := &in.HasRepositoryProjects, &out.HasRepositoryProjects
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultRepositoryPermission != nil {
+		in, out := &in.DefaultRepositoryPermission, &out.DefaultRepositoryPermission
+		*out = new(DefaultRepositoryPermission)
+		**out = **in
+	}
+	if in.MembersCanCreateRepositories != nil {
+		in, out := &in.MembersCanCreateRepositories, &out.MembersCanCreateRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreateInternalRepositories != nil {
+		in, out := &in.MembersCanCreateInternalRepositories, &out.MembersCanCreateInternalRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePrivateRepositories != nil {
+		in, out := &in.MembersCanCreatePrivateRepositories, &out.MembersCanCreatePrivateRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePublicRepositories != nil {
+		in, out := &in.MembersCanCreatePublicRepositories, &out.MembersCanCreatePublicRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePages != nil {
+		in, out := &in.MembersCanCreatePages, &out.MembersCanCreatePages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePublicPages != nil {
+		in, out := &in.MembersCanCreatePublicPages, &out.MembersCanCreatePublicPages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePrivatePages != nil {
+		in, out := &in.MembersCanCreatePrivatePages, &out.MembersCanCreatePrivatePages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanForkPrivateRepositories != nil {
+		in, out := &in.MembersCanForkPrivateRepositories, &out.MembersCanForkPrivateRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WebCommitSignoffRequired != nil {
+		in, out := &in.WebCommitSignoffRequired, &out.WebCommitSignoffRequired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Blog != nil {
+		in, out := &in.Blog, &out.Blog
+		*out = new(string)
+		**out = **in
+	}
+	if in.AdvancedSecurityEnabledForNewRepositories != nil {
+		in, out := &in.AdvancedSecurityEnabledForNewRepositories, &out.AdvancedSecurityEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DependabotAlertsEnabledForNewRepositories != nil {
+		in, out := &in.DependabotAlertsEnabledForNewRepositories, &out.DependabotAlertsEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DependabotSecurityUpdatesEnabledForNewRepositories != nil {
+		in, out := &in.DependabotSecurityUpdatesEnabledForNewRepositories, &out.DependabotSecurityUpdatesEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DependencyGraphEnabledForNewRepositories != nil {
+		in, out := &in.DependencyGraphEnabledForNewRepositories, &out.DependencyGraphEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecretScanningEnabledForNewRepositories != nil {
+		in, out := &in.SecretScanningEnabledForNewRepositories, &out.SecretScanningEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecretScanningPushProtectionEnabledForNewRepositories != nil {
+		in, out := &in.SecretScanningPushProtectionEnabledForNewRepositories, &out.SecretScanningPushProtectionEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Membership != nil {
+		in, out := &in.Membership, &out.Membership
+		*out = new(OrganizationMembership)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
+	}
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationSpec.
+func (in *OrganizationSpec) DeepCopy() *OrganizationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrganizationStatus) DeepCopyInto(out *OrganizationStatus) {
+	*out = *in
+	if in.Login != nil {
+		in, out := &in.Login, &out.Login
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeId != nil {
+		in, out := &in.NodeId, &out.NodeId
+		*out = new(string)
+		**out = **in
+	}
+	if in.LastUpdateTimestamp != nil {
+		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.TwitterUsername != nil {
+		in, out := &in.TwitterUsername, &out.TwitterUsername
+		*out = new(string)
+		**out = **in
+	}
+	if in.Location != nil {
+		in, out := &in.Location, &out.Location
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.HasOrganizationProjects != nil {
+		in, out := &in.HasOrganizationProjects, &out.HasOrganizationProjects
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HasRepositoryProjects != nil {
+		in, out := &in.HasRepositoryProjects, &out.HasRepositoryProjects
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultRepositoryPermission != nil {
+		in, out := &in.DefaultRepositoryPermission, &out.DefaultRepositoryPermission
+		*out = new(DefaultRepositoryPermission)
+		**out = **in
+	}
+	if in.MembersCanCreateRepositories != nil {
+		in, out := &in.MembersCanCreateRepositories, &out.MembersCanCreateRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreateInternalRepositories != nil {
+		in, out := &in.MembersCanCreateInternalRepositories, &out.MembersCanCreateInternalRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePrivateRepositories != nil {
+		in, out := &in.MembersCanCreatePrivateRepositories, &out.MembersCanCreatePrivateRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePublicRepositories != nil {
+		in, out := &in.MembersCanCreatePublicRepositories, &out.MembersCanCreatePublicRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePages != nil {
+		in, out := &in.MembersCanCreatePages, &out.MembersCanCreatePages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePublicPages != nil {
+		in, out := &in.MembersCanCreatePublicPages, &out.MembersCanCreatePublicPages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanCreatePrivatePages != nil {
+		in, out := &in.MembersCanCreatePrivatePages, &out.MembersCanCreatePrivatePages
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MembersCanForkPrivateRepositories != nil {
+		in, out := &in.MembersCanForkPrivateRepositories, &out.MembersCanForkPrivateRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WebCommitSignoffRequired != nil {
+		in, out := &in.WebCommitSignoffRequired, &out.WebCommitSignoffRequired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Blog != nil {
+		in, out := &in.Blog, &out.Blog
+		*out = new(string)
+		**out = **in
+	}
+	if in.AdvancedSecurityEnabledForNewRepositories != nil {
+		in, out := &in.AdvancedSecurityEnabledForNewRepositories, &out.AdvancedSecurityEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DependabotAlertsEnabledForNewRepositories != nil {
+		in, out := &in.DependabotAlertsEnabledForNewRepositories, &out.DependabotAlertsEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DependabotSecurityUpdatesEnabledForNewRepositories != nil {
+		in, out := &in.DependabotSecurityUpdatesEnabledForNewRepositories, &out.DependabotSecurityUpdatesEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DependencyGraphEnabledForNewRepositories != nil {
+		in, out := &in.DependencyGraphEnabledForNewRepositories, &out.DependencyGraphEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecretScanningEnabledForNewRepositories != nil {
+		in, out := &in.SecretScanningEnabledForNewRepositories, &out.SecretScanningEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecretScanningPushProtectionEnabledForNewRepositories != nil {
+		in, out := &in.SecretScanningPushProtectionEnabledForNewRepositories, &out.SecretScanningPushProtectionEnabledForNewRepositories
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Admins != nil {
+		in, out := &in.Admins, &out.Admins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Invitations != nil {
+		in, out := &in.Invitations, &out.Invitations
+		*out = make([]OrganizationInvitation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrganizationStatus.
+func (in *OrganizationStatus) DeepCopy() *OrganizationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OrganizationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderReference) DeepCopyInto(out *ProviderReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderReference.
+func (in *ProviderReference) DeepCopy() *ProviderReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Repository) DeepCopyInto(out *Repository) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Repository.
+func (in *Repository) DeepCopy() *Repository {
+	if in == nil {
+		return nil
+	}
+	out := new(Repository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Repository) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryLabel) DeepCopyInto(out *RepositoryLabel) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.PreviousNames != nil {
+		in, out := &in.PreviousNames, &out.PreviousNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryLabel.
+func (in *RepositoryLabel) DeepCopy() *RepositoryLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryList) DeepCopyInto(out *RepositoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Repository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryList.
+func (in *RepositoryList) DeepCopy() *RepositoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositorySpec) DeepCopyInto(out *RepositorySpec) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Homepage != nil {
+		in, out := &in.Homepage, &out.Homepage
+		*out = new(string)
+		**out = **in
+	}
+	if in.DefaultBranch != nil {
+		in, out := &in.DefaultBranch, &out.DefaultBranch
+		*out = new(string)
+		**out = **in
+	}
+	if in.TemplateOwner != nil {
+		in, out := &in.TemplateOwner, &out.TemplateOwner
+		*out = new(string)
+		**out = **in
+	}
+	if in.TemplateRepository != nil {
+		in, out := &in.TemplateRepository, &out.TemplateRepository
+		*out = new(string)
+		**out = **in
+	}
+	if in.AllowRebaseMerge != nil {
+		in, out := &in.AllowRebaseMerge, &out.AllowRebaseMerge
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowUpdateBranch != nil {
+		in, out := &in.AllowUpdateBranch, &out.AllowUpdateBranch
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowSquashMerge != nil {
+		in, out := &in.AllowSquashMerge, &out.AllowSquashMerge
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowMergeCommit != nil {
+		in, out := &in.AllowMergeCommit, &out.AllowMergeCommit
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowAutoMerge != nil {
+		in, out := &in.AllowAutoMerge, &out.AllowAutoMerge
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowForking != nil {
+		in, out := &in.AllowForking, &out.AllowForking
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WebCommitSignoffRequired != nil {
+		in, out := &in.WebCommitSignoffRequired, &out.WebCommitSignoffRequired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeleteBranchOnMerge != nil {
+		in, out := &in.DeleteBranchOnMerge, &out.DeleteBranchOnMerge
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SquashMergeCommitTitle != nil {
+		in, out := &in.SquashMergeCommitTitle, &out.SquashMergeCommitTitle
+		*out = new(SquashMergeCommitTitle)
+		**out = **in
+	}
+	if in.SquashMergeCommitMessage != nil {
+		in, out := &in.SquashMergeCommitMessage, &out.SquashMergeCommitMessage
+		*out = new(SquashMergeCommitMessage)
+		**out = **in
+	}
+	if in.MergeCommitTitle != nil {
+		in, out := &in.MergeCommitTitle, &out.MergeCommitTitle
+		*out = new(MergeCommitTitle)
+		**out = **in
+	}
+	if in.MergeCommitMessage != nil {
+		in, out := &in.MergeCommitMessage, &out.MergeCommitMessage
+		*out = new(MergeCommitMessage)
+		**out = **in
+	}
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Archived != nil {
+		in, out := &in.Archived, &out.Archived
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HasIssues != nil {
+		in, out := &in.HasIssues, &out.HasIssues
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HasWiki != nil {
+		in, out := &in.HasWiki, &out.HasWiki
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HasProjects != nil {
+		in, out := &in.HasProjects, &out.HasProjects
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HasDownloads != nil {
+		in, out := &in.HasDownloads, &out.HasDownloads
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HasDiscussions != nil {
+		in, out := &in.HasDiscussions, &out.HasDiscussions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Visibility != nil {
+		in, out := &in.Visibility, &out.Visibility
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecurityAndAnalysis != nil {
+		in, out := &in.SecurityAndAnalysis, &out.SecurityAndAnalysis
+		*out = new(SecurityAndAnalysis)
+		**out = **in
+	}
+	if in.Collaborators != nil {
+		in, out := &in.Collaborators, &out.Collaborators
+		*out = make(map[string]RepositoryPermission, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PruneCollaborators != nil {
+		in, out := &in.PruneCollaborators, &out.PruneCollaborators
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]RepositoryLabel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PruneLabels != nil {
+		in, out := &in.PruneLabels, &out.PruneLabels
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(RepositoryDeletionPolicy)
+		**out = **in
+	}
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
+	}
+	if in.TransferPolicy != nil {
+		in, out := &in.TransferPolicy, &out.TransferPolicy
+		*out = new(TransferPolicy)
+		**out = **in
+	}
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySpec.
+func (in *RepositorySpec) DeepCopy() *RepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryStatus) DeepCopyInto(out *RepositoryStatus) {
+	*out = *in
+	if in.LastUpdateTimestamp != nil {
+		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Id != nil {
+		in, out := &in.Id, &out.Id
+		*out = new(int64)
+		**out = **in
+	}
+	if in.NodeId != nil {
+		in, out := &in.NodeId, &out.NodeId
+		*out = new(string)
+		**out = **in
+	}
+	if in.OwnerLogin != nil {
+		in, out := &in.OwnerLogin, &out.OwnerLogin
+		*out = new(string)
+		**out = **in
+	}
+	if in.OwnerNodeId != nil {
+		in, out := &in.OwnerNodeId, &out.OwnerNodeId
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.FullName != nil {
+		in, out := &in.FullName, &out.FullName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(string)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Homepage != nil {
+		in, out := &in.Homepage, &out.Homepage
+		*out = new(string)
+		**out = **in
+	}
+	if in.DefaultBranch != nil {
+		in, out := &in.DefaultBranch, &out.DefaultBranch
+		*out = new(string)
+		**out = **in
+	}
+	if in.TemplateOwner != nil {
+		in, out := &in.TemplateOwner, &out.TemplateOwner
+		*out = new(string)
+		**out = **in
+	}
+	if in.TemplateRepository != nil {
 		in, out := &in.TemplateRepository, &out.TemplateRepository
 		*out = new(string)
 		**out = **in
@@ -1021,275 +2423,570 @@ func (in *RepositorySpec) DeepCopyInto(out *RepositorySpec) {
 		*out = new(SecurityAndAnalysis)
 		**out = **in
 	}
+	if in.Collaborators != nil {
+		in, out := &in.Collaborators, &out.Collaborators
+		*out = make(map[string]RepositoryPermission, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PendingCollaborators != nil {
+		in, out := &in.PendingCollaborators, &out.PendingCollaborators
+		*out = make(map[string]RepositoryPermission, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ParentName != nil {
+		in, out := &in.ParentName, &out.ParentName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ParentId != nil {
+		in, out := &in.ParentId, &out.ParentId
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ParentNodeId != nil {
+		in, out := &in.ParentNodeId, &out.ParentNodeId
+		*out = new(string)
+		**out = **in
+	}
+	if in.TemplateRepositoryOwnerLogin != nil {
+		in, out := &in.TemplateRepositoryOwnerLogin, &out.TemplateRepositoryOwnerLogin
+		*out = new(string)
+		**out = **in
+	}
+	if in.TemplateRepositoryOwnerNodeId != nil {
+		in, out := &in.TemplateRepositoryOwnerNodeId, &out.TemplateRepositoryOwnerNodeId
+		*out = new(string)
+		**out = **in
+	}
+	if in.TemplateRepositoryName != nil {
+		in, out := &in.TemplateRepositoryName, &out.TemplateRepositoryName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TemplateRepositoryId != nil {
+		in, out := &in.TemplateRepositoryId, &out.TemplateRepositoryId
+		*out = new(int64)
+		**out = **in
+	}
+	if in.OrganizationLogin != nil {
+		in, out := &in.OrganizationLogin, &out.OrganizationLogin
+		*out = new(string)
+		**out = **in
+	}
+	if in.OrganizationId != nil {
+		in, out := &in.OrganizationId, &out.OrganizationId
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.PushedAt != nil {
+		in, out := &in.PushedAt, &out.PushedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryStatus.
+func (in *RepositoryStatus) DeepCopy() *RepositoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredStatusCheck) DeepCopyInto(out *RequiredStatusCheck) {
+	*out = *in
+	if in.AppId != nil {
+		in, out := &in.AppId, &out.AppId
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredStatusCheck.
+func (in *RequiredStatusCheck) DeepCopy() *RequiredStatusCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredStatusCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceReference) DeepCopyInto(out *ResourceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceReference.
+func (in *ResourceReference) DeepCopy() *ResourceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ruleset) DeepCopyInto(out *Ruleset) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ruleset.
+func (in *Ruleset) DeepCopy() *Ruleset {
+	if in == nil {
+		return nil
+	}
+	out := new(Ruleset)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Ruleset) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesetBypassActor) DeepCopyInto(out *RulesetBypassActor) {
+	*out = *in
+	if in.ActorId != nil {
+		in, out := &in.ActorId, &out.ActorId
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TeamRef != nil {
+		in, out := &in.TeamRef, &out.TeamRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
+	if in.BypassMode != nil {
+		in, out := &in.BypassMode, &out.BypassMode
+		*out = new(RulesetBypassMode)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositorySpec.
-func (in *RepositorySpec) DeepCopy() *RepositorySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesetBypassActor.
+func (in *RulesetBypassActor) DeepCopy() *RulesetBypassActor {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositorySpec)
+	out := new(RulesetBypassActor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RepositoryStatus) DeepCopyInto(out *RepositoryStatus) {
+func (in *RulesetList) DeepCopyInto(out *RulesetList) {
 	*out = *in
-	if in.LastUpdateTimestamp != nil {
-		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
-		*out = (*in).DeepCopy()
-	}
-	if in.Id != nil {
-		in, out := &in.Id, &out.Id
-		*out = new(int64)
-		**out = **in
-	}
-	if in.NodeId != nil {
-		in, out := &in.NodeId, &out.NodeId
-		*out = new(string)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Ruleset, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.OwnerLogin != nil {
-		in, out := &in.OwnerLogin, &out.OwnerLogin
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesetList.
+func (in *RulesetList) DeepCopy() *RulesetList {
+	if in == nil {
+		return nil
 	}
-	if in.OwnerNodeId != nil {
-		in, out := &in.OwnerNodeId, &out.OwnerNodeId
-		*out = new(int64)
-		**out = **in
+	out := new(RulesetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RulesetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesetPatternRule) DeepCopyInto(out *RulesetPatternRule) {
+	*out = *in
 	if in.Name != nil {
 		in, out := &in.Name, &out.Name
 		*out = new(string)
 		**out = **in
 	}
-	if in.FullName != nil {
-		in, out := &in.FullName, &out.FullName
-		*out = new(string)
-		**out = **in
-	}
-	if in.Owner != nil {
-		in, out := &in.Owner, &out.Owner
-		*out = new(string)
-		**out = **in
-	}
-	if in.Description != nil {
-		in, out := &in.Description, &out.Description
-		*out = new(string)
+	if in.Negate != nil {
+		in, out := &in.Negate, &out.Negate
+		*out = new(bool)
 		**out = **in
 	}
-	if in.Homepage != nil {
-		in, out := &in.Homepage, &out.Homepage
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesetPatternRule.
+func (in *RulesetPatternRule) DeepCopy() *RulesetPatternRule {
+	if in == nil {
+		return nil
 	}
-	if in.DefaultBranch != nil {
-		in, out := &in.DefaultBranch, &out.DefaultBranch
-		*out = new(string)
-		**out = **in
+	out := new(RulesetPatternRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesetPullRequestRule) DeepCopyInto(out *RulesetPullRequestRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesetPullRequestRule.
+func (in *RulesetPullRequestRule) DeepCopy() *RulesetPullRequestRule {
+	if in == nil {
+		return nil
 	}
-	if in.TemplateOwner != nil {
-		in, out := &in.TemplateOwner, &out.TemplateOwner
-		*out = new(string)
-		**out = **in
+	out := new(RulesetPullRequestRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesetRequiredStatusChecksRule) DeepCopyInto(out *RulesetRequiredStatusChecksRule) {
+	*out = *in
+	if in.RequiredStatusChecks != nil {
+		in, out := &in.RequiredStatusChecks, &out.RequiredStatusChecks
+		*out = make([]RulesetStatusCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.TemplateRepository != nil {
-		in, out := &in.TemplateRepository, &out.TemplateRepository
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesetRequiredStatusChecksRule.
+func (in *RulesetRequiredStatusChecksRule) DeepCopy() *RulesetRequiredStatusChecksRule {
+	if in == nil {
+		return nil
 	}
-	if in.AllowRebaseMerge != nil {
-		in, out := &in.AllowRebaseMerge, &out.AllowRebaseMerge
+	out := new(RulesetRequiredStatusChecksRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesetRules) DeepCopyInto(out *RulesetRules) {
+	*out = *in
+	if in.Creation != nil {
+		in, out := &in.Creation, &out.Creation
 		*out = new(bool)
 		**out = **in
 	}
-	if in.AllowUpdateBranch != nil {
-		in, out := &in.AllowUpdateBranch, &out.AllowUpdateBranch
+	if in.Update != nil {
+		in, out := &in.Update, &out.Update
 		*out = new(bool)
 		**out = **in
 	}
-	if in.AllowSquashMerge != nil {
-		in, out := &in.AllowSquashMerge, &out.AllowSquashMerge
+	if in.UpdateAllowsFetchAndMerge != nil {
+		in, out := &in.UpdateAllowsFetchAndMerge, &out.UpdateAllowsFetchAndMerge
 		*out = new(bool)
 		**out = **in
 	}
-	if in.AllowMergeCommit != nil {
-		in, out := &in.AllowMergeCommit, &out.AllowMergeCommit
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
 		*out = new(bool)
 		**out = **in
 	}
-	if in.AllowAutoMerge != nil {
-		in, out := &in.AllowAutoMerge, &out.AllowAutoMerge
+	if in.RequiredLinearHistory != nil {
+		in, out := &in.RequiredLinearHistory, &out.RequiredLinearHistory
 		*out = new(bool)
 		**out = **in
 	}
-	if in.AllowForking != nil {
-		in, out := &in.AllowForking, &out.AllowForking
+	if in.RequiredSignatures != nil {
+		in, out := &in.RequiredSignatures, &out.RequiredSignatures
 		*out = new(bool)
 		**out = **in
 	}
-	if in.WebCommitSignoffRequired != nil {
-		in, out := &in.WebCommitSignoffRequired, &out.WebCommitSignoffRequired
+	if in.NonFastForward != nil {
+		in, out := &in.NonFastForward, &out.NonFastForward
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DeleteBranchOnMerge != nil {
-		in, out := &in.DeleteBranchOnMerge, &out.DeleteBranchOnMerge
-		*out = new(bool)
-		**out = **in
+	if in.RequiredDeploymentEnvironments != nil {
+		in, out := &in.RequiredDeploymentEnvironments, &out.RequiredDeploymentEnvironments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.SquashMergeCommitTitle != nil {
-		in, out := &in.SquashMergeCommitTitle, &out.SquashMergeCommitTitle
-		*out = new(SquashMergeCommitTitle)
+	if in.PullRequest != nil {
+		in, out := &in.PullRequest, &out.PullRequest
+		*out = new(RulesetPullRequestRule)
 		**out = **in
 	}
-	if in.SquashMergeCommitMessage != nil {
-		in, out := &in.SquashMergeCommitMessage, &out.SquashMergeCommitMessage
-		*out = new(SquashMergeCommitMessage)
-		**out = **in
+	if in.RequiredStatusChecks != nil {
+		in, out := &in.RequiredStatusChecks, &out.RequiredStatusChecks
+		*out = new(RulesetRequiredStatusChecksRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommitMessagePattern != nil {
+		in, out := &in.CommitMessagePattern, &out.CommitMessagePattern
+		*out = new(RulesetPatternRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommitAuthorEmailPattern != nil {
+		in, out := &in.CommitAuthorEmailPattern, &out.CommitAuthorEmailPattern
+		*out = new(RulesetPatternRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommitterEmailPattern != nil {
+		in, out := &in.CommitterEmailPattern, &out.CommitterEmailPattern
+		*out = new(RulesetPatternRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BranchNamePattern != nil {
+		in, out := &in.BranchNamePattern, &out.BranchNamePattern
+		*out = new(RulesetPatternRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TagNamePattern != nil {
+		in, out := &in.TagNamePattern, &out.TagNamePattern
+		*out = new(RulesetPatternRule)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.MergeCommitTitle != nil {
-		in, out := &in.MergeCommitTitle, &out.MergeCommitTitle
-		*out = new(MergeCommitTitle)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesetRules.
+func (in *RulesetRules) DeepCopy() *RulesetRules {
+	if in == nil {
+		return nil
+	}
+	out := new(RulesetRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesetSpec) DeepCopyInto(out *RulesetSpec) {
+	*out = *in
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
+		*out = new(string)
 		**out = **in
 	}
-	if in.MergeCommitMessage != nil {
-		in, out := &in.MergeCommitMessage, &out.MergeCommitMessage
-		*out = new(MergeCommitMessage)
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(RulesetTarget)
 		**out = **in
 	}
-	if in.Topics != nil {
-		in, out := &in.Topics, &out.Topics
+	if in.BypassActors != nil {
+		in, out := &in.BypassActors, &out.BypassActors
+		*out = make([]RulesetBypassActor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IncludeRefs != nil {
+		in, out := &in.IncludeRefs, &out.IncludeRefs
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Archived != nil {
-		in, out := &in.Archived, &out.Archived
-		*out = new(bool)
-		**out = **in
+	if in.ExcludeRefs != nil {
+		in, out := &in.ExcludeRefs, &out.ExcludeRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.HasIssues != nil {
-		in, out := &in.HasIssues, &out.HasIssues
-		*out = new(bool)
-		**out = **in
+	if in.IncludeRepositories != nil {
+		in, out := &in.IncludeRepositories, &out.IncludeRepositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.HasWiki != nil {
-		in, out := &in.HasWiki, &out.HasWiki
-		*out = new(bool)
-		**out = **in
+	if in.ExcludeRepositories != nil {
+		in, out := &in.ExcludeRepositories, &out.ExcludeRepositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.HasProjects != nil {
-		in, out := &in.HasProjects, &out.HasProjects
-		*out = new(bool)
-		**out = **in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = new(RulesetRules)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.HasDownloads != nil {
-		in, out := &in.HasDownloads, &out.HasDownloads
-		*out = new(bool)
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
 		**out = **in
 	}
-	if in.HasDiscussions != nil {
-		in, out := &in.HasDiscussions, &out.HasDiscussions
-		*out = new(bool)
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
 		**out = **in
 	}
-	if in.Visibility != nil {
-		in, out := &in.Visibility, &out.Visibility
-		*out = new(string)
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
 		**out = **in
 	}
-	if in.SecurityAndAnalysis != nil {
-		in, out := &in.SecurityAndAnalysis, &out.SecurityAndAnalysis
-		*out = new(SecurityAndAnalysis)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesetSpec.
+func (in *RulesetSpec) DeepCopy() *RulesetSpec {
+	if in == nil {
+		return nil
 	}
-	if in.ParentName != nil {
-		in, out := &in.ParentName, &out.ParentName
-		*out = new(string)
-		**out = **in
+	out := new(RulesetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesetStatus) DeepCopyInto(out *RulesetStatus) {
+	*out = *in
+	if in.LastUpdateTimestamp != nil {
+		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
+		*out = (*in).DeepCopy()
 	}
-	if in.ParentId != nil {
-		in, out := &in.ParentId, &out.ParentId
+	if in.Id != nil {
+		in, out := &in.Id, &out.Id
 		*out = new(int64)
 		**out = **in
 	}
-	if in.ParentNodeId != nil {
-		in, out := &in.ParentNodeId, &out.ParentNodeId
+	if in.NodeId != nil {
+		in, out := &in.NodeId, &out.NodeId
 		*out = new(string)
 		**out = **in
 	}
-	if in.TemplateRepositoryOwnerLogin != nil {
-		in, out := &in.TemplateRepositoryOwnerLogin, &out.TemplateRepositoryOwnerLogin
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
 		*out = new(string)
 		**out = **in
 	}
-	if in.TemplateRepositoryOwnerNodeId != nil {
-		in, out := &in.TemplateRepositoryOwnerNodeId, &out.TemplateRepositoryOwnerNodeId
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
 		*out = new(string)
 		**out = **in
 	}
-	if in.TemplateRepositoryName != nil {
-		in, out := &in.TemplateRepositoryName, &out.TemplateRepositoryName
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
 		*out = new(string)
 		**out = **in
 	}
-	if in.TemplateRepositoryId != nil {
-		in, out := &in.TemplateRepositoryId, &out.TemplateRepositoryId
-		*out = new(int64)
-		**out = **in
-	}
-	if in.OrganizationLogin != nil {
-		in, out := &in.OrganizationLogin, &out.OrganizationLogin
+	if in.SourceType != nil {
+		in, out := &in.SourceType, &out.SourceType
 		*out = new(string)
 		**out = **in
 	}
-	if in.OrganizationId != nil {
-		in, out := &in.OrganizationId, &out.OrganizationId
-		*out = new(int64)
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(RulesetTarget)
 		**out = **in
 	}
-	if in.CreatedAt != nil {
-		in, out := &in.CreatedAt, &out.CreatedAt
-		*out = (*in).DeepCopy()
+	if in.Enforcement != nil {
+		in, out := &in.Enforcement, &out.Enforcement
+		*out = new(RulesetEnforcement)
+		**out = **in
 	}
-	if in.PushedAt != nil {
-		in, out := &in.PushedAt, &out.PushedAt
-		*out = (*in).DeepCopy()
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.UpdatedAt != nil {
-		in, out := &in.UpdatedAt, &out.UpdatedAt
-		*out = (*in).DeepCopy()
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryStatus.
-func (in *RepositoryStatus) DeepCopy() *RepositoryStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesetStatus.
+func (in *RulesetStatus) DeepCopy() *RulesetStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RepositoryStatus)
+	out := new(RulesetStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RequiredStatusCheck) DeepCopyInto(out *RequiredStatusCheck) {
+func (in *RulesetStatusCheck) DeepCopyInto(out *RulesetStatusCheck) {
 	*out = *in
-	if in.AppId != nil {
-		in, out := &in.AppId, &out.AppId
-		*out = new(string)
+	if in.IntegrationId != nil {
+		in, out := &in.IntegrationId, &out.IntegrationId
+		*out = new(int64)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredStatusCheck.
-func (in *RequiredStatusCheck) DeepCopy() *RequiredStatusCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesetStatusCheck.
+func (in *RulesetStatusCheck) DeepCopy() *RulesetStatusCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(RequiredStatusCheck)
+	out := new(RulesetStatusCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1409,6 +3106,11 @@ func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ParentTeamRef != nil {
+		in, out := &in.ParentTeamRef, &out.ParentTeamRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.Repositories != nil {
 		in, out := &in.Repositories, &out.Repositories
 		*out = make(map[string]RepositoryPermission, len(*in))
@@ -1416,6 +3118,43 @@ func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.RepositoryRefs != nil {
+		in, out := &in.RepositoryRefs, &out.RepositoryRefs
+		*out = make(map[string]RepositoryPermission, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintainers != nil {
+		in, out := &in.Maintainers, &out.Maintainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PruneMembers != nil {
+		in, out := &in.PruneMembers, &out.PruneMembers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		**out = **in
+	}
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
+	}
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamSpec.
@@ -1497,6 +3236,40 @@ func (in *TeamStatus) DeepCopyInto(out *TeamStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintainers != nil {
+		in, out := &in.Maintainers, &out.Maintainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingInvitations != nil {
+		in, out := &in.PendingInvitations, &out.PendingInvitations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamStatus.
@@ -1508,3 +3281,257 @@ func (in *TeamStatus) DeepCopy() *TeamStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Webhook) DeepCopyInto(out *Webhook) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Webhook.
+func (in *Webhook) DeepCopy() *Webhook {
+	if in == nil {
+		return nil
+	}
+	out := new(Webhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Webhook) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookDelivery) DeepCopyInto(out *WebhookDelivery) {
+	*out = *in
+	if in.Id != nil {
+		in, out := &in.Id, &out.Id
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Event != nil {
+		in, out := &in.Event, &out.Event
+		*out = new(string)
+		**out = **in
+	}
+	if in.Action != nil {
+		in, out := &in.Action, &out.Action
+		*out = new(string)
+		**out = **in
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(string)
+		**out = **in
+	}
+	if in.StatusCode != nil {
+		in, out := &in.StatusCode, &out.StatusCode
+		*out = new(int)
+		**out = **in
+	}
+	if in.DeliveredAt != nil {
+		in, out := &in.DeliveredAt, &out.DeliveredAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookDelivery.
+func (in *WebhookDelivery) DeepCopy() *WebhookDelivery {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookDelivery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookList) DeepCopyInto(out *WebhookList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Webhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookList.
+func (in *WebhookList) DeepCopy() *WebhookList {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WebhookList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookSpec) DeepCopyInto(out *WebhookSpec) {
+	*out = *in
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ContentType != nil {
+		in, out := &in.ContentType, &out.ContentType
+		*out = new(WebhookContentType)
+		**out = **in
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InsecureSSL != nil {
+		in, out := &in.InsecureSSL, &out.InsecureSSL
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		**out = **in
+	}
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ManagementPolicy)
+		**out = **in
+	}
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(ProviderReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookSpec.
+func (in *WebhookSpec) DeepCopy() *WebhookSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookStatus) DeepCopyInto(out *WebhookStatus) {
+	*out = *in
+	if in.LastUpdateTimestamp != nil {
+		in, out := &in.LastUpdateTimestamp, &out.LastUpdateTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Id != nil {
+		in, out := &in.Id, &out.Id
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(string)
+		**out = **in
+	}
+	if in.RepositoryName != nil {
+		in, out := &in.RepositoryName, &out.RepositoryName
+		*out = new(string)
+		**out = **in
+	}
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+	if in.ContentType != nil {
+		in, out := &in.ContentType, &out.ContentType
+		*out = new(WebhookContentType)
+		**out = **in
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InsecureSSL != nil {
+		in, out := &in.InsecureSSL, &out.InsecureSSL
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CreatedAt != nil {
+		in, out := &in.CreatedAt, &out.CreatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdatedAt != nil {
+		in, out := &in.UpdatedAt, &out.UpdatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDelivery != nil {
+		in, out := &in.LastDelivery, &out.LastDelivery
+		*out = new(WebhookDelivery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]FieldDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookStatus.
+func (in *WebhookStatus) DeepCopy() *WebhookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"time"
@@ -28,17 +30,23 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
 	"github.com/eczy/github-operator/internal/controller"
+	"github.com/eczy/github-operator/internal/importer"
 	"github.com/eczy/github-operator/internal/utils"
 	//+kubebuilder:scaffold:imports
 )
@@ -55,17 +63,34 @@ func init() {
 }
 
 func main() {
+	// `import` generates manifests for an existing organization instead of running the manager
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var deleteOnResourceDeletion bool
+	var dryRun bool
+	var githubWebhookAddr string
+	var githubBaseURL string
+	var githubUploadURL string
+	var githubGraphQLURL string
+	var githubCABundle string
 	var requeueInterval int
 	var teamRequeueInterval int
 	var repositoryRequeueInterval int
 	var organizationRequeueInterval int
 	var branchProtectionRequeueInterval int
+	var rulesetRequeueInterval int
+	var environmentRequeueInterval int
+	var actionsSecretRequeueInterval int
+	var actionsVariableRequeueInterval int
+	var webhookRequeueInterval int
+	var deployKeyRequeueInterval int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -80,7 +105,26 @@ func main() {
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.BoolVar(&deleteOnResourceDeletion, "delete-on-resource-deletion", false,
-		"Delete corresponding external resource when a resource is delete.")
+		"Delete corresponding external resource when a resource is deleted. "+
+			"Only a default; resources can override it with spec.deletionPolicy.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Plan GitHub mutations without applying them. Planned mutations are written to status.plan and events. "+
+			"Individual resources can be put in dry-run mode with the github.github-operator.eczy.io/dry-run annotation.")
+	flag.StringVar(&githubWebhookAddr, "github-webhook-bind-address", "0",
+		"The address the GitHub webhook receiver binds to, e.g. :8082. Deliveries must be signed with "+
+			"the GITHUB_WEBHOOK_SECRET env var. Set to 0 to disable the receiver.")
+	flag.StringVar(&githubBaseURL, "github-base-url", "",
+		"REST API URL of a GitHub Enterprise Server instance, e.g. https://github.example.com/api/v3/. "+
+			"Overrides the GITHUB_BASE_URL env var. Defaults to github.com.")
+	flag.StringVar(&githubUploadURL, "github-upload-url", "",
+		"Upload URL of a GitHub Enterprise Server instance. Overrides the GITHUB_UPLOAD_URL env var. "+
+			"Defaults to the upload URL of the instance at --github-base-url.")
+	flag.StringVar(&githubGraphQLURL, "github-graphql-url", "",
+		"GraphQL API URL of a GitHub Enterprise Server instance. Overrides the GITHUB_GRAPHQL_URL env var. "+
+			"Defaults to the GraphQL URL of the instance at --github-base-url.")
+	flag.StringVar(&githubCABundle, "github-ca-bundle", "",
+		"Path to a PEM file of CA certificates to trust when connecting to GitHub, in addition to the system roots. "+
+			"Overrides the GITHUB_CA_BUNDLE_FILE env var.")
 	flag.IntVar(&requeueInterval, "requeue-interval", 0,
 		"Requeue interval for all custom resources managed by this Manager in seconds. "+
 			"Resource-specific flags override this value.")
@@ -92,6 +136,18 @@ func main() {
 		"Requeue interval for Organization resources in seconds.")
 	flag.IntVar(&branchProtectionRequeueInterval, "branch-protection-requeue-interval", 0,
 		"Requeue interval for BranchProtection resources in seconds.")
+	flag.IntVar(&rulesetRequeueInterval, "ruleset-requeue-interval", 0,
+		"Requeue interval for Ruleset resources in seconds.")
+	flag.IntVar(&environmentRequeueInterval, "environment-requeue-interval", 0,
+		"Requeue interval for Environment resources in seconds.")
+	flag.IntVar(&actionsSecretRequeueInterval, "actions-secret-requeue-interval", 0,
+		"Requeue interval for ActionsSecret resources in seconds.")
+	flag.IntVar(&actionsVariableRequeueInterval, "actions-variable-requeue-interval", 0,
+		"Requeue interval for ActionsVariable resources in seconds.")
+	flag.IntVar(&webhookRequeueInterval, "webhook-requeue-interval", 0,
+		"Requeue interval for Webhook resources in seconds.")
+	flag.IntVar(&deployKeyRequeueInterval, "deploy-key-requeue-interval", 0,
+		"Requeue interval for DeployKey resources in seconds.")
 	flag.Parse()
 
 	// set resource-specific requeue intervals to the general requeue interval if they are not explicitly set
@@ -101,6 +157,12 @@ func main() {
 			&repositoryRequeueInterval,
 			&organizationRequeueInterval,
 			&branchProtectionRequeueInterval,
+			&rulesetRequeueInterval,
+			&environmentRequeueInterval,
+			&actionsSecretRequeueInterval,
+			&actionsVariableRequeueInterval,
+			&webhookRequeueInterval,
+			&deployKeyRequeueInterval,
 		}
 		for _, v := range intervals {
 			if *v == 0 {
@@ -138,6 +200,16 @@ func main() {
 			SecureServing: secureMetrics,
 			TLSOpts:       tlsOpts,
 		},
+		// Secrets are read directly from the API server, and only the Secrets labeled for the
+		// operator are cached and watched instead of every Secret in the cluster
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.Secret{}: {Label: labels.SelectorFromSet(labels.Set{controller.WatchedSecretLabel: "true"})},
+			},
+		},
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}}},
+		},
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -161,18 +233,51 @@ func main() {
 
 	ctx := context.Background()
 
-	ghClient, err := utils.GitHubClientFromEnv(ctx, http.DefaultTransport)
+	ghClient, providers, err := newGitHubClients(ctx, mgr.GetClient(),
+		githubBaseURL, githubUploadURL, githubGraphQLURL, githubCABundle)
 	if err != nil {
 		setupLog.Error(err, "unable to create GitHub client")
 		os.Exit(1)
 	}
 
+	// GitHub webhook deliveries trigger reconciles of the resources they affect
+	var receiver *controller.GitHubEventReceiver
+	if githubWebhookAddr != "0" {
+		env, err := utils.LookupEnvVarsError("GITHUB_WEBHOOK_SECRET")
+		if err == nil && env["GITHUB_WEBHOOK_SECRET"] == "" {
+			// every signature would be computed with an empty key, so anyone could forge deliveries
+			err = fmt.Errorf("GITHUB_WEBHOOK_SECRET is empty")
+		}
+		if err != nil {
+			setupLog.Error(err, "unable to create GitHub webhook receiver")
+			os.Exit(1)
+		}
+		receiver = controller.NewGitHubEventReceiver(mgr.GetClient(), []byte(env["GITHUB_WEBHOOK_SECRET"]))
+		// only the leader runs reconcilers to consume the events
+		if err := mgr.Add(&manager.Server{
+			Name: "github-webhook-receiver",
+			Server: &http.Server{
+				Addr:              githubWebhookAddr,
+				Handler:           receiver,
+				ReadHeaderTimeout: 10 * time.Second,
+			},
+			OnlyServeWhenLeader: true,
+		}); err != nil {
+			setupLog.Error(err, "unable to add GitHub webhook receiver")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controller.TeamReconciler{
 		Client:                   mgr.GetClient(),
 		Scheme:                   mgr.GetScheme(),
 		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("team-controller"),
 		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
 		RequeueInterval:          time.Duration(teamRequeueInterval) * time.Second,
+		GitHubEvents:             receiver.TeamEvents(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Team")
 		os.Exit(1)
@@ -181,8 +286,12 @@ func main() {
 		Client:                   mgr.GetClient(),
 		Scheme:                   mgr.GetScheme(),
 		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("repository-controller"),
 		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
 		RequeueInterval:          time.Duration(repositoryRequeueInterval) * time.Second,
+		GitHubEvents:             receiver.RepositoryEvents(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Repository")
 		os.Exit(1)
@@ -191,8 +300,12 @@ func main() {
 		Client:                   mgr.GetClient(),
 		Scheme:                   mgr.GetScheme(),
 		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("organization-controller"),
 		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
 		RequeueInterval:          time.Duration(organizationRequeueInterval) * time.Second,
+		GitHubEvents:             receiver.OrganizationEvents(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Organization")
 		os.Exit(1)
@@ -201,12 +314,103 @@ func main() {
 		Client:                   mgr.GetClient(),
 		Scheme:                   mgr.GetScheme(),
 		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("branchprotection-controller"),
 		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
 		RequeueInterval:          time.Duration(branchProtectionRequeueInterval) * time.Second,
+		GitHubEvents:             receiver.BranchProtectionEvents(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "BranchProtection")
 		os.Exit(1)
 	}
+	if err = (&controller.RulesetReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("ruleset-controller"),
+		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
+		RequeueInterval:          time.Duration(rulesetRequeueInterval) * time.Second,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Ruleset")
+		os.Exit(1)
+	}
+	if err = (&controller.EnvironmentReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("environment-controller"),
+		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
+		RequeueInterval:          time.Duration(environmentRequeueInterval) * time.Second,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Environment")
+		os.Exit(1)
+	}
+	if err = (&controller.ActionsSecretReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("actionssecret-controller"),
+		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
+		RequeueInterval:          time.Duration(actionsSecretRequeueInterval) * time.Second,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ActionsSecret")
+		os.Exit(1)
+	}
+	if err = (&controller.ActionsVariableReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("actionsvariable-controller"),
+		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
+		RequeueInterval:          time.Duration(actionsVariableRequeueInterval) * time.Second,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ActionsVariable")
+		os.Exit(1)
+	}
+	if err = (&controller.WebhookReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("webhook-controller"),
+		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
+		RequeueInterval:          time.Duration(webhookRequeueInterval) * time.Second,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Webhook")
+		os.Exit(1)
+	}
+	if err = (&controller.DeployKeyReconciler{
+		Client:                   mgr.GetClient(),
+		Scheme:                   mgr.GetScheme(),
+		GitHubClient:             ghClient,
+		Recorder:                 mgr.GetEventRecorderFor("deploykey-controller"),
+		DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		DryRun:                   dryRun,
+		Providers:                providers,
+		RequeueInterval:          time.Duration(deployKeyRequeueInterval) * time.Second,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DeployKey")
+		os.Exit(1)
+	}
+	if err = (&controller.GitHubProviderReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("githubprovider-controller"),
+		Providers: providers,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GitHubProvider")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -224,3 +428,125 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runImport writes Organization, Team, Repository and BranchProtection manifests for an existing
+// GitHub organization, using the GitHub credentials and server from the environment.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	org := fs.String("org", "", "The login of the GitHub organization to import.")
+	namespace := fs.String("namespace", "default", "The namespace of the generated resources.")
+	output := fs.String("output", "-", "The file the manifests are written to, or - for stdout.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *org == "" {
+		fmt.Fprintln(os.Stderr, "--org is required")
+		return 2
+	}
+
+	ctx := context.Background()
+	server, err := utils.GitHubServerFromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unable to configure GitHub server:", err)
+		return 1
+	}
+	creds, err := utils.GitHubCredentialsFromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unable to read GitHub credentials:", err)
+		return 1
+	}
+	var ghClient importer.GitHubRequester
+	if creds.DiscoversInstallations() {
+		installations, err := utils.NewGitHubAppClients(ctx, http.DefaultTransport, server, creds)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "unable to create GitHub client:", err)
+			return 1
+		}
+		ghClient, err = installations.GitHubClient(ctx, *org)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "unable to create GitHub client:", err)
+			return 1
+		}
+	} else {
+		ghClient, err = utils.GitHubClientFromCredentials(ctx, http.DefaultTransport, server, creds)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "unable to create GitHub client:", err)
+			return 1
+		}
+	}
+
+	imp := &importer.Importer{
+		GitHubClient: ghClient,
+		Namespace:    *namespace,
+	}
+	objects, err := imp.Import(ctx, *org)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unable to import organization:", err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "unable to create output file:", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := importer.WriteYAML(w, objects); err != nil {
+		fmt.Fprintln(os.Stderr, "unable to write manifests:", err)
+		return 1
+	}
+	return 0
+}
+
+// newGitHubClients creates the operator's own GitHub client from the credentials in env, and the clients of
+// GitHubProviders. The flags locating the GitHub instance take precedence over env vars. Without credentials
+// in env, or with GitHub App credentials that discover their installations, the returned client is nil.
+func newGitHubClients(ctx context.Context, c client.Client, baseURL, uploadURL, graphQLURL, caBundle string) (
+	controller.GitHubRequester, *controller.GitHubProviderClients, error) {
+	server, err := utils.GitHubServerFromEnv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("configuring GitHub server: %w", err)
+	}
+	if baseURL != "" {
+		server.BaseURL = baseURL
+	}
+	if uploadURL != "" {
+		server.UploadURL = uploadURL
+	}
+	if graphQLURL != "" {
+		server.GraphQLURL = graphQLURL
+	}
+	if caBundle != "" {
+		server.CABundle, err = os.ReadFile(caBundle)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading GitHub CA bundle: %w", err)
+		}
+	}
+
+	// the operator's own credentials are optional, resources can use a GitHubProvider instead
+	providers := controller.NewGitHubProviderClients(c, http.DefaultTransport, server)
+	creds, err := utils.GitHubCredentialsFromEnv()
+	if err != nil {
+		setupLog.Info("no GitHub credentials in env, only resources with a providerRef will be reconciled",
+			"reason", err.Error())
+		return nil, providers, nil
+	}
+	if creds.DiscoversInstallations() {
+		// without GITHUB_INSTALLATION_ID, each resource uses the App's installation on its owner
+		installations, err := utils.NewGitHubAppClients(ctx, http.DefaultTransport, server, creds)
+		if err != nil {
+			return nil, nil, err
+		}
+		providers.Installations = installations
+		return nil, providers, nil
+	}
+	ghClient, err := utils.GitHubClientFromCredentials(ctx, http.DefaultTransport, server, creds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ghClient, providers, nil
+}
@@ -0,0 +1,495 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+	"github.com/google/go-github/v60/github"
+)
+
+var (
+	actionsSecretFinalizerName = "github.github-operator.eczy.io/actions-secret-finalizer"
+
+	// field index of ActionsSecrets by the name of the Secret they read their value from
+	actionsSecretSecretKeyRefField = ".spec.secretKeyRef.name"
+)
+
+type ActionsSecretRequester interface {
+	GetRepositoryActionsPublicKey(ctx context.Context, owner, repo string) (*github.PublicKey, error)
+	GetOrganizationActionsPublicKey(ctx context.Context, org string) (*github.PublicKey, error)
+	GetEnvironmentActionsPublicKey(ctx context.Context, repoId int64, env string) (*github.PublicKey, error)
+	GetRepositoryActionsSecret(ctx context.Context, owner, repo, name string) (*github.Secret, error)
+	GetOrganizationActionsSecret(ctx context.Context, org, name string) (*github.Secret, error)
+	GetEnvironmentActionsSecret(ctx context.Context, repoId int64, env, name string) (*github.Secret, error)
+	CreateOrUpdateRepositoryActionsSecret(ctx context.Context, owner, repo string, secret *github.EncryptedSecret) error
+	CreateOrUpdateOrganizationActionsSecret(ctx context.Context, org string, secret *github.EncryptedSecret) error
+	CreateOrUpdateEnvironmentActionsSecret(ctx context.Context, repoId int64, env string, secret *github.EncryptedSecret) error
+	DeleteRepositoryActionsSecret(ctx context.Context, owner, repo, name string) error
+	DeleteOrganizationActionsSecret(ctx context.Context, org, name string) error
+	DeleteEnvironmentActionsSecret(ctx context.Context, repoId int64, env, name string) error
+	GetOrganizationActionsSecretRepositories(ctx context.Context, org, name string) ([]*github.Repository, error)
+
+	// needed to resolve environment and selected repository ids
+	GetRepositoryByName(ctx context.Context, owner string, name string) (*github.Repository, error)
+}
+
+// ActionsSecretReconciler reconciles an ActionsSecret object
+type ActionsSecretReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	GitHubClient             ActionsSecretRequester
+	Recorder                 record.EventRecorder
+	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
+	RequeueInterval          time.Duration
+}
+
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=actionssecrets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=actionssecrets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=actionssecrets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
+func (r *ActionsSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := log.FromContext(ctx)
+
+	// fetch resource
+	secret := &githubv1alpha1.ActionsSecret{}
+	if err := r.Get(ctx, req.NamespacedName, secret); err != nil {
+		log.Error(err, "error fetching ActionsSecret resource")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub
+	managementPolicy := resolveManagementPolicy(secret.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(secret, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, secret, &secret.Status.Conditions, &secret.Status.ObservedGeneration, &secret.Status.Drift, &secret.Status.Plan, err)
+	}()
+
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, secret.Spec.ProviderRef, secret.Spec.Owner, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
+	if secret.Spec.EnvironmentName != nil && secret.Spec.RepositoryName == nil {
+		return ctrl.Result{}, fmt.Errorf("actions secret '%s' sets environmentName without repositoryName", secret.Spec.Name)
+	}
+
+	// try to fetch external resource
+	observed, repoId, err := r.observeActionsSecret(ctx, secret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// handle finalizer
+	deletionPolicy := resolveDeletionPolicy(secret.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion)
+	if secret.DeletionTimestamp.IsZero() {
+		// not being deleted
+		if deletionPolicy != githubv1alpha1.DeletionPolicyOrphan && !controllerutil.ContainsFinalizer(secret, actionsSecretFinalizerName) {
+			controllerutil.AddFinalizer(secret, actionsSecretFinalizerName)
+			if err := r.Update(ctx, secret); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if deletionPolicy == githubv1alpha1.DeletionPolicyOrphan && controllerutil.ContainsFinalizer(secret, actionsSecretFinalizerName) {
+			// the GitHub resource is left in place, so there is nothing to clean up
+			controllerutil.RemoveFinalizer(secret, actionsSecretFinalizerName)
+			if err := r.Update(ctx, secret); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else if controllerutil.ContainsFinalizer(secret, actionsSecretFinalizerName) {
+		// being deleted
+		if deletionPolicy == githubv1alpha1.DeletionPolicyDelete && secret.Status.Name != nil && observed != nil {
+			// if we have never resolved this resource before, don't
+			// touch external state
+			if !shouldMutate(ctx, "delete actions secret %s", secret.Spec.Name) {
+				// keep the finalizer so the deletion is applied once the resource leaves dry-run mode
+				return ctrl.Result{}, nil
+			}
+			if err := r.deleteActionsSecret(ctx, secret, repoId); err != nil {
+				log.Error(err, "error deleting actions secret")
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(secret, corev1.EventTypeNormal, eventReasonDeleted, "Deleted GitHub actions secret")
+		}
+
+		controllerutil.RemoveFinalizer(secret, actionsSecretFinalizerName)
+		if err := r.Update(ctx, secret); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// nothing to write for a resource that is being deleted
+	if !secret.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// only the Full management policy creates missing GitHub resources
+	if observed == nil && managementPolicy != githubv1alpha1.ManagementPolicyFull {
+		skipMutation(ctx, "create actions secret %s", secret.Spec.Name)
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+	}
+
+	// create or update external resource. Secrets are write-only, so creation
+	// and update share the same path.
+	err = r.updateActionsSecret(ctx, secret, observed, repoId)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ActionsSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &githubv1alpha1.ActionsSecret{}, actionsSecretSecretKeyRefField, func(obj client.Object) []string {
+		return []string{obj.(*githubv1alpha1.ActionsSecret).Spec.SecretKeyRef.Name}
+	})
+	if err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&githubv1alpha1.ActionsSecret{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.actionsSecretsForSecret)).
+		Complete(r)
+}
+
+// maps a Kubernetes Secret to the ActionsSecrets in the same namespace that reference it,
+// so that rotating a Secret labeled with WatchedSecretLabel re-uploads the value without
+// waiting for the requeue interval.
+func (r *ActionsSecretReconciler) actionsSecretsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	secrets := &githubv1alpha1.ActionsSecretList{}
+	if err := r.List(ctx, secrets, client.InNamespace(obj.GetNamespace()), client.MatchingFields{actionsSecretSecretKeyRefField: obj.GetName()}); err != nil {
+		log.Error(err, "error listing ActionsSecret resources")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, secret := range secrets.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name},
+		})
+	}
+	return requests
+}
+
+func (r *ActionsSecretReconciler) updateActionsSecret(ctx context.Context, secret *githubv1alpha1.ActionsSecret, ghSecret *github.Secret, repoId int64) error {
+	log := log.FromContext(ctx)
+
+	value, valueHash, err := secretKeyValueAndHash(ctx, r.Client, secret.Namespace, secret.Spec.SecretKeyRef)
+	if err != nil {
+		log.Error(err, "error reading secret value", "secret", secret.Spec.SecretKeyRef.Name, "key", secret.Spec.SecretKeyRef.Key)
+		return err
+	}
+
+	visibility, err := actionsSecretVisibility(secret)
+	if err != nil {
+		return err
+	}
+
+	needsUpdate := false
+
+	if ghSecret == nil {
+		log.Info("creating actions secret", "name", secret.Spec.Name)
+		needsUpdate = true
+	}
+	// Value
+	if secret.Status.ValueHash == nil || *secret.Status.ValueHash != valueHash {
+		log.Info("actions secret value update", "name", secret.Spec.Name)
+		recordDrift(ctx, "valueHash", valueHash, secret.Status.ValueHash)
+		needsUpdate = true
+	}
+
+	// Visibility and SelectedRepositories
+	selectedRepositories := []string{}
+	// status reflects the observed secret unless it is written below
+	var observedVisibility *githubv1alpha1.ActionsVisibility
+	observedRepositories := []string{}
+	if visibility != nil {
+		if ghSecret != nil {
+			observedVisibility = (*githubv1alpha1.ActionsVisibility)(&ghSecret.Visibility)
+			if ghSecret.Visibility != string(*visibility) {
+				log.Info("actions secret Visibility update", "from", ghSecret.Visibility, "to", *visibility)
+				recordDrift(ctx, "visibility", *visibility, ghSecret.Visibility)
+				needsUpdate = true
+			}
+		}
+		observedRepositories, err = r.getActionsSecretRepositories(ctx, secret, ghSecret)
+		if err != nil {
+			return err
+		}
+		if *visibility == githubv1alpha1.ActionsVisibilitySelected {
+			if !cmpSlices(secret.Spec.SelectedRepositories, observedRepositories) {
+				log.Info("actions secret SelectedRepositories update", "from", observedRepositories, "to", secret.Spec.SelectedRepositories)
+				recordDrift(ctx, "selectedRepositories", secret.Spec.SelectedRepositories, observedRepositories)
+				needsUpdate = true
+			}
+			selectedRepositories = append(selectedRepositories, secret.Spec.SelectedRepositories...)
+			sort.Strings(selectedRepositories)
+		}
+	}
+
+	// perform update if necessary
+	valueHashStatus := secret.Status.ValueHash
+	if needsUpdate && shouldMutate(ctx, "update actions secret %s", secret.Spec.Name) {
+		log.Info("updating actions secret", "name", secret.Spec.Name)
+		if err := r.writeActionsSecret(ctx, secret, value, visibility, repoId); err != nil {
+			return err
+		}
+		r.Recorder.Event(secret, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub actions secret")
+
+		updated, err := r.getActionsSecret(ctx, secret, repoId)
+		if err != nil {
+			log.Error(err, "error fetching GitHub actions secret")
+			return err
+		}
+		ghSecret = updated
+		observedVisibility = visibility
+		observedRepositories = selectedRepositories
+		valueHashStatus = github.String(valueHash)
+	}
+
+	// populate status from the written secret, or the observed secret if it wasn't written
+	if (needsUpdate || secret.Status.LastUpdateTimestamp == nil) && ghSecret != nil {
+		now := v1.Now()
+		secret.Status = githubv1alpha1.ActionsSecretStatus{
+			LastUpdateTimestamp:  &now,
+			Name:                 github.String(secret.Spec.Name),
+			Owner:                github.String(secret.Spec.Owner),
+			RepositoryName:       secret.Spec.RepositoryName,
+			EnvironmentName:      secret.Spec.EnvironmentName,
+			Visibility:           observedVisibility,
+			SelectedRepositories: observedRepositories,
+			CreatedAt:            timestampOrNil(ghSecret.CreatedAt),
+			UpdatedAt:            timestampOrNil(ghSecret.UpdatedAt),
+			ValueHash:            valueHashStatus,
+			Conditions:           secret.Status.Conditions,
+			ObservedGeneration:   secret.Status.ObservedGeneration,
+			Drift:                secret.Status.Drift,
+			Plan:                 secret.Status.Plan,
+		}
+
+		// update status
+		if err := r.Status().Update(ctx, secret); err != nil {
+			log.Error(err, "error updating ActionsSecret status", "name", secret.Spec.Name)
+		}
+	}
+	return nil
+}
+
+// actionsSecretVisibility returns the visibility of an organization secret, which defaults to
+// private, or nil for repository and environment secrets
+func actionsSecretVisibility(secret *githubv1alpha1.ActionsSecret) (*githubv1alpha1.ActionsVisibility, error) {
+	if secret.Spec.RepositoryName != nil {
+		if secret.Spec.Visibility != nil || len(secret.Spec.SelectedRepositories) > 0 {
+			return nil, fmt.Errorf("actions secret '%s': visibility and selectedRepositories only apply to organization secrets", secret.Spec.Name)
+		}
+		return nil, nil
+	}
+	visibility := githubv1alpha1.ActionsVisibilityPrivate
+	if secret.Spec.Visibility != nil {
+		visibility = *secret.Spec.Visibility
+	}
+	if visibility != githubv1alpha1.ActionsVisibilitySelected && len(secret.Spec.SelectedRepositories) > 0 {
+		return nil, fmt.Errorf("actions secret '%s': selectedRepositories requires selected visibility", secret.Spec.Name)
+	}
+	return &visibility, nil
+}
+
+// returns the sorted names of the repositories an organization secret is shared with, if it
+// has selected visibility
+func (r *ActionsSecretReconciler) getActionsSecretRepositories(ctx context.Context, secret *githubv1alpha1.ActionsSecret, ghSecret *github.Secret) ([]string, error) {
+	log := log.FromContext(ctx)
+
+	names := []string{}
+	if ghSecret == nil || ghSecret.Visibility != string(githubv1alpha1.ActionsVisibilitySelected) {
+		return names, nil
+	}
+	repos, err := r.GitHubClient.GetOrganizationActionsSecretRepositories(ctx, secret.Spec.Owner, secret.Spec.Name)
+	if err != nil {
+		log.Error(err, "error fetching actions secret repositories")
+		return nil, err
+	}
+	for _, repo := range repos {
+		names = append(names, repo.GetName())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// encrypts a value with the public key of the secret's scope and writes it to GitHub. visibility
+// is nil for repository and environment secrets.
+func (r *ActionsSecretReconciler) writeActionsSecret(ctx context.Context, secret *githubv1alpha1.ActionsSecret, value []byte, visibility *githubv1alpha1.ActionsVisibility, repoId int64) error {
+	log := log.FromContext(ctx)
+
+	var publicKey *github.PublicKey
+	var err error
+	switch {
+	case visibility != nil:
+		publicKey, err = r.GitHubClient.GetOrganizationActionsPublicKey(ctx, secret.Spec.Owner)
+	case secret.Spec.EnvironmentName != nil:
+		publicKey, err = r.GitHubClient.GetEnvironmentActionsPublicKey(ctx, repoId, *secret.Spec.EnvironmentName)
+	default:
+		publicKey, err = r.GitHubClient.GetRepositoryActionsPublicKey(ctx, secret.Spec.Owner, *secret.Spec.RepositoryName)
+	}
+	if err != nil {
+		log.Error(err, "error fetching actions public key")
+		return err
+	}
+
+	encrypted, err := gh.EncryptActionsSecret(publicKey, secret.Spec.Name, value)
+	if err != nil {
+		log.Error(err, "error encrypting actions secret", "name", secret.Spec.Name)
+		return err
+	}
+
+	switch {
+	case visibility != nil:
+		encrypted.Visibility = string(*visibility)
+		if *visibility == githubv1alpha1.ActionsVisibilitySelected {
+			ids, err := selectedRepositoryIds(ctx, r.GitHubClient, secret.Spec.Owner, secret.Spec.SelectedRepositories)
+			if err != nil {
+				log.Error(err, "error resolving selected repositories")
+				return err
+			}
+			encrypted.SelectedRepositoryIDs = ids
+		}
+		err = r.GitHubClient.CreateOrUpdateOrganizationActionsSecret(ctx, secret.Spec.Owner, encrypted)
+	case secret.Spec.EnvironmentName != nil:
+		err = r.GitHubClient.CreateOrUpdateEnvironmentActionsSecret(ctx, repoId, *secret.Spec.EnvironmentName, encrypted)
+	default:
+		err = r.GitHubClient.CreateOrUpdateRepositoryActionsSecret(ctx, secret.Spec.Owner, *secret.Spec.RepositoryName, encrypted)
+	}
+	if err != nil {
+		log.Error(err, "error updating actions secret", "name", secret.Spec.Name)
+		return err
+	}
+	return nil
+}
+
+// GitHub omits the timestamps of secrets in some responses
+func timestampOrNil(ts github.Timestamp) *v1.Time {
+	if ts.IsZero() {
+		return nil
+	}
+	return &v1.Time{Time: ts.Time}
+}
+
+// observeActionsSecret fetches the GitHub actions secret of an ActionsSecret resource, along with the id
+// of its repository if it is an environment secret. The secret is nil if it doesn't exist.
+func (r *ActionsSecretReconciler) observeActionsSecret(ctx context.Context, secret *githubv1alpha1.ActionsSecret) (*github.Secret, int64, error) {
+	log := log.FromContext(ctx)
+
+	// environment secrets are addressed by repository id. If the repository is gone, so are its
+	// environments and their secrets.
+	var repoId int64
+	if secret.Spec.EnvironmentName != nil {
+		repo, err := r.GitHubClient.GetRepositoryByName(ctx, secret.Spec.Owner, *secret.Spec.RepositoryName)
+		if _, ok := err.(*gh.RepositoryNotFoundError); ok && !secret.DeletionTimestamp.IsZero() {
+			log.Info(err.Error())
+			return nil, 0, nil
+		} else if err != nil {
+			log.Error(err, "error fetching GitHub repository")
+			return nil, 0, err
+		}
+		repoId = repo.GetID()
+	}
+
+	ghSecret, err := r.getActionsSecret(ctx, secret, repoId)
+	if _, ok := err.(*gh.ActionsSecretNotFoundError); ok {
+		log.Info(err.Error())
+		return nil, repoId, nil
+	} else if err != nil {
+		log.Error(err, "error fetching GitHub actions secret")
+		return nil, repoId, err
+	}
+	return ghSecret, repoId, nil
+}
+
+func (r *ActionsSecretReconciler) getActionsSecret(ctx context.Context, secret *githubv1alpha1.ActionsSecret, repoId int64) (*github.Secret, error) {
+	if secret.Spec.RepositoryName == nil {
+		return r.GitHubClient.GetOrganizationActionsSecret(ctx, secret.Spec.Owner, secret.Spec.Name)
+	} else if secret.Spec.EnvironmentName != nil {
+		return r.GitHubClient.GetEnvironmentActionsSecret(ctx, repoId, *secret.Spec.EnvironmentName, secret.Spec.Name)
+	}
+	return r.GitHubClient.GetRepositoryActionsSecret(ctx, secret.Spec.Owner, *secret.Spec.RepositoryName, secret.Spec.Name)
+}
+
+func (r *ActionsSecretReconciler) deleteActionsSecret(ctx context.Context, secret *githubv1alpha1.ActionsSecret, repoId int64) error {
+	if secret.Status.Owner == nil {
+		return fmt.Errorf("actions secret Owner is nil")
+	} else if secret.Status.Name == nil {
+		return fmt.Errorf("actions secret Name is nil")
+	}
+	if secret.Status.RepositoryName == nil {
+		return r.GitHubClient.DeleteOrganizationActionsSecret(ctx, *secret.Status.Owner, *secret.Status.Name)
+	} else if secret.Status.EnvironmentName != nil {
+		return r.GitHubClient.DeleteEnvironmentActionsSecret(ctx, repoId, *secret.Status.EnvironmentName, *secret.Status.Name)
+	}
+	return r.GitHubClient.DeleteRepositoryActionsSecret(ctx, *secret.Status.Owner, *secret.Status.RepositoryName, *secret.Status.Name)
+}
+
+// resolves the names of repositories owned by owner to the ids expected by the
+// selected repository APIs for organization secrets and variables
+func selectedRepositoryIds(ctx context.Context, getter interface {
+	GetRepositoryByName(ctx context.Context, owner string, name string) (*github.Repository, error)
+}, owner string, names []string) (github.SelectedRepoIDs, error) {
+	ids := github.SelectedRepoIDs{}
+	for _, name := range names {
+		repo, err := getter.GetRepositoryByName(ctx, owner, name)
+		if err != nil {
+			return nil, fmt.Errorf("resolving selected repository '%s': %w", name, err)
+		}
+		ids = append(ids, repo.GetID())
+	}
+	return ids, nil
+}
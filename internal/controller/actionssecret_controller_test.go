@@ -0,0 +1,249 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+var _ = Describe("ActionsSecret Controller", func() {
+	const resourceName = "test-resource"
+	const testSecretName = "DEPLOY_TOKEN"
+	const testSecretKey = "token"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	valueNamespacedName := types.NamespacedName{
+		Name:      "test-actions-secret-value",
+		Namespace: "default",
+	}
+	testRepoName := ghTestResourcePrefix + "actions-secret-test-repo"
+
+	reconcileActionsSecret := func(deleteOnResourceDeletion bool) {
+		controllerReconciler := &ActionsSecretReconciler{
+			Client:                   k8sClient,
+			Scheme:                   k8sClient.Scheme(),
+			GitHubClient:             fakeGHClient,
+			Recorder:                 &record.FakeRecorder{},
+			DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	createActionsSecret := func(environmentName *string) {
+		By("Creating the custom resource for the Kind ActionsSecret")
+		resource := &githubv1alpha1.ActionsSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: githubv1alpha1.ActionsSecretSpec{
+				Name:            testSecretName,
+				Owner:           testOrganization,
+				RepositoryName:  &testRepoName,
+				EnvironmentName: environmentName,
+				SecretKeyRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: valueNamespacedName.Name},
+					Key:                  testSecretKey,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		By("Creating a test repository")
+		_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+			Name: &testRepoName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Creating the Secret holding the value")
+		value := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      valueNamespacedName.Name,
+				Namespace: valueNamespacedName.Namespace,
+			},
+			Data: map[string][]byte{testSecretKey: []byte("first-value")},
+		}
+		Expect(k8sClient.Create(ctx, value)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the specific resource instance ActionsSecret")
+		cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.ActionsSecret{})
+		cleanUpResource(ctx, valueNamespacedName, &corev1.Secret{})
+
+		By("Cleaning up the test repository")
+		Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+	})
+
+	Context("When creating an ActionsSecret resource", func() {
+		It("should create a GitHub repository actions secret", func() {
+			createActionsSecret(nil)
+
+			By("Reconciling the resource")
+			reconcileActionsSecret(false)
+
+			By("Checking the ActionsSecret status")
+			resource := &githubv1alpha1.ActionsSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Name).To(Equal(github.String(testSecretName)))
+			value := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, valueNamespacedName, value)).To(Succeed())
+			Expect(resource.Status.ValueHash).To(Equal(github.String(secretValueHash(value.UID, []byte("first-value")))))
+
+			By("Checking the GitHub actions secret")
+			ghValue, ok := fakeServer.ActionsSecret(testOrganization, testRepoName, "", testSecretName)
+			Expect(ok).To(BeTrue())
+			Expect(ghValue).To(Equal("first-value"))
+		})
+
+		It("should create a GitHub environment actions secret", func() {
+			By("Creating a GitHub environment")
+			_, err := fakeGHClient.CreateUpdateEnvironment(ctx, testOrganization, testRepoName, "staging", &github.CreateUpdateEnvironment{})
+			Expect(err).NotTo(HaveOccurred())
+			createActionsSecret(github.String("staging"))
+
+			By("Reconciling the resource")
+			reconcileActionsSecret(false)
+
+			By("Checking the GitHub actions secret")
+			value, ok := fakeServer.ActionsSecret(testOrganization, testRepoName, "staging", testSecretName)
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("first-value"))
+			_, ok = fakeServer.ActionsSecret(testOrganization, testRepoName, "", testSecretName)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("When the ActionsSecret value drifts", func() {
+		BeforeEach(func() {
+			createActionsSecret(nil)
+		})
+
+		It("should update the GitHub actions secret when the referenced value changes", func() {
+			By("Reconciling the resource")
+			reconcileActionsSecret(false)
+
+			By("Changing the referenced value")
+			value := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, valueNamespacedName, value)).To(Succeed())
+			value.Data[testSecretKey] = []byte("second-value")
+			Expect(k8sClient.Update(ctx, value)).To(Succeed())
+
+			By("Reconciling the resource")
+			reconcileActionsSecret(false)
+
+			By("Checking the GitHub actions secret")
+			ghValue, ok := fakeServer.ActionsSecret(testOrganization, testRepoName, "", testSecretName)
+			Expect(ok).To(BeTrue())
+			Expect(ghValue).To(Equal("second-value"))
+
+			By("Checking the drift is reported")
+			resource := &githubv1alpha1.ActionsSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "valueHash")))
+		})
+
+		It("should recreate a GitHub actions secret deleted outside of the operator", func() {
+			By("Reconciling the resource")
+			reconcileActionsSecret(false)
+
+			By("Deleting the GitHub actions secret outside of the operator")
+			Expect(fakeGHClient.DeleteRepositoryActionsSecret(ctx, testOrganization, testRepoName, testSecretName)).To(Succeed())
+
+			By("Reconciling the resource")
+			reconcileActionsSecret(false)
+
+			By("Checking the GitHub actions secret")
+			value, ok := fakeServer.ActionsSecret(testOrganization, testRepoName, "", testSecretName)
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("first-value"))
+		})
+	})
+
+	Context("When deleting an ActionsSecret resource", func() {
+		BeforeEach(func() {
+			createActionsSecret(nil)
+		})
+
+		It("should delete the GitHub actions secret with the resource", func() {
+			By("Reconciling the resource")
+			reconcileActionsSecret(true)
+			resource := &githubv1alpha1.ActionsSecret{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(actionsSecretFinalizerName))
+
+			By("Deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource, &client.DeleteOptions{
+				GracePeriodSeconds: &deletionGracePeriod,
+			})).To(Succeed())
+			reconcileActionsSecret(true)
+
+			By("Checking the resource is gone")
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking the GitHub actions secret is gone")
+			_, err = fakeGHClient.GetRepositoryActionsSecret(ctx, testOrganization, testRepoName, testSecretName)
+			Expect(err).To(BeAssignableToTypeOf(&gh.ActionsSecretNotFoundError{}))
+		})
+	})
+})
+
+var _ = Describe("secretValueHash", func() {
+	value := []byte("hunter2")
+
+	It("should not be the plain SHA-256 of the value", func() {
+		sum := sha256.Sum256(value)
+		Expect(secretValueHash("8f4c1a2e-uid", value)).NotTo(Equal(hex.EncodeToString(sum[:])))
+	})
+
+	It("should be stable for the same Secret and value", func() {
+		Expect(secretValueHash("8f4c1a2e-uid", value)).To(Equal(secretValueHash("8f4c1a2e-uid", value)))
+		Expect(secretValueHash("8f4c1a2e-uid", value)).NotTo(Equal(secretValueHash("8f4c1a2e-uid", []byte("hunter3"))))
+	})
+
+	It("should differ between Secrets holding the same value", func() {
+		Expect(secretValueHash("8f4c1a2e-uid", value)).NotTo(Equal(secretValueHash("d03b77c1-uid", value)))
+	})
+})
@@ -0,0 +1,429 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+	"github.com/google/go-github/v60/github"
+)
+
+var (
+	actionsVariableFinalizerName = "github.github-operator.eczy.io/actions-variable-finalizer"
+)
+
+type ActionsVariableRequester interface {
+	GetRepositoryActionsVariable(ctx context.Context, owner, repo, name string) (*github.ActionsVariable, error)
+	GetOrganizationActionsVariable(ctx context.Context, org, name string) (*github.ActionsVariable, error)
+	GetEnvironmentActionsVariable(ctx context.Context, repoId int64, env, name string) (*github.ActionsVariable, error)
+	CreateRepositoryActionsVariable(ctx context.Context, owner, repo string, variable *github.ActionsVariable) error
+	CreateOrganizationActionsVariable(ctx context.Context, org string, variable *github.ActionsVariable) error
+	CreateEnvironmentActionsVariable(ctx context.Context, repoId int64, env string, variable *github.ActionsVariable) error
+	UpdateRepositoryActionsVariable(ctx context.Context, owner, repo string, variable *github.ActionsVariable) error
+	UpdateOrganizationActionsVariable(ctx context.Context, org string, variable *github.ActionsVariable) error
+	UpdateEnvironmentActionsVariable(ctx context.Context, repoId int64, env string, variable *github.ActionsVariable) error
+	DeleteRepositoryActionsVariable(ctx context.Context, owner, repo, name string) error
+	DeleteOrganizationActionsVariable(ctx context.Context, org, name string) error
+	DeleteEnvironmentActionsVariable(ctx context.Context, repoId int64, env, name string) error
+	GetOrganizationActionsVariableRepositories(ctx context.Context, org, name string) ([]*github.Repository, error)
+
+	// needed to resolve environment and selected repository ids
+	GetRepositoryByName(ctx context.Context, owner string, name string) (*github.Repository, error)
+}
+
+// ActionsVariableReconciler reconciles an ActionsVariable object
+type ActionsVariableReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	GitHubClient             ActionsVariableRequester
+	Recorder                 record.EventRecorder
+	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
+	RequeueInterval          time.Duration
+}
+
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=actionsvariables,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=actionsvariables/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=actionsvariables/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
+func (r *ActionsVariableReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := log.FromContext(ctx)
+
+	// fetch resource
+	variable := &githubv1alpha1.ActionsVariable{}
+	if err := r.Get(ctx, req.NamespacedName, variable); err != nil {
+		log.Error(err, "error fetching ActionsVariable resource")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub
+	managementPolicy := resolveManagementPolicy(variable.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(variable, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, variable, &variable.Status.Conditions, &variable.Status.ObservedGeneration, &variable.Status.Drift, &variable.Status.Plan, err)
+	}()
+
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, variable.Spec.ProviderRef, variable.Spec.Owner, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
+	if variable.Spec.EnvironmentName != nil && variable.Spec.RepositoryName == nil {
+		return ctrl.Result{}, fmt.Errorf("actions variable '%s' sets environmentName without repositoryName", variable.Spec.Name)
+	}
+	if variable.Spec.RepositoryName != nil && (variable.Spec.Visibility != nil || len(variable.Spec.SelectedRepositories) > 0) {
+		return ctrl.Result{}, fmt.Errorf("actions variable '%s': visibility and selectedRepositories only apply to organization variables", variable.Spec.Name)
+	}
+
+	// try to fetch external resource
+	observed, repoId, err := r.observeActionsVariable(ctx, variable)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// if external resource does't exist and we aren't deleting the resource, create external resource
+	if observed == nil && variable.DeletionTimestamp.IsZero() {
+		observed, err = r.createMissingActionsVariable(ctx, variable, managementPolicy, repoId)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// handle finalizer
+	deletionPolicy := resolveDeletionPolicy(variable.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion)
+	if variable.DeletionTimestamp.IsZero() {
+		// not being deleted
+		if deletionPolicy != githubv1alpha1.DeletionPolicyOrphan && !controllerutil.ContainsFinalizer(variable, actionsVariableFinalizerName) {
+			controllerutil.AddFinalizer(variable, actionsVariableFinalizerName)
+			if err := r.Update(ctx, variable); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if deletionPolicy == githubv1alpha1.DeletionPolicyOrphan && controllerutil.ContainsFinalizer(variable, actionsVariableFinalizerName) {
+			// the GitHub resource is left in place, so there is nothing to clean up
+			controllerutil.RemoveFinalizer(variable, actionsVariableFinalizerName)
+			if err := r.Update(ctx, variable); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else if controllerutil.ContainsFinalizer(variable, actionsVariableFinalizerName) {
+		// being deleted
+		if deletionPolicy == githubv1alpha1.DeletionPolicyDelete && variable.Status.Name != nil && observed != nil {
+			// if we have never resolved this resource before, don't
+			// touch external state
+			if !shouldMutate(ctx, "delete actions variable %s", variable.Spec.Name) {
+				// keep the finalizer so the deletion is applied once the resource leaves dry-run mode
+				return ctrl.Result{}, nil
+			}
+			if err := r.deleteActionsVariable(ctx, variable, repoId); err != nil {
+				log.Error(err, "error deleting actions variable")
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(variable, corev1.EventTypeNormal, eventReasonDeleted, "Deleted GitHub actions variable")
+		}
+
+		controllerutil.RemoveFinalizer(variable, actionsVariableFinalizerName)
+		if err := r.Update(ctx, variable); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// external resource is gone or wasn't created under the management policy
+	if observed == nil {
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+	}
+
+	// update external resource
+	err = r.updateActionsVariable(ctx, variable, observed, repoId)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ActionsVariableReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&githubv1alpha1.ActionsVariable{}).
+		Complete(r)
+}
+
+func (r *ActionsVariableReconciler) updateActionsVariable(ctx context.Context, variable *githubv1alpha1.ActionsVariable, ghVariable *github.ActionsVariable, repoId int64) error {
+	log := log.FromContext(ctx)
+
+	isOrg := variable.Spec.RepositoryName == nil
+	update := &github.ActionsVariable{
+		Name:  variable.Spec.Name,
+		Value: ghVariable.Value,
+	}
+	needsUpdate := false
+
+	// Value
+	if variable.Spec.Value != ghVariable.Value {
+		log.Info("actions variable Value update", "from", ghVariable.Value, "to", variable.Spec.Value)
+		recordDrift(ctx, "value", variable.Spec.Value, ghVariable.Value)
+		update.Value = variable.Spec.Value
+		needsUpdate = true
+	}
+
+	// Visibility and SelectedRepositories
+	var visibility *githubv1alpha1.ActionsVisibility
+	selectedRepositories := []string{}
+	// status reflects the observed variable unless it is updated below
+	var observedVisibility *githubv1alpha1.ActionsVisibility
+	observedRepositories := []string{}
+	if isOrg {
+		visibility = actionsVariableVisibility(variable)
+		if *visibility != githubv1alpha1.ActionsVisibilitySelected && len(variable.Spec.SelectedRepositories) > 0 {
+			return fmt.Errorf("actions variable '%s': selectedRepositories requires selected visibility", variable.Spec.Name)
+		}
+		update.Visibility = ghVariable.Visibility
+		observedVisibility = (*githubv1alpha1.ActionsVisibility)(ghVariable.Visibility)
+		if ghVariable.GetVisibility() != string(*visibility) {
+			log.Info("actions variable Visibility update", "from", ghVariable.GetVisibility(), "to", *visibility)
+			recordDrift(ctx, "visibility", *visibility, ghVariable.GetVisibility())
+			update.Visibility = github.String(string(*visibility))
+			needsUpdate = true
+		}
+		if ghVariable.GetVisibility() == string(githubv1alpha1.ActionsVisibilitySelected) {
+			repos, err := r.GitHubClient.GetOrganizationActionsVariableRepositories(ctx, variable.Spec.Owner, variable.Spec.Name)
+			if err != nil {
+				log.Error(err, "error fetching actions variable repositories")
+				return err
+			}
+			for _, repo := range repos {
+				observedRepositories = append(observedRepositories, repo.GetName())
+			}
+			sort.Strings(observedRepositories)
+		}
+		if *visibility == githubv1alpha1.ActionsVisibilitySelected {
+			if !cmpSlices(variable.Spec.SelectedRepositories, observedRepositories) {
+				log.Info("actions variable SelectedRepositories update", "from", observedRepositories, "to", variable.Spec.SelectedRepositories)
+				recordDrift(ctx, "selectedRepositories", variable.Spec.SelectedRepositories, observedRepositories)
+				ids, err := selectedRepositoryIds(ctx, r.GitHubClient, variable.Spec.Owner, variable.Spec.SelectedRepositories)
+				if err != nil {
+					log.Error(err, "error resolving selected repositories")
+					return err
+				}
+				update.SelectedRepositoryIDs = &ids
+				needsUpdate = true
+			}
+			selectedRepositories = append(selectedRepositories, variable.Spec.SelectedRepositories...)
+			sort.Strings(selectedRepositories)
+		}
+	}
+
+	// perform update if necessary
+	if needsUpdate && shouldMutate(ctx, "update actions variable %s", variable.Spec.Name) {
+		log.Info("updating actions variable", "name", variable.Spec.Name)
+		var err error
+		switch {
+		case isOrg:
+			err = r.GitHubClient.UpdateOrganizationActionsVariable(ctx, variable.Spec.Owner, update)
+		case variable.Spec.EnvironmentName != nil:
+			err = r.GitHubClient.UpdateEnvironmentActionsVariable(ctx, repoId, *variable.Spec.EnvironmentName, update)
+		default:
+			err = r.GitHubClient.UpdateRepositoryActionsVariable(ctx, variable.Spec.Owner, *variable.Spec.RepositoryName, update)
+		}
+		if err != nil {
+			log.Error(err, "error updating actions variable", "name", variable.Spec.Name)
+			return err
+		}
+		r.Recorder.Event(variable, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub actions variable")
+
+		updated, err := r.getActionsVariable(ctx, variable, repoId)
+		if err != nil {
+			log.Error(err, "error fetching GitHub actions variable")
+			return err
+		}
+		ghVariable = updated
+		observedVisibility = visibility
+		observedRepositories = selectedRepositories
+	}
+
+	// populate status from the updated variable, or the observed variable if it wasn't updated
+	if needsUpdate || variable.Status.LastUpdateTimestamp == nil {
+		now := v1.Now()
+		variable.Status = githubv1alpha1.ActionsVariableStatus{
+			LastUpdateTimestamp:  &now,
+			Name:                 github.String(ghVariable.Name),
+			Owner:                github.String(variable.Spec.Owner),
+			RepositoryName:       variable.Spec.RepositoryName,
+			EnvironmentName:      variable.Spec.EnvironmentName,
+			Value:                github.String(ghVariable.Value),
+			Visibility:           observedVisibility,
+			SelectedRepositories: observedRepositories,
+			CreatedAt:            (*v1.Time)(ghVariable.CreatedAt),
+			UpdatedAt:            (*v1.Time)(ghVariable.UpdatedAt),
+			Conditions:           variable.Status.Conditions,
+			ObservedGeneration:   variable.Status.ObservedGeneration,
+			Drift:                variable.Status.Drift,
+			Plan:                 variable.Status.Plan,
+		}
+
+		// update status
+		if err := r.Status().Update(ctx, variable); err != nil {
+			log.Error(err, "error updating ActionsVariable status", "name", variable.Spec.Name)
+		}
+	}
+	return nil
+}
+
+// observeActionsVariable fetches the GitHub actions variable of an ActionsVariable resource, along with the id
+// of its repository if it is an environment variable. The variable is nil if it doesn't exist.
+func (r *ActionsVariableReconciler) observeActionsVariable(ctx context.Context, variable *githubv1alpha1.ActionsVariable) (*github.ActionsVariable, int64, error) {
+	log := log.FromContext(ctx)
+
+	// environment variables are addressed by repository id. If the repository is gone, so are its
+	// environments and their variables.
+	var repoId int64
+	if variable.Spec.EnvironmentName != nil {
+		repo, err := r.GitHubClient.GetRepositoryByName(ctx, variable.Spec.Owner, *variable.Spec.RepositoryName)
+		if _, ok := err.(*gh.RepositoryNotFoundError); ok && !variable.DeletionTimestamp.IsZero() {
+			log.Info(err.Error())
+			return nil, 0, nil
+		} else if err != nil {
+			log.Error(err, "error fetching GitHub repository")
+			return nil, 0, err
+		}
+		repoId = repo.GetID()
+	}
+
+	ghVariable, err := r.getActionsVariable(ctx, variable, repoId)
+	if _, ok := err.(*gh.ActionsVariableNotFoundError); ok {
+		log.Info(err.Error())
+		return nil, repoId, nil
+	} else if err != nil {
+		log.Error(err, "error fetching GitHub actions variable")
+		return nil, repoId, err
+	}
+	return ghVariable, repoId, nil
+}
+
+// createMissingActionsVariable creates the GitHub actions variable of an ActionsVariable resource under the
+// Full management policy. It returns nil if the variable isn't created.
+func (r *ActionsVariableReconciler) createMissingActionsVariable(ctx context.Context, variable *githubv1alpha1.ActionsVariable, managementPolicy githubv1alpha1.ManagementPolicy, repoId int64) (*github.ActionsVariable, error) {
+	log := log.FromContext(ctx)
+
+	if managementPolicy != githubv1alpha1.ManagementPolicyFull {
+		// only the Full management policy creates missing GitHub resources
+		skipMutation(ctx, "create actions variable %s", variable.Spec.Name)
+		return nil, nil
+	}
+	if !shouldMutate(ctx, "create actions variable %s", variable.Spec.Name) {
+		return nil, nil
+	}
+	ghVariable, err := r.createActionsVariable(ctx, variable, repoId)
+	if err != nil {
+		log.Error(err, "error creating GitHub actions variable")
+		return nil, err
+	}
+	r.Recorder.Event(variable, corev1.EventTypeNormal, eventReasonCreated, "Created GitHub actions variable")
+	return ghVariable, nil
+}
+
+func (r *ActionsVariableReconciler) createActionsVariable(ctx context.Context, variable *githubv1alpha1.ActionsVariable, repoId int64) (*github.ActionsVariable, error) {
+	create := &github.ActionsVariable{
+		Name:  variable.Spec.Name,
+		Value: variable.Spec.Value,
+	}
+
+	var err error
+	switch {
+	case variable.Spec.RepositoryName == nil:
+		visibility := actionsVariableVisibility(variable)
+		create.Visibility = github.String(string(*visibility))
+		if *visibility == githubv1alpha1.ActionsVisibilitySelected {
+			ids, err := selectedRepositoryIds(ctx, r.GitHubClient, variable.Spec.Owner, variable.Spec.SelectedRepositories)
+			if err != nil {
+				return nil, err
+			}
+			create.SelectedRepositoryIDs = &ids
+		}
+		err = r.GitHubClient.CreateOrganizationActionsVariable(ctx, variable.Spec.Owner, create)
+	case variable.Spec.EnvironmentName != nil:
+		err = r.GitHubClient.CreateEnvironmentActionsVariable(ctx, repoId, *variable.Spec.EnvironmentName, create)
+	default:
+		err = r.GitHubClient.CreateRepositoryActionsVariable(ctx, variable.Spec.Owner, *variable.Spec.RepositoryName, create)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.getActionsVariable(ctx, variable, repoId)
+}
+
+func (r *ActionsVariableReconciler) getActionsVariable(ctx context.Context, variable *githubv1alpha1.ActionsVariable, repoId int64) (*github.ActionsVariable, error) {
+	if variable.Spec.RepositoryName == nil {
+		return r.GitHubClient.GetOrganizationActionsVariable(ctx, variable.Spec.Owner, variable.Spec.Name)
+	} else if variable.Spec.EnvironmentName != nil {
+		return r.GitHubClient.GetEnvironmentActionsVariable(ctx, repoId, *variable.Spec.EnvironmentName, variable.Spec.Name)
+	}
+	return r.GitHubClient.GetRepositoryActionsVariable(ctx, variable.Spec.Owner, *variable.Spec.RepositoryName, variable.Spec.Name)
+}
+
+func (r *ActionsVariableReconciler) deleteActionsVariable(ctx context.Context, variable *githubv1alpha1.ActionsVariable, repoId int64) error {
+	if variable.Status.Owner == nil {
+		return fmt.Errorf("actions variable Owner is nil")
+	} else if variable.Status.Name == nil {
+		return fmt.Errorf("actions variable Name is nil")
+	}
+	if variable.Status.RepositoryName == nil {
+		return r.GitHubClient.DeleteOrganizationActionsVariable(ctx, *variable.Status.Owner, *variable.Status.Name)
+	} else if variable.Status.EnvironmentName != nil {
+		return r.GitHubClient.DeleteEnvironmentActionsVariable(ctx, repoId, *variable.Status.EnvironmentName, *variable.Status.Name)
+	}
+	return r.GitHubClient.DeleteRepositoryActionsVariable(ctx, *variable.Status.Owner, *variable.Status.RepositoryName, *variable.Status.Name)
+}
+
+// organization variables default to private visibility
+func actionsVariableVisibility(variable *githubv1alpha1.ActionsVariable) *githubv1alpha1.ActionsVisibility {
+	if variable.Spec.Visibility != nil {
+		return variable.Spec.Visibility
+	}
+	visibility := githubv1alpha1.ActionsVisibilityPrivate
+	return &visibility
+}
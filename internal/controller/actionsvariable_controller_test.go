@@ -0,0 +1,163 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+var _ = Describe("ActionsVariable Controller", func() {
+	const resourceName = "test-resource"
+	const testVariableName = "DEPLOY_REGION"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	testRepoName := ghTestResourcePrefix + "actions-variable-test-repo"
+
+	reconcileActionsVariable := func(deleteOnResourceDeletion bool) {
+		controllerReconciler := &ActionsVariableReconciler{
+			Client:                   k8sClient,
+			Scheme:                   k8sClient.Scheme(),
+			GitHubClient:             fakeGHClient,
+			Recorder:                 &record.FakeRecorder{},
+			DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		By("Creating a test repository")
+		_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+			Name: &testRepoName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Creating the custom resource for the Kind ActionsVariable")
+		resource := &githubv1alpha1.ActionsVariable{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: githubv1alpha1.ActionsVariableSpec{
+				Name:           testVariableName,
+				Owner:          testOrganization,
+				RepositoryName: &testRepoName,
+				Value:          "us-east-1",
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the specific resource instance ActionsVariable")
+		cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.ActionsVariable{})
+
+		By("Cleaning up the test repository")
+		Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+	})
+
+	Context("When creating an ActionsVariable resource", func() {
+		It("should create a GitHub actions variable", func() {
+			By("Reconciling the resource")
+			reconcileActionsVariable(false)
+
+			By("Checking the ActionsVariable status")
+			resource := &githubv1alpha1.ActionsVariable{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Name).To(Equal(github.String(testVariableName)))
+
+			By("Checking the GitHub actions variable")
+			ghVariable, err := fakeGHClient.GetRepositoryActionsVariable(ctx, testOrganization, testRepoName, testVariableName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghVariable.Value).To(Equal("us-east-1"))
+		})
+	})
+
+	Context("When the GitHub actions variable drifts", func() {
+		It("should correct drift of the GitHub actions variable", func() {
+			By("Reconciling the resource")
+			reconcileActionsVariable(false)
+
+			By("Changing the GitHub actions variable outside of the operator")
+			Expect(fakeGHClient.UpdateRepositoryActionsVariable(ctx, testOrganization, testRepoName, &github.ActionsVariable{
+				Name:  testVariableName,
+				Value: "eu-west-1",
+			})).To(Succeed())
+
+			By("Reconciling the resource")
+			reconcileActionsVariable(false)
+
+			By("Checking the GitHub actions variable")
+			ghVariable, err := fakeGHClient.GetRepositoryActionsVariable(ctx, testOrganization, testRepoName, testVariableName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghVariable.Value).To(Equal("us-east-1"))
+
+			By("Checking the drift is reported")
+			resource := &githubv1alpha1.ActionsVariable{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Drift).To(ContainElement(And(
+				HaveField("Field", "value"),
+				HaveField("Observed", ContainSubstring("eu-west-1")),
+			)))
+		})
+	})
+
+	Context("When deleting an ActionsVariable resource", func() {
+		It("should delete the GitHub actions variable with the resource", func() {
+			By("Reconciling the resource")
+			reconcileActionsVariable(true)
+			resource := &githubv1alpha1.ActionsVariable{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(actionsVariableFinalizerName))
+
+			By("Deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource, &client.DeleteOptions{
+				GracePeriodSeconds: &deletionGracePeriod,
+			})).To(Succeed())
+			reconcileActionsVariable(true)
+
+			By("Checking the resource is gone")
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking the GitHub actions variable is gone")
+			_, err = fakeGHClient.GetRepositoryActionsVariable(ctx, testOrganization, testRepoName, testVariableName)
+			Expect(err).To(BeAssignableToTypeOf(&gh.ActionsVariableNotFoundError{}))
+		})
+	})
+})
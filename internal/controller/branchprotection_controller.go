@@ -19,14 +19,25 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
 	gh "github.com/eczy/github-operator/internal/github"
@@ -52,13 +63,22 @@ type BranchProtectionReconciler struct {
 	client.Client
 	Scheme                   *runtime.Scheme
 	GitHubClient             BranchProtectionRequester
+	Recorder                 record.EventRecorder
 	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
 	RequeueInterval          time.Duration
+	// GitHubEvents optionally triggers reconciles for resources changed on GitHub,
+	// see GitHubEventReceiver.
+	GitHubEvents <-chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=branchprotections,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=branchprotections/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=branchprotections/finalizers,verbs=update
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=repositories,verbs=get;list;watch
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=teams,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -69,13 +89,9 @@ type BranchProtectionReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
-func (r *BranchProtectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *BranchProtectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := log.FromContext(ctx)
 
-	if r.GitHubClient == nil {
-		return ctrl.Result{}, fmt.Errorf("nil GitHub client")
-	}
-
 	// fetch resource
 	bp := &githubv1alpha1.BranchProtection{}
 	if err := r.Get(ctx, req.NamespacedName, bp); err != nil {
@@ -83,66 +99,115 @@ func (r *BranchProtectionReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	var observed *gh.BranchProtection
-	// try to fetch external resource
-	if bp.Status.NodeId != nil {
-		ghBp, err := r.GitHubClient.GetBranchProtection(ctx, *bp.Status.NodeId)
-		if err != nil {
-			// TODO: determine if the error is a "not found" error vs another type of error
-			log.Info(err.Error())
-		}
-		observed = ghBp
-	} else {
-		ghBp, err := r.GitHubClient.GetBranchProtectionByOwnerRepoPattern(ctx, bp.Spec.RepositoryOwner, bp.Spec.RepositoryName, bp.Spec.Pattern)
-		if err != nil {
-			// TODO: determine if the error is a "not found" error vs another type of error
-			log.Info(err.Error())
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub
+	managementPolicy := resolveManagementPolicy(bp.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(bp, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, bp, &bp.Status.Conditions, &bp.Status.ObservedGeneration, &bp.Status.Drift, &bp.Status.Plan, err)
+		// referenced resources are watched, so there is no need to retry while waiting for them
+		err = ignoreDependencyNotReady(err)
+	}()
+
+	// the desired state is the spec with references to other resources resolved
+	spec, err := r.resolveSpec(ctx, bp)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, bp.Spec.ProviderRef, spec.RepositoryOwner, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
+	// branch protection rules of a repository that is deleted along with its Repository are
+	// owned by it, so they are deleted with it too
+	if bp.DeletionTimestamp.IsZero() {
+		if err := r.setRepositoryOwnerReference(ctx, bp, spec); err != nil {
+			return ctrl.Result{}, err
 		}
-		observed = ghBp
+	}
+
+	// try to fetch external resource
+	observed, err := r.getBranchProtection(ctx, bp, spec)
+	if err != nil {
+		log.Error(err, "error fetching GitHub branch protection")
+		return ctrl.Result{}, err
 	}
 
 	// if external resource does't exist and we aren't deleting the resource, create external resource
 	if observed == nil && bp.DeletionTimestamp.IsZero() {
-		ghBp, err := r.createBranchProtection(ctx, bp)
-		if err != nil {
-			log.Error(err, "error creating GitHub branch protection")
-			return ctrl.Result{}, err
+		if managementPolicy != githubv1alpha1.ManagementPolicyFull {
+			// only the Full management policy creates missing GitHub resources
+			skipMutation(ctx, "create branch protection %s on %s/%s", bp.Spec.Pattern, spec.RepositoryOwner, spec.RepositoryName)
+		} else if shouldMutate(ctx, "create branch protection %s on %s/%s", bp.Spec.Pattern, spec.RepositoryOwner, spec.RepositoryName) {
+			ghBp, err := r.createBranchProtection(ctx, bp, spec)
+			if err != nil {
+				log.Error(err, "error creating GitHub branch protection")
+				return ctrl.Result{}, err
+			}
+			observed = ghBp
+			r.Recorder.Event(bp, corev1.EventTypeNormal, eventReasonCreated, "Created GitHub branch protection")
 		}
-		observed = ghBp
 	}
 
 	// handle finalizer
-	if r.DeleteOnResourceDeletion {
-		if bp.DeletionTimestamp.IsZero() {
-			// not being deleted
-			if !controllerutil.ContainsFinalizer(bp, branchProtectionFinalizerName) {
-				controllerutil.AddFinalizer(bp, branchProtectionFinalizerName)
-				if err := r.Update(ctx, bp); err != nil {
-					return ctrl.Result{}, err
-				}
-			}
-		} else {
-			// being deleted
-			if bp.Status.LastUpdateTimestamp != nil {
-				// if we have never resolved this resource before, don't
-				// touch external state
-				if err := r.deleteBranchProtection(ctx, bp); err != nil {
-					log.Error(err, "error deleting branch protection")
-					return ctrl.Result{}, err
-				}
+	deletionPolicy := resolveDeletionPolicy(bp.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion)
+	if bp.DeletionTimestamp.IsZero() {
+		// not being deleted
+		if deletionPolicy != githubv1alpha1.DeletionPolicyOrphan && !controllerutil.ContainsFinalizer(bp, branchProtectionFinalizerName) {
+			controllerutil.AddFinalizer(bp, branchProtectionFinalizerName)
+			if err := r.Update(ctx, bp); err != nil {
+				return ctrl.Result{}, err
 			}
-
+		} else if deletionPolicy == githubv1alpha1.DeletionPolicyOrphan && controllerutil.ContainsFinalizer(bp, branchProtectionFinalizerName) {
+			// the GitHub resource is left in place, so there is nothing to clean up
 			controllerutil.RemoveFinalizer(bp, branchProtectionFinalizerName)
 			if err := r.Update(ctx, bp); err != nil {
 				return ctrl.Result{}, err
 			}
+		}
+	} else if controllerutil.ContainsFinalizer(bp, branchProtectionFinalizerName) {
+		// being deleted
+		if deletionPolicy == githubv1alpha1.DeletionPolicyDelete && bp.Status.LastUpdateTimestamp != nil && observed != nil {
+			// if we have never resolved this resource before, don't
+			// touch external state. A rule that wasn't found needs no
+			// deleting either
+			if !shouldMutate(ctx, "delete branch protection %s on %s/%s", bp.Spec.Pattern, spec.RepositoryOwner, spec.RepositoryName) {
+				// keep the finalizer so the deletion is applied once the resource leaves dry-run mode
+				return ctrl.Result{}, nil
+			}
+			deleted, err := r.deleteBranchProtectionOfRepository(ctx, bp, observed)
+			if err != nil {
+				log.Error(err, "error deleting branch protection")
+				return ctrl.Result{}, err
+			}
+			if deleted {
+				r.Recorder.Event(bp, corev1.EventTypeNormal, eventReasonDeleted, "Deleted GitHub branch protection")
+			}
+		}
 
-			return ctrl.Result{}, nil
+		controllerutil.RemoveFinalizer(bp, branchProtectionFinalizerName)
+		if err := r.Update(ctx, bp); err != nil {
+			return ctrl.Result{}, err
 		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// external resource is gone or wasn't created under the management policy
+	if observed == nil {
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
 	}
 
 	// update external resource
-	err := r.updateBranchProtection(ctx, bp, observed)
+	err = r.updateBranchProtection(ctx, bp, spec, observed)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -152,13 +217,258 @@ func (r *BranchProtectionReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *BranchProtectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&githubv1alpha1.BranchProtection{}).
-		Complete(r)
+		// branch protection rules are reconciled again when the resources they reference change
+		Watches(&githubv1alpha1.Repository{}, handler.EnqueueRequestsFromMapFunc(r.branchProtectionsForRepository),
+			builder.WithPredicates(predicate.Or(referencedRepositoryChanged, repositoryDeletionPolicyChanged))).
+		Watches(&githubv1alpha1.Team{}, handler.EnqueueRequestsFromMapFunc(r.branchProtectionsForTeam), builder.WithPredicates(referencedTeamChanged))
+	if r.GitHubEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.GitHubEvents, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
 
-func (r *BranchProtectionReconciler) createBranchProtection(ctx context.Context, bp *githubv1alpha1.BranchProtection) (*gh.BranchProtection, error) {
-	input, err := r.branchProtectionToCreateInput(ctx, bp)
+// repositoryDeletionPolicyChanged passes Repository updates that change whether deleting the
+// Repository deletes its repository, and so whether it owns the repository's branch protection rules.
+var repositoryDeletionPolicyChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldRepo, okOld := e.ObjectOld.(*githubv1alpha1.Repository)
+		newRepo, okNew := e.ObjectNew.(*githubv1alpha1.Repository)
+		if !okOld || !okNew {
+			return true
+		}
+		return !ptrEqual(oldRepo.Spec.DeletionPolicy, newRepo.Spec.DeletionPolicy) ||
+			!ptrEqual(oldRepo.Spec.ManagementPolicy, newRepo.Spec.ManagementPolicy)
+	},
+}
+
+// maps a Repository to the branch protection rules in its namespace that reference it,
+// are owned by it or apply to its repository
+func (r *BranchProtectionReconciler) branchProtectionsForRepository(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	repo, ok := obj.(*githubv1alpha1.Repository)
+	if !ok {
+		return nil
+	}
+
+	bps := &githubv1alpha1.BranchProtectionList{}
+	if err := r.List(ctx, bps, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "error listing BranchProtection resources")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, bp := range bps.Items {
+		if (bp.Spec.RepositoryRef != nil && bp.Spec.RepositoryRef.Name == obj.GetName()) || isOwnedBy(&bp, obj) || branchProtectionTargets(&bp, &bp.Spec, repo) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: bp.Namespace, Name: bp.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// branchProtectionTargets returns true if a branch protection rule with the given spec applies to
+// the repository managed by repo.
+func branchProtectionTargets(bp *githubv1alpha1.BranchProtection, spec *githubv1alpha1.BranchProtectionSpec, repo *githubv1alpha1.Repository) bool {
+	// node IDs survive renames, so names are only compared until both sides have been observed
+	if bp.Status.RepositoryNodeId != nil && repo.Status.NodeId != nil {
+		return *bp.Status.RepositoryNodeId == *repo.Status.NodeId
+	}
+	return strings.EqualFold(spec.RepositoryOwner, repo.Spec.Owner) && strings.EqualFold(spec.RepositoryName, repo.Spec.Name)
+}
+
+// maps a Team to the branch protection rules in its namespace that reference it in their allowances
+func (r *BranchProtectionReconciler) branchProtectionsForTeam(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	bps := &githubv1alpha1.BranchProtectionList{}
+	if err := r.List(ctx, bps, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "error listing BranchProtection resources")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, bp := range bps.Items {
+		for _, refs := range branchProtectionTeamRefs(&bp.Spec) {
+			if slices.ContainsFunc(refs.refs, func(ref githubv1alpha1.ResourceReference) bool { return ref.Name == obj.GetName() }) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: bp.Namespace, Name: bp.Name},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// teamRefs pairs a list of Team references with the list of team slugs they resolve into
+type teamRefs struct {
+	refs  []githubv1alpha1.ResourceReference
+	slugs *[]string
+}
+
+func branchProtectionTeamRefs(spec *githubv1alpha1.BranchProtectionSpec) []teamRefs {
+	return []teamRefs{
+		{spec.BypassForcePushTeamRefs, &spec.BypassForcePushTeams},
+		{spec.BypassPullRequestTeamRefs, &spec.BypassPullRequestTeams},
+		{spec.PushAllowanceTeamRefs, &spec.PushAllowanceTeams},
+		{spec.ReviewDismissalTeamRefs, &spec.ReviewDismissalTeams},
+	}
+}
+
+// resolveSpec returns the desired state of bp, its spec with references to other resources resolved.
+// Deleting the branch protection rule doesn't depend on its references, only on the repository it
+// was last observed on.
+func (r *BranchProtectionReconciler) resolveSpec(ctx context.Context, bp *githubv1alpha1.BranchProtection) (*githubv1alpha1.BranchProtectionSpec, error) {
+	spec, err := r.resolveReferences(ctx, bp)
+	if err == nil || bp.DeletionTimestamp.IsZero() {
+		return spec, err
+	}
+	spec = bp.Spec.DeepCopy()
+	if bp.Status.RepositoryOwner != nil && bp.Status.RepositoryName != nil {
+		spec.RepositoryOwner = *bp.Status.RepositoryOwner
+		spec.RepositoryName = *bp.Status.RepositoryName
+	}
+	return spec, nil
+}
+
+// resolveReferences returns a copy of the branch protection spec with the fields given as references
+// to other resources filled in. Resolved fields take precedence over the ones set directly.
+func (r *BranchProtectionReconciler) resolveReferences(ctx context.Context, bp *githubv1alpha1.BranchProtection) (*githubv1alpha1.BranchProtectionSpec, error) {
+	spec := bp.Spec.DeepCopy()
+	if spec.RepositoryRef != nil {
+		repo, err := getReferencedRepository(ctx, r.Client, bp.Namespace, *spec.RepositoryRef)
+		if err != nil {
+			return nil, err
+		}
+		spec.RepositoryOwner = *repo.Status.OwnerLogin
+		spec.RepositoryName = *repo.Status.Name
+		if bp.Status.RepositoryNodeId != nil && *bp.Status.RepositoryNodeId != *repo.Status.NodeId {
+			// the Repository manages another repository than the one the rule was observed on,
+			// e.g. after it was recreated, so the rule is looked up and created on the new one
+			bp.Status.RepositoryNodeId = repo.Status.NodeId
+			bp.Status.NodeId = nil
+		}
+	}
+	if spec.RepositoryOwner == "" || spec.RepositoryName == "" {
+		return nil, fmt.Errorf("repositoryOwner and repositoryName are required unless repositoryRef is set")
+	}
+	if spec.RepositoryRef == nil && bp.Status.RepositoryNodeId != nil && bp.Status.RepositoryOwner != nil && bp.Status.RepositoryName != nil {
+		// once observed, the repository is followed through its node ID, so that a renamed
+		// or transferred repository keeps its branch protection rules without editing the spec
+		spec.RepositoryOwner = *bp.Status.RepositoryOwner
+		spec.RepositoryName = *bp.Status.RepositoryName
+	}
+	for _, refs := range branchProtectionTeamRefs(spec) {
+		for _, ref := range refs.refs {
+			team, err := getReferencedTeam(ctx, r.Client, bp.Namespace, ref)
+			if err != nil {
+				return nil, err
+			}
+			if !strings.EqualFold(*team.Status.OrganizationLogin, spec.RepositoryOwner) {
+				return nil, fmt.Errorf("referenced Team '%s' belongs to organization '%s', not '%s'", ref.Name, *team.Status.OrganizationLogin, spec.RepositoryOwner)
+			}
+			if !slices.Contains(*refs.slugs, *team.Status.Slug) {
+				*refs.slugs = append(*refs.slugs, *team.Status.Slug)
+			}
+		}
+	}
+	return spec, nil
+}
+
+// managingRepository returns the Repository in the namespace of bp that manages the repository
+// of bp, or nil if there is none.
+func (r *BranchProtectionReconciler) managingRepository(ctx context.Context, bp *githubv1alpha1.BranchProtection, spec *githubv1alpha1.BranchProtectionSpec) (*githubv1alpha1.Repository, error) {
+	if spec.RepositoryRef != nil {
+		repo := &githubv1alpha1.Repository{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: bp.Namespace, Name: spec.RepositoryRef.Name}, repo); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		return repo, nil
+	}
+	repos := &githubv1alpha1.RepositoryList{}
+	if err := r.List(ctx, repos, client.InNamespace(bp.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range repos.Items {
+		if branchProtectionTargets(bp, spec, &repos.Items[i]) {
+			return &repos.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// setRepositoryOwnerReference makes the Repository managing the repository of bp an owner of bp if
+// deleting the Repository deletes the repository, and removes other owner references to Repositories.
+// Repositories that orphan or archive their repository don't own its branch protection rules, which
+// would otherwise be garbage collected and possibly deleted from the repository that is kept.
+func (r *BranchProtectionReconciler) setRepositoryOwnerReference(ctx context.Context, bp *githubv1alpha1.BranchProtection, spec *githubv1alpha1.BranchProtectionSpec) error {
+	repo, err := r.managingRepository(ctx, bp, spec)
+	if err != nil {
+		return err
+	}
+	if repo != nil && !r.deletesRepository(repo) {
+		repo = nil
+	}
+
+	changed := false
+	ownerRefs := []v1.OwnerReference{}
+	for _, ref := range bp.OwnerReferences {
+		if ref.APIVersion == githubv1alpha1.GroupVersion.String() && ref.Kind == "Repository" && (repo == nil || ref.UID != repo.UID) {
+			changed = true
+			continue
+		}
+		ownerRefs = append(ownerRefs, ref)
+	}
+	bp.OwnerReferences = ownerRefs
+	if repo != nil {
+		owned, err := controllerutil.HasOwnerReference(bp.OwnerReferences, repo, r.Scheme)
+		if err != nil {
+			return err
+		}
+		if !owned {
+			if err := controllerutil.SetOwnerReference(repo, bp, r.Scheme); err != nil {
+				return err
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.Update(ctx, bp)
+}
+
+// deletesRepository returns true if deleting repo deletes the GitHub repository it manages.
+func (r *BranchProtectionReconciler) deletesRepository(repo *githubv1alpha1.Repository) bool {
+	managementPolicy := resolveManagementPolicy(repo.Spec.ManagementPolicy)
+	return resolveDeletionPolicy(repo.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion) == githubv1alpha1.RepositoryDeletionPolicyDelete
+}
+
+// getBranchProtection fetches the GitHub branch protection rule of bp, by its node ID once it has been
+// observed and by its repository and pattern before that. It returns nil if the rule doesn't exist.
+func (r *BranchProtectionReconciler) getBranchProtection(ctx context.Context, bp *githubv1alpha1.BranchProtection, spec *githubv1alpha1.BranchProtectionSpec) (*gh.BranchProtection, error) {
+	log := log.FromContext(ctx)
+
+	if bp.Status.NodeId == nil {
+		ghBp, err := r.GitHubClient.GetBranchProtectionByOwnerRepoPattern(ctx, spec.RepositoryOwner, spec.RepositoryName, bp.Spec.Pattern)
+		if err != nil {
+			// TODO: determine if the error is a "not found" error vs another type of error
+			log.Info(err.Error())
+		}
+		return ghBp, nil
+	}
+	ghBp, err := r.GitHubClient.GetBranchProtection(ctx, *bp.Status.NodeId)
+	if _, ok := err.(*gh.BranchProtectionNotFoundError); ok {
+		log.Info(err.Error())
+		return nil, nil
+	}
+	return ghBp, err
+}
+
+func (r *BranchProtectionReconciler) createBranchProtection(ctx context.Context, bp *githubv1alpha1.BranchProtection, spec *githubv1alpha1.BranchProtectionSpec) (*gh.BranchProtection, error) {
+	input, err := r.branchProtectionToCreateInput(ctx, bp, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -172,7 +482,7 @@ func (r *BranchProtectionReconciler) createBranchProtection(ctx context.Context,
 // TODO: this needs refactoring, but ignore for now so that CI passes
 //
 //gocyclo:ignore
-func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context, bp *githubv1alpha1.BranchProtection, ghBp *gh.BranchProtection) error {
+func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context, bp *githubv1alpha1.BranchProtection, spec *githubv1alpha1.BranchProtectionSpec, ghBp *gh.BranchProtection) error {
 	log := log.FromContext(ctx)
 
 	update := githubv4.UpdateBranchProtectionRuleInput{
@@ -181,24 +491,28 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 	needsUpdate := false
 
 	// Pattern
-	if bp.Spec.Pattern != ghBp.Pattern {
-		update.Pattern = (*githubv4.String)(&bp.Spec.Pattern)
+	if spec.Pattern != ghBp.Pattern {
+		recordDrift(ctx, "pattern", spec.Pattern, ghBp.Pattern)
+		update.Pattern = (*githubv4.String)(&spec.Pattern)
 		needsUpdate = true
 	}
 
 	// AllowsDeletions
-	if ptrNonNilAndNotEqualTo(bp.Spec.AllowsDeletions, ghBp.AllowsDeletions) {
-		update.AllowsDeletions = (*githubv4.Boolean)(bp.Spec.AllowsDeletions)
+	if ptrNonNilAndNotEqualTo(spec.AllowsDeletions, ghBp.AllowsDeletions) {
+		recordDrift(ctx, "allowsDeletions", spec.AllowsDeletions, ghBp.AllowsDeletions)
+		update.AllowsDeletions = (*githubv4.Boolean)(spec.AllowsDeletions)
 		needsUpdate = true
 	}
 	// AllowsForcePushes
-	if ptrNonNilAndNotEqualTo(bp.Spec.AllowsForcePushes, ghBp.AllowsForcePushes) {
-		update.AllowsForcePushes = (*githubv4.Boolean)(bp.Spec.AllowsForcePushes)
+	if ptrNonNilAndNotEqualTo(spec.AllowsForcePushes, ghBp.AllowsForcePushes) {
+		recordDrift(ctx, "allowsForcePushes", spec.AllowsForcePushes, ghBp.AllowsForcePushes)
+		update.AllowsForcePushes = (*githubv4.Boolean)(spec.AllowsForcePushes)
 		needsUpdate = true
 	}
 	// BlocksCreations
-	if ptrNonNilAndNotEqualTo(bp.Spec.BlocksCreations, ghBp.BlocksCreations) {
-		update.BlocksCreations = (*githubv4.Boolean)(bp.Spec.BlocksCreations)
+	if ptrNonNilAndNotEqualTo(spec.BlocksCreations, ghBp.BlocksCreations) {
+		recordDrift(ctx, "blocksCreations", spec.BlocksCreations, ghBp.BlocksCreations)
+		update.BlocksCreations = (*githubv4.Boolean)(spec.BlocksCreations)
 		needsUpdate = true
 	}
 
@@ -207,38 +521,41 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 	// BypassForcePushUsers
 	userLogins := []string{}
 	userIds := []githubv4.ID{}
-	for _, user := range ghBp.GetPushAllowances().Users {
+	for _, user := range ghBp.GetBypassForcePushAllowances().Users {
 		userIds = append(userIds, user.Id)
 		userLogins = append(userLogins, user.Login)
 	}
 	specBypassForcePushIds = append(specBypassForcePushIds, userIds...)
-	if !cmpSlices(bp.Spec.BypassForcePushUsers, userLogins) {
+	if !cmpSlices(spec.BypassForcePushUsers, userLogins) {
+		recordDrift(ctx, "bypassForcePushUsers", spec.BypassForcePushUsers, userLogins)
 		needsBypassForcePushUpdate = true
 	}
 	// BypassForcePushApps
 	appSlugs := []string{}
 	appIds := []githubv4.ID{}
-	for _, app := range ghBp.GetPushAllowances().Apps {
+	for _, app := range ghBp.GetBypassForcePushAllowances().Apps {
 		appIds = append(appIds, app.Id)
 		appSlugs = append(appSlugs, app.Slug)
 	}
 	specBypassForcePushIds = append(specBypassForcePushIds, appIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceApps, appSlugs) {
+	if !cmpSlices(spec.BypassForcePushApps, appSlugs) {
+		recordDrift(ctx, "bypassForcePushApps", spec.BypassForcePushApps, appSlugs)
 		needsBypassForcePushUpdate = true
 	}
 	// BypassForcePushTeams
 	teamSlugs := []string{}
 	teamIds := []githubv4.ID{}
-	for _, team := range ghBp.GetPushAllowances().Teams {
-		teamIds = append(appIds, team.Id)
-		teamSlugs = append(appSlugs, team.Slug)
+	for _, team := range ghBp.GetBypassForcePushAllowances().Teams {
+		teamIds = append(teamIds, team.Id)
+		teamSlugs = append(teamSlugs, team.Slug)
 	}
 	specBypassForcePushIds = append(specBypassForcePushIds, teamIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceTeams, teamSlugs) {
+	if !cmpSlices(spec.BypassForcePushTeams, teamSlugs) {
+		recordDrift(ctx, "bypassForcePushTeams", spec.BypassForcePushTeams, teamSlugs)
 		needsBypassForcePushUpdate = true
 	}
 	if needsBypassForcePushUpdate {
-		update.PushActorIDs = &specBypassForcePushIds
+		update.BypassForcePushActorIDs = &specBypassForcePushIds
 		needsUpdate = true
 	}
 
@@ -247,59 +564,66 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 	// BypassPullRequestUsers
 	userLogins = []string{}
 	userIds = []githubv4.ID{}
-	for _, user := range ghBp.GetPushAllowances().Users {
+	for _, user := range ghBp.GetBypassPullRequestAllowances().Users {
 		userIds = append(userIds, user.Id)
 		userLogins = append(userLogins, user.Login)
 	}
 	specBypassPullRequestIds = append(specBypassPullRequestIds, userIds...)
-	if !cmpSlices(bp.Spec.BypassPullRequestUsers, userLogins) {
+	if !cmpSlices(spec.BypassPullRequestUsers, userLogins) {
+		recordDrift(ctx, "bypassPullRequestUsers", spec.BypassPullRequestUsers, userLogins)
 		needsBypassPullRequestUpdate = true
 	}
 	// BypassPullRequestApps
 	appSlugs = []string{}
 	appIds = []githubv4.ID{}
-	for _, app := range ghBp.GetPushAllowances().Apps {
+	for _, app := range ghBp.GetBypassPullRequestAllowances().Apps {
 		appIds = append(appIds, app.Id)
 		appSlugs = append(appSlugs, app.Slug)
 	}
 	specBypassPullRequestIds = append(specBypassPullRequestIds, appIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceApps, appSlugs) {
+	if !cmpSlices(spec.BypassPullRequestApps, appSlugs) {
+		recordDrift(ctx, "bypassPullRequestApps", spec.BypassPullRequestApps, appSlugs)
 		needsBypassPullRequestUpdate = true
 	}
 	// BypassPullRequestTeams
 	teamSlugs = []string{}
 	teamIds = []githubv4.ID{}
-	for _, team := range ghBp.GetPushAllowances().Teams {
-		teamIds = append(appIds, team.Id)
-		teamSlugs = append(appSlugs, team.Slug)
+	for _, team := range ghBp.GetBypassPullRequestAllowances().Teams {
+		teamIds = append(teamIds, team.Id)
+		teamSlugs = append(teamSlugs, team.Slug)
 	}
 	specBypassPullRequestIds = append(specBypassPullRequestIds, teamIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceTeams, teamSlugs) {
+	if !cmpSlices(spec.BypassPullRequestTeams, teamSlugs) {
+		recordDrift(ctx, "bypassPullRequestTeams", spec.BypassPullRequestTeams, teamSlugs)
 		needsBypassPullRequestUpdate = true
 	}
 	if needsBypassPullRequestUpdate {
-		update.PushActorIDs = &specBypassPullRequestIds
+		update.BypassPullRequestActorIDs = &specBypassPullRequestIds
 		needsUpdate = true
 	}
 
 	// DismissesStaleReviews
-	if ptrNonNilAndNotEqualTo(bp.Spec.DismissesStaleReviews, ghBp.DismissesStaleReviews) {
-		update.DismissesStaleReviews = (*githubv4.Boolean)(bp.Spec.DismissesStaleReviews)
+	if ptrNonNilAndNotEqualTo(spec.DismissesStaleReviews, ghBp.DismissesStaleReviews) {
+		recordDrift(ctx, "dismissesStaleReviews", spec.DismissesStaleReviews, ghBp.DismissesStaleReviews)
+		update.DismissesStaleReviews = (*githubv4.Boolean)(spec.DismissesStaleReviews)
 		needsUpdate = true
 	}
 	// IsAdminEnforced
-	if ptrNonNilAndNotEqualTo(bp.Spec.AllowsDeletions, ghBp.AllowsDeletions) {
-		update.IsAdminEnforced = (*githubv4.Boolean)(bp.Spec.IsAdminEnforced)
+	if ptrNonNilAndNotEqualTo(spec.IsAdminEnforced, ghBp.IsAdminEnforced) {
+		recordDrift(ctx, "isAdminEnforced", spec.IsAdminEnforced, ghBp.IsAdminEnforced)
+		update.IsAdminEnforced = (*githubv4.Boolean)(spec.IsAdminEnforced)
 		needsUpdate = true
 	}
 	// LockAllowsFetchAndMerge
-	if ptrNonNilAndNotEqualTo(bp.Spec.LockAllowsFetchAndMerge, ghBp.LockAllowsFetchAndMerge) {
-		update.LockAllowsFetchAndMerge = (*githubv4.Boolean)(bp.Spec.LockAllowsFetchAndMerge)
+	if ptrNonNilAndNotEqualTo(spec.LockAllowsFetchAndMerge, ghBp.LockAllowsFetchAndMerge) {
+		recordDrift(ctx, "lockAllowsFetchAndMerge", spec.LockAllowsFetchAndMerge, ghBp.LockAllowsFetchAndMerge)
+		update.LockAllowsFetchAndMerge = (*githubv4.Boolean)(spec.LockAllowsFetchAndMerge)
 		needsUpdate = true
 	}
 	// LockBranch
-	if ptrNonNilAndNotEqualTo(bp.Spec.LockBranch, ghBp.LockBranch) {
-		update.LockBranch = (*githubv4.Boolean)(bp.Spec.LockBranch)
+	if ptrNonNilAndNotEqualTo(spec.LockBranch, ghBp.LockBranch) {
+		recordDrift(ctx, "lockBranch", spec.LockBranch, ghBp.LockBranch)
+		update.LockBranch = (*githubv4.Boolean)(spec.LockBranch)
 		needsUpdate = true
 	}
 
@@ -313,7 +637,8 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 		userLogins = append(userLogins, user.Login)
 	}
 	specPushActorIds = append(specPushActorIds, userIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceUsers, userLogins) {
+	if !cmpSlices(spec.PushAllowanceUsers, userLogins) {
+		recordDrift(ctx, "pushAllowanceUsers", spec.PushAllowanceUsers, userLogins)
 		needsPushAllowanceUpdate = true
 	}
 	// PushAllowanceApps
@@ -324,18 +649,20 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 		appSlugs = append(appSlugs, app.Slug)
 	}
 	specPushActorIds = append(specPushActorIds, appIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceApps, appSlugs) {
+	if !cmpSlices(spec.PushAllowanceApps, appSlugs) {
+		recordDrift(ctx, "pushAllowanceApps", spec.PushAllowanceApps, appSlugs)
 		needsPushAllowanceUpdate = true
 	}
 	// PushAllowanceTeams
 	teamSlugs = []string{}
 	teamIds = []githubv4.ID{}
 	for _, team := range ghBp.GetPushAllowances().Teams {
-		teamIds = append(appIds, team.Id)
-		teamSlugs = append(appSlugs, team.Slug)
+		teamIds = append(teamIds, team.Id)
+		teamSlugs = append(teamSlugs, team.Slug)
 	}
 	specPushActorIds = append(specPushActorIds, teamIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceTeams, teamSlugs) {
+	if !cmpSlices(spec.PushAllowanceTeams, teamSlugs) {
+		recordDrift(ctx, "pushAllowanceTeams", spec.PushAllowanceTeams, teamSlugs)
 		needsPushAllowanceUpdate = true
 	}
 	if needsPushAllowanceUpdate {
@@ -344,30 +671,34 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 	}
 
 	// RequireLastPushApproval
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequireLastPushApproval, ghBp.RequireLastPushApproval) {
-		update.RequireLastPushApproval = (*githubv4.Boolean)(bp.Spec.RequireLastPushApproval)
+	if ptrNonNilAndNotEqualTo(spec.RequireLastPushApproval, ghBp.RequireLastPushApproval) {
+		recordDrift(ctx, "requireLastPushApproval", spec.RequireLastPushApproval, ghBp.RequireLastPushApproval)
+		update.RequireLastPushApproval = (*githubv4.Boolean)(spec.RequireLastPushApproval)
 		needsUpdate = true
 	}
 	// RequiredApprovingReviewCount
 	ghCount := int(ghBp.RequiredApprovingReviewCount)
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequiredApprovingReviewCount, ghCount) {
-		val := *bp.Spec.RequiredApprovingReviewCount
+	if ptrNonNilAndNotEqualTo(spec.RequiredApprovingReviewCount, ghCount) {
+		recordDrift(ctx, "requiredApprovingReviewCount", spec.RequiredApprovingReviewCount, ghCount)
+		val := *spec.RequiredApprovingReviewCount
 		update.RequiredApprovingReviewCount = githubv4.NewInt(githubv4.Int(val))
 		needsUpdate = true
 	}
 	// RequiredDeploymentEnvironments
-	if !cmpSlices(bp.Spec.RequiredDeploymentEnvironments, ghBp.RequiredDeploymentEnvironments) {
+	if !cmpSlices(spec.RequiredDeploymentEnvironments, ghBp.RequiredDeploymentEnvironments) {
+		recordDrift(ctx, "requiredDeploymentEnvironments", spec.RequiredDeploymentEnvironments, ghBp.RequiredDeploymentEnvironments)
 		conv := []githubv4.String{}
-		for _, x := range bp.Spec.RequiredDeploymentEnvironments {
+		for _, x := range spec.RequiredDeploymentEnvironments {
 			conv = append(conv, githubv4.String(x))
 		}
 		update.RequiredDeploymentEnvironments = &conv
 		needsUpdate = true
 	}
 	// RequiredStatusCheckContexts
-	if !cmpSlices(bp.Spec.RequiredStatusCheckContexts, ghBp.RequiredStatusCheckContexts) {
+	if !cmpSlices(spec.RequiredStatusCheckContexts, ghBp.RequiredStatusCheckContexts) {
+		recordDrift(ctx, "requiredStatusCheckContexts", spec.RequiredStatusCheckContexts, ghBp.RequiredStatusCheckContexts)
 		conv := []githubv4.String{}
-		for _, x := range bp.Spec.RequiredStatusCheckContexts {
+		for _, x := range spec.RequiredStatusCheckContexts {
 			conv = append(conv, githubv4.String(x))
 		}
 		update.RequiredStatusCheckContexts = &conv
@@ -376,11 +707,11 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 	// RequiredStatusChecks
 	ghChecks := map[string]gh.RequiredStatusCheckDescription{}
 	updateChecks := []githubv4.RequiredStatusCheckInput{}
-	requiredStatusChecksNeedUpdate := len(ghBp.RequiredStatusChecks) == len(bp.Spec.RequiredStatusChecks)
+	requiredStatusChecksNeedUpdate := len(ghBp.RequiredStatusChecks) != len(spec.RequiredStatusChecks)
 	for _, check := range ghBp.RequiredStatusChecks {
 		ghChecks[check.Context] = check
 	}
-	for _, check := range bp.Spec.RequiredStatusChecks {
+	for _, check := range spec.RequiredStatusChecks {
 		var appId githubv4.ID
 		if check.AppId != nil {
 			appId = check.AppId
@@ -392,7 +723,7 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 		if ghCheck, ok := ghChecks[check.Context]; ok {
 			if ghCheck.Context != check.Context {
 				requiredStatusChecksNeedUpdate = true
-			} else if !ptrNonNilAndNotEqualTo(check.AppId, ghCheck.App.Id) {
+			} else if ptrNonNilAndNotEqualTo(check.AppId, ghCheck.App.Id) {
 				requiredStatusChecksNeedUpdate = true
 			}
 		} else {
@@ -400,58 +731,77 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 		}
 	}
 	if requiredStatusChecksNeedUpdate {
+		desiredChecks := []string{}
+		for _, check := range spec.RequiredStatusChecks {
+			desiredChecks = append(desiredChecks, check.Context)
+		}
+		observedChecks := []string{}
+		for _, check := range ghBp.RequiredStatusChecks {
+			observedChecks = append(observedChecks, check.Context)
+		}
+		recordDrift(ctx, "requiredStatusChecks", desiredChecks, observedChecks)
 		update.RequiredStatusChecks = &updateChecks
 		needsUpdate = true
 	}
 
 	// RequiresApprovingReviews
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequiresApprovingReviews, ghBp.RequiresApprovingReviews) {
-		update.RequiresApprovingReviews = (*githubv4.Boolean)(bp.Spec.RequiresApprovingReviews)
+	if ptrNonNilAndNotEqualTo(spec.RequiresApprovingReviews, ghBp.RequiresApprovingReviews) {
+		recordDrift(ctx, "requiresApprovingReviews", spec.RequiresApprovingReviews, ghBp.RequiresApprovingReviews)
+		update.RequiresApprovingReviews = (*githubv4.Boolean)(spec.RequiresApprovingReviews)
 		needsUpdate = true
 	}
 	// RequiresCodeOwnerReviews
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequiresCodeOwnerReviews, ghBp.RequiresCodeOwnerReviews) {
-		update.RequiresCodeOwnerReviews = (*githubv4.Boolean)(bp.Spec.RequiresCodeOwnerReviews)
+	if ptrNonNilAndNotEqualTo(spec.RequiresCodeOwnerReviews, ghBp.RequiresCodeOwnerReviews) {
+		recordDrift(ctx, "requiresCodeOwnerReviews", spec.RequiresCodeOwnerReviews, ghBp.RequiresCodeOwnerReviews)
+		update.RequiresCodeOwnerReviews = (*githubv4.Boolean)(spec.RequiresCodeOwnerReviews)
 		needsUpdate = true
 	}
 	// RequiresCommitSignatures
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequiresCommitSignatures, ghBp.RequiresCommitSignatures) {
-		update.RequiresCommitSignatures = (*githubv4.Boolean)(bp.Spec.RequiresCommitSignatures)
+	if ptrNonNilAndNotEqualTo(spec.RequiresCommitSignatures, ghBp.RequiresCommitSignatures) {
+		recordDrift(ctx, "requiresCommitSignatures", spec.RequiresCommitSignatures, ghBp.RequiresCommitSignatures)
+		update.RequiresCommitSignatures = (*githubv4.Boolean)(spec.RequiresCommitSignatures)
 		needsUpdate = true
 	}
 	// RequiresConversationResolution
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequiresConversationResolution, ghBp.RequiresConversationResolution) {
-		update.RequiresConversationResolution = (*githubv4.Boolean)(bp.Spec.RequiresConversationResolution)
+	if ptrNonNilAndNotEqualTo(spec.RequiresConversationResolution, ghBp.RequiresConversationResolution) {
+		recordDrift(ctx, "requiresConversationResolution", spec.RequiresConversationResolution, ghBp.RequiresConversationResolution)
+		update.RequiresConversationResolution = (*githubv4.Boolean)(spec.RequiresConversationResolution)
 		needsUpdate = true
 	}
 	// RequiresDeployments
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequiresDeployments, ghBp.RequiresDeployments) {
-		update.RequiresDeployments = (*githubv4.Boolean)(bp.Spec.RequiresDeployments)
+	if ptrNonNilAndNotEqualTo(spec.RequiresDeployments, ghBp.RequiresDeployments) {
+		recordDrift(ctx, "requiresDeployments", spec.RequiresDeployments, ghBp.RequiresDeployments)
+		update.RequiresDeployments = (*githubv4.Boolean)(spec.RequiresDeployments)
 		needsUpdate = true
 	}
 	// RequiresLinearHistory
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequiresLinearHistory, ghBp.RequiresLinearHistory) {
-		update.RequiresLinearHistory = (*githubv4.Boolean)(bp.Spec.RequiresLinearHistory)
+	if ptrNonNilAndNotEqualTo(spec.RequiresLinearHistory, ghBp.RequiresLinearHistory) {
+		recordDrift(ctx, "requiresLinearHistory", spec.RequiresLinearHistory, ghBp.RequiresLinearHistory)
+		update.RequiresLinearHistory = (*githubv4.Boolean)(spec.RequiresLinearHistory)
 		needsUpdate = true
 	}
 	// RequiresStatusChecks
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequiresStatusChecks, ghBp.RequiresStatusChecks) {
-		update.RequiresStatusChecks = (*githubv4.Boolean)(bp.Spec.RequiresStatusChecks)
+	if ptrNonNilAndNotEqualTo(spec.RequiresStatusChecks, ghBp.RequiresStatusChecks) {
+		recordDrift(ctx, "requiresStatusChecks", spec.RequiresStatusChecks, ghBp.RequiresStatusChecks)
+		update.RequiresStatusChecks = (*githubv4.Boolean)(spec.RequiresStatusChecks)
 		needsUpdate = true
 	}
 	// RequiresStrictStatusChecks
-	if ptrNonNilAndNotEqualTo(bp.Spec.RequiresStrictStatusChecks, ghBp.RequiresStrictStatusChecks) {
-		update.RequiresStrictStatusChecks = (*githubv4.Boolean)(bp.Spec.RequiresStrictStatusChecks)
+	if ptrNonNilAndNotEqualTo(spec.RequiresStrictStatusChecks, ghBp.RequiresStrictStatusChecks) {
+		recordDrift(ctx, "requiresStrictStatusChecks", spec.RequiresStrictStatusChecks, ghBp.RequiresStrictStatusChecks)
+		update.RequiresStrictStatusChecks = (*githubv4.Boolean)(spec.RequiresStrictStatusChecks)
 		needsUpdate = true
 	}
 	// RestrictsPushes
-	if ptrNonNilAndNotEqualTo(bp.Spec.RestrictsPushes, ghBp.RestrictsPushes) {
-		update.RestrictsPushes = (*githubv4.Boolean)(bp.Spec.RestrictsPushes)
+	if ptrNonNilAndNotEqualTo(spec.RestrictsPushes, ghBp.RestrictsPushes) {
+		recordDrift(ctx, "restrictsPushes", spec.RestrictsPushes, ghBp.RestrictsPushes)
+		update.RestrictsPushes = (*githubv4.Boolean)(spec.RestrictsPushes)
 		needsUpdate = true
 	}
 	// RestrictsReviewDismissals
-	if ptrNonNilAndNotEqualTo(bp.Spec.RestrictsReviewDismissals, ghBp.RestrictsReviewDismissals) {
-		update.RestrictsReviewDismissals = (*githubv4.Boolean)(bp.Spec.RestrictsReviewDismissals)
+	if ptrNonNilAndNotEqualTo(spec.RestrictsReviewDismissals, ghBp.RestrictsReviewDismissals) {
+		recordDrift(ctx, "restrictsReviewDismissals", spec.RestrictsReviewDismissals, ghBp.RestrictsReviewDismissals)
+		update.RestrictsReviewDismissals = (*githubv4.Boolean)(spec.RestrictsReviewDismissals)
 		needsUpdate = true
 	}
 
@@ -465,7 +815,8 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 		userLogins = append(userLogins, user.Login)
 	}
 	specReviewDismissalIds = append(specReviewDismissalIds, userIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceUsers, userLogins) {
+	if !cmpSlices(spec.ReviewDismissalUsers, userLogins) {
+		recordDrift(ctx, "reviewDismissalUsers", spec.ReviewDismissalUsers, userLogins)
 		needsReviewDismissalUpdate = true
 	}
 	// ReviewDismissalApps
@@ -476,18 +827,20 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 		appSlugs = append(appSlugs, app.Slug)
 	}
 	specReviewDismissalIds = append(specReviewDismissalIds, appIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceApps, appSlugs) {
+	if !cmpSlices(spec.ReviewDismissalApps, appSlugs) {
+		recordDrift(ctx, "reviewDismissalApps", spec.ReviewDismissalApps, appSlugs)
 		needsReviewDismissalUpdate = true
 	}
 	// ReviewDismissalTeams
 	teamSlugs = []string{}
 	teamIds = []githubv4.ID{}
 	for _, team := range ghBp.GetReviewDismissalAllowances().Teams {
-		teamIds = append(appIds, team.Id)
-		teamSlugs = append(appSlugs, team.Slug)
+		teamIds = append(teamIds, team.Id)
+		teamSlugs = append(teamSlugs, team.Slug)
 	}
 	specReviewDismissalIds = append(specReviewDismissalIds, teamIds...)
-	if !cmpSlices(bp.Spec.PushAllowanceTeams, teamSlugs) {
+	if !cmpSlices(spec.ReviewDismissalTeams, teamSlugs) {
+		recordDrift(ctx, "reviewDismissalTeams", spec.ReviewDismissalTeams, teamSlugs)
 		needsReviewDismissalUpdate = true
 	}
 	if needsReviewDismissalUpdate {
@@ -496,14 +849,26 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 	}
 
 	// perform update if necessary
-	if needsUpdate || bp.Status.LastUpdateTimestamp == nil {
-		log.Info("updating branch protection", "pattern", bp.Spec.Pattern)
+	if needsUpdate && shouldMutate(ctx, "update branch protection %s on %s/%s", spec.Pattern, spec.RepositoryOwner, spec.RepositoryName) {
+		log.Info("updating branch protection", "pattern", spec.Pattern)
 
 		updated, err := r.GitHubClient.UpdateBranchProtection(ctx, &update)
 		if err != nil {
 			return err
 		}
+		r.Recorder.Event(bp, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub branch protection")
+		ghBp = updated
+	}
+
+	// a renamed, transferred or recreated repository or a recreated rule is reflected in status even if
+	// the rule itself is unchanged
+	repositoryMoved := bp.Status.RepositoryOwner == nil || *bp.Status.RepositoryOwner != ghBp.Repository.Owner.Login ||
+		bp.Status.RepositoryName == nil || *bp.Status.RepositoryName != ghBp.Repository.Name ||
+		!ptrEqual(bp.Status.RepositoryNodeId, &ghBp.Repository.Id) || !ptrEqual(bp.Status.NodeId, &ghBp.Id)
 
+	// populate status from the updated branch protection, or the observed branch protection if it wasn't updated
+	if needsUpdate || repositoryMoved || bp.Status.LastUpdateTimestamp == nil {
+		updated := ghBp
 		var ownerLogin string
 		if updated.Repository.Owner.Id != "" {
 			ownerLogin = updated.Repository.Owner.Login
@@ -523,24 +888,24 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 			AllowsDeletions:                &updated.AllowsDeletions,
 			AllowsForcePushes:              &updated.AllowsForcePushes,
 			BlocksCreations:                &updated.BlocksCreations,
-			BypassForcePushUsers:           bp.Spec.BypassForcePushUsers,
-			BypassForcePushApps:            bp.Spec.BypassForcePushApps,
-			BypassForcePushTeams:           bp.Spec.BypassForcePushTeams,
-			BypassPullRequestUsers:         bp.Spec.BypassForcePushUsers,
-			BypassPullRequestApps:          bp.Spec.BypassForcePushApps,
-			BypassPullRequestTeams:         bp.Spec.BypassForcePushTeams,
+			BypassForcePushUsers:           spec.BypassForcePushUsers,
+			BypassForcePushApps:            spec.BypassForcePushApps,
+			BypassForcePushTeams:           spec.BypassForcePushTeams,
+			BypassPullRequestUsers:         spec.BypassPullRequestUsers,
+			BypassPullRequestApps:          spec.BypassPullRequestApps,
+			BypassPullRequestTeams:         spec.BypassPullRequestTeams,
 			DismissesStaleReviews:          &updated.DismissesStaleReviews,
 			IsAdminEnforced:                &updated.IsAdminEnforced,
 			LockAllowsFetchAndMerge:        &updated.LockAllowsFetchAndMerge,
 			LockBranch:                     &updated.LockBranch,
-			PushAllowanceUsers:             bp.Spec.PushAllowanceUsers,
-			PushAllowanceApps:              bp.Spec.PushAllowanceApps,
-			PushAllowanceTeams:             bp.Spec.PushAllowanceTeams,
+			PushAllowanceUsers:             spec.PushAllowanceUsers,
+			PushAllowanceApps:              spec.PushAllowanceApps,
+			PushAllowanceTeams:             spec.PushAllowanceTeams,
 			RequireLastPushApproval:        &updated.RequireLastPushApproval,
 			RequiredApprovingReviewCount:   &reviewCount,
-			RequiredDeploymentEnvironments: bp.Spec.RequiredDeploymentEnvironments,
-			RequiredStatusCheckContexts:    bp.Spec.RequiredStatusCheckContexts,
-			RequiredStatusChecks:           bp.Spec.RequiredStatusChecks,
+			RequiredDeploymentEnvironments: spec.RequiredDeploymentEnvironments,
+			RequiredStatusCheckContexts:    spec.RequiredStatusCheckContexts,
+			RequiredStatusChecks:           spec.RequiredStatusChecks,
 			RequiresApprovingReviews:       &updated.RequiresApprovingReviews,
 			RequiresCodeOwnerReviews:       &updated.RequiresCodeOwnerReviews,
 			RequiresCommitSignatures:       &updated.RequiresCommitSignatures,
@@ -551,19 +916,45 @@ func (r *BranchProtectionReconciler) updateBranchProtection(ctx context.Context,
 			RequiresStrictStatusChecks:     &updated.RequiresStrictStatusChecks,
 			RestrictsPushes:                &updated.RestrictsPushes,
 			RestrictsReviewDismissals:      &updated.RestrictsReviewDismissals,
-			ReviewDismissalUsers:           bp.Spec.ReviewDismissalUsers,
-			ReviewDismissalApps:            bp.Spec.ReviewDismissalApps,
-			ReviewDismissalTeams:           bp.Spec.ReviewDismissalTeams,
+			ReviewDismissalUsers:           spec.ReviewDismissalUsers,
+			ReviewDismissalApps:            spec.ReviewDismissalApps,
+			ReviewDismissalTeams:           spec.ReviewDismissalTeams,
+			Conditions:                     bp.Status.Conditions,
+			ObservedGeneration:             bp.Status.ObservedGeneration,
+			Drift:                          bp.Status.Drift,
+			Plan:                           bp.Status.Plan,
 		}
 
 		// update status
 		if err := r.Status().Update(ctx, bp); err != nil {
-			log.Error(err, "error updating BranchProtection status", "pattern", bp.Spec.Pattern)
+			log.Error(err, "error updating BranchProtection status", "pattern", spec.Pattern)
 		}
 	}
 	return nil
 }
 
+// deletes the rule unless its repository was archived, which makes the rule read-only, or deleted
+// along with the rule. The repository is only looked up when the deletion fails, so deleting a
+// rule of an active repository takes a single request.
+func (r *BranchProtectionReconciler) deleteBranchProtectionOfRepository(ctx context.Context, bp *githubv1alpha1.BranchProtection, observed *gh.BranchProtection) (bool, error) {
+	log := log.FromContext(ctx)
+
+	err := r.deleteBranchProtection(ctx, bp)
+	if err == nil {
+		return true, nil
+	}
+	repo, repoErr := r.GitHubClient.GetRepositoryByNodeId(ctx, observed.Repository.Id)
+	if _, ok := repoErr.(*gh.RepositoryNotFoundError); ok {
+		log.Info("not deleting branch protection of deleted repository", "pattern", bp.Spec.Pattern)
+		return false, nil
+	} else if repoErr == nil && repo.GetArchived() {
+		// rules of archived repositories stay with the repository
+		log.Info("not deleting branch protection of archived repository", "pattern", bp.Spec.Pattern)
+		return false, nil
+	}
+	return false, err
+}
+
 func (r *BranchProtectionReconciler) deleteBranchProtection(ctx context.Context, bp *githubv1alpha1.BranchProtection) error {
 	if bp.Status.NodeId == nil {
 		return fmt.Errorf("branch protection NodeID is nil")
@@ -574,16 +965,16 @@ func (r *BranchProtectionReconciler) deleteBranchProtection(ctx context.Context,
 	})
 }
 
-func (r *BranchProtectionReconciler) branchProtectionToCreateInput(ctx context.Context, bp *githubv1alpha1.BranchProtection) (*githubv4.CreateBranchProtectionRuleInput, error) {
+func (r *BranchProtectionReconciler) branchProtectionToCreateInput(ctx context.Context, bp *githubv1alpha1.BranchProtection, spec *githubv1alpha1.BranchProtectionSpec) (*githubv4.CreateBranchProtectionRuleInput, error) {
 	var id githubv4.ID
 	if bp.Status.RepositoryNodeId != nil {
 		id = bp.Status.RepositoryNodeId
 	} else {
 		// return nil, fmt.Errorf("branch protection Status.RepositoryId is nil")
-		repo, err := r.GitHubClient.GetRepositoryByName(ctx, bp.Spec.RepositoryOwner, bp.Spec.RepositoryName)
+		repo, err := r.GitHubClient.GetRepositoryByName(ctx, spec.RepositoryOwner, spec.RepositoryName)
 		if err != nil {
 			// TODO: custom error type
-			return nil, fmt.Errorf("no repository '%s' found for owner '%s'", bp.Spec.RepositoryName, bp.Spec.RepositoryOwner)
+			return nil, fmt.Errorf("no repository '%s' found for owner '%s'", spec.RepositoryName, spec.RepositoryOwner)
 		}
 		id = repo.GetNodeID()
 	}
@@ -593,6 +984,6 @@ func (r *BranchProtectionReconciler) branchProtectionToCreateInput(ctx context.C
 	// TODO: create with all fields populated to avoid 2 api calls
 	return &githubv4.CreateBranchProtectionRuleInput{
 		RepositoryID: id,
-		Pattern:      githubv4.String(bp.Spec.Pattern),
+		Pattern:      githubv4.String(spec.Pattern),
 	}, nil
 }
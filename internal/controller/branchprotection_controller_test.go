@@ -25,6 +25,7 @@ import (
 	"github.com/shurcooL/githubv4"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -102,6 +103,7 @@ var _ = Describe("BranchProtection Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -132,6 +134,7 @@ var _ = Describe("BranchProtection Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -186,6 +189,7 @@ var _ = Describe("BranchProtection Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -217,6 +221,7 @@ var _ = Describe("BranchProtection Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -293,6 +298,7 @@ var _ = Describe("BranchProtection Controller", func() {
 				Client:                   k8sClient,
 				Scheme:                   k8sClient.Scheme(),
 				GitHubClient:             ghClient,
+				Recorder:                 &record.FakeRecorder{},
 				DeleteOnResourceDeletion: true,
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -338,6 +344,7 @@ var _ = Describe("BranchProtection Controller", func() {
 				Client:                   k8sClient,
 				Scheme:                   k8sClient.Scheme(),
 				GitHubClient:             ghClient,
+				Recorder:                 &record.FakeRecorder{},
 				DeleteOnResourceDeletion: true,
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -373,6 +380,7 @@ var _ = Describe("BranchProtection Controller", func() {
 				Client:                   k8sClient,
 				Scheme:                   k8sClient.Scheme(),
 				GitHubClient:             ghClient,
+				Recorder:                 &record.FakeRecorder{},
 				DeleteOnResourceDeletion: true,
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -393,6 +401,7 @@ var _ = Describe("BranchProtection Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -416,4 +425,134 @@ var _ = Describe("BranchProtection Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Context("When a BranchProtection resource references a Repository resource", func() {
+		repoNamespacedName := types.NamespacedName{
+			Name:      "test-branch-protection-repository",
+			Namespace: "default",
+		}
+
+		// observeRepository sets the status of the Repository resource as if it had been reconciled
+		observeRepository := func(ghRepo *github.Repository) {
+			repo := &githubv1alpha1.Repository{}
+			Expect(k8sClient.Get(ctx, repoNamespacedName, repo)).To(Succeed())
+			repo.Status.NodeId = ghRepo.NodeID
+			repo.Status.OwnerLogin = ghRepo.Owner.Login
+			repo.Status.Name = ghRepo.Name
+			Expect(k8sClient.Status().Update(ctx, repo)).To(Succeed())
+		}
+
+		reconcileBranchProtection := func() *githubv1alpha1.BranchProtection {
+			controllerReconciler := &BranchProtectionReconciler{
+				Client:       k8sClient,
+				Scheme:       k8sClient.Scheme(),
+				GitHubClient: fakeGHClient,
+				Recorder:     &record.FakeRecorder{},
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &githubv1alpha1.BranchProtection{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource
+		}
+
+		BeforeEach(func() {
+			By("Creating a test repository")
+			r, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+				Name:       &testRepoName,
+				Visibility: github.String("public"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			testRepository = r
+
+			By("Creating the Repository resource managing it")
+			repo := &githubv1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      repoNamespacedName.Name,
+					Namespace: repoNamespacedName.Namespace,
+				},
+				Spec: githubv1alpha1.RepositorySpec{
+					Name:  testRepoName,
+					Owner: testOrganization,
+				},
+			}
+			Expect(k8sClient.Create(ctx, repo)).To(Succeed())
+			observeRepository(r)
+
+			By("Creating the BranchProtection resource referencing it")
+			resource := &githubv1alpha1.BranchProtection{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: githubv1alpha1.BranchProtectionSpec{
+					RepositoryRef: &githubv1alpha1.ResourceReference{Name: repoNamespacedName.Name},
+					Pattern:       testBranchProtectionPattern,
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Cleanup the resources")
+			cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.BranchProtection{})
+			cleanUpResource(ctx, repoNamespacedName, &githubv1alpha1.Repository{})
+
+			By("Cleanup test repository")
+			if _, err := fakeGHClient.GetRepositoryByName(ctx, testOrganization, testRepoName); err == nil {
+				Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+			}
+			testRepository = nil
+		})
+
+		It("should only be owned by a Repository that deletes its repository", func() {
+			By("Reconciling the resource while the Repository orphans its repository")
+			resource := reconcileBranchProtection()
+			repo := &githubv1alpha1.Repository{}
+			Expect(k8sClient.Get(ctx, repoNamespacedName, repo)).To(Succeed())
+			Expect(resource.OwnerReferences).To(BeEmpty())
+
+			By("Reconciling the resource once the Repository deletes its repository")
+			delete := githubv1alpha1.RepositoryDeletionPolicyDelete
+			repo.Spec.DeletionPolicy = &delete
+			Expect(k8sClient.Update(ctx, repo)).To(Succeed())
+			resource = reconcileBranchProtection()
+			Expect(resource.OwnerReferences).To(ConsistOf(HaveField("UID", repo.UID)))
+
+			By("Reconciling the resource once the Repository archives its repository")
+			Expect(k8sClient.Get(ctx, repoNamespacedName, repo)).To(Succeed())
+			archive := githubv1alpha1.RepositoryDeletionPolicyArchive
+			repo.Spec.DeletionPolicy = &archive
+			Expect(k8sClient.Update(ctx, repo)).To(Succeed())
+			resource = reconcileBranchProtection()
+			Expect(resource.OwnerReferences).To(BeEmpty())
+		})
+
+		It("should follow the Repository to a recreated repository", func() {
+			By("Reconciling the resource")
+			resource := reconcileBranchProtection()
+			Expect(resource.Status.RepositoryNodeId).To(HaveValue(Equal(testRepository.GetNodeID())))
+			oldNodeId := *resource.Status.NodeId
+
+			By("Recreating the repository")
+			Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+			r, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+				Name:       &testRepoName,
+				Visibility: github.String("public"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			testRepository = r
+			observeRepository(r)
+
+			By("Checking the branch protection rule is created on the new repository")
+			resource = reconcileBranchProtection()
+			Expect(resource.Status.RepositoryNodeId).To(HaveValue(Equal(r.GetNodeID())))
+			Expect(resource.Status.NodeId).NotTo(HaveValue(Equal(oldNodeId)))
+			ghBp, err := fakeGHClient.GetBranchProtectionByOwnerRepoPattern(ctx, testOrganization, testRepoName, testBranchProtectionPattern)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghBp.Id).To(Equal(*resource.Status.NodeId))
+		})
+	})
 })
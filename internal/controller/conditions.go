@@ -0,0 +1,203 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+)
+
+// Event reasons emitted by all reconcilers.
+const (
+	eventReasonCreated            = "Created"
+	eventReasonUpdated            = "Updated"
+	eventReasonDeleted            = "Deleted"
+	eventReasonArchived           = "Archived"
+	eventReasonTransferred        = "Transferred"
+	eventReasonPlanned            = "Planned"
+	eventReasonDependencyNotReady = "DependencyNotReady"
+	eventReasonReconcileError     = "ReconcileError"
+)
+
+// setReconcileConditions records the outcome of a reconcile in the standard status
+// conditions and observed generation. Mutations that were not applied to GitHub leave
+// the resource not ready. Returns true if either changed.
+func setReconcileConditions(conditions *[]v1.Condition, observedGeneration *int64, generation int64, skipped []string, err error) bool {
+	changed := *observedGeneration != generation
+	*observedGeneration = generation
+
+	// waiting for a referenced resource is not a failure of the reconcile
+	var notReady *dependencyNotReadyError
+	if errors.As(err, &notReady) {
+		for _, conditionType := range []string{githubv1alpha1.ConditionTypeReady, githubv1alpha1.ConditionTypeSynced} {
+			changed = meta.SetStatusCondition(conditions, v1.Condition{
+				Type:               conditionType,
+				Status:             v1.ConditionFalse,
+				ObservedGeneration: generation,
+				Reason:             githubv1alpha1.ConditionReasonDependencyNotReady,
+				Message:            err.Error(),
+			}) || changed
+		}
+		changed = meta.SetStatusCondition(conditions, v1.Condition{
+			Type:               githubv1alpha1.ConditionTypeDegraded,
+			Status:             v1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             githubv1alpha1.ConditionReasonDependencyNotReady,
+			Message:            err.Error(),
+		}) || changed
+		return changed
+	}
+
+	if err != nil {
+		changed = meta.SetStatusCondition(conditions, v1.Condition{
+			Type:               githubv1alpha1.ConditionTypeReady,
+			Status:             v1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             githubv1alpha1.ConditionReasonReconcileError,
+			Message:            err.Error(),
+		}) || changed
+		changed = meta.SetStatusCondition(conditions, v1.Condition{
+			Type:               githubv1alpha1.ConditionTypeSynced,
+			Status:             v1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             githubv1alpha1.ConditionReasonReconcileError,
+			Message:            err.Error(),
+		}) || changed
+		changed = meta.SetStatusCondition(conditions, v1.Condition{
+			Type:               githubv1alpha1.ConditionTypeDegraded,
+			Status:             v1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             githubv1alpha1.ConditionReasonReconcileError,
+			Message:            err.Error(),
+		}) || changed
+		return changed
+	}
+
+	if len(skipped) > 0 {
+		changed = meta.SetStatusCondition(conditions, v1.Condition{
+			Type:               githubv1alpha1.ConditionTypeReady,
+			Status:             v1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             githubv1alpha1.ConditionReasonChangesNotApplied,
+			Message:            fmt.Sprintf("%d change(s) not applied: %s", len(skipped), strings.Join(skipped, "; ")),
+		}) || changed
+	} else {
+		changed = meta.SetStatusCondition(conditions, v1.Condition{
+			Type:               githubv1alpha1.ConditionTypeReady,
+			Status:             v1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             githubv1alpha1.ConditionReasonReconcileSuccess,
+			Message:            "GitHub resource matches the spec",
+		}) || changed
+	}
+	changed = meta.SetStatusCondition(conditions, v1.Condition{
+		Type:               githubv1alpha1.ConditionTypeSynced,
+		Status:             v1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             githubv1alpha1.ConditionReasonReconcileSuccess,
+		Message:            "Reconcile succeeded",
+	}) || changed
+	changed = meta.SetStatusCondition(conditions, v1.Condition{
+		Type:               githubv1alpha1.ConditionTypeDegraded,
+		Status:             v1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             githubv1alpha1.ConditionReasonReconcileSuccess,
+		Message:            "Reconcile succeeded",
+	}) || changed
+	return changed
+}
+
+// setDriftConditions records the drift detected by a reconcile in the resource status
+// and the Drifted condition. Returns true if either changed.
+func setDriftConditions(conditions *[]v1.Condition, drift *[]githubv1alpha1.FieldDrift, generation int64, detected []githubv1alpha1.FieldDrift) bool {
+	merged := mergeDrift(*drift, detected)
+	changed := !reflect.DeepEqual(merged, *drift)
+	*drift = merged
+
+	if len(merged) > 0 {
+		fields := []string{}
+		for _, d := range merged {
+			fields = append(fields, d.Field)
+		}
+		return meta.SetStatusCondition(conditions, v1.Condition{
+			Type:               githubv1alpha1.ConditionTypeDrifted,
+			Status:             v1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             githubv1alpha1.ConditionReasonDriftDetected,
+			Message:            fmt.Sprintf("%d field(s) differ from the spec: %s", len(fields), strings.Join(fields, ", ")),
+		}) || changed
+	}
+	return meta.SetStatusCondition(conditions, v1.Condition{
+		Type:               githubv1alpha1.ConditionTypeDrifted,
+		Status:             v1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             githubv1alpha1.ConditionReasonNoDrift,
+		Message:            "GitHub resource matches the spec",
+	}) || changed
+}
+
+// reportReconcileStatus is deferred by every reconciler once its resource has been fetched.
+// It emits a warning event if the reconcile failed and writes the standard conditions,
+// detected drift and planned mutations to the resource status when they change. Drift and
+// the plan are left as-is if the reconcile failed, since not every field may have been compared.
+func reportReconcileStatus(ctx context.Context, c client.Client, recorder record.EventRecorder, obj client.Object, conditions *[]v1.Condition, observedGeneration *int64, drift *[]githubv1alpha1.FieldDrift, plan *[]string, err error) {
+	log := log.FromContext(ctx)
+
+	var notReady *dependencyNotReadyError
+	if errors.As(err, &notReady) {
+		recorder.Event(obj, corev1.EventTypeNormal, eventReasonDependencyNotReady, err.Error())
+	} else if err != nil {
+		recorder.Event(obj, corev1.EventTypeWarning, eventReasonReconcileError, err.Error())
+	}
+
+	// the resource may already be gone once its finalizer is removed, unless
+	// removing it was held back by a planned deletion
+	skipped := skippedMutations(ctx)
+	if !obj.GetDeletionTimestamp().IsZero() && err == nil && len(skipped) == 0 {
+		return
+	}
+
+	changed := setReconcileConditions(conditions, observedGeneration, obj.GetGeneration(), skipped, err)
+	if detected, ok := recordedDrift(ctx); ok && err == nil {
+		changed = setDriftConditions(conditions, drift, obj.GetGeneration(), detected) || changed
+	}
+	if err == nil && !slices.Equal(*plan, skipped) {
+		*plan = skipped
+		changed = true
+		if len(skipped) > 0 {
+			recorder.Eventf(obj, corev1.EventTypeNormal, eventReasonPlanned, "%d change(s) planned: %s", len(skipped), strings.Join(skipped, "; "))
+		}
+	}
+	if changed {
+		if err := c.Status().Update(ctx, obj); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "error updating status conditions")
+		}
+	}
+}
@@ -16,11 +16,31 @@ limitations under the License.
 
 package controller
 
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+)
+
 type GitHubRequester interface {
 	TeamRequester
 	RepositoryRequester
 	OrganizationRequester
 	BranchProtectionRequester
+	RulesetRequester
+	EnvironmentRequester
+	ActionsSecretRequester
+	ActionsVariableRequester
+	WebhookRequester
+	DeployKeyRequester
 }
 
 // ptrNonNilAndNotEqualTo returns true if a is not nil and its underlying value does not equal b.
@@ -33,6 +53,14 @@ func ptrNonNilAndNotEqualTo[T comparable](a *T, b T) bool {
 	return *a != b
 }
 
+// ptrEqual returns true if a and b are both nil or point to equal values.
+func ptrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // returns if set(a) is equivalent to set(b)
 // inefficient if the slices are frequently compared since this constructs
 // a new set every time it is called
@@ -51,3 +79,68 @@ func cmpSlices[T comparable](a, b []T) bool {
 	}
 	return true
 }
+
+// WatchedSecretLabel opts a Kubernetes Secret into change notifications. Referenced Secrets are
+// read directly from the API server, but only Secrets with this label set to "true" are cached and
+// watched, so that rotating them is applied without waiting for the requeue interval.
+const WatchedSecretLabel = "github.github-operator.eczy.io/watch"
+
+// secretKeyValue reads the value of a key in a Kubernetes Secret
+func secretKeyValue(ctx context.Context, c client.Client, namespace string, ref corev1.SecretKeySelector) ([]byte, error) {
+	value, _, err := secretKeyValueAndHash(ctx, c, namespace, ref)
+	return value, err
+}
+
+// secretKeyValueAndHash reads the value of a key in a Kubernetes Secret, along with an
+// HMAC-SHA256 of the value keyed on the Secret's UID. The HMAC tells whether the value
+// changed without publishing a plain hash, which anyone able to read a resource's status
+// could brute-force against guessable values.
+func secretKeyValueAndHash(ctx context.Context, c client.Client, namespace string, ref corev1.SecretKeySelector) ([]byte, string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		return nil, "", err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, "", fmt.Errorf("key '%s' not found in secret '%s'", ref.Key, ref.Name)
+	}
+	return value, secretValueHash(secret.UID, value), nil
+}
+
+func secretValueHash(uid types.UID, value []byte) string {
+	mac := hmac.New(sha256.New, []byte(uid))
+	mac.Write(value)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// resolveDeletionPolicy returns the deletion policy of a resource, falling back to the
+// manager-wide default when the resource doesn't set one. Observed resources are
+// always orphaned since nothing is written to GitHub under the Observe policy.
+func resolveDeletionPolicy[T ~string](policy *T, managementPolicy githubv1alpha1.ManagementPolicy, deleteByDefault bool) T {
+	if managementPolicy == githubv1alpha1.ManagementPolicyObserve {
+		return T(githubv1alpha1.DeletionPolicyOrphan)
+	}
+	if policy != nil {
+		return *policy
+	}
+	if deleteByDefault {
+		return T(githubv1alpha1.DeletionPolicyDelete)
+	}
+	return T(githubv1alpha1.DeletionPolicyOrphan)
+}
+
+// resolveTransferPolicy returns the transfer policy of a repository, defaulting to Deny.
+func resolveTransferPolicy(policy *githubv1alpha1.TransferPolicy) githubv1alpha1.TransferPolicy {
+	if policy != nil {
+		return *policy
+	}
+	return githubv1alpha1.TransferPolicyDeny
+}
+
+// resolveManagementPolicy returns the management policy of a resource, defaulting to Full.
+func resolveManagementPolicy(policy *githubv1alpha1.ManagementPolicy) githubv1alpha1.ManagementPolicy {
+	if policy != nil {
+		return *policy
+	}
+	return githubv1alpha1.ManagementPolicyFull
+}
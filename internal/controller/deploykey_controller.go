@@ -0,0 +1,506 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+	"github.com/google/go-github/v60/github"
+)
+
+var (
+	deployKeyFinalizerName = "github.github-operator.eczy.io/deploy-key-finalizer"
+
+	// setting this annotation to a new value regenerates a generated key pair
+	deployKeyRotateAnnotation = "github.github-operator.eczy.io/rotate"
+)
+
+const (
+	deployKeyPublicKeySecretKey = "ssh-publickey"
+
+	// field index of DeployKeys by the name of the Secret they read their public key from
+	deployKeyPublicKeySecretField = ".spec.publicKeySecretRef.name"
+)
+
+type DeployKeyRequester interface {
+	GetDeployKey(ctx context.Context, owner, repo string, id int64) (*github.Key, error)
+	GetDeployKeys(ctx context.Context, owner, repo string) ([]*github.Key, error)
+	CreateDeployKey(ctx context.Context, owner, repo string, key *github.Key) (*github.Key, error)
+	DeleteDeployKey(ctx context.Context, owner, repo string, id int64) error
+}
+
+// DeployKeyReconciler reconciles a DeployKey object
+type DeployKeyReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	GitHubClient             DeployKeyRequester
+	Recorder                 record.EventRecorder
+	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
+	RequeueInterval          time.Duration
+}
+
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=deploykeys,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=deploykeys/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=deploykeys/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
+func (r *DeployKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := log.FromContext(ctx)
+
+	// fetch resource
+	key := &githubv1alpha1.DeployKey{}
+	if err := r.Get(ctx, req.NamespacedName, key); err != nil {
+		log.Error(err, "error fetching DeployKey resource")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub
+	managementPolicy := resolveManagementPolicy(key.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(key, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, key, &key.Status.Conditions, &key.Status.ObservedGeneration, &key.Status.Drift, &key.Status.Plan, err)
+	}()
+
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, key.Spec.ProviderRef, key.Spec.RepositoryOwner, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
+	// resolve the public key to register, generating a key pair if necessary
+	publicKey := ""
+	rotationPending := false
+	if key.DeletionTimestamp.IsZero() {
+		var err error
+		publicKey, rotationPending, err = r.resolvePublicKey(ctx, key)
+		if err != nil {
+			log.Error(err, "error resolving deploy key public key")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// try to fetch external resource
+	var observed *github.Key
+	if key.Status.Id != nil {
+		log.Info("getting deploy key by id", "id", *key.Status.Id)
+		ghKey, err := r.GitHubClient.GetDeployKey(ctx, key.Spec.RepositoryOwner, key.Spec.RepositoryName, *key.Status.Id)
+		if _, ok := err.(*gh.DeployKeyNotFoundError); ok {
+			log.Info(err.Error())
+		} else if err != nil {
+			log.Error(err, "error fetching GitHub deploy key")
+			return ctrl.Result{}, err
+		}
+		observed = ghKey
+	} else if publicKey != "" {
+		log.Info("getting deploy key by public key", "title", key.Spec.Title)
+		ghKey, err := r.getDeployKeyByPublicKey(ctx, key, publicKey)
+		if err != nil {
+			log.Error(err, "error fetching GitHub deploy keys")
+			return ctrl.Result{}, err
+		}
+		observed = ghKey
+	}
+
+	// if external resource does't exist and we aren't deleting the resource, create external resource
+	if observed == nil && key.DeletionTimestamp.IsZero() {
+		observed, err = r.createMissingDeployKey(ctx, key, managementPolicy, publicKey)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// handle finalizer
+	deletionPolicy := resolveDeletionPolicy(key.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion)
+	if key.DeletionTimestamp.IsZero() {
+		// not being deleted
+		if deletionPolicy != githubv1alpha1.DeletionPolicyOrphan && !controllerutil.ContainsFinalizer(key, deployKeyFinalizerName) {
+			controllerutil.AddFinalizer(key, deployKeyFinalizerName)
+			if err := r.Update(ctx, key); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if deletionPolicy == githubv1alpha1.DeletionPolicyOrphan && controllerutil.ContainsFinalizer(key, deployKeyFinalizerName) {
+			// the GitHub resource is left in place, so there is nothing to clean up
+			controllerutil.RemoveFinalizer(key, deployKeyFinalizerName)
+			if err := r.Update(ctx, key); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else if controllerutil.ContainsFinalizer(key, deployKeyFinalizerName) {
+		// being deleted
+		if deletionPolicy == githubv1alpha1.DeletionPolicyDelete && key.Status.Id != nil && observed != nil {
+			// if we have never resolved this resource before, don't
+			// touch external state
+			if !shouldMutate(ctx, "delete deploy key %s on %s/%s", key.Spec.Title, key.Spec.RepositoryOwner, key.Spec.RepositoryName) {
+				// keep the finalizer so the deletion is applied once the resource leaves dry-run mode
+				return ctrl.Result{}, nil
+			}
+			if err := r.deleteDeployKey(ctx, key); err != nil {
+				log.Error(err, "error deleting deploy key")
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(key, corev1.EventTypeNormal, eventReasonDeleted, "Deleted GitHub deploy key")
+		}
+
+		controllerutil.RemoveFinalizer(key, deployKeyFinalizerName)
+		if err := r.Update(ctx, key); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// external resource is gone or wasn't created under the management policy
+	if observed == nil {
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+	}
+
+	// update external resource
+	err = r.updateDeployKey(ctx, key, observed, publicKey, rotationPending)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DeployKeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &githubv1alpha1.DeployKey{}, deployKeyPublicKeySecretField, func(obj client.Object) []string {
+		key := obj.(*githubv1alpha1.DeployKey)
+		if key.Spec.PublicKeySecretRef == nil {
+			return nil
+		}
+		return []string{key.Spec.PublicKeySecretRef.Name}
+	})
+	if err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&githubv1alpha1.DeployKey{}).
+		Owns(&corev1.Secret{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.deployKeysForSecret)).
+		Complete(r)
+}
+
+// maps a Kubernetes Secret to the DeployKeys in the same namespace that read their public key from it
+func (r *DeployKeyReconciler) deployKeysForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	keys := &githubv1alpha1.DeployKeyList{}
+	if err := r.List(ctx, keys, client.InNamespace(obj.GetNamespace()), client.MatchingFields{deployKeyPublicKeySecretField: obj.GetName()}); err != nil {
+		log.Error(err, "error listing DeployKey resources")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, key := range keys.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: key.Namespace, Name: key.Name},
+		})
+	}
+	return requests
+}
+
+// createMissingDeployKey creates the GitHub deploy key of a DeployKey resource under the Full management
+// policy, unless it is in dry-run mode. It returns nil if the key isn't created.
+func (r *DeployKeyReconciler) createMissingDeployKey(ctx context.Context, key *githubv1alpha1.DeployKey, managementPolicy githubv1alpha1.ManagementPolicy, publicKey string) (*github.Key, error) {
+	log := log.FromContext(ctx)
+
+	if managementPolicy != githubv1alpha1.ManagementPolicyFull {
+		// only the Full management policy creates missing GitHub resources
+		skipMutation(ctx, "create deploy key %s on %s/%s", key.Spec.Title, key.Spec.RepositoryOwner, key.Spec.RepositoryName)
+		return nil, nil
+	}
+	if !shouldMutate(ctx, "create deploy key %s on %s/%s", key.Spec.Title, key.Spec.RepositoryOwner, key.Spec.RepositoryName) {
+		return nil, nil
+	}
+	ghKey, err := r.GitHubClient.CreateDeployKey(ctx, key.Spec.RepositoryOwner, key.Spec.RepositoryName, deployKeyToCreate(key, publicKey))
+	if err != nil {
+		log.Error(err, "error creating GitHub deploy key")
+		return nil, err
+	}
+	r.Recorder.Event(key, corev1.EventTypeNormal, eventReasonCreated, "Created GitHub deploy key")
+	return ghKey, nil
+}
+
+// Deploy keys cannot be edited, so any change is applied by replacing the key. rotationPending
+// reports that a requested rotation hasn't generated a new key pair yet.
+func (r *DeployKeyReconciler) updateDeployKey(ctx context.Context, key *githubv1alpha1.DeployKey, ghKey *github.Key, publicKey string, rotationPending bool) error {
+	log := log.FromContext(ctx)
+
+	owner := key.Spec.RepositoryOwner
+	repo := key.Spec.RepositoryName
+	needsReplace := false
+	replaced := false
+
+	// Key (a key pair that hasn't been generated yet can't be compared)
+	sameKey := publicKey == "" || normalizePublicKey(ghKey.GetKey()) == normalizePublicKey(publicKey)
+	if !sameKey {
+		log.Info("deploy key Key update", "from", ghKey.GetKey(), "to", publicKey)
+		recordDrift(ctx, "publicKey", publicKey, ghKey.GetKey())
+		needsReplace = true
+	}
+	// Title
+	if key.Spec.Title != ghKey.GetTitle() {
+		log.Info("deploy key Title update", "from", ghKey.GetTitle(), "to", key.Spec.Title)
+		recordDrift(ctx, "title", key.Spec.Title, ghKey.GetTitle())
+		needsReplace = true
+	}
+	// ReadOnly
+	readOnly := key.Spec.ReadOnly == nil || *key.Spec.ReadOnly
+	if readOnly != ghKey.GetReadOnly() {
+		log.Info("deploy key ReadOnly update", "from", ghKey.GetReadOnly(), "to", readOnly)
+		recordDrift(ctx, "readOnly", readOnly, ghKey.GetReadOnly())
+		needsReplace = true
+	}
+
+	// perform update if necessary
+	if needsReplace && shouldMutate(ctx, "replace deploy key %s on %s/%s", key.Spec.Title, owner, repo) {
+		log.Info("replacing deploy key", "id", ghKey.GetID())
+		// GitHub rejects a key that is already registered, so only create
+		// the new key first when the key material is changing
+		if sameKey {
+			if err := r.GitHubClient.DeleteDeployKey(ctx, owner, repo, ghKey.GetID()); err != nil {
+				log.Error(err, "error deleting deploy key", "id", ghKey.GetID())
+				return err
+			}
+		}
+		created, err := r.GitHubClient.CreateDeployKey(ctx, owner, repo, deployKeyToCreate(key, publicKey))
+		if err != nil {
+			log.Error(err, "error creating deploy key", "title", key.Spec.Title)
+			return err
+		}
+		if !sameKey {
+			if err := r.GitHubClient.DeleteDeployKey(ctx, owner, repo, ghKey.GetID()); err != nil {
+				log.Error(err, "error deleting deploy key", "id", ghKey.GetID())
+				return err
+			}
+		}
+		ghKey = created
+		replaced = true
+		r.Recorder.Event(key, corev1.EventTypeNormal, eventReasonUpdated, "Replaced GitHub deploy key")
+	}
+
+	// a rotation is only recorded once GitHub has the key pair generated for it
+	rotation := key.Status.Rotation
+	if !rotationPending && (!needsReplace || replaced) {
+		rotation = nil
+		if value, ok := key.GetAnnotations()[deployKeyRotateAnnotation]; ok {
+			rotation = github.String(value)
+		}
+	}
+
+	// a key recreated after it was deleted outside of the operator has a new ID
+	idChanged := !ptrEqual(ghKey.ID, key.Status.Id)
+	if needsReplace || idChanged || key.Status.LastUpdateTimestamp == nil || !ptrEqual(rotation, key.Status.Rotation) {
+		now := v1.Now()
+		key.Status = githubv1alpha1.DeployKeyStatus{
+			LastUpdateTimestamp: &now,
+			Id:                  ghKey.ID,
+			RepositoryOwner:     github.String(owner),
+			RepositoryName:      github.String(repo),
+			Title:               ghKey.Title,
+			ReadOnly:            ghKey.ReadOnly,
+			Key:                 ghKey.Key,
+			Verified:            ghKey.Verified,
+			CreatedAt:           (*v1.Time)(ghKey.CreatedAt),
+			LastUsed:            (*v1.Time)(ghKey.LastUsed),
+			Rotation:            rotation,
+			Conditions:          key.Status.Conditions,
+			ObservedGeneration:  key.Status.ObservedGeneration,
+			Drift:               key.Status.Drift,
+			Plan:                key.Status.Plan,
+		}
+
+		// update status
+		if err := r.Status().Update(ctx, key); err != nil {
+			log.Error(err, "error updating DeployKey status", "title", key.Spec.Title)
+		}
+	}
+	return nil
+}
+
+// returns the public key to register. If the DeployKey does not reference a public key,
+// a key pair is generated and stored in a Secret controlled by the DeployKey, and regenerated
+// whenever the rotation annotation changes. Key pairs are only generated when mutations are
+// allowed, otherwise the existing public key (if any) is returned and the rotation is reported
+// as pending.
+func (r *DeployKeyReconciler) resolvePublicKey(ctx context.Context, key *githubv1alpha1.DeployKey) (string, bool, error) {
+	log := log.FromContext(ctx)
+
+	if key.Spec.PublicKeySecretRef != nil {
+		value, err := secretKeyValue(ctx, r.Client, key.Namespace, *key.Spec.PublicKeySecretRef)
+		if err != nil {
+			return "", false, err
+		}
+		return strings.TrimSpace(string(value)), false, nil
+	}
+
+	secretName := key.Name + "-deploy-key"
+	if key.Spec.PrivateKeySecretName != nil {
+		secretName = *key.Spec.PrivateKeySecretName
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: secretName}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", false, err
+	}
+	exists := err == nil
+	if exists && !v1.IsControlledBy(secret, key) {
+		// never overwrite a private key the DeployKey doesn't control
+		return "", false, fmt.Errorf("secret %s/%s is not controlled by DeployKey %s", key.Namespace, secretName, key.Name)
+	}
+
+	rotation, rotate := key.GetAnnotations()[deployKeyRotateAnnotation]
+	rotate = rotate && !ptrEqual(&rotation, key.Status.Rotation)
+
+	publicKey := strings.TrimSpace(string(secret.Data[deployKeyPublicKeySecretKey]))
+	if exists && !rotate && publicKey != "" && len(secret.Data[corev1.SSHAuthPrivateKey]) > 0 {
+		return publicKey, false, nil
+	}
+
+	if !shouldMutate(ctx, "generate deploy key pair in secret %s/%s", key.Namespace, secretName) {
+		return publicKey, rotate, nil
+	}
+	log.Info("generating deploy key pair", "secret", secretName, "rotation", rotate)
+	publicKey, privateKey, err := generateDeployKeyPair(key.Spec.Title)
+	if err != nil {
+		return "", false, err
+	}
+
+	secret.Name = secretName
+	secret.Namespace = key.Namespace
+	secret.Type = corev1.SecretTypeSSHAuth
+	// the DeployKey owns the Secret, so it must be watched to regenerate a deleted key pair
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[WatchedSecretLabel] = "true"
+	secret.Data = map[string][]byte{
+		corev1.SSHAuthPrivateKey:    privateKey,
+		deployKeyPublicKeySecretKey: []byte(publicKey),
+	}
+	if err := controllerutil.SetControllerReference(key, secret, r.Scheme); err != nil {
+		return "", false, err
+	}
+	if exists {
+		err = r.Update(ctx, secret)
+	} else {
+		err = r.Create(ctx, secret)
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return publicKey, false, nil
+}
+
+func (r *DeployKeyReconciler) getDeployKeyByPublicKey(ctx context.Context, key *githubv1alpha1.DeployKey, publicKey string) (*github.Key, error) {
+	keys, err := r.GitHubClient.GetDeployKeys(ctx, key.Spec.RepositoryOwner, key.Spec.RepositoryName)
+	if err != nil {
+		return nil, err
+	}
+	for _, ghKey := range keys {
+		if normalizePublicKey(ghKey.GetKey()) == normalizePublicKey(publicKey) {
+			return ghKey, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *DeployKeyReconciler) deleteDeployKey(ctx context.Context, key *githubv1alpha1.DeployKey) error {
+	if key.Status.RepositoryOwner == nil {
+		return fmt.Errorf("deploy key RepositoryOwner is nil")
+	} else if key.Status.RepositoryName == nil {
+		return fmt.Errorf("deploy key RepositoryName is nil")
+	} else if key.Status.Id == nil {
+		return fmt.Errorf("deploy key Id is nil")
+	}
+	return r.GitHubClient.DeleteDeployKey(ctx, *key.Status.RepositoryOwner, *key.Status.RepositoryName, *key.Status.Id)
+}
+
+func deployKeyToCreate(key *githubv1alpha1.DeployKey, publicKey string) *github.Key {
+	return &github.Key{
+		Title:    github.String(key.Spec.Title),
+		Key:      github.String(publicKey),
+		ReadOnly: github.Bool(key.Spec.ReadOnly == nil || *key.Spec.ReadOnly),
+	}
+}
+
+// generates an ed25519 key pair, returning the public key in authorized_keys format
+// and the private key as a PEM encoded OpenSSH private key
+func generateDeployKeyPair(comment string) (string, []byte, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", nil, fmt.Errorf("generating ed25519 key: %w", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return "", nil, fmt.Errorf("encoding public key: %w", err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return "", nil, fmt.Errorf("encoding private key: %w", err)
+	}
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	return publicKey, pem.EncodeToMemory(block), nil
+}
+
+// strips the comment from an authorized_keys formatted public key, since GitHub
+// does not return it
+func normalizePublicKey(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return key
+	}
+	return fields[0] + " " + fields[1]
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+var _ = Describe("DeployKey Controller", func() {
+	const resourceName = "test-resource"
+	const testKeyTitle = "github-operator-test"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	keyPairNamespacedName := types.NamespacedName{
+		Name:      resourceName + "-deploy-key",
+		Namespace: "default",
+	}
+	testRepoName := ghTestResourcePrefix + "deploy-key-test-repo"
+
+	reconcileDeployKey := func(deleteOnResourceDeletion bool) {
+		controllerReconciler := &DeployKeyReconciler{
+			Client:                   k8sClient,
+			Scheme:                   k8sClient.Scheme(),
+			GitHubClient:             fakeGHClient,
+			Recorder:                 &record.FakeRecorder{},
+			DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		By("Creating a test repository")
+		_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+			Name: &testRepoName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Creating the custom resource for the Kind DeployKey")
+		resource := &githubv1alpha1.DeployKey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: githubv1alpha1.DeployKeySpec{
+				RepositoryOwner: testOrganization,
+				RepositoryName:  testRepoName,
+				Title:           testKeyTitle,
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the specific resource instance DeployKey")
+		cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.DeployKey{})
+		// envtest doesn't run the garbage collector that would delete the owned key pair
+		cleanUpResource(ctx, keyPairNamespacedName, &corev1.Secret{})
+
+		By("Cleaning up the test repository")
+		Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+	})
+
+	Context("When creating a DeployKey resource", func() {
+		It("should generate a key pair and create a GitHub deploy key", func() {
+			By("Reconciling the resource")
+			reconcileDeployKey(false)
+
+			By("Checking the generated key pair")
+			keyPair := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, keyPairNamespacedName, keyPair)).To(Succeed())
+			Expect(keyPair.Type).To(Equal(corev1.SecretTypeSSHAuth))
+			Expect(keyPair.Data).To(HaveKey(corev1.SSHAuthPrivateKey))
+			Expect(keyPair.Labels).To(HaveKeyWithValue(WatchedSecretLabel, "true"))
+			Expect(keyPair.Data).To(HaveKey(deployKeyPublicKeySecretKey))
+
+			By("Checking the DeployKey status")
+			resource := &githubv1alpha1.DeployKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Id).NotTo(BeNil())
+
+			By("Checking the GitHub deploy key")
+			ghKey, err := fakeGHClient.GetDeployKey(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghKey.GetTitle()).To(Equal(testKeyTitle))
+			Expect(ghKey.GetReadOnly()).To(BeTrue())
+			Expect(normalizePublicKey(ghKey.GetKey())).To(Equal(normalizePublicKey(string(keyPair.Data[deployKeyPublicKeySecretKey]))))
+		})
+	})
+
+	Context("When the GitHub deploy key drifts", func() {
+		It("should replace the GitHub deploy key when its title changes", func() {
+			By("Reconciling the resource")
+			reconcileDeployKey(false)
+			resource := &githubv1alpha1.DeployKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			oldId := *resource.Status.Id
+
+			By("Updating the DeployKey resource Spec title")
+			resource.Spec.Title = testKeyTitle + "-renamed"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			By("Reconciling the resource")
+			reconcileDeployKey(false)
+
+			By("Checking the GitHub deploy key was replaced")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(*resource.Status.Id).NotTo(Equal(oldId))
+			_, err := fakeGHClient.GetDeployKey(ctx, testOrganization, testRepoName, oldId)
+			Expect(err).To(BeAssignableToTypeOf(&gh.DeployKeyNotFoundError{}))
+			ghKey, err := fakeGHClient.GetDeployKey(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghKey.GetTitle()).To(Equal(testKeyTitle + "-renamed"))
+
+			By("Checking the drift is reported")
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "title")))
+		})
+
+		It("should recreate a GitHub deploy key deleted outside of the operator", func() {
+			By("Reconciling the resource")
+			reconcileDeployKey(false)
+			resource := &githubv1alpha1.DeployKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			oldId := *resource.Status.Id
+
+			By("Deleting the GitHub deploy key outside of the operator")
+			Expect(fakeGHClient.DeleteDeployKey(ctx, testOrganization, testRepoName, oldId)).To(Succeed())
+
+			By("Reconciling the resource")
+			reconcileDeployKey(false)
+
+			By("Checking the DeployKey status follows the new GitHub deploy key")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(*resource.Status.Id).NotTo(Equal(oldId))
+			ghKey, err := fakeGHClient.GetDeployKey(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghKey.GetTitle()).To(Equal(testKeyTitle))
+		})
+	})
+
+	Context("When a DeployKey resource is in dry-run mode", func() {
+		setDryRun := func(dryRun bool) {
+			resource := &githubv1alpha1.DeployKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			if dryRun {
+				resource.SetAnnotations(map[string]string{dryRunAnnotation: "true"})
+			} else {
+				resource.SetAnnotations(nil)
+			}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		}
+
+		It("should plan the GitHub deploy key without creating it", func() {
+			setDryRun(true)
+
+			By("Reconciling the resource")
+			reconcileDeployKey(false)
+
+			By("Checking the plan in the DeployKey status")
+			resource := &githubv1alpha1.DeployKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Id).To(BeNil())
+			Expect(resource.Status.Plan).To(ConsistOf(
+				"generate deploy key pair in secret "+keyPairNamespacedName.String(),
+				"create deploy key "+testKeyTitle+" on "+testOrganization+"/"+testRepoName,
+			))
+
+			By("Checking no key pair was generated")
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, keyPairNamespacedName, &corev1.Secret{}))).To(BeTrue())
+			Expect(resource.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", githubv1alpha1.ConditionTypeReady),
+				HaveField("Reason", githubv1alpha1.ConditionReasonChangesNotApplied),
+			)))
+
+			By("Checking no GitHub deploy key was created")
+			ghKeys, err := fakeGHClient.GetDeployKeys(ctx, testOrganization, testRepoName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghKeys).To(BeEmpty())
+
+			By("Leaving dry-run mode")
+			setDryRun(false)
+			reconcileDeployKey(false)
+
+			By("Checking the GitHub deploy key was created")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Id).NotTo(BeNil())
+			Expect(resource.Status.Plan).To(BeEmpty())
+			_, err = fakeGHClient.GetDeployKey(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should keep the GitHub deploy key and the finalizer when deleting the resource", func() {
+			By("Reconciling the resource")
+			reconcileDeployKey(true)
+			resource := &githubv1alpha1.DeployKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			id := *resource.Status.Id
+			setDryRun(true)
+
+			By("Deleting the resource")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource, &client.DeleteOptions{
+				GracePeriodSeconds: &deletionGracePeriod,
+			})).To(Succeed())
+			reconcileDeployKey(true)
+
+			By("Checking the finalizer is kept")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(deployKeyFinalizerName))
+
+			By("Checking the GitHub deploy key still exists")
+			_, err := fakeGHClient.GetDeployKey(ctx, testOrganization, testRepoName, id)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When deleting a DeployKey resource", func() {
+		It("should delete the GitHub deploy key with the resource", func() {
+			By("Reconciling the resource")
+			reconcileDeployKey(true)
+			resource := &githubv1alpha1.DeployKey{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(deployKeyFinalizerName))
+			id := *resource.Status.Id
+
+			By("Deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource, &client.DeleteOptions{
+				GracePeriodSeconds: &deletionGracePeriod,
+			})).To(Succeed())
+			reconcileDeployKey(true)
+
+			By("Checking the resource is gone")
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking the GitHub deploy key is gone")
+			_, err = fakeGHClient.GetDeployKey(ctx, testOrganization, testRepoName, id)
+			Expect(err).To(BeAssignableToTypeOf(&gh.DeployKeyNotFoundError{}))
+		})
+	})
+})
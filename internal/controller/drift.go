@@ -0,0 +1,105 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+)
+
+// driftRecorder collects the fields of a GitHub resource that differ from the spec
+// during a single reconcile.
+type driftRecorder struct {
+	drift []githubv1alpha1.FieldDrift
+}
+
+type driftRecorderKey struct{}
+
+// withDriftRecorder returns a context carrying a new drift recorder.
+func withDriftRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, driftRecorderKey{}, &driftRecorder{})
+}
+
+// recordDrift records that field of the GitHub resource differs from the spec.
+// Pointers are dereferenced before desired and observed are formatted.
+func recordDrift(ctx context.Context, field string, desired, observed any) {
+	d, ok := ctx.Value(driftRecorderKey{}).(*driftRecorder)
+	if !ok {
+		return
+	}
+	drift := githubv1alpha1.FieldDrift{
+		Field:      field,
+		Desired:    driftValue(desired),
+		Observed:   driftValue(observed),
+		DetectedAt: v1.Now(),
+	}
+	for i := range d.drift {
+		if d.drift[i].Field == field {
+			d.drift[i] = drift
+			return
+		}
+	}
+	d.drift = append(d.drift, drift)
+}
+
+// recordedDrift returns the drift recorded in ctx, and false if ctx has no drift recorder.
+func recordedDrift(ctx context.Context) ([]githubv1alpha1.FieldDrift, bool) {
+	d, ok := ctx.Value(driftRecorderKey{}).(*driftRecorder)
+	if !ok {
+		return nil, false
+	}
+	return d.drift, true
+}
+
+func driftValue(v any) string {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// mergeDrift returns the current drift, keeping when a field was first detected
+// if it had already drifted to the same value.
+func mergeDrift(previous, current []githubv1alpha1.FieldDrift) []githubv1alpha1.FieldDrift {
+	if len(current) == 0 {
+		return nil
+	}
+	detectedAt := map[string]githubv1alpha1.FieldDrift{}
+	for _, drift := range previous {
+		detectedAt[drift.Field] = drift
+	}
+	merged := []githubv1alpha1.FieldDrift{}
+	for _, drift := range current {
+		if prev, ok := detectedAt[drift.Field]; ok && prev.Desired == drift.Desired && prev.Observed == drift.Observed {
+			drift.DetectedAt = prev.DetectedAt
+		}
+		merged = append(merged, drift)
+	}
+	return merged
+}
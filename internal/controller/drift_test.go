@@ -0,0 +1,117 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+)
+
+var _ = Describe("recordDrift", func() {
+	It("should do nothing without a recorder", func() {
+		ctx := context.Background()
+		recordDrift(ctx, "description", "foo", "bar")
+		_, ok := recordedDrift(ctx)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should dereference pointers and keep the last drift of a field", func() {
+		ctx := withDriftRecorder(context.Background())
+		recordDrift(ctx, "description", github.String("foo"), "bar")
+		recordDrift(ctx, "homepage", (*string)(nil), github.String("https://example.com"))
+		recordDrift(ctx, "description", "foo", "baz")
+
+		drift, ok := recordedDrift(ctx)
+		Expect(ok).To(BeTrue())
+		Expect(drift).To(HaveExactElements(
+			And(HaveField("Field", "description"), HaveField("Desired", "foo"), HaveField("Observed", "baz")),
+			And(HaveField("Field", "homepage"), HaveField("Desired", ""), HaveField("Observed", "https://example.com")),
+		))
+	})
+})
+
+var _ = Describe("setDriftConditions", func() {
+	earlier := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	now := metav1.NewTime(time.Now().Truncate(time.Second))
+
+	It("should report drifted fields in the Drifted condition", func() {
+		conditions := []metav1.Condition{}
+		drift := []githubv1alpha1.FieldDrift{}
+
+		Expect(setDriftConditions(&conditions, &drift, 2, []githubv1alpha1.FieldDrift{
+			{Field: "description", Desired: "foo", Observed: "bar", DetectedAt: now},
+			{Field: "homepage", Desired: "https://example.com", DetectedAt: now},
+		})).To(BeTrue())
+
+		Expect(drift).To(HaveLen(2))
+		condition := meta.FindStatusCondition(conditions, githubv1alpha1.ConditionTypeDrifted)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(githubv1alpha1.ConditionReasonDriftDetected))
+		Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+		Expect(condition.Message).To(Equal("2 field(s) differ from the spec: description, homepage"))
+	})
+
+	It("should keep when a field was first detected while it drifts to the same value", func() {
+		conditions := []metav1.Condition{}
+		drift := []githubv1alpha1.FieldDrift{
+			{Field: "description", Desired: "foo", Observed: "bar", DetectedAt: earlier},
+			{Field: "homepage", Desired: "https://example.com", DetectedAt: earlier},
+		}
+		Expect(setDriftConditions(&conditions, &drift, 1, []githubv1alpha1.FieldDrift{
+			{Field: "description", Desired: "foo", Observed: "bar", DetectedAt: now},
+			{Field: "homepage", Desired: "https://example.com", Observed: "https://example.org", DetectedAt: now},
+		})).To(BeTrue())
+
+		Expect(drift).To(HaveExactElements(
+			And(HaveField("Field", "description"), HaveField("DetectedAt", earlier)),
+			And(HaveField("Field", "homepage"), HaveField("DetectedAt", now)),
+		))
+	})
+
+	It("should report nothing changed when the same drift is detected again", func() {
+		conditions := []metav1.Condition{}
+		drift := []githubv1alpha1.FieldDrift{}
+		detected := []githubv1alpha1.FieldDrift{{Field: "description", Desired: "foo", Observed: "bar", DetectedAt: earlier}}
+		Expect(setDriftConditions(&conditions, &drift, 1, detected)).To(BeTrue())
+
+		detected = []githubv1alpha1.FieldDrift{{Field: "description", Desired: "foo", Observed: "bar", DetectedAt: now}}
+		Expect(setDriftConditions(&conditions, &drift, 1, detected)).To(BeFalse())
+		Expect(drift).To(ConsistOf(HaveField("DetectedAt", earlier)))
+	})
+
+	It("should clear the drift once the GitHub resource matches the spec", func() {
+		conditions := []metav1.Condition{}
+		drift := []githubv1alpha1.FieldDrift{{Field: "description", Desired: "foo", Observed: "bar", DetectedAt: earlier}}
+
+		Expect(setDriftConditions(&conditions, &drift, 1, nil)).To(BeTrue())
+
+		Expect(drift).To(BeNil())
+		condition := meta.FindStatusCondition(conditions, githubv1alpha1.ConditionTypeDrifted)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(githubv1alpha1.ConditionReasonNoDrift))
+	})
+})
@@ -0,0 +1,513 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+	"github.com/google/go-github/v60/github"
+)
+
+var (
+	environmentFinalizerName = "github.github-operator.eczy.io/environment-finalizer"
+)
+
+const (
+	deploymentBranchPolicyTypeBranch = "branch"
+	deploymentBranchPolicyTypeTag    = "tag"
+)
+
+type EnvironmentRequester interface {
+	GetEnvironment(ctx context.Context, owner, repo, name string) (*github.Environment, error)
+	CreateUpdateEnvironment(ctx context.Context, owner, repo, name string, update *github.CreateUpdateEnvironment) (*github.Environment, error)
+	DeleteEnvironment(ctx context.Context, owner, repo, name string) error
+	GetDeploymentBranchPolicies(ctx context.Context, owner, repo, environment string) ([]*github.DeploymentBranchPolicy, error)
+	CreateDeploymentBranchPolicy(ctx context.Context, owner, repo, environment, pattern, policyType string) (*github.DeploymentBranchPolicy, error)
+	DeleteDeploymentBranchPolicy(ctx context.Context, owner, repo, environment string, id int64) error
+
+	// needed to resolve required reviewers
+	GetUser(ctx context.Context, login string) (*github.User, error)
+	GetTeamBySlug(ctx context.Context, org, slug string) (*github.Team, error)
+}
+
+// EnvironmentReconciler reconciles an Environment object
+type EnvironmentReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	GitHubClient             EnvironmentRequester
+	Recorder                 record.EventRecorder
+	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
+	RequeueInterval          time.Duration
+}
+
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=environments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=environments/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=environments/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
+func (r *EnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := log.FromContext(ctx)
+
+	// fetch resource
+	env := &githubv1alpha1.Environment{}
+	if err := r.Get(ctx, req.NamespacedName, env); err != nil {
+		log.Error(err, "error fetching Environment resource")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub
+	managementPolicy := resolveManagementPolicy(env.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(env, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, env, &env.Status.Conditions, &env.Status.ObservedGeneration, &env.Status.Drift, &env.Status.Plan, err)
+	}()
+
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, env.Spec.ProviderRef, env.Spec.RepositoryOwner, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
+	// try to fetch external resource
+	var observed *github.Environment
+	ghEnv, err := r.GitHubClient.GetEnvironment(ctx, env.Spec.RepositoryOwner, env.Spec.RepositoryName, env.Spec.Name)
+	if _, ok := err.(*gh.EnvironmentNotFoundError); ok {
+		log.Info(err.Error())
+	} else if err != nil {
+		log.Error(err, "error fetching GitHub environment")
+		return ctrl.Result{}, err
+	}
+	observed = ghEnv
+
+	// if external resource does't exist and we aren't deleting the resource, create external resource
+	if observed == nil && env.DeletionTimestamp.IsZero() {
+		if managementPolicy != githubv1alpha1.ManagementPolicyFull {
+			// only the Full management policy creates missing GitHub resources
+			skipMutation(ctx, "create environment %s on %s/%s", env.Spec.Name, env.Spec.RepositoryOwner, env.Spec.RepositoryName)
+		} else if shouldMutate(ctx, "create environment %s on %s/%s", env.Spec.Name, env.Spec.RepositoryOwner, env.Spec.RepositoryName) {
+			// rely on create then update
+			ghEnv, err := r.GitHubClient.CreateUpdateEnvironment(ctx, env.Spec.RepositoryOwner, env.Spec.RepositoryName, env.Spec.Name, &github.CreateUpdateEnvironment{})
+			if err != nil {
+				log.Error(err, "error creating GitHub environment")
+				return ctrl.Result{}, err
+			}
+			observed = ghEnv
+			r.Recorder.Event(env, corev1.EventTypeNormal, eventReasonCreated, "Created GitHub environment")
+		}
+	}
+
+	// handle finalizer
+	deletionPolicy := resolveDeletionPolicy(env.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion)
+	if env.DeletionTimestamp.IsZero() {
+		// not being deleted
+		if deletionPolicy != githubv1alpha1.DeletionPolicyOrphan && !controllerutil.ContainsFinalizer(env, environmentFinalizerName) {
+			controllerutil.AddFinalizer(env, environmentFinalizerName)
+			if err := r.Update(ctx, env); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if deletionPolicy == githubv1alpha1.DeletionPolicyOrphan && controllerutil.ContainsFinalizer(env, environmentFinalizerName) {
+			// the GitHub resource is left in place, so there is nothing to clean up
+			controllerutil.RemoveFinalizer(env, environmentFinalizerName)
+			if err := r.Update(ctx, env); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else if controllerutil.ContainsFinalizer(env, environmentFinalizerName) {
+		// being deleted
+		if deletionPolicy == githubv1alpha1.DeletionPolicyDelete && env.Status.Name != nil && observed != nil {
+			// if we have never resolved this resource before, don't
+			// touch external state
+			if !shouldMutate(ctx, "delete environment %s on %s/%s", env.Spec.Name, env.Spec.RepositoryOwner, env.Spec.RepositoryName) {
+				// keep the finalizer so the deletion is applied once the resource leaves dry-run mode
+				return ctrl.Result{}, nil
+			}
+			if err := r.deleteEnvironment(ctx, env); err != nil {
+				log.Error(err, "error deleting environment")
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(env, corev1.EventTypeNormal, eventReasonDeleted, "Deleted GitHub environment")
+		}
+
+		controllerutil.RemoveFinalizer(env, environmentFinalizerName)
+		if err := r.Update(ctx, env); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// external resource is gone or wasn't created under the management policy
+	if observed == nil {
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+	}
+
+	// update external resource
+	err = r.updateEnvironment(ctx, env, observed)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *EnvironmentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&githubv1alpha1.Environment{}).
+		Complete(r)
+}
+
+// environmentProtectionRules are the settings GitHub reports as protection rules of an environment
+type environmentProtectionRules struct {
+	waitTimer         int
+	preventSelfReview bool
+	reviewerUsers     []string
+	reviewerTeams     []string
+}
+
+// protection rules are only reported when they are enabled
+func observedProtectionRules(ghEnv *github.Environment) *environmentProtectionRules {
+	rules := &environmentProtectionRules{
+		reviewerUsers: []string{},
+		reviewerTeams: []string{},
+	}
+	for _, rule := range ghEnv.ProtectionRules {
+		switch rule.GetType() {
+		case "wait_timer":
+			rules.waitTimer = rule.GetWaitTimer()
+		case "required_reviewers":
+			rules.preventSelfReview = rule.GetPreventSelfReview()
+			for _, reviewer := range rule.Reviewers {
+				switch v := reviewer.Reviewer.(type) {
+				case *github.User:
+					rules.reviewerUsers = append(rules.reviewerUsers, v.GetLogin())
+				case *github.Team:
+					rules.reviewerTeams = append(rules.reviewerTeams, v.GetSlug())
+				}
+			}
+		}
+	}
+	return rules
+}
+
+// desiredDeploymentBranchPolicy returns the deployment branch policy of an environment spec, or nil
+// if deployments aren't restricted
+func desiredDeploymentBranchPolicy(env *githubv1alpha1.Environment) (*github.BranchPolicy, error) {
+	policy := env.Spec.DeploymentBranchPolicy
+	if policy == nil {
+		return nil, nil
+	}
+	if policy.ProtectedBranches && (len(policy.BranchPatterns) > 0 || len(policy.TagPatterns) > 0) {
+		return nil, fmt.Errorf("environment '%s' deployment branch policy cannot use both protected branches and patterns", env.Spec.Name)
+	}
+	return &github.BranchPolicy{
+		ProtectedBranches:    github.Bool(policy.ProtectedBranches),
+		CustomBranchPolicies: github.Bool(!policy.ProtectedBranches),
+	}, nil
+}
+
+// environmentUpdate compares an environment spec to the observed environment and returns the
+// update that reconciles them, and whether anything differs.
+func environmentUpdate(ctx context.Context, env *githubv1alpha1.Environment, ghEnv *github.Environment, observed *environmentProtectionRules, desiredPolicy *github.BranchPolicy) (*github.CreateUpdateEnvironment, bool) {
+	log := log.FromContext(ctx)
+
+	update := &github.CreateUpdateEnvironment{
+		WaitTimer:              github.Int(observed.waitTimer),
+		CanAdminsBypass:        github.Bool(ghEnv.GetCanAdminsBypass()),
+		PreventSelfReview:      github.Bool(observed.preventSelfReview),
+		DeploymentBranchPolicy: ghEnv.DeploymentBranchPolicy,
+	}
+	needsUpdate := false
+
+	// WaitTimer
+	if ptrNonNilAndNotEqualTo(env.Spec.WaitTimer, observed.waitTimer) {
+		log.Info("environment WaitTimer update", "from", observed.waitTimer, "to", env.Spec.WaitTimer)
+		recordDrift(ctx, "waitTimer", env.Spec.WaitTimer, observed.waitTimer)
+		update.WaitTimer = env.Spec.WaitTimer
+		needsUpdate = true
+	}
+	// PreventSelfReview
+	if ptrNonNilAndNotEqualTo(env.Spec.PreventSelfReview, observed.preventSelfReview) {
+		log.Info("environment PreventSelfReview update", "from", observed.preventSelfReview, "to", env.Spec.PreventSelfReview)
+		recordDrift(ctx, "preventSelfReview", env.Spec.PreventSelfReview, observed.preventSelfReview)
+		update.PreventSelfReview = env.Spec.PreventSelfReview
+		needsUpdate = true
+	}
+	// CanAdminsBypass
+	if ptrNonNilAndNotEqualTo(env.Spec.CanAdminsBypass, ghEnv.GetCanAdminsBypass()) {
+		log.Info("environment CanAdminsBypass update", "from", ghEnv.GetCanAdminsBypass(), "to", env.Spec.CanAdminsBypass)
+		recordDrift(ctx, "canAdminsBypass", env.Spec.CanAdminsBypass, ghEnv.GetCanAdminsBypass())
+		update.CanAdminsBypass = env.Spec.CanAdminsBypass
+		needsUpdate = true
+	}
+	// RequiredReviewerUsers
+	if !cmpSlices(env.Spec.RequiredReviewerUsers, observed.reviewerUsers) {
+		log.Info("environment RequiredReviewerUsers update", "from", observed.reviewerUsers, "to", env.Spec.RequiredReviewerUsers)
+		recordDrift(ctx, "requiredReviewerUsers", env.Spec.RequiredReviewerUsers, observed.reviewerUsers)
+		needsUpdate = true
+	}
+	// RequiredReviewerTeams
+	if !cmpSlices(env.Spec.RequiredReviewerTeams, observed.reviewerTeams) {
+		log.Info("environment RequiredReviewerTeams update", "from", observed.reviewerTeams, "to", env.Spec.RequiredReviewerTeams)
+		recordDrift(ctx, "requiredReviewerTeams", env.Spec.RequiredReviewerTeams, observed.reviewerTeams)
+		needsUpdate = true
+	}
+	// DeploymentBranchPolicy
+	if desiredPolicy == nil && ghEnv.DeploymentBranchPolicy != nil {
+		log.Info("environment DeploymentBranchPolicy update", "from", ghEnv.DeploymentBranchPolicy, "to", nil)
+		recordDrift(ctx, "deploymentBranchPolicy", nil, ghEnv.DeploymentBranchPolicy)
+		update.DeploymentBranchPolicy = nil
+		needsUpdate = true
+	} else if desiredPolicy != nil && (ghEnv.DeploymentBranchPolicy == nil ||
+		desiredPolicy.GetProtectedBranches() != ghEnv.DeploymentBranchPolicy.GetProtectedBranches() ||
+		desiredPolicy.GetCustomBranchPolicies() != ghEnv.DeploymentBranchPolicy.GetCustomBranchPolicies()) {
+		log.Info("environment DeploymentBranchPolicy update", "from", ghEnv.DeploymentBranchPolicy, "to", desiredPolicy)
+		recordDrift(ctx, "deploymentBranchPolicy", desiredPolicy, ghEnv.DeploymentBranchPolicy)
+		update.DeploymentBranchPolicy = desiredPolicy
+		needsUpdate = true
+	}
+
+	return update, needsUpdate
+}
+
+// resolves the required reviewers of an environment spec to GitHub users and teams
+func (r *EnvironmentReconciler) environmentReviewers(ctx context.Context, env *githubv1alpha1.Environment) ([]*github.EnvReviewers, error) {
+	log := log.FromContext(ctx)
+
+	reviewers := []*github.EnvReviewers{}
+	for _, login := range env.Spec.RequiredReviewerUsers {
+		user, err := r.GitHubClient.GetUser(ctx, login)
+		if err != nil {
+			log.Error(err, "error resolving environment reviewer", "user", login)
+			return nil, err
+		}
+		reviewers = append(reviewers, &github.EnvReviewers{Type: github.String("User"), ID: user.ID})
+	}
+	for _, slug := range env.Spec.RequiredReviewerTeams {
+		team, err := r.GitHubClient.GetTeamBySlug(ctx, env.Spec.RepositoryOwner, slug)
+		if err != nil {
+			log.Error(err, "error resolving environment reviewer", "team", slug)
+			return nil, err
+		}
+		reviewers = append(reviewers, &github.EnvReviewers{Type: github.String("Team"), ID: team.ID})
+	}
+	return reviewers, nil
+}
+
+func (r *EnvironmentReconciler) updateEnvironment(ctx context.Context, env *githubv1alpha1.Environment, ghEnv *github.Environment) error {
+	log := log.FromContext(ctx)
+
+	owner := env.Spec.RepositoryOwner
+	repo := env.Spec.RepositoryName
+
+	observed := observedProtectionRules(ghEnv)
+	desiredPolicy, err := desiredDeploymentBranchPolicy(env)
+	if err != nil {
+		return err
+	}
+	update, needsUpdate := environmentUpdate(ctx, env, ghEnv, observed, desiredPolicy)
+
+	// status reflects the observed environment unless it is updated below
+	statusWaitTimer := github.Int(observed.waitTimer)
+	statusPreventSelfReview := github.Bool(observed.preventSelfReview)
+	statusReviewerUsers := observed.reviewerUsers
+	statusReviewerTeams := observed.reviewerTeams
+
+	// perform update if necessary
+	if needsUpdate && shouldMutate(ctx, "update environment %s on %s/%s", env.Spec.Name, owner, repo) {
+		log.Info("updating environment", "name", env.Spec.Name)
+
+		reviewers, err := r.environmentReviewers(ctx, env)
+		if err != nil {
+			return err
+		}
+		update.Reviewers = reviewers
+
+		updated, err := r.GitHubClient.CreateUpdateEnvironment(ctx, owner, repo, env.Spec.Name, update)
+		if err != nil {
+			log.Error(err, "error updating environment", "name", env.Spec.Name)
+			return err
+		}
+		r.Recorder.Event(env, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub environment")
+		ghEnv = updated
+		statusWaitTimer = update.WaitTimer
+		statusPreventSelfReview = update.PreventSelfReview
+		statusReviewerUsers = env.Spec.RequiredReviewerUsers
+		statusReviewerTeams = env.Spec.RequiredReviewerTeams
+	}
+
+	// Deployment branch and tag patterns
+	var statusPolicy *githubv1alpha1.DeploymentBranchPolicy
+	if ghEnv.DeploymentBranchPolicy != nil {
+		statusPolicy = &githubv1alpha1.DeploymentBranchPolicy{ProtectedBranches: ghEnv.DeploymentBranchPolicy.GetProtectedBranches()}
+	}
+	if desiredPolicy.GetCustomBranchPolicies() && ghEnv.DeploymentBranchPolicy.GetCustomBranchPolicies() {
+		branchPatterns, tagPatterns, err := r.updateDeploymentBranchPolicies(ctx, env)
+		if err != nil {
+			return err
+		}
+		if !cmpSlices(branchPatterns, env.Status.DeploymentBranchPolicy.GetBranchPatterns()) ||
+			!cmpSlices(tagPatterns, env.Status.DeploymentBranchPolicy.GetTagPatterns()) {
+			needsUpdate = true
+		}
+		statusPolicy.BranchPatterns = branchPatterns
+		statusPolicy.TagPatterns = tagPatterns
+	}
+
+	if needsUpdate || env.Status.LastUpdateTimestamp == nil {
+		now := v1.Now()
+		env.Status = githubv1alpha1.EnvironmentStatus{
+			LastUpdateTimestamp:    &now,
+			Id:                     ghEnv.ID,
+			NodeId:                 ghEnv.NodeID,
+			RepositoryOwner:        github.String(owner),
+			RepositoryName:         github.String(repo),
+			Name:                   ghEnv.Name,
+			WaitTimer:              statusWaitTimer,
+			PreventSelfReview:      statusPreventSelfReview,
+			CanAdminsBypass:        ghEnv.CanAdminsBypass,
+			RequiredReviewerUsers:  statusReviewerUsers,
+			RequiredReviewerTeams:  statusReviewerTeams,
+			DeploymentBranchPolicy: statusPolicy,
+			CreatedAt:              (*v1.Time)(ghEnv.CreatedAt),
+			UpdatedAt:              (*v1.Time)(ghEnv.UpdatedAt),
+			Conditions:             env.Status.Conditions,
+			ObservedGeneration:     env.Status.ObservedGeneration,
+			Drift:                  env.Status.Drift,
+			Plan:                   env.Status.Plan,
+		}
+
+		// update status
+		if err := r.Status().Update(ctx, env); err != nil {
+			log.Error(err, "error updating Environment status", "name", env.Spec.Name)
+		}
+	}
+	return nil
+}
+
+// creates and deletes deployment branch policies so that they match the environment spec.
+// Returns the observed branch and tag patterns after any changes.
+func (r *EnvironmentReconciler) updateDeploymentBranchPolicies(ctx context.Context, env *githubv1alpha1.Environment) ([]string, []string, error) {
+	log := log.FromContext(ctx)
+
+	owner := env.Spec.RepositoryOwner
+	repo := env.Spec.RepositoryName
+
+	desired := map[string]map[string]struct{}{
+		deploymentBranchPolicyTypeBranch: {},
+		deploymentBranchPolicyTypeTag:    {},
+	}
+	for _, pattern := range env.Spec.DeploymentBranchPolicy.BranchPatterns {
+		desired[deploymentBranchPolicyTypeBranch][pattern] = struct{}{}
+	}
+	for _, pattern := range env.Spec.DeploymentBranchPolicy.TagPatterns {
+		desired[deploymentBranchPolicyTypeTag][pattern] = struct{}{}
+	}
+
+	policies, err := r.GitHubClient.GetDeploymentBranchPolicies(ctx, owner, repo, env.Spec.Name)
+	if err != nil {
+		log.Error(err, "error getting deployment branch policies")
+		return nil, nil, err
+	}
+
+	observed := map[string]map[string]struct{}{
+		deploymentBranchPolicyTypeBranch: {},
+		deploymentBranchPolicyTypeTag:    {},
+	}
+	for _, policy := range policies {
+		policyType := policy.GetType()
+		if policyType == "" {
+			policyType = deploymentBranchPolicyTypeBranch
+		}
+		if _, ok := desired[policyType][policy.GetName()]; !ok && shouldMutate(ctx, "delete environment %s deployment %s policy %s", env.Spec.Name, policyType, policy.GetName()) {
+			log.Info("deleting deployment branch policy", "environment", env.Spec.Name, "type", policyType, "pattern", policy.GetName())
+			if err := r.GitHubClient.DeleteDeploymentBranchPolicy(ctx, owner, repo, env.Spec.Name, policy.GetID()); err != nil {
+				log.Error(err, "error deleting deployment branch policy")
+				return nil, nil, err
+			}
+			continue
+		}
+		observed[policyType][policy.GetName()] = struct{}{}
+	}
+
+	for policyType, patterns := range desired {
+		for pattern := range patterns {
+			if _, ok := observed[policyType][pattern]; ok {
+				continue
+			}
+			if !shouldMutate(ctx, "create environment %s deployment %s policy %s", env.Spec.Name, policyType, pattern) {
+				continue
+			}
+			log.Info("creating deployment branch policy", "environment", env.Spec.Name, "type", policyType, "pattern", pattern)
+			if _, err := r.GitHubClient.CreateDeploymentBranchPolicy(ctx, owner, repo, env.Spec.Name, pattern, policyType); err != nil {
+				log.Error(err, "error creating deployment branch policy")
+				return nil, nil, err
+			}
+			observed[policyType][pattern] = struct{}{}
+		}
+	}
+
+	branchPatterns := []string{}
+	for pattern := range observed[deploymentBranchPolicyTypeBranch] {
+		branchPatterns = append(branchPatterns, pattern)
+	}
+	tagPatterns := []string{}
+	for pattern := range observed[deploymentBranchPolicyTypeTag] {
+		tagPatterns = append(tagPatterns, pattern)
+	}
+	sort.Strings(branchPatterns)
+	sort.Strings(tagPatterns)
+	return branchPatterns, tagPatterns, nil
+}
+
+func (r *EnvironmentReconciler) deleteEnvironment(ctx context.Context, env *githubv1alpha1.Environment) error {
+	if env.Status.RepositoryOwner == nil {
+		return fmt.Errorf("environment RepositoryOwner is nil")
+	} else if env.Status.RepositoryName == nil {
+		return fmt.Errorf("environment RepositoryName is nil")
+	} else if env.Status.Name == nil {
+		return fmt.Errorf("environment Name is nil")
+	}
+	return r.GitHubClient.DeleteEnvironment(ctx, *env.Status.RepositoryOwner, *env.Status.RepositoryName, *env.Status.Name)
+}
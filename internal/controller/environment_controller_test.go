@@ -0,0 +1,181 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+var _ = Describe("Environment Controller", func() {
+	const resourceName = "test-resource"
+	const testEnvironmentName = "production"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	testRepoName := ghTestResourcePrefix + "environment-test-repo"
+
+	reconcileEnvironment := func(deleteOnResourceDeletion bool) {
+		controllerReconciler := &EnvironmentReconciler{
+			Client:                   k8sClient,
+			Scheme:                   k8sClient.Scheme(),
+			GitHubClient:             fakeGHClient,
+			Recorder:                 &record.FakeRecorder{},
+			DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	expectEnvironmentMatchesSpec := func() {
+		ghEnv, err := fakeGHClient.GetEnvironment(ctx, testOrganization, testRepoName, testEnvironmentName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ghEnv.ProtectionRules).To(ContainElement(And(
+			HaveField("Type", github.String("wait_timer")),
+			HaveField("WaitTimer", github.Int(10)),
+		)))
+		Expect(ghEnv.ProtectionRules).To(ContainElement(And(
+			HaveField("Type", github.String("required_reviewers")),
+			HaveField("Reviewers", ContainElement(HaveField("Type", github.String("User")))),
+		)))
+		Expect(ghEnv.GetDeploymentBranchPolicy().GetCustomBranchPolicies()).To(BeTrue())
+
+		policies, err := fakeGHClient.GetDeploymentBranchPolicies(ctx, testOrganization, testRepoName, testEnvironmentName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policies).To(ConsistOf(HaveField("Name", github.String("release/*"))))
+	}
+
+	BeforeEach(func() {
+		By("Creating a test repository")
+		_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+			Name: &testRepoName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Creating the custom resource for the Kind Environment")
+		resource := &githubv1alpha1.Environment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: githubv1alpha1.EnvironmentSpec{
+				RepositoryOwner:       testOrganization,
+				RepositoryName:        testRepoName,
+				Name:                  testEnvironmentName,
+				WaitTimer:             github.Int(10),
+				RequiredReviewerUsers: []string{testUser},
+				DeploymentBranchPolicy: &githubv1alpha1.DeploymentBranchPolicy{
+					BranchPatterns: []string{"release/*"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the specific resource instance Environment")
+		cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Environment{})
+
+		By("Cleaning up the test repository")
+		Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+	})
+
+	Context("When creating an Environment resource", func() {
+		It("should create a GitHub environment", func() {
+			By("Reconciling the resource")
+			reconcileEnvironment(false)
+
+			By("Checking the Environment status")
+			resource := &githubv1alpha1.Environment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Id).NotTo(BeNil())
+			Expect(resource.Status.Name).To(Equal(github.String(testEnvironmentName)))
+
+			By("Checking the GitHub environment")
+			expectEnvironmentMatchesSpec()
+		})
+	})
+
+	Context("When the GitHub environment drifts", func() {
+		It("should correct drift of the GitHub environment", func() {
+			By("Reconciling the resource")
+			reconcileEnvironment(false)
+
+			By("Changing the GitHub environment outside of the operator")
+			_, err := fakeGHClient.CreateUpdateEnvironment(ctx, testOrganization, testRepoName, testEnvironmentName, &github.CreateUpdateEnvironment{
+				WaitTimer: github.Int(30),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Reconciling the resource")
+			reconcileEnvironment(false)
+
+			By("Checking the GitHub environment")
+			expectEnvironmentMatchesSpec()
+
+			By("Checking the drift is reported")
+			resource := &githubv1alpha1.Environment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "waitTimer")))
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "requiredReviewerUsers")))
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "deploymentBranchPolicy")))
+		})
+	})
+
+	Context("When deleting an Environment resource", func() {
+		It("should delete the GitHub environment with the resource", func() {
+			By("Reconciling the resource")
+			reconcileEnvironment(true)
+			resource := &githubv1alpha1.Environment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(environmentFinalizerName))
+
+			By("Deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource, &client.DeleteOptions{
+				GracePeriodSeconds: &deletionGracePeriod,
+			})).To(Succeed())
+			reconcileEnvironment(true)
+
+			By("Checking the resource is gone")
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking the GitHub environment is gone")
+			_, err = fakeGHClient.GetEnvironment(ctx, testOrganization, testRepoName, testEnvironmentName)
+			Expect(err).To(BeAssignableToTypeOf(&gh.EnvironmentNotFoundError{}))
+		})
+	})
+})
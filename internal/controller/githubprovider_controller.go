@@ -0,0 +1,123 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+)
+
+// GitHubProviderReconciler reconciles a GitHubProvider object. It only validates the
+// credentials; the clients themselves are built on demand by the resource reconcilers.
+type GitHubProviderReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	Providers *GitHubProviderClients
+}
+
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=githubproviders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=githubproviders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile checks that the credentials Secret of a GitHubProvider can be used to build
+// a GitHub client and reports the result in the provider status.
+func (r *GitHubProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	provider := &githubv1alpha1.GitHubProvider{}
+	if err := r.Get(ctx, req.NamespacedName, provider); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Providers.Forget(req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var authType *string
+	ref := provider.Spec.CredentialsSecretRef
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret)
+	if err == nil {
+		creds, credsErr := providerCredentials(secret)
+		if credsErr == nil {
+			t := creds.AuthType()
+			authType = &t
+		}
+		err = r.Providers.Validate(ctx, provider.Name)
+		err = errors.Join(credsErr, err)
+	}
+	if err != nil {
+		log.Error(err, "invalid GitHubProvider credentials")
+		r.Recorder.Event(provider, corev1.EventTypeWarning, eventReasonReconcileError, err.Error())
+	}
+
+	changed := setReconcileConditions(&provider.Status.Conditions, &provider.Status.ObservedGeneration, provider.Generation, nil, err)
+	if !ptrEqual(authType, provider.Status.AuthType) {
+		provider.Status.AuthType = authType
+		changed = true
+	}
+	if changed {
+		if err := r.Status().Update(ctx, provider); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	// a Secret that is fixed later triggers another reconcile through the watch
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GitHubProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&githubv1alpha1.GitHubProvider{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.providersForSecret)).
+		Complete(r)
+}
+
+// maps a Kubernetes Secret to the GitHubProviders that read their credentials from it
+func (r *GitHubProviderReconciler) providersForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	providers := &githubv1alpha1.GitHubProviderList{}
+	if err := r.List(ctx, providers); err != nil {
+		log.Error(err, "error listing GitHubProvider resources")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, provider := range providers.Items {
+		ref := provider.Spec.CredentialsSecretRef
+		if ref.Namespace == obj.GetNamespace() && ref.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: provider.Name},
+			})
+		}
+	}
+	return requests
+}
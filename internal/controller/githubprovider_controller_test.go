@@ -0,0 +1,215 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	"github.com/eczy/github-operator/internal/utils"
+)
+
+var _ = Describe("GitHubProvider Controller", func() {
+	const providerName = "test-provider"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name: providerName,
+	}
+	credentialsNamespacedName := types.NamespacedName{
+		Name:      "test-provider-credentials",
+		Namespace: "default",
+	}
+
+	// providers reach the in-memory GitHub API
+	newProviders := func() *GitHubProviderClients {
+		return NewGitHubProviderClients(k8sClient, http.DefaultTransport, utils.GitHubServer{BaseURL: fakeServer.URL})
+	}
+
+	reconcileProvider := func(providers *GitHubProviderClients) {
+		controllerReconciler := &GitHubProviderReconciler{
+			Client:    k8sClient,
+			Scheme:    k8sClient.Scheme(),
+			Recorder:  &record.FakeRecorder{},
+			Providers: providers,
+		}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	createCredentials := func(data map[string][]byte) {
+		By("Creating the credentials Secret")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      credentialsNamespacedName.Name,
+				Namespace: credentialsNamespacedName.Namespace,
+			},
+			Data: data,
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		By("Creating the custom resource for the Kind GitHubProvider")
+		resource := &githubv1alpha1.GitHubProvider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: providerName,
+			},
+			Spec: githubv1alpha1.GitHubProviderSpec{
+				CredentialsSecretRef: githubv1alpha1.SecretReference{
+					Name:      credentialsNamespacedName.Name,
+					Namespace: credentialsNamespacedName.Namespace,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the specific resource instance GitHubProvider")
+		cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.GitHubProvider{})
+		cleanUpResource(ctx, credentialsNamespacedName, &corev1.Secret{})
+	})
+
+	Context("When reconciling a GitHubProvider resource", func() {
+		It("should report token credentials as ready", func() {
+			createCredentials(map[string][]byte{providerTokenKey: []byte("test-token")})
+
+			By("Reconciling the resource")
+			reconcileProvider(newProviders())
+
+			By("Checking the GitHubProvider status")
+			resource := &githubv1alpha1.GitHubProvider{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, githubv1alpha1.ConditionTypeReady)).To(BeTrue())
+			Expect(resource.Status.AuthType).To(Equal(github.String("Token")))
+		})
+
+		It("should report a missing credentials Secret", func() {
+			By("Reconciling the resource")
+			reconcileProvider(newProviders())
+
+			By("Checking the GitHubProvider status")
+			resource := &githubv1alpha1.GitHubProvider{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, githubv1alpha1.ConditionTypeReady)).To(BeTrue())
+			Expect(resource.Status.AuthType).To(BeNil())
+		})
+
+		It("should become ready once invalid credentials are fixed", func() {
+			createCredentials(map[string][]byte{providerAppIdKey: []byte("not-a-number")})
+			providers := newProviders()
+
+			By("Reconciling the resource")
+			reconcileProvider(providers)
+
+			By("Checking the GitHubProvider status")
+			resource := &githubv1alpha1.GitHubProvider{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, githubv1alpha1.ConditionTypeReady)).To(BeTrue())
+
+			By("Fixing the credentials Secret")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, credentialsNamespacedName, secret)).To(Succeed())
+			secret.Data = map[string][]byte{providerTokenKey: []byte("test-token")}
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			By("Reconciling the resource")
+			reconcileProvider(providers)
+
+			By("Checking the GitHubProvider status")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, githubv1alpha1.ConditionTypeReady)).To(BeTrue())
+			Expect(resource.Status.AuthType).To(Equal(github.String("Token")))
+		})
+	})
+
+	Context("When a resource references a GitHubProvider", func() {
+		variableNamespacedName := types.NamespacedName{
+			Name:      "test-provider-variable",
+			Namespace: "default",
+		}
+		testRepoName := ghTestResourcePrefix + "provider-test-repo"
+
+		BeforeEach(func() {
+			createCredentials(map[string][]byte{providerTokenKey: []byte("test-token")})
+
+			By("Creating a test repository")
+			_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+				Name: &testRepoName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			cleanUpResource(ctx, variableNamespacedName, &githubv1alpha1.ActionsVariable{})
+
+			By("Cleaning up the test repository")
+			Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+		})
+
+		It("should reconcile the resource with the provider's client", func() {
+			By("Creating a resource that references the GitHubProvider")
+			variable := &githubv1alpha1.ActionsVariable{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      variableNamespacedName.Name,
+					Namespace: variableNamespacedName.Namespace,
+				},
+				Spec: githubv1alpha1.ActionsVariableSpec{
+					Name:           "PROVIDER_TEST",
+					Owner:          testOrganization,
+					RepositoryName: &testRepoName,
+					Value:          "from-provider",
+					ProviderRef:    &githubv1alpha1.ProviderReference{Name: providerName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, variable)).To(Succeed())
+
+			By("Reconciling the resource without a GitHub client of its own")
+			controllerReconciler := &ActionsVariableReconciler{
+				Client:    k8sClient,
+				Scheme:    k8sClient.Scheme(),
+				Recorder:  &record.FakeRecorder{},
+				Providers: newProviders(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: variableNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the GitHub actions variable")
+			ghVariable, err := fakeGHClient.GetRepositoryActionsVariable(ctx, testOrganization, testRepoName, "PROVIDER_TEST")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghVariable.Value).To(Equal("from-provider"))
+		})
+	})
+})
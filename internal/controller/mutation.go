@@ -0,0 +1,93 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunAnnotation puts a single resource in dry-run mode when set to "true".
+const dryRunAnnotation = "github.github-operator.eczy.io/dry-run"
+
+// resolveDryRun returns true if mutations for obj should only be planned, either because
+// the manager runs in dry-run mode or obj is annotated with dryRunAnnotation.
+func resolveDryRun(obj client.Object, dryRun bool) bool {
+	if dryRun {
+		return true
+	}
+	value, ok := obj.GetAnnotations()[dryRunAnnotation]
+	if !ok {
+		return false
+	}
+	annotated, err := strconv.ParseBool(value)
+	return err == nil && annotated
+}
+
+// mutationRecorder tracks the GitHub mutations a single reconcile would make.
+// Mutations are only sent to GitHub if apply is true, otherwise they are
+// collected as skipped so they can be reported in the resource status.
+type mutationRecorder struct {
+	apply   bool
+	skipped []string
+}
+
+type mutationRecorderKey struct{}
+
+// withMutationRecorder returns a context carrying a new mutation recorder.
+func withMutationRecorder(ctx context.Context, apply bool) context.Context {
+	return context.WithValue(ctx, mutationRecorderKey{}, &mutationRecorder{apply: apply})
+}
+
+func mutationRecorderFrom(ctx context.Context) *mutationRecorder {
+	m, _ := ctx.Value(mutationRecorderKey{}).(*mutationRecorder)
+	return m
+}
+
+// shouldMutate records a GitHub mutation described by format and args and returns
+// true if it should be sent to GitHub. Every GitHub write must be guarded by it.
+func shouldMutate(ctx context.Context, format string, args ...any) bool {
+	m := mutationRecorderFrom(ctx)
+	if m == nil {
+		return true
+	}
+	if !m.apply {
+		m.skipped = append(m.skipped, fmt.Sprintf(format, args...))
+	}
+	return m.apply
+}
+
+// skipMutation records a GitHub mutation that must not be sent to GitHub
+// regardless of the recorder, e.g. creating a resource that is only adopted.
+func skipMutation(ctx context.Context, format string, args ...any) {
+	if m := mutationRecorderFrom(ctx); m != nil {
+		m.skipped = append(m.skipped, fmt.Sprintf(format, args...))
+	}
+}
+
+// skippedMutations returns the mutations recorded but not sent to GitHub, sorted so
+// that the plan reported in status is stable across reconciles.
+func skippedMutations(ctx context.Context) []string {
+	if m := mutationRecorderFrom(ctx); m != nil && len(m.skipped) > 0 {
+		return slices.Sorted(slices.Values(m.skipped))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+)
+
+var _ = Describe("resolveDryRun", func() {
+	annotated := func(annotations map[string]string) *githubv1alpha1.Repository {
+		return &githubv1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	It("should put every resource in dry-run mode when the manager runs in dry-run mode", func() {
+		Expect(resolveDryRun(annotated(nil), true)).To(BeTrue())
+		Expect(resolveDryRun(annotated(map[string]string{dryRunAnnotation: "false"}), true)).To(BeTrue())
+	})
+
+	DescribeTable("should follow the dry-run annotation otherwise",
+		func(annotations map[string]string, expected bool) {
+			Expect(resolveDryRun(annotated(annotations), false)).To(Equal(expected))
+		},
+		Entry("without the annotation", nil, false),
+		Entry("annotated true", map[string]string{dryRunAnnotation: "true"}, true),
+		Entry("annotated false", map[string]string{dryRunAnnotation: "false"}, false),
+		Entry("annotated with a value that isn't a boolean", map[string]string{dryRunAnnotation: "yes please"}, false),
+	)
+})
+
+var _ = Describe("shouldMutate", func() {
+	It("should apply mutations without a recorder", func() {
+		ctx := context.Background()
+		Expect(shouldMutate(ctx, "create repository %s", "foo")).To(BeTrue())
+		Expect(skippedMutations(ctx)).To(BeNil())
+	})
+
+	It("should apply mutations the recorder applies without planning them", func() {
+		ctx := withMutationRecorder(context.Background(), true)
+		Expect(shouldMutate(ctx, "create repository %s", "foo")).To(BeTrue())
+		Expect(skippedMutations(ctx)).To(BeNil())
+	})
+
+	It("should plan mutations the recorder doesn't apply, sorted", func() {
+		ctx := withMutationRecorder(context.Background(), false)
+		Expect(shouldMutate(ctx, "update repository %s", "foo")).To(BeFalse())
+		Expect(shouldMutate(ctx, "create repository %s", "bar")).To(BeFalse())
+		Expect(skippedMutations(ctx)).To(Equal([]string{"create repository bar", "update repository foo"}))
+	})
+
+	It("should plan mutations that are skipped even when the recorder applies them", func() {
+		ctx := withMutationRecorder(context.Background(), true)
+		skipMutation(ctx, "create repository %s", "foo")
+		Expect(skippedMutations(ctx)).To(Equal([]string{"create repository foo"}))
+	})
+})
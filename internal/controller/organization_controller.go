@@ -18,14 +18,20 @@ package controller
 
 import (
 	"context"
-	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
 	gh "github.com/eczy/github-operator/internal/github"
@@ -36,20 +42,40 @@ type OrganizationRequester interface {
 	GetOrganization(ctx context.Context, org string) (*github.Organization, error)
 	GetOrganizationByNodeId(ctx context.Context, nodeId string) (*github.Organization, error)
 	UpdateOrganization(ctx context.Context, org string, updateOrg *github.Organization) (*github.Organization, error)
+
+	GetOrganizationMemberships(ctx context.Context, org string) ([]*gh.OrganizationMembership, error)
+	GetOrganizationInvitations(ctx context.Context, org string) ([]*github.Invitation, error)
+	GetOrganizationFailedInvitations(ctx context.Context, org string) ([]*github.Invitation, error)
+	UpdateOrganizationMembership(ctx context.Context, org, user, role string) (*github.Membership, error)
+	RemoveOrganizationMembership(ctx context.Context, org, user string) error
+	CancelOrganizationInvitation(ctx context.Context, org string, invitationId int64) error
+	GetViewerLogin(ctx context.Context) (string, error)
 }
 
+const (
+	organizationRoleMember = "member"
+	organizationRoleAdmin  = "admin"
+)
+
 // OrganizationReconciler reconciles a Organization object
 type OrganizationReconciler struct {
 	client.Client
 	Scheme                   *runtime.Scheme
 	GitHubClient             OrganizationRequester
+	Recorder                 record.EventRecorder
 	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
 	RequeueInterval          time.Duration
+	// GitHubEvents optionally triggers reconciles for resources changed on GitHub,
+	// see GitHubEventReceiver.
+	GitHubEvents <-chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=organizations,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=organizations/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=organizations/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -60,13 +86,9 @@ type OrganizationReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
-func (r *OrganizationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *OrganizationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := log.FromContext(ctx)
 
-	if r.GitHubClient == nil {
-		return ctrl.Result{}, fmt.Errorf("nil GitHub client")
-	}
-
 	// fetch resource
 	org := &githubv1alpha1.Organization{}
 	if err := r.Get(ctx, req.NamespacedName, org); err != nil {
@@ -74,6 +96,26 @@ func (r *OrganizationReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub.
+	// Organizations can't be created, so Adopt and Full behave the same.
+	managementPolicy := resolveManagementPolicy(org.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(org, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, org, &org.Status.Conditions, &org.Status.ObservedGeneration, &org.Status.Drift, &org.Status.Plan, err)
+	}()
+
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, org.Spec.ProviderRef, org.Spec.Login, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
 	var observed *github.Organization
 	// try to fetch external resource
 	if org.Status.NodeId != nil {
@@ -103,19 +145,30 @@ func (r *OrganizationReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// update external resource
-	err := r.updateOrganization(ctx, org, observed)
+	err = r.updateOrganization(ctx, org, observed)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// update members
+	if org.Spec.Membership != nil {
+		err := r.updateOrganizationMembership(ctx, org)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OrganizationReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&githubv1alpha1.Organization{}).
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&githubv1alpha1.Organization{})
+	if r.GitHubEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.GitHubEvents, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
 
 // updates both args in place
@@ -131,206 +184,489 @@ func (r *OrganizationReconciler) updateOrganization(ctx context.Context, organiz
 	// name
 	if ptrNonNilAndNotEqualTo(organization.Spec.Name, ghOrganization.GetName()) {
 		log.Info("organization name update", "from", ghOrganization.GetName(), "to", organization.Spec.Name)
+		recordDrift(ctx, "name", organization.Spec.Name, ghOrganization.GetName())
 		updateOrg.Name = organization.Spec.Name
 		needsUpdate = true
 	}
 	// billing email
 	if ptrNonNilAndNotEqualTo(organization.Spec.BillingEmail, ghOrganization.GetBillingEmail()) {
 		log.Info("organization billing email update", "from", ghOrganization.GetBillingEmail(), "to", organization.Spec.BillingEmail)
+		recordDrift(ctx, "billingEmail", organization.Spec.BillingEmail, ghOrganization.GetBillingEmail())
 		updateOrg.BillingEmail = organization.Spec.BillingEmail
 		needsUpdate = true
 	}
 	// company
 	if ptrNonNilAndNotEqualTo(organization.Spec.Company, ghOrganization.GetCompany()) {
 		log.Info("organization company update", "from", ghOrganization.GetCompany(), "to", organization.Spec.Company)
+		recordDrift(ctx, "company", organization.Spec.Company, ghOrganization.GetCompany())
 		updateOrg.Company = organization.Spec.Company
 		needsUpdate = true
 	}
 	// email
 	if ptrNonNilAndNotEqualTo(organization.Spec.Email, ghOrganization.GetEmail()) {
 		log.Info("organization email update", "from", ghOrganization.GetEmail(), "to", organization.Spec.Email)
+		recordDrift(ctx, "email", organization.Spec.Email, ghOrganization.GetEmail())
 		updateOrg.Email = organization.Spec.Email
 		needsUpdate = true
 	}
 	// twitter username
 	if ptrNonNilAndNotEqualTo(organization.Spec.TwitterUsername, ghOrganization.GetTwitterUsername()) {
 		log.Info("organization twitter username update", "from", ghOrganization.GetTwitterUsername(), "to", *organization.Spec.TwitterUsername)
+		recordDrift(ctx, "twitterUsername", *organization.Spec.TwitterUsername, ghOrganization.GetTwitterUsername())
 		updateOrg.TwitterUsername = organization.Spec.TwitterUsername
 		needsUpdate = true
 	}
 	// location
 	if ptrNonNilAndNotEqualTo(organization.Spec.Location, ghOrganization.GetLocation()) {
 		log.Info("organization location update", "from", ghOrganization.GetLocation(), "to", *organization.Spec.Location)
+		recordDrift(ctx, "location", *organization.Spec.Location, ghOrganization.GetLocation())
 		updateOrg.Location = organization.Spec.Location
 		needsUpdate = true
 	}
 	// description
 	if ptrNonNilAndNotEqualTo(organization.Spec.Description, ghOrganization.GetDescription()) {
 		log.Info("organization description update", "from", ghOrganization.GetDescription(), "to", *organization.Spec.Description)
+		recordDrift(ctx, "description", *organization.Spec.Description, ghOrganization.GetDescription())
 		updateOrg.Description = organization.Spec.Description
 		needsUpdate = true
 	}
 	// has organization projects
 	if ptrNonNilAndNotEqualTo(organization.Spec.HasOrganizationProjects, ghOrganization.GetHasOrganizationProjects()) {
 		log.Info("organization hasOrganizationProjects update", "from", ghOrganization.GetHasOrganizationProjects(), "to", *organization.Spec.HasOrganizationProjects)
+		recordDrift(ctx, "hasOrganizationProjects", *organization.Spec.HasOrganizationProjects, ghOrganization.GetHasOrganizationProjects())
 		updateOrg.HasOrganizationProjects = organization.Spec.HasOrganizationProjects
 		needsUpdate = true
 	}
 	// has repository projects
 	if ptrNonNilAndNotEqualTo(organization.Spec.HasRepositoryProjects, ghOrganization.GetHasRepositoryProjects()) {
 		log.Info("organization hasRepositoryProjects update", "from", ghOrganization.GetHasRepositoryProjects(), "to", *organization.Spec.HasRepositoryProjects)
+		recordDrift(ctx, "hasRepositoryProjects", *organization.Spec.HasRepositoryProjects, ghOrganization.GetHasRepositoryProjects())
 		updateOrg.HasRepositoryProjects = organization.Spec.HasRepositoryProjects
 		needsUpdate = true
 	}
 	// default repository permission
 	if ptrNonNilAndNotEqualTo(organization.Spec.DefaultRepositoryPermission, (githubv1alpha1.DefaultRepositoryPermission)(ghOrganization.GetDefaultRepoPermission())) {
 		log.Info("organization defaultRepositoryPermission update", "from", ghOrganization.GetDefaultRepoPermission(), "to", *organization.Spec.DefaultRepositoryPermission)
+		recordDrift(ctx, "defaultRepositoryPermission", *organization.Spec.DefaultRepositoryPermission, ghOrganization.GetDefaultRepoPermission())
 		updateOrg.DefaultRepoPermission = (*string)(organization.Spec.DefaultRepositoryPermission)
 		needsUpdate = true
 	}
 	// members can create repositories
 	if ptrNonNilAndNotEqualTo(organization.Spec.MembersCanCreateRepositories, ghOrganization.GetMembersCanCreateRepos()) {
 		log.Info("organization membersCanCreateRepositories update", "from", ghOrganization.GetMembersCanCreateRepos(), "to", *organization.Spec.MembersCanCreateRepositories)
+		recordDrift(ctx, "membersCanCreateRepositories", *organization.Spec.MembersCanCreateRepositories, ghOrganization.GetMembersCanCreateRepos())
 		updateOrg.MembersCanCreateRepos = organization.Spec.MembersCanCreateRepositories
 		needsUpdate = true
 	}
 	// members can create internal repositories
 	if ptrNonNilAndNotEqualTo(organization.Spec.MembersCanCreateInternalRepositories, ghOrganization.GetMembersCanCreateInternalRepos()) {
 		log.Info("organization membersCanCreateInternalRepositories update", "from", ghOrganization.GetMembersCanCreateInternalRepos(), "to", *organization.Spec.MembersCanCreateInternalRepositories)
+		recordDrift(ctx, "membersCanCreateInternalRepositories", *organization.Spec.MembersCanCreateInternalRepositories, ghOrganization.GetMembersCanCreateInternalRepos())
 		updateOrg.MembersCanCreateInternalRepos = organization.Spec.MembersCanCreateInternalRepositories
 		needsUpdate = true
 	}
 	// members can create private repositories
 	if ptrNonNilAndNotEqualTo(organization.Spec.MembersCanCreatePrivateRepositories, ghOrganization.GetMembersCanCreatePrivateRepos()) {
 		log.Info("organization membersCanCreatePrivateRepositories update", "from", ghOrganization.GetMembersCanCreatePrivateRepos(), "to", *organization.Spec.MembersCanCreatePrivateRepositories)
+		recordDrift(ctx, "membersCanCreatePrivateRepositories", *organization.Spec.MembersCanCreatePrivateRepositories, ghOrganization.GetMembersCanCreatePrivateRepos())
 		updateOrg.MembersCanCreatePrivateRepos = organization.Spec.MembersCanCreatePrivateRepositories
 		needsUpdate = true
 	}
 	// members can create public repositories
 	if ptrNonNilAndNotEqualTo(organization.Spec.MembersCanCreatePublicRepositories, ghOrganization.GetMembersCanCreatePublicRepos()) {
 		log.Info("organization membersCanCreatePublicRepositories update", "from", ghOrganization.GetMembersCanCreatePublicRepos(), "to", *organization.Spec.MembersCanCreatePublicRepositories)
+		recordDrift(ctx, "membersCanCreatePublicRepositories", *organization.Spec.MembersCanCreatePublicRepositories, ghOrganization.GetMembersCanCreatePublicRepos())
 		updateOrg.MembersCanCreatePublicRepos = organization.Spec.MembersCanCreatePublicRepositories
 		needsUpdate = true
 	}
 	// members can create pages
 	if ptrNonNilAndNotEqualTo(organization.Spec.MembersCanCreatePages, ghOrganization.GetMembersCanCreatePages()) {
 		log.Info("organization membersCanCreatePages update", "from", ghOrganization.GetMembersCanCreatePages(), "to", *organization.Spec.MembersCanCreatePages)
+		recordDrift(ctx, "membersCanCreatePages", *organization.Spec.MembersCanCreatePages, ghOrganization.GetMembersCanCreatePages())
 		updateOrg.MembersCanCreatePages = organization.Spec.MembersCanCreatePages
 		needsUpdate = true
 	}
 	// members can create public pages
 	if ptrNonNilAndNotEqualTo(organization.Spec.MembersCanCreatePublicPages, ghOrganization.GetMembersCanCreatePublicPages()) {
 		log.Info("organization membersCanCreatePublicPages update", "from", ghOrganization.GetMembersCanCreatePublicPages(), "to", *organization.Spec.MembersCanCreatePublicPages)
+		recordDrift(ctx, "membersCanCreatePublicPages", *organization.Spec.MembersCanCreatePublicPages, ghOrganization.GetMembersCanCreatePublicPages())
 		updateOrg.MembersCanCreatePublicPages = organization.Spec.MembersCanCreatePublicPages
 		needsUpdate = true
 	}
 	// members can create private pages
 	if ptrNonNilAndNotEqualTo(organization.Spec.MembersCanCreatePrivatePages, ghOrganization.GetMembersCanCreatePrivatePages()) {
 		log.Info("organization membersCanCreatePrivatePages update", "from", ghOrganization.GetMembersCanCreatePrivatePages(), "to", *organization.Spec.MembersCanCreatePrivatePages)
+		recordDrift(ctx, "membersCanCreatePrivatePages", *organization.Spec.MembersCanCreatePrivatePages, ghOrganization.GetMembersCanCreatePrivatePages())
 		updateOrg.MembersCanCreatePrivatePages = organization.Spec.MembersCanCreatePrivatePages
 		needsUpdate = true
 	}
 	// members can fork private repositories
 	if ptrNonNilAndNotEqualTo(organization.Spec.MembersCanForkPrivateRepositories, ghOrganization.GetMembersCanForkPrivateRepos()) {
 		log.Info("organization membersCanForkPrivateRepositories update", "from", ghOrganization.GetMembersCanForkPrivateRepos(), "to", *organization.Spec.MembersCanForkPrivateRepositories)
+		recordDrift(ctx, "membersCanForkPrivateRepositories", *organization.Spec.MembersCanForkPrivateRepositories, ghOrganization.GetMembersCanForkPrivateRepos())
 		updateOrg.MembersCanForkPrivateRepos = organization.Spec.MembersCanForkPrivateRepositories
 		needsUpdate = true
 	}
 	// web commit signoff required
 	if ptrNonNilAndNotEqualTo(organization.Spec.WebCommitSignoffRequired, ghOrganization.GetWebCommitSignoffRequired()) {
 		log.Info("organization webCommitSignoffRequired update", "from", ghOrganization.GetWebCommitSignoffRequired(), "to", *organization.Spec.WebCommitSignoffRequired)
+		recordDrift(ctx, "webCommitSignoffRequired", *organization.Spec.WebCommitSignoffRequired, ghOrganization.GetWebCommitSignoffRequired())
 		updateOrg.WebCommitSignoffRequired = organization.Spec.WebCommitSignoffRequired
 		needsUpdate = true
 	}
 	// blog
 	if ptrNonNilAndNotEqualTo(organization.Spec.Blog, ghOrganization.GetBlog()) {
 		log.Info("organization blog update", "from", ghOrganization.GetBlog(), "to", *organization.Spec.Blog)
+		recordDrift(ctx, "blog", *organization.Spec.Blog, ghOrganization.GetBlog())
 		updateOrg.Blog = organization.Spec.Blog
 		needsUpdate = true
 	}
 	// advanced security enabled for new repositories
 	if ptrNonNilAndNotEqualTo(organization.Spec.AdvancedSecurityEnabledForNewRepositories, ghOrganization.GetAdvancedSecurityEnabledForNewRepos()) {
 		log.Info("organization advancedSecurityEnabledForNewRepositories update", "from", ghOrganization.GetAdvancedSecurityEnabledForNewRepos(), "to", *organization.Spec.AdvancedSecurityEnabledForNewRepositories)
+		recordDrift(ctx, "advancedSecurityEnabledForNewRepositories", *organization.Spec.AdvancedSecurityEnabledForNewRepositories, ghOrganization.GetAdvancedSecurityEnabledForNewRepos())
 		updateOrg.AdvancedSecurityEnabledForNewRepos = organization.Spec.AdvancedSecurityEnabledForNewRepositories
 		needsUpdate = true
 	}
 	// dependabot alerts enabled for new repositories
-	if ptrNonNilAndNotEqualTo(organization.Spec.DependabotAlertsEnabledForNewRepositories, ghOrganization.GetAdvancedSecurityEnabledForNewRepos()) {
-		log.Info("organization dependabotAlertsEnabledForNewRepositories update", "from", ghOrganization.GetAdvancedSecurityEnabledForNewRepos(), "to", *organization.Spec.DependabotAlertsEnabledForNewRepositories)
+	if ptrNonNilAndNotEqualTo(organization.Spec.DependabotAlertsEnabledForNewRepositories, ghOrganization.GetDependabotAlertsEnabledForNewRepos()) {
+		log.Info("organization dependabotAlertsEnabledForNewRepositories update", "from", ghOrganization.GetDependabotAlertsEnabledForNewRepos(), "to", *organization.Spec.DependabotAlertsEnabledForNewRepositories)
+		recordDrift(ctx, "dependabotAlertsEnabledForNewRepositories", *organization.Spec.DependabotAlertsEnabledForNewRepositories, ghOrganization.GetDependabotAlertsEnabledForNewRepos())
 		updateOrg.DependabotAlertsEnabledForNewRepos = organization.Spec.DependabotAlertsEnabledForNewRepositories
 		needsUpdate = true
 	}
 	// dependabot security updates enabled for new repositories
 	if ptrNonNilAndNotEqualTo(organization.Spec.DependabotSecurityUpdatesEnabledForNewRepositories, ghOrganization.GetDependabotSecurityUpdatesEnabledForNewRepos()) {
-		log.Info("organization dependabotSecurityUpdatesEnabledForNewRepositories update", "from", ghOrganization.GetDependabotSecurityUpdatesEnabledForNewRepos(), "to", *organization.Spec.DependabotAlertsEnabledForNewRepositories)
+		log.Info("organization dependabotSecurityUpdatesEnabledForNewRepositories update", "from", ghOrganization.GetDependabotSecurityUpdatesEnabledForNewRepos(), "to", *organization.Spec.DependabotSecurityUpdatesEnabledForNewRepositories)
+		recordDrift(ctx, "dependabotSecurityUpdatesEnabledForNewRepositories", *organization.Spec.DependabotSecurityUpdatesEnabledForNewRepositories, ghOrganization.GetDependabotSecurityUpdatesEnabledForNewRepos())
 		updateOrg.DependabotSecurityUpdatesEnabledForNewRepos = organization.Spec.DependabotSecurityUpdatesEnabledForNewRepositories
 		needsUpdate = true
 	}
 	// dependency graph enabled for new repositories
 	if ptrNonNilAndNotEqualTo(organization.Spec.DependencyGraphEnabledForNewRepositories, ghOrganization.GetDependencyGraphEnabledForNewRepos()) {
 		log.Info("organization dependencyGraphEnabledForNewRepositories update", "from", ghOrganization.GetDependencyGraphEnabledForNewRepos(), "to", *organization.Spec.DependencyGraphEnabledForNewRepositories)
+		recordDrift(ctx, "dependencyGraphEnabledForNewRepositories", *organization.Spec.DependencyGraphEnabledForNewRepositories, ghOrganization.GetDependencyGraphEnabledForNewRepos())
 		updateOrg.DependencyGraphEnabledForNewRepos = organization.Spec.DependencyGraphEnabledForNewRepositories
 		needsUpdate = true
 	}
 	// secret scanning enabled for new repositories
 	if ptrNonNilAndNotEqualTo(organization.Spec.SecretScanningEnabledForNewRepositories, ghOrganization.GetSecretScanningEnabledForNewRepos()) {
 		log.Info("organization secretScanningEnabledForNewRepositories update", "from", ghOrganization.GetSecretScanningEnabledForNewRepos(), "to", *organization.Spec.SecretScanningEnabledForNewRepositories)
+		recordDrift(ctx, "secretScanningEnabledForNewRepositories", *organization.Spec.SecretScanningEnabledForNewRepositories, ghOrganization.GetSecretScanningEnabledForNewRepos())
 		updateOrg.SecretScanningEnabledForNewRepos = organization.Spec.SecretScanningEnabledForNewRepositories
 		needsUpdate = true
 	}
 
 	// perform update if necessary
-	if needsUpdate || organization.Status.LastUpdateTimestamp == nil {
-		log.Info("updating organization", "login", organization.Spec.Login)
-		updated, err := r.GitHubClient.UpdateOrganization(ctx, *ghOrganization.Login, &updateOrg)
+	if needsUpdate {
+		updated, err := r.applyOrganizationUpdate(ctx, organization, ghOrganization, &updateOrg)
 		if err != nil {
-			log.Error(err, "unable to update organization", "login", organization.Spec.Login)
 			return err
 		}
 		ghOrganization = updated
+	}
+
+	// populate status from the updated organization, or the observed organization if it wasn't updated
+	if needsUpdate || organization.Status.LastUpdateTimestamp == nil {
+		r.updateOrganizationStatus(ctx, organization, ghOrganization)
+	}
+
+	return nil
+}
+
+// applies an organization update unless mutations are only recorded, and returns the organization as it is afterwards
+func (r *OrganizationReconciler) applyOrganizationUpdate(ctx context.Context, organization *githubv1alpha1.Organization, ghOrganization *github.Organization, updateOrg *github.Organization) (*github.Organization, error) {
+	log := log.FromContext(ctx)
 
-		now := v1.Now()
-		organization.Status = githubv1alpha1.OrganizationStatus{
-			Login:                                updated.Login,
-			NodeId:                               updated.NodeID,
-			LastUpdateTimestamp:                  &now,
-			Name:                                 updated.GetName(),
-			BillingEmail:                         updated.GetBillingEmail(),
-			Company:                              updated.GetCompany(),
-			Email:                                updated.GetEmail(),
-			TwitterUsername:                      updated.TwitterUsername,
-			Location:                             updated.Location,
-			Description:                          updated.Description,
-			HasOrganizationProjects:              updated.HasOrganizationProjects,
-			HasRepositoryProjects:                updated.HasRepositoryProjects,
-			DefaultRepositoryPermission:          (*githubv1alpha1.DefaultRepositoryPermission)(updated.DefaultRepoPermission),
-			MembersCanCreateRepositories:         updated.MembersCanCreateRepos,
-			MembersCanCreateInternalRepositories: updated.MembersCanCreateInternalRepos,
-			MembersCanCreatePrivateRepositories:  updated.MembersCanCreatePrivateRepos,
-			MembersCanCreatePublicRepositories:   updated.MembersCanCreatePublicRepos,
-			MembersCanCreatePages:                updated.MembersCanCreatePages,
-			MembersCanCreatePublicPages:          updated.MembersCanCreatePublicPages,
-			MembersCanCreatePrivatePages:         updated.MembersCanCreatePrivatePages,
-			MembersCanForkPrivateRepositories:    updated.MembersCanForkPrivateRepos,
-			WebCommitSignoffRequired:             updated.WebCommitSignoffRequired,
-			Blog:                                 updated.Blog,
-			AdvancedSecurityEnabledForNewRepositories:             updated.AdvancedSecurityEnabledForNewRepos,
-			DependabotAlertsEnabledForNewRepositories:             updated.DependabotAlertsEnabledForNewRepos,
-			DependabotSecurityUpdatesEnabledForNewRepositories:    updated.DependabotSecurityUpdatesEnabledForNewRepos,
-			DependencyGraphEnabledForNewRepositories:              updated.DependencyGraphEnabledForNewRepos,
-			SecretScanningEnabledForNewRepositories:               updated.SecretScanningEnabledForNewRepos,
-			SecretScanningPushProtectionEnabledForNewRepositories: updated.SecretScanningPushProtectionEnabledForNewRepos,
+	if !shouldMutate(ctx, "update organization %s", organization.Spec.Login) {
+		return ghOrganization, nil
+	}
+	log.Info("updating organization", "login", organization.Spec.Login)
+	updated, err := r.GitHubClient.UpdateOrganization(ctx, *ghOrganization.Login, updateOrg)
+	if err != nil {
+		log.Error(err, "unable to update organization", "login", organization.Spec.Login)
+		return nil, err
+	}
+	r.Recorder.Event(organization, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub organization")
+	return updated, nil
+}
+
+// populates organization status from the GitHub organization and updates it
+func (r *OrganizationReconciler) updateOrganizationStatus(ctx context.Context, organization *githubv1alpha1.Organization, ghOrganization *github.Organization) {
+	log := log.FromContext(ctx)
+
+	now := v1.Now()
+	organization.Status = githubv1alpha1.OrganizationStatus{
+		Login:                                ghOrganization.Login,
+		NodeId:                               ghOrganization.NodeID,
+		LastUpdateTimestamp:                  &now,
+		Name:                                 ghOrganization.GetName(),
+		BillingEmail:                         ghOrganization.GetBillingEmail(),
+		Company:                              ghOrganization.GetCompany(),
+		Email:                                ghOrganization.GetEmail(),
+		TwitterUsername:                      ghOrganization.TwitterUsername,
+		Location:                             ghOrganization.Location,
+		Description:                          ghOrganization.Description,
+		HasOrganizationProjects:              ghOrganization.HasOrganizationProjects,
+		HasRepositoryProjects:                ghOrganization.HasRepositoryProjects,
+		DefaultRepositoryPermission:          (*githubv1alpha1.DefaultRepositoryPermission)(ghOrganization.DefaultRepoPermission),
+		MembersCanCreateRepositories:         ghOrganization.MembersCanCreateRepos,
+		MembersCanCreateInternalRepositories: ghOrganization.MembersCanCreateInternalRepos,
+		MembersCanCreatePrivateRepositories:  ghOrganization.MembersCanCreatePrivateRepos,
+		MembersCanCreatePublicRepositories:   ghOrganization.MembersCanCreatePublicRepos,
+		MembersCanCreatePages:                ghOrganization.MembersCanCreatePages,
+		MembersCanCreatePublicPages:          ghOrganization.MembersCanCreatePublicPages,
+		MembersCanCreatePrivatePages:         ghOrganization.MembersCanCreatePrivatePages,
+		MembersCanForkPrivateRepositories:    ghOrganization.MembersCanForkPrivateRepos,
+		WebCommitSignoffRequired:             ghOrganization.WebCommitSignoffRequired,
+		Blog:                                 ghOrganization.Blog,
+		AdvancedSecurityEnabledForNewRepositories:             ghOrganization.AdvancedSecurityEnabledForNewRepos,
+		DependabotAlertsEnabledForNewRepositories:             ghOrganization.DependabotAlertsEnabledForNewRepos,
+		DependabotSecurityUpdatesEnabledForNewRepositories:    ghOrganization.DependabotSecurityUpdatesEnabledForNewRepos,
+		DependencyGraphEnabledForNewRepositories:              ghOrganization.DependencyGraphEnabledForNewRepos,
+		SecretScanningEnabledForNewRepositories:               ghOrganization.SecretScanningEnabledForNewRepos,
+		SecretScanningPushProtectionEnabledForNewRepositories: ghOrganization.SecretScanningPushProtectionEnabledForNewRepos,
+		Conditions:         organization.Status.Conditions,
+		ObservedGeneration: organization.Status.ObservedGeneration,
+		Drift:              organization.Status.Drift,
+		Plan:               organization.Status.Plan,
+	}
+
+	// update status
+	if err := r.Status().Update(ctx, organization); err != nil {
+		log.Error(err, "unable to update Organization status", "name", organization.Spec.Name)
+	}
+}
+
+// invites, promotes, demotes and (if pruning) removes organization members so that
+// the organization roster matches the Organization spec. Modifies organization status in place.
+func (r *OrganizationReconciler) updateOrganizationMembership(ctx context.Context, organization *githubv1alpha1.Organization) error {
+	log := log.FromContext(ctx)
+
+	login := organization.Spec.Login
+	if organization.Status.Login != nil {
+		login = *organization.Status.Login
+	}
+	spec := organization.Spec.Membership
+	prune := spec.Prune != nil && *spec.Prune
+
+	// logins are not case sensitive, so key everything on the lowercase login
+	specLogins := map[string]string{}
+	specRoles := map[string]string{}
+	for _, user := range spec.Members {
+		specLogins[strings.ToLower(user)] = user
+		specRoles[strings.ToLower(user)] = organizationRoleMember
+	}
+	for _, user := range spec.Admins {
+		specLogins[strings.ToLower(user)] = user
+		specRoles[strings.ToLower(user)] = organizationRoleAdmin
+	}
+
+	// never prune the identity the operator authenticates as, which would lock it out of the organization.
+	// GitHub App installations authenticate as a bot that can't be a member.
+	viewer := ""
+	if prune {
+		login, err := r.GitHubClient.GetViewerLogin(ctx)
+		if err != nil {
+			log.Error(err, "error getting authenticated user")
+			return err
 		}
+		viewer = login
+	}
 
-		// update status
-		if err := r.Status().Update(ctx, organization); err != nil {
-			log.Error(err, "unable to update Organization status", "name", organization.Spec.Name)
+	memberships, err := r.GitHubClient.GetOrganizationMemberships(ctx, login)
+	if err != nil {
+		log.Error(err, "error getting organization memberships")
+		return err
+	}
+	invitations, err := r.GitHubClient.GetOrganizationInvitations(ctx, login)
+	if err != nil {
+		log.Error(err, "error getting organization invitations")
+		return err
+	}
+	failedInvitations, err := r.GitHubClient.GetOrganizationFailedInvitations(ctx, login)
+	if err != nil {
+		log.Error(err, "error getting organization failed invitations")
+		return err
+	}
+
+	members, admins, observed, err := r.updateOrganizationMembers(ctx, login, memberships, specRoles, prune, viewer)
+	if err != nil {
+		return err
+	}
+	statusInvitations, pending, err := r.updateOrganizationInvitations(ctx, login, invitations, specRoles, prune)
+	if err != nil {
+		return err
+	}
+
+	for key, role := range specRoles {
+		if _, ok := observed[key]; ok {
+			continue
+		}
+		// don't resend invitations to users who haven't accepted yet
+		if _, ok := pending[key]; ok {
+			continue
 		}
+
+		user := specLogins[key]
+		if !shouldMutate(ctx, "invite %s to organization %s as %s", user, login, role) {
+			continue
+		}
+		log.Info("inviting organization member", "organization", login, "user", user, "role", role)
+		membership, err := r.GitHubClient.UpdateOrganizationMembership(ctx, login, user, role)
+		if err != nil {
+			log.Error(err, "error inviting organization member")
+			return err
+		}
+
+		if membership.GetState() == "pending" {
+			statusInvitations = append(statusInvitations, githubv1alpha1.OrganizationInvitation{
+				Login: github.String(user),
+				Role:  role,
+				State: githubv1alpha1.OrganizationInvitationStatePending,
+			})
+		} else if role == organizationRoleAdmin {
+			admins = append(admins, user)
+		} else {
+			members = append(members, user)
+		}
+	}
+
+	// failed invitations of listed users have been resent above, so only report the rest
+	for _, invitation := range failedInvitations {
+		if _, ok := specRoles[strings.ToLower(invitation.GetLogin())]; ok {
+			continue
+		}
+		statusInvitations = append(statusInvitations, organizationInvitationStatus(invitation, githubv1alpha1.OrganizationInvitationStateFailed))
+	}
+
+	sort.Strings(members)
+	sort.Strings(admins)
+	sort.Slice(statusInvitations, func(i, j int) bool {
+		return statusInvitations[i].Id < statusInvitations[j].Id
+	})
+
+	organization.Status.Members = members
+	organization.Status.Admins = admins
+	organization.Status.Invitations = statusInvitations
+	// update status
+	if err := r.Status().Update(ctx, organization); err != nil {
+		log.Error(err, "unable to update Organization status", "login", organization.Spec.Login)
 	}
 
 	return nil
 }
 
+// promotes, demotes and (if pruning) removes the members of an organization whose role differs from the
+// one in specRoles, never removing the viewer. Returns the resulting members and admins, and the lowercase
+// logins of all members observed.
+func (r *OrganizationReconciler) updateOrganizationMembers(ctx context.Context, login string, memberships []*gh.OrganizationMembership, specRoles map[string]string, prune bool, viewer string) (members, admins []string, observed map[string]struct{}, err error) {
+	log := log.FromContext(ctx)
+
+	members = []string{}
+	admins = []string{}
+	observed = map[string]struct{}{}
+	for _, membership := range memberships {
+		key := strings.ToLower(membership.Login)
+		observed[key] = struct{}{}
+
+		role, ok := specRoles[key]
+		if !ok {
+			if prune && !strings.EqualFold(membership.Login, viewer) && shouldMutate(ctx, "remove organization %s member %s", login, membership.Login) {
+				log.Info("removing organization member", "organization", login, "user", membership.Login)
+				if err := r.GitHubClient.RemoveOrganizationMembership(ctx, login, membership.Login); err != nil {
+					log.Error(err, "error removing organization member")
+					return nil, nil, nil, err
+				}
+				continue
+			}
+			// unmanaged members are reported as-is
+			role = membership.Role
+		} else if role != membership.Role {
+			if shouldMutate(ctx, "update organization %s member %s role from %s to %s", login, membership.Login, membership.Role, role) {
+				log.Info("updating organization member role", "organization", login, "user", membership.Login, "from", membership.Role, "to", role)
+				if _, err := r.GitHubClient.UpdateOrganizationMembership(ctx, login, membership.Login, role); err != nil {
+					log.Error(err, "error updating organization member role")
+					return nil, nil, nil, err
+				}
+			} else {
+				role = membership.Role
+			}
+		}
+
+		if role == organizationRoleAdmin {
+			admins = append(admins, membership.Login)
+		} else {
+			members = append(members, membership.Login)
+		}
+	}
+
+	return members, admins, observed, nil
+}
+
+// cancels the pending invitations of an organization that have an outdated role or (if pruning) are for
+// users not in specRoles. Returns the remaining invitations, and the lowercase logins they are for.
+func (r *OrganizationReconciler) updateOrganizationInvitations(ctx context.Context, login string, invitations []*github.Invitation, specRoles map[string]string, prune bool) (statusInvitations []githubv1alpha1.OrganizationInvitation, pending map[string]struct{}, err error) {
+	log := log.FromContext(ctx)
+
+	statusInvitations = []githubv1alpha1.OrganizationInvitation{}
+	pending = map[string]struct{}{}
+	for _, invitation := range invitations {
+		key := strings.ToLower(invitation.GetLogin())
+		// an invitation can't change role, so one with the wrong role is cancelled and sent again below
+		if role, ok := specRoles[key]; ok && invitation.Login != nil && organizationInvitationRole(invitation.GetRole()) != role &&
+			shouldMutate(ctx, "reissue organization %s invitation of %s as %s", login, invitation.GetLogin(), role) {
+			log.Info("cancelling organization invitation with outdated role", "organization", login, "user", invitation.GetLogin(), "role", invitation.GetRole())
+			if err := r.GitHubClient.CancelOrganizationInvitation(ctx, login, invitation.GetID()); err != nil {
+				log.Error(err, "error cancelling organization invitation")
+				return nil, nil, err
+			}
+			continue
+		}
+		// invitations sent by email have no login and are never pruned
+		if _, ok := specRoles[key]; !ok && prune && invitation.Login != nil && shouldMutate(ctx, "cancel organization %s invitation of %s", login, invitation.GetLogin()) {
+			log.Info("cancelling organization invitation", "organization", login, "user", invitation.GetLogin())
+			if err := r.GitHubClient.CancelOrganizationInvitation(ctx, login, invitation.GetID()); err != nil {
+				log.Error(err, "error cancelling organization invitation")
+				return nil, nil, err
+			}
+			continue
+		}
+		if invitation.Login != nil {
+			pending[key] = struct{}{}
+		}
+		statusInvitations = append(statusInvitations, organizationInvitationStatus(invitation, githubv1alpha1.OrganizationInvitationStatePending))
+	}
+
+	return statusInvitations, pending, nil
+}
+
+// organizationInvitationRole converts the role of an invitation to the equivalent membership role
+func organizationInvitationRole(role string) string {
+	if role == "direct_member" {
+		return organizationRoleMember
+	}
+	return role
+}
+
+func organizationInvitationStatus(invitation *github.Invitation, state string) githubv1alpha1.OrganizationInvitation {
+	out := githubv1alpha1.OrganizationInvitation{
+		Id:           invitation.GetID(),
+		Login:        invitation.Login,
+		Email:        invitation.Email,
+		Role:         invitation.GetRole(),
+		State:        state,
+		FailedReason: invitation.FailedReason,
+	}
+	if invitation.CreatedAt != nil {
+		out.CreatedAt = &v1.Time{Time: invitation.GetCreatedAt().Time}
+	}
+	if invitation.FailedAt != nil {
+		out.FailedAt = &v1.Time{Time: invitation.GetFailedAt().Time}
+	}
+	return out
+}
+
 // func (r *OrganizationReconciler) deleteOrganization(ctx context.Context, organization *githubv1alpha1.Organization) error {
 // 	if organization.Status.Login == nil {
 // 		return fmt.Errorf("organization login is nil")
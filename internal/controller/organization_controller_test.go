@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -65,6 +66,7 @@ var _ = Describe("Organization Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -102,6 +104,7 @@ var _ = Describe("Organization Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -135,6 +138,7 @@ var _ = Describe("Organization Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -153,4 +157,152 @@ var _ = Describe("Organization Controller", func() {
 		})
 		// TODO: other fields
 	})
+
+	Context("When an Organization resource manages membership", func() {
+		// users that are never added to the organization, so that they are invited
+		frank, grace := "org-membership-frank", "org-membership-grace"
+
+		controllerReconciler := &OrganizationReconciler{}
+
+		createOrganization := func(spec githubv1alpha1.OrganizationSpec) {
+			spec.Login = testOrganization
+			Expect(k8sClient.Create(ctx, &githubv1alpha1.Organization{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: spec,
+			})).To(Succeed())
+		}
+
+		reconcileOrganization := func() *githubv1alpha1.Organization {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &githubv1alpha1.Organization{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource
+		}
+
+		invitations := func() []*github.Invitation {
+			invitations, err := fakeGHClient.GetOrganizationInvitations(ctx, testOrganization)
+			Expect(err).NotTo(HaveOccurred())
+			return invitations
+		}
+
+		BeforeEach(func() {
+			for _, login := range []string{frank, grace} {
+				if _, err := fakeGHClient.GetUser(ctx, login); err != nil {
+					fakeServer.AddUser(login)
+				}
+			}
+			fakeServer.SetInvitations(true)
+			controllerReconciler = &OrganizationReconciler{
+				Client:       k8sClient,
+				Scheme:       k8sClient.Scheme(),
+				GitHubClient: fakeGHClient,
+				Recorder:     &record.FakeRecorder{},
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance Organization")
+			cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Organization{})
+
+			By("Cancelling the organization invitations")
+			for _, invitation := range invitations() {
+				Expect(fakeGHClient.CancelOrganizationInvitation(ctx, testOrganization, invitation.GetID())).To(Succeed())
+			}
+			fakeServer.SetInvitations(false)
+		})
+
+		It("should invite users that aren't members", func() {
+			createOrganization(githubv1alpha1.OrganizationSpec{
+				Membership: &githubv1alpha1.OrganizationMembership{
+					Members: []string{frank},
+					Admins:  []string{grace},
+				},
+			})
+
+			resource := reconcileOrganization()
+
+			Expect(invitations()).To(ConsistOf(
+				And(HaveField("Login", HaveValue(Equal(frank))), HaveField("Role", HaveValue(Equal("direct_member")))),
+				And(HaveField("Login", HaveValue(Equal(grace))), HaveField("Role", HaveValue(Equal("admin")))),
+			))
+			Expect(resource.Status.Invitations).To(ConsistOf(
+				And(HaveField("Login", HaveValue(Equal(frank))), HaveField("State", githubv1alpha1.OrganizationInvitationStatePending)),
+				And(HaveField("Login", HaveValue(Equal(grace))), HaveField("State", githubv1alpha1.OrganizationInvitationStatePending)),
+			))
+		})
+
+		It("should not resend pending invitations with the listed role", func() {
+			_, err := fakeGHClient.UpdateOrganizationMembership(ctx, testOrganization, frank, organizationRoleMember)
+			Expect(err).NotTo(HaveOccurred())
+			sent := invitations()
+			Expect(sent).To(HaveLen(1))
+			createOrganization(githubv1alpha1.OrganizationSpec{
+				Membership: &githubv1alpha1.OrganizationMembership{Members: []string{frank}},
+			})
+
+			resource := reconcileOrganization()
+
+			Expect(invitations()).To(Equal(sent))
+			Expect(resource.Status.Invitations).To(ConsistOf(
+				And(HaveField("Id", sent[0].GetID()), HaveField("Role", "direct_member")),
+			))
+		})
+
+		It("should reissue pending invitations with an outdated role", func() {
+			_, err := fakeGHClient.UpdateOrganizationMembership(ctx, testOrganization, frank, organizationRoleMember)
+			Expect(err).NotTo(HaveOccurred())
+			sent := invitations()
+			Expect(sent).To(HaveLen(1))
+			createOrganization(githubv1alpha1.OrganizationSpec{
+				Membership: &githubv1alpha1.OrganizationMembership{Admins: []string{frank}},
+			})
+
+			reconcileOrganization()
+
+			Expect(invitations()).To(ConsistOf(And(
+				HaveField("ID", HaveValue(Not(Equal(sent[0].GetID())))),
+				HaveField("Login", HaveValue(Equal(frank))),
+				HaveField("Role", HaveValue(Equal("admin"))),
+			)))
+
+			By("Leaving the reissued invitation alone")
+			reissued := invitations()
+			resource := reconcileOrganization()
+			Expect(invitations()).To(Equal(reissued))
+			Expect(resource.Status.Invitations).To(ConsistOf(
+				And(HaveField("Id", reissued[0].GetID()), HaveField("Role", "admin")),
+			))
+		})
+
+		It("should only plan reissuing invitations under the Observe management policy", func() {
+			_, err := fakeGHClient.UpdateOrganizationMembership(ctx, testOrganization, frank, organizationRoleMember)
+			Expect(err).NotTo(HaveOccurred())
+			sent := invitations()
+			observe := githubv1alpha1.ManagementPolicyObserve
+			createOrganization(githubv1alpha1.OrganizationSpec{
+				Membership:       &githubv1alpha1.OrganizationMembership{Admins: []string{frank}},
+				ManagementPolicy: &observe,
+			})
+
+			resource := reconcileOrganization()
+
+			Expect(invitations()).To(Equal(sent))
+			Expect(resource.Status.Plan).To(ContainElement("reissue organization " + testOrganization + " invitation of " + frank + " as admin"))
+		})
+	})
+})
+
+var _ = Describe("organizationInvitationRole", func() {
+	DescribeTable("should convert the role of an invitation to the equivalent membership role",
+		func(role string, expected string) {
+			Expect(organizationInvitationRole(role)).To(Equal(expected))
+		},
+		Entry("direct member", "direct_member", organizationRoleMember),
+		Entry("admin", "admin", organizationRoleAdmin),
+		Entry("billing manager", "billing_manager", "billing_manager"),
+	)
 })
@@ -0,0 +1,240 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+	"github.com/eczy/github-operator/internal/utils"
+)
+
+// Keys of a GitHubProvider credentials Secret.
+const (
+	providerTokenKey          = "token"
+	providerAppIdKey          = "appId"
+	providerInstallationIdKey = "installationId"
+	providerPrivateKeyKey     = "privateKey"
+)
+
+type providerClient struct {
+	generation    int64
+	secretVersion string
+	client        *gh.Client
+	// set instead of client for GitHub App credentials without an installation
+	installations *utils.GitHubAppClients
+}
+
+// GitHubProviderClients builds a GitHub client per GitHubProvider and caches it until
+// the provider or its credentials Secret changes.
+type GitHubProviderClients struct {
+	client.Client
+	Base http.RoundTripper
+	// The GitHub instance of providers that don't set their own URLs.
+	Server utils.GitHubServer
+	// The operator's own GitHub App if it has no fixed installation, used by resources
+	// without a providerRef.
+	Installations *utils.GitHubAppClients
+
+	mu      sync.Mutex
+	clients map[string]*providerClient
+}
+
+func NewGitHubProviderClients(c client.Client, base http.RoundTripper, server utils.GitHubServer) *GitHubProviderClients {
+	return &GitHubProviderClients{
+		Client:  c,
+		Base:    base,
+		Server:  server,
+		clients: map[string]*providerClient{},
+	}
+}
+
+// ResolveGitHubClient returns the client for a resource owned by the account with the given login.
+// That is the client of the resource's GitHubProvider if ref is set, or else the installation of
+// the operator's GitHub App on the account if the App discovers its installations. Otherwise it
+// returns nil, and the reconciler's own client applies.
+func (p *GitHubProviderClients) ResolveGitHubClient(ctx context.Context, ref *githubv1alpha1.ProviderReference, owner string) (*gh.Client, error) {
+	if ref != nil {
+		return p.GitHubClient(ctx, ref.Name, owner)
+	}
+	if p == nil || p.Installations == nil {
+		return nil, nil
+	}
+	return p.Installations.GitHubClient(ctx, owner)
+}
+
+// resolveGitHubClient returns the client a reconciler uses for a resource owned by the account
+// with the given login: the client ResolveGitHubClient resolves, or else the reconciler's own.
+func resolveGitHubClient[T any](ctx context.Context, providers *GitHubProviderClients, ref *githubv1alpha1.ProviderReference, owner string, fallback T) (T, ctrl.Result, error) {
+	var none T
+	ghClient, err := providers.ResolveGitHubClient(ctx, ref, owner)
+	if err != nil {
+		return none, ctrl.Result{}, err
+	}
+	if ghClient != nil {
+		return any(ghClient).(T), ctrl.Result{}, nil
+	}
+	if any(fallback) == nil {
+		return none, ctrl.Result{}, fmt.Errorf("nil GitHub client")
+	}
+	return fallback, ctrl.Result{}, nil
+}
+
+// GitHubClient returns the client of the named GitHubProvider for a resource owned by the
+// account with the given login. The owner only matters for GitHub App credentials without
+// an installation.
+func (p *GitHubProviderClients) GitHubClient(ctx context.Context, name, owner string) (*gh.Client, error) {
+	cached, err := p.providerClient(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if cached.installations != nil {
+		ghClient, err := cached.installations.GitHubClient(ctx, owner)
+		if err != nil {
+			return nil, fmt.Errorf("GitHubProvider '%s': %w", name, err)
+		}
+		return ghClient, nil
+	}
+	return cached.client, nil
+}
+
+// Validate checks that the credentials of the named GitHubProvider can be used. For GitHub App
+// credentials without an installation, this lists the App's installations.
+func (p *GitHubProviderClients) Validate(ctx context.Context, name string) error {
+	cached, err := p.providerClient(ctx, name)
+	if err != nil {
+		return err
+	}
+	if cached.installations != nil {
+		if err := cached.installations.Refresh(ctx); err != nil {
+			return fmt.Errorf("GitHubProvider '%s': %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (p *GitHubProviderClients) providerClient(ctx context.Context, name string) (*providerClient, error) {
+	if p == nil {
+		return nil, fmt.Errorf("GitHubProvider '%s': providers are not configured", name)
+	}
+	provider := &githubv1alpha1.GitHubProvider{}
+	if err := p.Get(ctx, types.NamespacedName{Name: name}, provider); err != nil {
+		return nil, fmt.Errorf("fetching GitHubProvider '%s': %w", name, err)
+	}
+	ref := provider.Spec.CredentialsSecretRef
+	secret := &corev1.Secret{}
+	if err := p.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, fmt.Errorf("fetching GitHubProvider '%s' credentials: %w", name, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cached, ok := p.clients[name]; ok && cached.generation == provider.Generation && cached.secretVersion == secret.ResourceVersion {
+		return cached, nil
+	}
+
+	creds, err := providerCredentials(secret)
+	if err != nil {
+		return nil, fmt.Errorf("GitHubProvider '%s': %w", name, err)
+	}
+	cached := &providerClient{
+		generation:    provider.Generation,
+		secretVersion: secret.ResourceVersion,
+	}
+	server := providerServer(provider, p.Server)
+	if creds.DiscoversInstallations() {
+		cached.installations, err = utils.NewGitHubAppClients(ctx, p.Base, server, creds)
+	} else {
+		cached.client, err = utils.GitHubClientFromCredentials(ctx, p.Base, server, creds)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating GitHub client for GitHubProvider '%s': %w", name, err)
+	}
+	p.clients[name] = cached
+	return cached, nil
+}
+
+// Forget drops the cached client of the named GitHubProvider.
+func (p *GitHubProviderClients) Forget(name string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, name)
+}
+
+// providerServer returns the GitHub instance of a provider, falling back to the operator's for unset fields.
+func providerServer(provider *githubv1alpha1.GitHubProvider, server utils.GitHubServer) utils.GitHubServer {
+	if provider.Spec.BaseURL != nil {
+		// URLs derived from the operator's base URL don't apply to another instance
+		server = utils.GitHubServer{BaseURL: *provider.Spec.BaseURL, CABundle: server.CABundle}
+	}
+	if provider.Spec.UploadURL != nil {
+		server.UploadURL = *provider.Spec.UploadURL
+	}
+	if provider.Spec.GraphQLURL != nil {
+		server.GraphQLURL = *provider.Spec.GraphQLURL
+	}
+	if len(provider.Spec.CABundle) > 0 {
+		server.CABundle = provider.Spec.CABundle
+	}
+	return server
+}
+
+// providerCredentials reads GitHub credentials from a GitHubProvider credentials Secret.
+// App credentials take precedence over a token, as they do for the operator's env vars.
+func providerCredentials(secret *corev1.Secret) (utils.GitHubCredentials, error) {
+	creds := utils.GitHubCredentials{}
+	if appId, ok := secret.Data[providerAppIdKey]; ok {
+		id, err := strconv.ParseInt(string(appId), 10, 64)
+		if err != nil {
+			return creds, fmt.Errorf("parsing %s: %w", providerAppIdKey, err)
+		}
+		// without an installationId, the App's installation is selected per account
+		if installationId, ok := secret.Data[providerInstallationIdKey]; ok {
+			id, err := strconv.ParseInt(string(installationId), 10, 64)
+			if err != nil {
+				return creds, fmt.Errorf("parsing %s: %w", providerInstallationIdKey, err)
+			}
+			creds.InstallationId = id
+		}
+		privateKey, ok := secret.Data[providerPrivateKeyKey]
+		if !ok {
+			return creds, fmt.Errorf("secret '%s/%s' has no %s", secret.Namespace, secret.Name, providerPrivateKeyKey)
+		}
+		creds.AppId = id
+		creds.PrivateKey = privateKey
+		return creds, nil
+	}
+	token, ok := secret.Data[providerTokenKey]
+	if !ok {
+		return creds, fmt.Errorf("secret '%s/%s' has neither %s nor %s", secret.Namespace, secret.Name, providerTokenKey, providerAppIdKey)
+	}
+	creds.Token = string(token)
+	return creds, nil
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+)
+
+// receiverEventBuffer is how many resources may wait to be enqueued per kind before
+// further webhook deliveries for that kind are dropped.
+const receiverEventBuffer = 1024
+
+// receiverMaxPayloadBytes is the largest delivery GitHub sends. Larger requests are rejected
+// before they are read into memory.
+const receiverMaxPayloadBytes = 25 << 20
+
+var receiverDroppedEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "github_operator_webhook_events_dropped_total",
+	Help: "Resources affected by GitHub webhook deliveries that weren't enqueued because their reconciler was behind, by kind.",
+}, []string{"kind"})
+
+func init() {
+	metrics.Registry.MustRegister(receiverDroppedEvents)
+}
+
+// GitHubEventReceiver handles GitHub webhook deliveries. Deliveries with a valid
+// X-Hub-Signature-256 are mapped to the resources they affect, which are sent on a
+// channel per kind so their reconcilers run without waiting for the requeue interval.
+type GitHubEventReceiver struct {
+	client.Client
+	Secret []byte
+
+	teams             chan event.GenericEvent
+	repositories      chan event.GenericEvent
+	organizations     chan event.GenericEvent
+	branchProtections chan event.GenericEvent
+}
+
+func NewGitHubEventReceiver(c client.Client, secret []byte) *GitHubEventReceiver {
+	return &GitHubEventReceiver{
+		Client:            c,
+		Secret:            secret,
+		teams:             make(chan event.GenericEvent, receiverEventBuffer),
+		repositories:      make(chan event.GenericEvent, receiverEventBuffer),
+		organizations:     make(chan event.GenericEvent, receiverEventBuffer),
+		branchProtections: make(chan event.GenericEvent, receiverEventBuffer),
+	}
+}
+
+// TeamEvents returns the channel Teams affected by deliveries are sent on.
+// A nil receiver returns a nil channel, which never triggers a reconcile.
+func (r *GitHubEventReceiver) TeamEvents() <-chan event.GenericEvent {
+	if r == nil {
+		return nil
+	}
+	return r.teams
+}
+
+// RepositoryEvents returns the channel Repositories affected by deliveries are sent on.
+// A nil receiver returns a nil channel, which never triggers a reconcile.
+func (r *GitHubEventReceiver) RepositoryEvents() <-chan event.GenericEvent {
+	if r == nil {
+		return nil
+	}
+	return r.repositories
+}
+
+// OrganizationEvents returns the channel Organizations affected by deliveries are sent on.
+// A nil receiver returns a nil channel, which never triggers a reconcile.
+func (r *GitHubEventReceiver) OrganizationEvents() <-chan event.GenericEvent {
+	if r == nil {
+		return nil
+	}
+	return r.organizations
+}
+
+// BranchProtectionEvents returns the channel BranchProtections affected by deliveries are sent on.
+// A nil receiver returns a nil channel, which never triggers a reconcile.
+func (r *GitHubEventReceiver) BranchProtectionEvents() <-chan event.GenericEvent {
+	if r == nil {
+		return nil
+	}
+	return r.branchProtections
+}
+
+func (r *GitHubEventReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	log := log.FromContext(req.Context()).WithValues("delivery", github.DeliveryID(req), "event", github.WebHookType(req))
+	ctx := ctrl.LoggerInto(req.Context(), log)
+
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// ValidatePayload falls back to the SHA-1 signature, which isn't accepted
+	if req.Header.Get(github.SHA256SignatureHeader) == "" {
+		http.Error(w, "missing "+github.SHA256SignatureHeader+" header", http.StatusUnauthorized)
+		return
+	}
+	req.Body = http.MaxBytesReader(w, req.Body, receiverMaxPayloadBytes)
+	payload, err := github.ValidatePayload(req, r.Secret)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	} else if err != nil {
+		log.Info("rejecting webhook delivery", "reason", err.Error())
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	parsed, err := github.ParseWebHook(github.WebHookType(req), payload)
+	if err != nil {
+		// GitHub may deliver event types that were never subscribed to, e.g. ping
+		log.V(1).Info("ignoring webhook delivery", "reason", err.Error())
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := r.dispatch(ctx, parsed); err != nil {
+		log.Error(err, "error mapping webhook delivery to resources")
+		http.Error(w, "error mapping delivery to resources", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// dispatch enqueues the resources affected by a parsed webhook event.
+func (r *GitHubEventReceiver) dispatch(ctx context.Context, parsed any) error {
+	switch e := parsed.(type) {
+	case *github.RepositoryEvent:
+		names := []string{e.GetRepo().GetName()}
+		if e.GetChanges().GetRepo().GetName().GetFrom() != "" {
+			names = append(names, e.GetChanges().GetRepo().GetName().GetFrom())
+		}
+		return r.enqueueRepositories(ctx, e.GetRepo().GetNodeID(), e.GetRepo().GetOwner().GetLogin(), names...)
+	case *github.TeamEvent:
+		names := []string{e.GetTeam().GetName()}
+		if e.GetChanges().GetName().GetFrom() != "" {
+			names = append(names, e.GetChanges().GetName().GetFrom())
+		}
+		return r.enqueueTeams(ctx, e.GetTeam().GetNodeID(), e.GetOrg().GetLogin(), names...)
+	case *github.MembershipEvent:
+		return r.enqueueTeams(ctx, e.GetTeam().GetNodeID(), e.GetOrg().GetLogin(), e.GetTeam().GetName())
+	case *github.OrganizationEvent:
+		return r.enqueueOrganizations(ctx, e.GetOrganization().GetNodeID(), e.GetOrganization().GetLogin())
+	case *github.BranchProtectionRuleEvent:
+		// rules are identified by GraphQL node ID, which the payload doesn't include,
+		// and the pattern may have changed, so every rule on the repository is enqueued
+		return r.enqueueBranchProtections(ctx, e.GetRepo().GetNodeID(), e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName())
+	}
+	return nil
+}
+
+func (r *GitHubEventReceiver) enqueueRepositories(ctx context.Context, nodeId, owner string, names ...string) error {
+	repos := &githubv1alpha1.RepositoryList{}
+	if err := r.List(ctx, repos); err != nil {
+		return err
+	}
+	for i := range repos.Items {
+		repo := &repos.Items[i]
+		if matchesNodeId(repo.Status.NodeId, nodeId) || (strings.EqualFold(repo.Spec.Owner, owner) && matchesName(repo.Spec.Name, names)) {
+			r.send(ctx, r.repositories, "Repository", repo)
+		}
+	}
+	return nil
+}
+
+func (r *GitHubEventReceiver) enqueueTeams(ctx context.Context, nodeId, org string, names ...string) error {
+	teams := &githubv1alpha1.TeamList{}
+	if err := r.List(ctx, teams); err != nil {
+		return err
+	}
+	for i := range teams.Items {
+		team := &teams.Items[i]
+		if matchesNodeId(team.Status.NodeId, nodeId) || (strings.EqualFold(team.Spec.Organization, org) && matchesName(team.Spec.Name, names)) {
+			r.send(ctx, r.teams, "Team", team)
+		}
+	}
+	return nil
+}
+
+func (r *GitHubEventReceiver) enqueueOrganizations(ctx context.Context, nodeId, login string) error {
+	orgs := &githubv1alpha1.OrganizationList{}
+	if err := r.List(ctx, orgs); err != nil {
+		return err
+	}
+	for i := range orgs.Items {
+		org := &orgs.Items[i]
+		if matchesNodeId(org.Status.NodeId, nodeId) || strings.EqualFold(org.Spec.Login, login) {
+			r.send(ctx, r.organizations, "Organization", org)
+		}
+	}
+	return nil
+}
+
+func (r *GitHubEventReceiver) enqueueBranchProtections(ctx context.Context, repositoryNodeId, owner, name string) error {
+	bps := &githubv1alpha1.BranchProtectionList{}
+	if err := r.List(ctx, bps); err != nil {
+		return err
+	}
+	// rules referencing a Repository that haven't been reconciled yet are matched through it
+	repos := &githubv1alpha1.RepositoryList{}
+	if err := r.List(ctx, repos); err != nil {
+		return err
+	}
+	reposByName := map[types.NamespacedName]*githubv1alpha1.Repository{}
+	for i := range repos.Items {
+		reposByName[client.ObjectKeyFromObject(&repos.Items[i])] = &repos.Items[i]
+	}
+	for i := range bps.Items {
+		bp := &bps.Items[i]
+		if matchesNodeId(bp.Status.RepositoryNodeId, repositoryNodeId) || (strings.EqualFold(bp.Spec.RepositoryOwner, owner) && strings.EqualFold(bp.Spec.RepositoryName, name)) {
+			r.send(ctx, r.branchProtections, "BranchProtection", bp)
+		} else if bp.Spec.RepositoryRef != nil {
+			repo, ok := reposByName[types.NamespacedName{Namespace: bp.Namespace, Name: bp.Spec.RepositoryRef.Name}]
+			if ok && (matchesNodeId(repo.Status.NodeId, repositoryNodeId) || (strings.EqualFold(repo.Spec.Owner, owner) && strings.EqualFold(repo.Spec.Name, name))) {
+				r.send(ctx, r.branchProtections, "BranchProtection", bp)
+			}
+		}
+	}
+	return nil
+}
+
+// send enqueues obj of the given kind without blocking the delivery. If the reconciler has
+// fallen behind, the event is dropped and the resource is picked up at its next requeue instead.
+func (r *GitHubEventReceiver) send(ctx context.Context, ch chan<- event.GenericEvent, kind string, obj client.Object) {
+	select {
+	case ch <- event.GenericEvent{Object: obj}:
+	default:
+		receiverDroppedEvents.WithLabelValues(kind).Inc()
+		log.FromContext(ctx).Info("dropping webhook event, reconciler is behind", "kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+	}
+}
+
+func matchesNodeId(statusNodeId *string, nodeId string) bool {
+	return statusNodeId != nil && nodeId != "" && *statusNodeId == nodeId
+}
+
+// GitHub names aren't case sensitive
+func matchesName(name string, names []string) bool {
+	for _, n := range names {
+		if n != "" && strings.EqualFold(name, n) {
+			return true
+		}
+	}
+	return false
+}
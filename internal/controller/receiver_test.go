@@ -0,0 +1,179 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+)
+
+var _ = Describe("GitHub Event Receiver", func() {
+	secret := []byte("test-webhook-secret")
+	payload := `{"zen":"Keep it logically awesome.","hook_id":1}`
+
+	sign := func(key []byte, body string) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	signSHA1 := func(key []byte, body string) string {
+		mac := hmac.New(sha1.New, key)
+		mac.Write([]byte(body))
+		return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	delivery := func(eventType, body string, headers map[string]string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(github.EventTypeHeader, eventType)
+		req.Header.Set(github.DeliveryIDHeader, "test-delivery")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return req
+	}
+
+	DescribeTable("validating delivery signatures",
+		func(headers map[string]string, status int) {
+			receiver := NewGitHubEventReceiver(k8sClient, secret)
+			rec := httptest.NewRecorder()
+			receiver.ServeHTTP(rec, delivery("ping", payload, headers))
+			Expect(rec.Code).To(Equal(status))
+		},
+		Entry("accepts a valid signature",
+			map[string]string{github.SHA256SignatureHeader: sign(secret, payload)}, http.StatusAccepted),
+		Entry("rejects a signature made with the wrong secret",
+			map[string]string{github.SHA256SignatureHeader: sign([]byte("wrong"), payload)}, http.StatusUnauthorized),
+		Entry("rejects a delivery without a signature",
+			map[string]string{}, http.StatusUnauthorized),
+		Entry("rejects a delivery with only a SHA-1 signature",
+			map[string]string{github.SHA1SignatureHeader: signSHA1(secret, payload)}, http.StatusUnauthorized),
+	)
+
+	It("should reject deliveries larger than GitHub sends", func() {
+		body := `{"zen":"` + strings.Repeat("a", receiverMaxPayloadBytes) + `"}`
+		receiver := NewGitHubEventReceiver(k8sClient, secret)
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, delivery("ping", body, map[string]string{github.SHA256SignatureHeader: sign(secret, body)}))
+		Expect(rec.Code).To(Equal(http.StatusRequestEntityTooLarge))
+	})
+
+	It("should enqueue the repository a delivery is about", func() {
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{Name: "test-receiver-repository", Namespace: "default"}
+		resource := &githubv1alpha1.Repository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      typeNamespacedName.Name,
+				Namespace: typeNamespacedName.Namespace,
+			},
+			Spec: githubv1alpha1.RepositorySpec{
+				Name:  ghTestResourcePrefix + "receiver",
+				Owner: testOrganization,
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		}()
+
+		body := `{"action":"edited","repository":{"name":"` + resource.Spec.Name + `","owner":{"login":"` + testOrganization + `"}}}`
+		receiver := NewGitHubEventReceiver(k8sClient, secret)
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, delivery("repository", body, map[string]string{github.SHA256SignatureHeader: sign(secret, body)}))
+		Expect(rec.Code).To(Equal(http.StatusAccepted))
+
+		var e event.GenericEvent
+		Eventually(receiver.RepositoryEvents(), time.Second).Should(Receive(&e))
+		Expect(e.Object.GetName()).To(Equal(typeNamespacedName.Name))
+	})
+
+	It("should enqueue the branch protection rules of a referenced Repository before they are reconciled", func() {
+		ctx := context.Background()
+		repo := &githubv1alpha1.Repository{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-receiver-bp-repository",
+				Namespace: "default",
+			},
+			Spec: githubv1alpha1.RepositorySpec{
+				Name:  ghTestResourcePrefix + "receiver-bp",
+				Owner: testOrganization,
+			},
+		}
+		Expect(k8sClient.Create(ctx, repo)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, repo)).To(Succeed())
+		}()
+		bp := &githubv1alpha1.BranchProtection{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-receiver-branch-protection",
+				Namespace: "default",
+			},
+			Spec: githubv1alpha1.BranchProtectionSpec{
+				RepositoryRef: &githubv1alpha1.ResourceReference{Name: repo.Name},
+				Pattern:       "main",
+			},
+		}
+		Expect(k8sClient.Create(ctx, bp)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, bp)).To(Succeed())
+		}()
+
+		body := `{"action":"edited","rule":{"name":"main"},"repository":{"name":"` + repo.Spec.Name + `","owner":{"login":"` + testOrganization + `"}}}`
+		receiver := NewGitHubEventReceiver(k8sClient, secret)
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, delivery("branch_protection_rule", body, map[string]string{github.SHA256SignatureHeader: sign(secret, body)}))
+		Expect(rec.Code).To(Equal(http.StatusAccepted))
+
+		var e event.GenericEvent
+		Eventually(receiver.BranchProtectionEvents(), time.Second).Should(Receive(&e))
+		Expect(e.Object.GetName()).To(Equal(bp.Name))
+	})
+
+	It("should count the events dropped while a reconciler is behind", func() {
+		dropped := func() float64 {
+			m := &dto.Metric{}
+			Expect(receiverDroppedEvents.WithLabelValues("Repository").Write(m)).To(Succeed())
+			return m.GetCounter().GetValue()
+		}
+		before := dropped()
+
+		receiver := NewGitHubEventReceiver(k8sClient, secret)
+		// an unbuffered channel nobody receives from is never ready
+		receiver.repositories = make(chan event.GenericEvent)
+		receiver.send(context.Background(), receiver.repositories, "Repository", &githubv1alpha1.Repository{})
+
+		Expect(dropped() - before).To(Equal(1.0))
+	})
+})
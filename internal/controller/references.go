@@ -0,0 +1,122 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+)
+
+// dependencyNotReadyError reports a referenced resource that doesn't exist or hasn't been
+// reconciled yet, so the fields it provides can't be resolved.
+type dependencyNotReadyError struct {
+	Kind   string
+	Name   string
+	Reason string
+}
+
+func (e *dependencyNotReadyError) Error() string {
+	return fmt.Sprintf("waiting for %s '%s': %s", e.Kind, e.Name, e.Reason)
+}
+
+// ignoreDependencyNotReady returns nil if err is a dependencyNotReadyError. Referenced resources
+// are watched, so a resource waiting for one is reconciled again once it changes.
+func ignoreDependencyNotReady(err error) error {
+	var notReady *dependencyNotReadyError
+	if errors.As(err, &notReady) {
+		return nil
+	}
+	return err
+}
+
+// getReferencedTeam fetches a Team referenced from namespace. It fails with a dependencyNotReadyError
+// until the Team has been reconciled.
+func getReferencedTeam(ctx context.Context, c client.Client, namespace string, ref githubv1alpha1.ResourceReference) (*githubv1alpha1.Team, error) {
+	team := &githubv1alpha1.Team{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, team); apierrors.IsNotFound(err) {
+		return nil, &dependencyNotReadyError{Kind: "Team", Name: ref.Name, Reason: "not found"}
+	} else if err != nil {
+		return nil, fmt.Errorf("fetching Team '%s': %w", ref.Name, err)
+	}
+	if team.Status.Id == nil || team.Status.Slug == nil || team.Status.OrganizationLogin == nil {
+		return nil, &dependencyNotReadyError{Kind: "Team", Name: ref.Name, Reason: "not reconciled yet"}
+	}
+	return team, nil
+}
+
+// getReferencedRepository fetches a Repository referenced from namespace. It fails with a
+// dependencyNotReadyError until the Repository has been reconciled.
+func getReferencedRepository(ctx context.Context, c client.Client, namespace string, ref githubv1alpha1.ResourceReference) (*githubv1alpha1.Repository, error) {
+	repo := &githubv1alpha1.Repository{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, repo); apierrors.IsNotFound(err) {
+		return nil, &dependencyNotReadyError{Kind: "Repository", Name: ref.Name, Reason: "not found"}
+	} else if err != nil {
+		return nil, fmt.Errorf("fetching Repository '%s': %w", ref.Name, err)
+	}
+	if repo.Status.NodeId == nil || repo.Status.Name == nil || repo.Status.OwnerLogin == nil {
+		return nil, &dependencyNotReadyError{Kind: "Repository", Name: ref.Name, Reason: "not reconciled yet"}
+	}
+	return repo, nil
+}
+
+// isOwnedBy returns true if obj has an owner reference to owner.
+func isOwnedBy(obj client.Object, owner client.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// referencedTeamChanged passes Team updates that change what references to the Team resolve to.
+var referencedTeamChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldTeam, okOld := e.ObjectOld.(*githubv1alpha1.Team)
+		newTeam, okNew := e.ObjectNew.(*githubv1alpha1.Team)
+		if !okOld || !okNew {
+			return true
+		}
+		return !ptrEqual(oldTeam.Status.Id, newTeam.Status.Id) ||
+			!ptrEqual(oldTeam.Status.Slug, newTeam.Status.Slug) ||
+			!ptrEqual(oldTeam.Status.OrganizationLogin, newTeam.Status.OrganizationLogin)
+	},
+}
+
+// referencedRepositoryChanged passes Repository updates that change what references to the
+// Repository resolve to.
+var referencedRepositoryChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldRepo, okOld := e.ObjectOld.(*githubv1alpha1.Repository)
+		newRepo, okNew := e.ObjectNew.(*githubv1alpha1.Repository)
+		if !okOld || !okNew {
+			return true
+		}
+		return !ptrEqual(oldRepo.Status.NodeId, newRepo.Status.NodeId) ||
+			!ptrEqual(oldRepo.Status.Name, newRepo.Status.Name) ||
+			!ptrEqual(oldRepo.Status.OwnerLogin, newRepo.Status.OwnerLogin)
+	},
+}
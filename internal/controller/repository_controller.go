@@ -19,14 +19,21 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
 	gh "github.com/eczy/github-operator/internal/github"
@@ -37,6 +44,10 @@ var (
 	repositoryFinalizerName = "github.github-operator.eczy.io/repo-finalizer"
 )
 
+const (
+	repositoryTombstoneSuffix = "-tombstone"
+)
+
 type RepositoryRequester interface {
 	RepositoryGetter
 
@@ -45,6 +56,19 @@ type RepositoryRequester interface {
 	CreateRepositoryFromTemplate(ctx context.Context, templateOwner string, templateRepository string, req *github.TemplateRepoRequest) (*github.Repository, error)
 	DeleteRepositoryByName(ctx context.Context, owner, name string) error
 	UpdateRepositoryTopics(ctx context.Context, owner string, repo string, topics []string) ([]string, error)
+	TransferRepository(ctx context.Context, owner, name, newOwner, newName string) (*github.Repository, error)
+
+	GetRepositoryCollaborators(ctx context.Context, owner, repo, affiliation string) ([]*gh.RepositoryCollaborator, error)
+	GetRepositoryInvitations(ctx context.Context, owner, repo string) ([]*gh.RepositoryCollaboratorInvitation, error)
+	UpdateRepositoryCollaborator(ctx context.Context, owner, repo, user, permission string) (*github.CollaboratorInvitation, error)
+	RemoveRepositoryCollaborator(ctx context.Context, owner, repo, user string) error
+	UpdateRepositoryInvitation(ctx context.Context, owner, repo string, invitationId int64, permission string) error
+	DeleteRepositoryInvitation(ctx context.Context, owner, repo string, invitationId int64) error
+
+	GetRepositoryLabels(ctx context.Context, owner, repo string) ([]*github.Label, error)
+	CreateRepositoryLabel(ctx context.Context, owner, repo string, label *github.Label) (*github.Label, error)
+	UpdateRepositoryLabel(ctx context.Context, owner, repo, name string, update *gh.RepositoryLabelUpdate) (*github.Label, error)
+	DeleteRepositoryLabel(ctx context.Context, owner, repo, name string) error
 }
 
 type RepositoryGetter interface {
@@ -57,13 +81,20 @@ type RepositoryReconciler struct {
 	client.Client
 	Scheme                   *runtime.Scheme
 	GitHubClient             RepositoryRequester
+	Recorder                 record.EventRecorder
 	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
 	RequeueInterval          time.Duration
+	// GitHubEvents optionally triggers reconciles for resources changed on GitHub,
+	// see GitHubEventReceiver.
+	GitHubEvents <-chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=repositories,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=repositories/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=repositories/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -74,13 +105,9 @@ type RepositoryReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
-func (r *RepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *RepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := log.FromContext(ctx)
 
-	if r.GitHubClient == nil {
-		return ctrl.Result{}, fmt.Errorf("nil GitHub client")
-	}
-
 	// fetch resource
 	repo := &githubv1alpha1.Repository{}
 	if err := r.Get(ctx, req.NamespacedName, repo); err != nil {
@@ -88,10 +115,48 @@ func (r *RepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub
+	managementPolicy := resolveManagementPolicy(repo.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(repo, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, repo, &repo.Status.Conditions, &repo.Status.ObservedGeneration, &repo.Status.Drift, &repo.Status.Plan, err)
+	}()
+
+	// the client is resolved for the owner the repository was last observed under, so that it is
+	// still found before a transfer
+	owner := repo.Spec.Owner
+	if repo.Status.OwnerLogin != nil && *repo.Status.OwnerLogin != "" {
+		owner = *repo.Status.OwnerLogin
+	}
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, repo.Spec.ProviderRef, owner, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
 	var observed *github.Repository
 	// try to fetch external resource
 	if repo.Status.NodeId != nil {
 		ghTeam, err := r.GitHubClient.GetRepositoryByNodeId(ctx, *repo.Status.NodeId)
+		if _, ok := err.(*gh.RepositoryNotFoundError); ok && !strings.EqualFold(owner, repo.Spec.Owner) {
+			// once a transfer completes, the repository may only be visible with the new owner's credentials
+			ownerClient, resolveErr := r.Providers.ResolveGitHubClient(ctx, repo.Spec.ProviderRef, repo.Spec.Owner)
+			if resolveErr != nil {
+				return ctrl.Result{}, resolveErr
+			}
+			if ownerClient != nil {
+				withOwner := *r
+				withOwner.GitHubClient = ownerClient
+				r = &withOwner
+				ghTeam, err = r.GitHubClient.GetRepositoryByNodeId(ctx, *repo.Status.NodeId)
+			}
+		}
 		if _, ok := err.(*gh.RepositoryNotFoundError); ok {
 			log.Info(err.Error())
 		} else if err != nil {
@@ -112,58 +177,120 @@ func (r *RepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// if external resource does't exist and we aren't deleting the resource, create external resource
 	if observed == nil && repo.DeletionTimestamp.IsZero() {
-		ghRepo, err := r.createRepository(ctx, repo)
-		if err != nil {
-			log.Error(err, "error creating GitHub repository")
-			return ctrl.Result{}, err
+		if managementPolicy != githubv1alpha1.ManagementPolicyFull {
+			// only the Full management policy creates missing GitHub resources
+			skipMutation(ctx, "create repository %s/%s", repo.Spec.Owner, repo.Spec.Name)
+		} else if shouldMutate(ctx, "create repository %s/%s", repo.Spec.Owner, repo.Spec.Name) {
+			ghRepo, err := r.createRepository(ctx, repo)
+			if err != nil {
+				log.Error(err, "error creating GitHub repository")
+				return ctrl.Result{}, err
+			}
+			observed = ghRepo
+			r.Recorder.Event(repo, corev1.EventTypeNormal, eventReasonCreated, "Created GitHub repository")
 		}
-		observed = ghRepo
 	}
 
 	// handle finalizer
-	if r.DeleteOnResourceDeletion {
-		if repo.DeletionTimestamp.IsZero() {
-			// not being deleted
-			if !controllerutil.ContainsFinalizer(repo, repositoryFinalizerName) {
-				controllerutil.AddFinalizer(repo, repositoryFinalizerName)
-				if err := r.Update(ctx, repo); err != nil {
-					return ctrl.Result{}, err
-				}
-			}
-		} else {
-			// being deleted
-			if repo.Status.NodeId != nil {
-				// if we have never resolved this resource before, don't
-				// touch external state
-				if err := r.deleteRepository(ctx, repo); err != nil {
-					log.Error(err, "error deleting repository")
-					return ctrl.Result{}, err
-				}
-			}
-
-			controllerutil.RemoveFinalizer(repo, repositoryFinalizerName)
-			if err := r.Update(ctx, repo); err != nil {
-				return ctrl.Result{}, err
-			}
+	deletionPolicy := resolveDeletionPolicy(repo.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion)
+	if done, err := r.finalizeRepository(ctx, repo, observed, deletionPolicy); err != nil || done {
+		return ctrl.Result{}, err
+	}
 
-			return ctrl.Result{}, nil
-		}
+	// external resource is gone or wasn't created under the management policy
+	if observed == nil {
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
 	}
 
 	// update external resource
-	err := r.updateRepository(ctx, repo, observed)
+	err = r.updateRepository(ctx, repo, observed)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// collaborators and labels are updated once a transferred repository is observed under its new owner
+	if !strings.EqualFold(observed.GetOwner().GetLogin(), repo.Spec.Owner) {
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+	}
+
+	// update collaborators
+	if err := r.updateRepositoryCollaborators(ctx, repo, observed); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// update labels
+	if err := r.updateRepositoryLabels(ctx, repo, observed); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *RepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&githubv1alpha1.Repository{}).
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&githubv1alpha1.Repository{})
+	if r.GitHubEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.GitHubEvents, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
+}
+
+// finalizeRepository adds or removes the finalizer of a Repository as its deletion policy requires and, once
+// the Repository is being deleted, deletes or archives the GitHub repository before releasing it. done is true
+// if the Repository is being deleted, so that there is nothing left to reconcile.
+func (r *RepositoryReconciler) finalizeRepository(ctx context.Context, repo *githubv1alpha1.Repository, observed *github.Repository, deletionPolicy githubv1alpha1.RepositoryDeletionPolicy) (done bool, err error) {
+	log := log.FromContext(ctx)
+
+	if repo.DeletionTimestamp.IsZero() {
+		// not being deleted
+		if deletionPolicy != githubv1alpha1.RepositoryDeletionPolicyOrphan && !controllerutil.ContainsFinalizer(repo, repositoryFinalizerName) {
+			controllerutil.AddFinalizer(repo, repositoryFinalizerName)
+			if err := r.Update(ctx, repo); err != nil {
+				return false, err
+			}
+		} else if deletionPolicy == githubv1alpha1.RepositoryDeletionPolicyOrphan && controllerutil.ContainsFinalizer(repo, repositoryFinalizerName) {
+			// the GitHub resource is left in place, so there is nothing to clean up
+			controllerutil.RemoveFinalizer(repo, repositoryFinalizerName)
+			if err := r.Update(ctx, repo); err != nil {
+				return false, err
+			}
+		}
+	} else if controllerutil.ContainsFinalizer(repo, repositoryFinalizerName) {
+		// being deleted
+		if deletionPolicy == githubv1alpha1.RepositoryDeletionPolicyDelete && repo.Status.NodeId != nil {
+			// if we have never resolved this resource before, don't
+			// touch external state
+			if !shouldMutate(ctx, "delete repository %s/%s", repo.Spec.Owner, repo.Spec.Name) {
+				// keep the finalizer so the deletion is applied once the resource leaves dry-run mode
+				return true, nil
+			}
+			if err := r.deleteRepository(ctx, repo); err != nil {
+				log.Error(err, "error deleting repository")
+				return false, err
+			}
+			r.Recorder.Event(repo, corev1.EventTypeNormal, eventReasonDeleted, "Deleted GitHub repository")
+		} else if deletionPolicy == githubv1alpha1.RepositoryDeletionPolicyArchive && observed != nil {
+			if !shouldMutate(ctx, "archive repository %s/%s", repo.Spec.Owner, repo.Spec.Name) {
+				// keep the finalizer so the archive is applied once the resource leaves dry-run mode
+				return true, nil
+			}
+			if err := r.archiveRepository(ctx, observed); err != nil {
+				log.Error(err, "error archiving repository")
+				return false, err
+			}
+			r.Recorder.Event(repo, corev1.EventTypeNormal, eventReasonArchived, "Archived GitHub repository")
+		}
+
+		controllerutil.RemoveFinalizer(repo, repositoryFinalizerName)
+		if err := r.Update(ctx, repo); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	return false, nil
 }
 
 func (r *RepositoryReconciler) createRepository(ctx context.Context, repo *githubv1alpha1.Repository) (*github.Repository, error) {
@@ -198,176 +325,225 @@ func (r *RepositoryReconciler) updateRepository(ctx context.Context, repo *githu
 	needsUpdate := false
 	needsTopicsUpdate := false
 
+	// Owner
+	ownerLogin := ghRepo.GetOwner().GetLogin()
+	if ownerLogin != "" && !strings.EqualFold(repo.Spec.Owner, ownerLogin) {
+		if resolveTransferPolicy(repo.Spec.TransferPolicy) != githubv1alpha1.TransferPolicyAllow {
+			return fmt.Errorf("repository owner '%s' does not match Spec owner '%s' and transferPolicy is not Allow", ownerLogin, repo.Spec.Owner)
+		}
+		log.Info("repository owner update", "from", ownerLogin, "to", repo.Spec.Owner)
+		recordDrift(ctx, "owner", repo.Spec.Owner, ownerLogin)
+		if shouldMutate(ctx, "transfer repository %s/%s to %s/%s", ownerLogin, ghRepo.GetName(), repo.Spec.Owner, repo.Spec.Name) {
+			log.Info("transferring repository", "name", ghRepo.GetName(), "to", repo.Spec.Owner)
+			if _, err := r.GitHubClient.TransferRepository(ctx, ownerLogin, ghRepo.GetName(), repo.Spec.Owner, repo.Spec.Name); err != nil {
+				log.Error(err, "error transferring repository", "name", ghRepo.GetName())
+				return err
+			}
+			r.Recorder.Eventf(repo, corev1.EventTypeNormal, eventReasonTransferred, "Transferred GitHub repository to %s", repo.Spec.Owner)
+		}
+		// GitHub completes transfers in the background, and the transfer also applies the spec name,
+		// so the rest of the repository is updated once it is observed under its new owner
+		return nil
+	}
+
 	// Name
 	if repo.Spec.Name != ghRepo.GetName() {
 		log.Info("repository name update", "from", ghRepo.GetName(), "to", repo.Spec.Name)
+		recordDrift(ctx, "name", repo.Spec.Name, ghRepo.GetName())
 		updateRepo.Name = &repo.Spec.Name
 		needsUpdate = true
 	}
-	// Owner
-	owner := ghRepo.GetOwner()
-	if owner != nil && repo.Spec.Owner != owner.GetLogin() {
-		return fmt.Errorf("repository owner '%s' does not match Spec owner '%s'", repo.Spec.Name, owner.GetLogin())
-	}
-	// TODO: transfer repository to new owner
-
 	// Description
 	if ptrNonNilAndNotEqualTo(repo.Spec.Description, ghRepo.GetDescription()) {
 		log.Info("repository Description update", "from", ghRepo.GetDescription(), "to", repo.Spec.Description)
+		recordDrift(ctx, "description", repo.Spec.Description, ghRepo.GetDescription())
 		updateRepo.Description = repo.Spec.Description
 		needsUpdate = true
 	}
 	// Homepage
-	if ptrNonNilAndNotEqualTo(repo.Spec.Homepage, ghRepo.GetDescription()) {
+	if ptrNonNilAndNotEqualTo(repo.Spec.Homepage, ghRepo.GetHomepage()) {
 		log.Info("repository Homepage update", "from", ghRepo.GetHomepage(), "to", repo.Spec.Homepage)
+		recordDrift(ctx, "homepage", repo.Spec.Homepage, ghRepo.GetHomepage())
 		updateRepo.Homepage = repo.Spec.Homepage
 		needsUpdate = true
 	}
 	// DefaultBranch
 	if ptrNonNilAndNotEqualTo(repo.Spec.DefaultBranch, ghRepo.GetDefaultBranch()) {
-		log.Info("repository DefaultBranch update", "from", ghRepo.GetDefaultBranch(), "to", repo.Spec.Description)
+		log.Info("repository DefaultBranch update", "from", ghRepo.GetDefaultBranch(), "to", repo.Spec.DefaultBranch)
+		recordDrift(ctx, "defaultBranch", repo.Spec.DefaultBranch, ghRepo.GetDefaultBranch())
 		updateRepo.DefaultBranch = repo.Spec.DefaultBranch
 		needsUpdate = true
 	}
 	// AllowRebaseMerge
 	if ptrNonNilAndNotEqualTo(repo.Spec.AllowRebaseMerge, ghRepo.GetAllowRebaseMerge()) {
-		log.Info("repository AllowRebaseMerge update", "from", ghRepo.GetDescription(), "to", repo.Spec.AllowRebaseMerge)
+		log.Info("repository AllowRebaseMerge update", "from", ghRepo.GetAllowRebaseMerge(), "to", repo.Spec.AllowRebaseMerge)
+		recordDrift(ctx, "allowRebaseMerge", repo.Spec.AllowRebaseMerge, ghRepo.GetAllowRebaseMerge())
 		updateRepo.AllowRebaseMerge = repo.Spec.AllowRebaseMerge
 		needsUpdate = true
 	}
 	// AllowUpdateBranch
 	if ptrNonNilAndNotEqualTo(repo.Spec.AllowUpdateBranch, ghRepo.GetAllowUpdateBranch()) {
 		log.Info("repository AllowUpdateBranch update", "from", ghRepo.GetAllowUpdateBranch(), "to", repo.Spec.AllowUpdateBranch)
+		recordDrift(ctx, "allowUpdateBranch", repo.Spec.AllowUpdateBranch, ghRepo.GetAllowUpdateBranch())
 		updateRepo.AllowUpdateBranch = repo.Spec.AllowUpdateBranch
 		needsUpdate = true
 	}
 	// AllowSquashMerge
 	if ptrNonNilAndNotEqualTo(repo.Spec.AllowSquashMerge, ghRepo.GetAllowSquashMerge()) {
 		log.Info("repository AllowSquashMerge update", "from", ghRepo.GetAllowSquashMerge(), "to", repo.Spec.AllowSquashMerge)
+		recordDrift(ctx, "allowSquashMerge", repo.Spec.AllowSquashMerge, ghRepo.GetAllowSquashMerge())
 		updateRepo.AllowSquashMerge = repo.Spec.AllowSquashMerge
 		needsUpdate = true
 	}
 	// AllowMergeCommit
 	if ptrNonNilAndNotEqualTo(repo.Spec.AllowMergeCommit, ghRepo.GetAllowMergeCommit()) {
 		log.Info("repository AllowMergeCommit update", "from", ghRepo.GetAllowMergeCommit(), "to", repo.Spec.AllowMergeCommit)
+		recordDrift(ctx, "allowMergeCommit", repo.Spec.AllowMergeCommit, ghRepo.GetAllowMergeCommit())
 		updateRepo.AllowMergeCommit = repo.Spec.AllowMergeCommit
 		needsUpdate = true
 	}
 	// AllowAutoMerge
 	if ptrNonNilAndNotEqualTo(repo.Spec.AllowAutoMerge, ghRepo.GetAllowAutoMerge()) {
 		log.Info("repository AllowAutoMerge update", "from", ghRepo.GetAllowAutoMerge(), "to", repo.Spec.AllowAutoMerge)
+		recordDrift(ctx, "allowAutoMerge", repo.Spec.AllowAutoMerge, ghRepo.GetAllowAutoMerge())
 		updateRepo.AllowAutoMerge = repo.Spec.AllowAutoMerge
 		needsUpdate = true
 	}
 	// AllowForking
 	if ptrNonNilAndNotEqualTo(repo.Spec.AllowForking, ghRepo.GetAllowForking()) {
 		log.Info("repository AllowForking update", "from", ghRepo.GetAllowForking(), "to", repo.Spec.AllowForking)
+		recordDrift(ctx, "allowForking", repo.Spec.AllowForking, ghRepo.GetAllowForking())
 		updateRepo.AllowForking = repo.Spec.AllowForking
 		needsUpdate = true
 	}
 	// WebCommitSignoffRequired
 	if ptrNonNilAndNotEqualTo(repo.Spec.WebCommitSignoffRequired, ghRepo.GetWebCommitSignoffRequired()) {
 		log.Info("repository WebCommitSignoffRequired update", "from", ghRepo.GetWebCommitSignoffRequired(), "to", repo.Spec.WebCommitSignoffRequired)
+		recordDrift(ctx, "webCommitSignoffRequired", repo.Spec.WebCommitSignoffRequired, ghRepo.GetWebCommitSignoffRequired())
 		updateRepo.WebCommitSignoffRequired = repo.Spec.WebCommitSignoffRequired
 		needsUpdate = true
 	}
 	// DeleteBranchOnMerge
 	if ptrNonNilAndNotEqualTo(repo.Spec.DeleteBranchOnMerge, ghRepo.GetDeleteBranchOnMerge()) {
 		log.Info("repository DeleteBranchOnMerge update", "from", ghRepo.GetDeleteBranchOnMerge(), "to", repo.Spec.DeleteBranchOnMerge)
+		recordDrift(ctx, "deleteBranchOnMerge", repo.Spec.DeleteBranchOnMerge, ghRepo.GetDeleteBranchOnMerge())
 		updateRepo.DeleteBranchOnMerge = repo.Spec.DeleteBranchOnMerge
 		needsUpdate = true
 	}
 	// SquashMergeCommitTitle
 	if ptrNonNilAndNotEqualTo(repo.Spec.SquashMergeCommitTitle, (githubv1alpha1.SquashMergeCommitTitle)(ghRepo.GetSquashMergeCommitTitle())) {
 		log.Info("repository SquashMergeCommitTitle update", "from", ghRepo.GetSquashMergeCommitTitle(), "to", repo.Spec.SquashMergeCommitTitle)
+		recordDrift(ctx, "squashMergeCommitTitle", repo.Spec.SquashMergeCommitTitle, ghRepo.GetSquashMergeCommitTitle())
 		updateRepo.SquashMergeCommitTitle = (*string)(repo.Spec.SquashMergeCommitTitle)
 		needsUpdate = true
 	}
 	// SquashMergeCommitMessage
 	if ptrNonNilAndNotEqualTo(repo.Spec.SquashMergeCommitMessage, (githubv1alpha1.SquashMergeCommitMessage)(ghRepo.GetSquashMergeCommitMessage())) {
 		log.Info("repository SquashMergeCommitMessage update", "from", ghRepo.GetSquashMergeCommitMessage(), "to", repo.Spec.SquashMergeCommitMessage)
+		recordDrift(ctx, "squashMergeCommitMessage", repo.Spec.SquashMergeCommitMessage, ghRepo.GetSquashMergeCommitMessage())
 		updateRepo.SquashMergeCommitMessage = (*string)(repo.Spec.SquashMergeCommitMessage)
 		needsUpdate = true
 	}
 	// MergeCommitTitle
 	if ptrNonNilAndNotEqualTo(repo.Spec.MergeCommitTitle, (githubv1alpha1.MergeCommitTitle)(ghRepo.GetMergeCommitTitle())) {
 		log.Info("repository MergeCommitTitle update", "from", ghRepo.GetMergeCommitTitle(), "to", repo.Spec.MergeCommitTitle)
+		recordDrift(ctx, "mergeCommitTitle", repo.Spec.MergeCommitTitle, ghRepo.GetMergeCommitTitle())
 		updateRepo.MergeCommitTitle = (*string)(repo.Spec.MergeCommitTitle)
 		needsUpdate = true
 	}
 	// MergeCommitMessage
 	if ptrNonNilAndNotEqualTo(repo.Spec.MergeCommitMessage, (githubv1alpha1.MergeCommitMessage)(ghRepo.GetMergeCommitMessage())) {
 		log.Info("repository MergeCommitMessage update", "from", ghRepo.GetMergeCommitMessage(), "to", repo.Spec.MergeCommitMessage)
+		recordDrift(ctx, "mergeCommitMessage", repo.Spec.MergeCommitMessage, ghRepo.GetMergeCommitMessage())
 		updateRepo.MergeCommitMessage = (*string)(repo.Spec.MergeCommitMessage)
 		needsUpdate = true
 	}
 	// Topics
 	if !cmpSlices(repo.Spec.Topics, ghRepo.Topics) {
 		log.Info("repository Topics update", "from", ghRepo.Topics, "to", repo.Spec.Topics)
+		recordDrift(ctx, "topics", repo.Spec.Topics, ghRepo.Topics)
 		updateRepo.Topics = repo.Spec.Topics
 		needsTopicsUpdate = true
 	}
 	// Archived
 	if ptrNonNilAndNotEqualTo(repo.Spec.Archived, ghRepo.GetArchived()) {
 		log.Info("repository Archived update", "from", ghRepo.GetArchived(), "to", repo.Spec.Archived)
+		recordDrift(ctx, "archived", repo.Spec.Archived, ghRepo.GetArchived())
 		updateRepo.Archived = repo.Spec.Archived
 		needsUpdate = true
 	}
 	// HasIssues
 	if ptrNonNilAndNotEqualTo(repo.Spec.HasIssues, ghRepo.GetHasIssues()) {
 		log.Info("repository HasIssues update", "from", ghRepo.GetHasIssues(), "to", repo.Spec.HasIssues)
+		recordDrift(ctx, "hasIssues", repo.Spec.HasIssues, ghRepo.GetHasIssues())
 		updateRepo.HasIssues = repo.Spec.HasIssues
 		needsUpdate = true
 	}
 	// HasWiki
 	if ptrNonNilAndNotEqualTo(repo.Spec.HasWiki, ghRepo.GetHasWiki()) {
 		log.Info("repository HasWiki update", "from", ghRepo.GetHasWiki(), "to", repo.Spec.HasWiki)
+		recordDrift(ctx, "hasWiki", repo.Spec.HasWiki, ghRepo.GetHasWiki())
 		updateRepo.HasWiki = repo.Spec.HasWiki
 		needsUpdate = true
 	}
 	// HasProjects
 	if ptrNonNilAndNotEqualTo(repo.Spec.HasProjects, ghRepo.GetHasProjects()) {
 		log.Info("repository HasProjects update", "from", ghRepo.GetHasProjects(), "to", repo.Spec.HasProjects)
+		recordDrift(ctx, "hasProjects", repo.Spec.HasProjects, ghRepo.GetHasProjects())
 		updateRepo.HasProjects = repo.Spec.HasProjects
 		needsUpdate = true
 	}
 	// HasDownloads
 	if ptrNonNilAndNotEqualTo(repo.Spec.HasDownloads, ghRepo.GetHasDownloads()) {
 		log.Info("repository HasDownloads update", "from", ghRepo.GetHasDownloads(), "to", repo.Spec.HasDownloads)
+		recordDrift(ctx, "hasDownloads", repo.Spec.HasDownloads, ghRepo.GetHasDownloads())
 		updateRepo.HasDownloads = repo.Spec.HasDownloads
 		needsUpdate = true
 	}
 	// HasDiscussions
 	if ptrNonNilAndNotEqualTo(repo.Spec.HasDiscussions, ghRepo.GetHasDiscussions()) {
 		log.Info("repository HasDiscussions update", "from", ghRepo.GetHasDiscussions(), "to", repo.Spec.HasDiscussions)
-		updateRepo.HasDownloads = repo.Spec.HasDownloads
+		recordDrift(ctx, "hasDiscussions", repo.Spec.HasDiscussions, ghRepo.GetHasDiscussions())
+		updateRepo.HasDiscussions = repo.Spec.HasDiscussions
 		needsUpdate = true
 	}
 	// Visibility
 	if ptrNonNilAndNotEqualTo(repo.Spec.Visibility, ghRepo.GetVisibility()) {
 		log.Info("repository Visibility update", "from", ghRepo.GetVisibility(), "to", repo.Spec.Visibility)
+		recordDrift(ctx, "visibility", repo.Spec.Visibility, ghRepo.GetVisibility())
 		updateRepo.Visibility = repo.Spec.Visibility
 		needsUpdate = true
 	}
 
 	// perform update if necessary
-	// TODO: more granular updates (allow just topic update)
-	if needsUpdate || needsTopicsUpdate || repo.Status.LastUpdateTimestamp == nil {
+	if needsUpdate && shouldMutate(ctx, "update repository %s/%s", ghRepo.GetOwner().GetLogin(), ghRepo.GetName()) {
 		log.Info("updating repository", "name", ghRepo.GetName())
 		updated, err := r.GitHubClient.UpdateRepositoryByName(ctx, ghRepo.GetOwner().GetLogin(), ghRepo.GetName(), updateRepo)
 		if err != nil {
 			log.Error(err, "error updating repository", "name", repo.Spec.Name)
 			return err
 		}
+		r.Recorder.Event(repo, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub repository")
+		ghRepo = updated
+	}
 
-		ghRepoTopics, err := r.GitHubClient.UpdateRepositoryTopics(ctx, repo.Spec.Owner, repo.Spec.Name, repo.Spec.Topics)
+	if needsTopicsUpdate && shouldMutate(ctx, "update repository %s/%s topics to %v", ghRepo.GetOwner().GetLogin(), ghRepo.GetName(), repo.Spec.Topics) {
+		ghRepoTopics, err := r.GitHubClient.UpdateRepositoryTopics(ctx, ghRepo.GetOwner().GetLogin(), ghRepo.GetName(), repo.Spec.Topics)
 		if err != nil {
 			log.Error(err, "error updating repository topics", "name", repo.Spec.Name)
+		} else {
+			ghRepo.Topics = ghRepoTopics
+			r.Recorder.Event(repo, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub repository topics")
 		}
-		ghRepo.Topics = ghRepoTopics
+	}
 
-		ghRepo = updated
+	// a repository renamed or transferred outside the operator is reflected in status even if
+	// nothing is updated, since dependent resources and credentials are resolved through it
+	moved := repo.Status.OwnerLogin == nil || *repo.Status.OwnerLogin != ghRepo.GetOwner().GetLogin() ||
+		repo.Status.Name == nil || *repo.Status.Name != ghRepo.GetName()
 
+	// populate status from the updated repository, or the observed repository if it wasn't updated
+	if needsUpdate || needsTopicsUpdate || moved || repo.Status.LastUpdateTimestamp == nil {
 		now := v1.Now()
 
 		owner := ghRepo.GetOwner()
@@ -445,16 +621,345 @@ func (r *RepositoryReconciler) updateRepository(ctx context.Context, repo *githu
 			HasDownloads:                 ghRepo.HasDownloads,
 			HasDiscussions:               ghRepo.HasDiscussions,
 			Visibility:                   ghRepo.Visibility,
+			Conditions:                   repo.Status.Conditions,
+			ObservedGeneration:           repo.Status.ObservedGeneration,
+			Drift:                        repo.Status.Drift,
+			Plan:                         repo.Status.Plan,
+		}
+
+		// update status
+		if err := r.Status().Update(ctx, repo); err != nil {
+			log.Error(err, "error updating Repository status", "name", repo.Spec.Name)
+		}
+	}
+	return nil
+}
+
+// invites, updates and optionally removes direct collaborators so that they match the
+// Repository spec. Collaborators are left alone unless listed or pruned. Modifies repository
+// status in place.
+func (r *RepositoryReconciler) updateRepositoryCollaborators(ctx context.Context, repo *githubv1alpha1.Repository, ghRepo *github.Repository) error {
+	log := log.FromContext(ctx)
+
+	owner := ghRepo.GetOwner().GetLogin()
+	name := ghRepo.GetName()
+	prune := repo.Spec.PruneCollaborators != nil && *repo.Spec.PruneCollaborators
+	if len(repo.Spec.Collaborators) == 0 && !prune {
+		return nil
+	}
+
+	// logins are not case sensitive, so key everything on the lowercase login
+	specLogins := map[string]string{}
+	specPermissions := map[string]githubv1alpha1.RepositoryPermission{}
+	for user, permission := range repo.Spec.Collaborators {
+		specLogins[strings.ToLower(user)] = user
+		specPermissions[strings.ToLower(user)] = permission
+	}
+
+	collaborators, err := r.GitHubClient.GetRepositoryCollaborators(ctx, owner, name, "direct")
+	if err != nil {
+		log.Error(err, "error getting repository collaborators")
+		return err
+	}
+	invitations, err := r.GitHubClient.GetRepositoryInvitations(ctx, owner, name)
+	if err != nil {
+		log.Error(err, "error getting repository invitations")
+		return err
+	}
+
+	observed, err := r.updateRepositoryCollaboratorPermissions(ctx, owner, name, collaborators, specPermissions, prune)
+	if err != nil {
+		return err
+	}
+	pending, invited, err := r.updateRepositoryInvitations(ctx, owner, name, invitations, specPermissions, prune)
+	if err != nil {
+		return err
+	}
+
+	for key, permission := range specPermissions {
+		if _, ok := observed[key]; ok {
+			continue
+		}
+		// don't resend invitations to users who haven't accepted yet
+		if _, ok := invited[key]; ok {
+			continue
+		}
+
+		user := specLogins[key]
+		if !shouldMutate(ctx, "add repository %s/%s collaborator %s with permission %s", owner, name, user, permission) {
+			continue
+		}
+		log.Info("adding repository collaborator", "repository", name, "user", user, "permission", permission)
+		invitation, err := r.GitHubClient.UpdateRepositoryCollaborator(ctx, owner, name, user, string(permission))
+		if err != nil {
+			log.Error(err, "error adding repository collaborator")
+			return err
+		}
+		if invitation != nil {
+			pending[user] = permission
+		}
+	}
+
+	// effective permissions include access granted through teams and organization roles
+	effective, err := r.GitHubClient.GetRepositoryCollaborators(ctx, owner, name, "all")
+	if err != nil {
+		log.Error(err, "error getting repository collaborators")
+		return err
+	}
+	statusCollaborators := map[string]githubv1alpha1.RepositoryPermission{}
+	for _, collaborator := range effective {
+		if _, ok := specPermissions[strings.ToLower(collaborator.Login)]; ok {
+			statusCollaborators[collaborator.Login] = githubv1alpha1.RepositoryPermission(collaborator.Permission)
+		}
+	}
+
+	repo.Status.Collaborators = statusCollaborators
+	repo.Status.PendingCollaborators = pending
+	// update status
+	if err := r.Status().Update(ctx, repo); err != nil {
+		log.Error(err, "error updating Repository status", "name", repo.Spec.Name)
+	}
+
+	return nil
+}
+
+// updateRepositoryCollaboratorPermissions updates the permissions of direct collaborators to those in
+// specPermissions, keyed by lowercase login, and removes collaborators missing from it if prune is set.
+// It returns the lowercase logins of all direct collaborators.
+func (r *RepositoryReconciler) updateRepositoryCollaboratorPermissions(ctx context.Context, owner, name string, collaborators []*gh.RepositoryCollaborator, specPermissions map[string]githubv1alpha1.RepositoryPermission, prune bool) (map[string]struct{}, error) {
+	log := log.FromContext(ctx)
+
+	observed := map[string]struct{}{}
+	for _, collaborator := range collaborators {
+		key := strings.ToLower(collaborator.Login)
+		observed[key] = struct{}{}
+
+		permission, ok := specPermissions[key]
+		if !ok {
+			if prune && shouldMutate(ctx, "remove repository %s/%s collaborator %s", owner, name, collaborator.Login) {
+				log.Info("removing repository collaborator", "repository", name, "user", collaborator.Login)
+				if err := r.GitHubClient.RemoveRepositoryCollaborator(ctx, owner, name, collaborator.Login); err != nil {
+					log.Error(err, "error removing repository collaborator")
+					return nil, err
+				}
+			}
+			continue
+		}
+		if permission != githubv1alpha1.RepositoryPermission(collaborator.Permission) && shouldMutate(ctx, "update repository %s/%s collaborator %s permission from %s to %s", owner, name, collaborator.Login, collaborator.Permission, permission) {
+			log.Info("updating repository collaborator permission", "repository", name, "user", collaborator.Login, "from", collaborator.Permission, "to", permission)
+			if _, err := r.GitHubClient.UpdateRepositoryCollaborator(ctx, owner, name, collaborator.Login, string(permission)); err != nil {
+				log.Error(err, "error updating repository collaborator permission")
+				return nil, err
+			}
+		}
+	}
+	return observed, nil
+}
+
+// updateRepositoryInvitations updates the permissions of pending invitations to those in specPermissions,
+// keyed by lowercase login, and deletes invitations missing from it if prune is set. It returns the
+// permissions of the invitations left pending by login, and their lowercase logins.
+func (r *RepositoryReconciler) updateRepositoryInvitations(ctx context.Context, owner, name string, invitations []*gh.RepositoryCollaboratorInvitation, specPermissions map[string]githubv1alpha1.RepositoryPermission, prune bool) (pending map[string]githubv1alpha1.RepositoryPermission, invited map[string]struct{}, err error) {
+	log := log.FromContext(ctx)
+
+	pending = map[string]githubv1alpha1.RepositoryPermission{}
+	invited = map[string]struct{}{}
+	for _, invitation := range invitations {
+		key := strings.ToLower(invitation.Login)
+
+		permission, ok := specPermissions[key]
+		if !ok {
+			if prune && shouldMutate(ctx, "delete repository %s/%s invitation of %s", owner, name, invitation.Login) {
+				log.Info("deleting repository invitation", "repository", name, "user", invitation.Login)
+				if err := r.GitHubClient.DeleteRepositoryInvitation(ctx, owner, name, invitation.Id); err != nil {
+					log.Error(err, "error deleting repository invitation")
+					return nil, nil, err
+				}
+			}
+			continue
+		}
+		if permission != githubv1alpha1.RepositoryPermission(invitation.Permission) {
+			if shouldMutate(ctx, "update repository %s/%s invitation of %s permission from %s to %s", owner, name, invitation.Login, invitation.Permission, permission) {
+				log.Info("updating repository invitation permission", "repository", name, "user", invitation.Login, "from", invitation.Permission, "to", permission)
+				if err := r.GitHubClient.UpdateRepositoryInvitation(ctx, owner, name, invitation.Id, string(permission)); err != nil {
+					log.Error(err, "error updating repository invitation permission")
+					return nil, nil, err
+				}
+			} else {
+				permission = githubv1alpha1.RepositoryPermission(invitation.Permission)
+			}
+		}
+		pending[invitation.Login] = permission
+		invited[key] = struct{}{}
+	}
+	return pending, invited, nil
+}
+
+// creates, updates, renames and optionally prunes issue labels so that they match
+// the Repository spec. Labels are left alone unless listed or pruned. Modifies repository
+// status in place.
+func (r *RepositoryReconciler) updateRepositoryLabels(ctx context.Context, repo *githubv1alpha1.Repository, ghRepo *github.Repository) error {
+	log := log.FromContext(ctx)
+
+	owner := ghRepo.GetOwner().GetLogin()
+	name := ghRepo.GetName()
+	prune := repo.Spec.PruneLabels != nil && *repo.Spec.PruneLabels
+	if len(repo.Spec.Labels) == 0 && !prune {
+		return nil
+	}
+
+	labels, err := r.GitHubClient.GetRepositoryLabels(ctx, owner, name)
+	if err != nil {
+		log.Error(err, "error getting repository labels")
+		return err
+	}
+
+	// label names are not case sensitive, so key everything on the lowercase name
+	observed := map[string]*github.Label{}
+	for _, label := range labels {
+		observed[strings.ToLower(label.GetName())] = label
+	}
+	desired := map[string]struct{}{}
+	for _, label := range repo.Spec.Labels {
+		desired[strings.ToLower(label.Name)] = struct{}{}
+	}
+
+	// labels that have been accounted for by the spec, either directly or as a previous name
+	matched := map[string]struct{}{}
+	statusLabels := []string{}
+	for _, label := range repo.Spec.Labels {
+		key := strings.ToLower(label.Name)
+
+		current, ok := observed[key]
+		if !ok {
+			for _, previousName := range label.PreviousNames {
+				previousKey := strings.ToLower(previousName)
+				if _, ok := matched[previousKey]; ok {
+					continue
+				}
+				// never rename a label that is itself declared in the spec
+				if _, ok := desired[previousKey]; ok {
+					continue
+				}
+				if previous, ok := observed[previousKey]; ok {
+					current = previous
+					matched[previousKey] = struct{}{}
+					break
+				}
+			}
+		}
+		matched[key] = struct{}{}
+
+		if current == nil {
+			if !shouldMutate(ctx, "create repository %s/%s label %s", owner, name, label.Name) {
+				continue
+			}
+			log.Info("creating repository label", "repository", name, "label", label.Name)
+			_, err := r.GitHubClient.CreateRepositoryLabel(ctx, owner, name, &github.Label{
+				Name:        github.String(label.Name),
+				Color:       github.String(strings.ToLower(label.Color)),
+				Description: label.Description,
+			})
+			if err != nil {
+				log.Error(err, "error creating repository label")
+				return err
+			}
+			statusLabels = append(statusLabels, label.Name)
+			continue
 		}
 
+		update := &gh.RepositoryLabelUpdate{}
+		needsUpdate := false
+		if current.GetName() != label.Name {
+			log.Info("repository label Name update", "from", current.GetName(), "to", label.Name)
+			recordDrift(ctx, "labels["+label.Name+"].name", label.Name, current.GetName())
+			update.NewName = github.String(label.Name)
+			needsUpdate = true
+		}
+		if !strings.EqualFold(current.GetColor(), label.Color) {
+			log.Info("repository label Color update", "label", label.Name, "from", current.GetColor(), "to", label.Color)
+			recordDrift(ctx, "labels["+label.Name+"].color", label.Color, current.GetColor())
+			update.Color = github.String(strings.ToLower(label.Color))
+			needsUpdate = true
+		}
+		if ptrNonNilAndNotEqualTo(label.Description, current.GetDescription()) {
+			log.Info("repository label Description update", "label", label.Name, "from", current.GetDescription(), "to", label.Description)
+			recordDrift(ctx, "labels["+label.Name+"].description", label.Description, current.GetDescription())
+			update.Description = label.Description
+			needsUpdate = true
+		}
+		if needsUpdate {
+			if !shouldMutate(ctx, "update repository %s/%s label %s", owner, name, current.GetName()) {
+				statusLabels = append(statusLabels, current.GetName())
+				continue
+			}
+			log.Info("updating repository label", "repository", name, "label", current.GetName())
+			if _, err := r.GitHubClient.UpdateRepositoryLabel(ctx, owner, name, current.GetName(), update); err != nil {
+				log.Error(err, "error updating repository label")
+				return err
+			}
+		}
+		statusLabels = append(statusLabels, label.Name)
+	}
+
+	if prune {
+		for key, label := range observed {
+			if _, ok := matched[key]; ok {
+				continue
+			}
+			if !shouldMutate(ctx, "delete repository %s/%s label %s", owner, name, label.GetName()) {
+				continue
+			}
+			log.Info("deleting repository label", "repository", name, "label", label.GetName())
+			if err := r.GitHubClient.DeleteRepositoryLabel(ctx, owner, name, label.GetName()); err != nil {
+				log.Error(err, "error deleting repository label")
+				return err
+			}
+		}
+	}
+
+	sort.Strings(statusLabels)
+	if !cmpSlices(statusLabels, repo.Status.Labels) {
+		repo.Status.Labels = statusLabels
 		// update status
 		if err := r.Status().Update(ctx, repo); err != nil {
 			log.Error(err, "error updating Repository status", "name", repo.Spec.Name)
 		}
 	}
+
 	return nil
 }
 
+// renames the repository with a tombstone suffix, so that its name can be reused,
+// and archives it
+func (r *RepositoryReconciler) archiveRepository(ctx context.Context, ghRepo *github.Repository) error {
+	log := log.FromContext(ctx)
+
+	// archived repositories are read-only, so there is nothing left to do
+	if ghRepo.GetArchived() {
+		return nil
+	}
+
+	owner := ghRepo.GetOwner().GetLogin()
+	name := ghRepo.GetName()
+
+	// the repository id keeps the tombstone name unique and stable across retries
+	suffix := fmt.Sprintf("%s-%d", repositoryTombstoneSuffix, ghRepo.GetID())
+	if !strings.HasSuffix(name, suffix) {
+		tombstone := name + suffix
+		log.Info("renaming repository", "from", name, "to", tombstone)
+		updated, err := r.GitHubClient.UpdateRepositoryByName(ctx, owner, name, &github.Repository{Name: github.String(tombstone)})
+		if err != nil {
+			return err
+		}
+		name = updated.GetName()
+	}
+
+	log.Info("archiving repository", "name", name)
+	_, err := r.GitHubClient.UpdateRepositoryByName(ctx, owner, name, &github.Repository{Archived: github.Bool(true)})
+	return err
+}
+
 func (r *RepositoryReconciler) deleteRepository(ctx context.Context, repo *githubv1alpha1.Repository) error {
 	if repo.Status.OwnerLogin == nil {
 		return fmt.Errorf("repo OwnerLogin is nil")
@@ -23,7 +23,9 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -85,6 +87,7 @@ var _ = Describe("Repository Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -123,6 +126,7 @@ var _ = Describe("Repository Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -168,6 +172,7 @@ var _ = Describe("Repository Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -200,6 +205,7 @@ var _ = Describe("Repository Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -233,6 +239,7 @@ var _ = Describe("Repository Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -295,6 +302,7 @@ var _ = Describe("Repository Controller", func() {
 				Client:                   k8sClient,
 				Scheme:                   k8sClient.Scheme(),
 				GitHubClient:             ghClient,
+				Recorder:                 &record.FakeRecorder{},
 				DeleteOnResourceDeletion: true,
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -340,6 +348,7 @@ var _ = Describe("Repository Controller", func() {
 				Client:                   k8sClient,
 				Scheme:                   k8sClient.Scheme(),
 				GitHubClient:             ghClient,
+				Recorder:                 &record.FakeRecorder{},
 				DeleteOnResourceDeletion: true,
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -376,6 +385,7 @@ var _ = Describe("Repository Controller", func() {
 				Client:                   k8sClient,
 				Scheme:                   k8sClient.Scheme(),
 				GitHubClient:             ghClient,
+				Recorder:                 &record.FakeRecorder{},
 				DeleteOnResourceDeletion: true,
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -396,6 +406,7 @@ var _ = Describe("Repository Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -422,4 +433,574 @@ var _ = Describe("Repository Controller", func() {
 			Expect(ghClient.DeleteRepositoryByName(ctx, ghRepository.GetOrganization().GetLogin(), ghRepository.GetName())).To(Succeed())
 		})
 	})
+
+	Context("When a Repository resource manages collaborators", func() {
+		collaboratorsRepoName := ghTestResourcePrefix + "collaborators"
+		alice, bob, carol := "repo-collaborators-alice", "repo-collaborators-bob", "repo-collaborators-carol"
+		// users that are never added to the organization, so that they are invited
+		dave, erin := "repo-collaborators-dave", "repo-collaborators-erin"
+
+		controllerReconciler := &RepositoryReconciler{}
+
+		// createRepository creates the Repository resource and a GitHub repository with the given collaborators
+		// and invitations. Inviting users enables invitations.
+		createRepository := func(spec githubv1alpha1.RepositorySpec, collaborators, invitees map[string]string) {
+			_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+				Name: &collaboratorsRepoName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			for login, permission := range collaborators {
+				_, err := fakeGHClient.UpdateRepositoryCollaborator(ctx, testOrganization, collaboratorsRepoName, login, permission)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			if len(invitees) > 0 {
+				fakeServer.SetInvitations(true)
+			}
+			for login, permission := range invitees {
+				invitation, err := fakeGHClient.UpdateRepositoryCollaborator(ctx, testOrganization, collaboratorsRepoName, login, permission)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(invitation).NotTo(BeNil())
+			}
+
+			spec.Owner = testOrganization
+			spec.Name = collaboratorsRepoName
+			Expect(k8sClient.Create(ctx, &githubv1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: spec,
+			})).To(Succeed())
+		}
+
+		reconcileRepository := func() *githubv1alpha1.Repository {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &githubv1alpha1.Repository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource
+		}
+
+		collaborators := func() map[string]string {
+			out := map[string]string{}
+			direct, err := fakeGHClient.GetRepositoryCollaborators(ctx, testOrganization, collaboratorsRepoName, "direct")
+			Expect(err).NotTo(HaveOccurred())
+			for _, collaborator := range direct {
+				out[collaborator.Login] = collaborator.Permission
+			}
+			return out
+		}
+
+		invitations := func() map[string]string {
+			out := map[string]string{}
+			pending, err := fakeGHClient.GetRepositoryInvitations(ctx, testOrganization, collaboratorsRepoName)
+			Expect(err).NotTo(HaveOccurred())
+			for _, invitation := range pending {
+				out[invitation.Login] = invitation.Permission
+			}
+			return out
+		}
+
+		BeforeEach(func() {
+			for _, login := range []string{alice, bob, carol, dave, erin} {
+				if _, err := fakeGHClient.GetUser(ctx, login); err != nil {
+					fakeServer.AddUser(login)
+				}
+			}
+			controllerReconciler = &RepositoryReconciler{
+				Client:       k8sClient,
+				Scheme:       k8sClient.Scheme(),
+				GitHubClient: fakeGHClient,
+				Recorder:     &record.FakeRecorder{},
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance Repository")
+			cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Repository{})
+
+			By("Cleaning up the GitHub repository")
+			Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, collaboratorsRepoName)).To(Succeed())
+			fakeServer.SetInvitations(false)
+		})
+
+		It("should add collaborators and update permissions without removing unlisted ones", func() {
+			createRepository(githubv1alpha1.RepositorySpec{
+				Collaborators: map[string]githubv1alpha1.RepositoryPermission{
+					alice: githubv1alpha1.Push,
+					bob:   githubv1alpha1.Admin,
+				},
+			}, map[string]string{bob: "pull", carol: "triage"}, nil)
+
+			resource := reconcileRepository()
+
+			Expect(collaborators()).To(Equal(map[string]string{alice: "push", bob: "admin", carol: "triage"}))
+			Expect(resource.Status.Collaborators).To(Equal(map[string]githubv1alpha1.RepositoryPermission{
+				alice: githubv1alpha1.Push,
+				bob:   githubv1alpha1.Admin,
+			}))
+		})
+
+		It("should not manage collaborators if they are unset and not pruned", func() {
+			createRepository(githubv1alpha1.RepositorySpec{}, map[string]string{carol: "triage"}, nil)
+
+			resource := reconcileRepository()
+
+			Expect(collaborators()).To(Equal(map[string]string{carol: "triage"}))
+			Expect(resource.Status.Collaborators).To(BeEmpty())
+		})
+
+		It("should remove unlisted collaborators and invitations if pruned", func() {
+			createRepository(githubv1alpha1.RepositorySpec{
+				Collaborators: map[string]githubv1alpha1.RepositoryPermission{
+					alice: githubv1alpha1.Maintain,
+					dave:  githubv1alpha1.Push,
+				},
+				PruneCollaborators: github.Bool(true),
+			}, map[string]string{alice: "pull", carol: "triage"}, map[string]string{dave: "pull", erin: "admin"})
+
+			resource := reconcileRepository()
+
+			Expect(collaborators()).To(Equal(map[string]string{alice: "maintain"}))
+			Expect(invitations()).To(Equal(map[string]string{dave: "push"}))
+			Expect(resource.Status.Collaborators).To(Equal(map[string]githubv1alpha1.RepositoryPermission{
+				alice: githubv1alpha1.Maintain,
+			}))
+			Expect(resource.Status.PendingCollaborators).To(Equal(map[string]githubv1alpha1.RepositoryPermission{
+				dave: githubv1alpha1.Push,
+			}))
+		})
+
+		It("should remove all direct collaborators if pruned without collaborators", func() {
+			createRepository(githubv1alpha1.RepositorySpec{
+				PruneCollaborators: github.Bool(true),
+			}, map[string]string{carol: "triage"}, map[string]string{erin: "admin"})
+
+			resource := reconcileRepository()
+
+			Expect(collaborators()).To(BeEmpty())
+			Expect(invitations()).To(BeEmpty())
+			Expect(resource.Status.Collaborators).To(BeEmpty())
+			Expect(resource.Status.PendingCollaborators).To(BeEmpty())
+		})
+
+		It("should only plan collaborator changes under the Observe management policy", func() {
+			observe := githubv1alpha1.ManagementPolicyObserve
+			createRepository(githubv1alpha1.RepositorySpec{
+				Collaborators: map[string]githubv1alpha1.RepositoryPermission{
+					alice: githubv1alpha1.Push,
+					bob:   githubv1alpha1.Admin,
+				},
+				PruneCollaborators: github.Bool(true),
+				ManagementPolicy:   &observe,
+			}, map[string]string{bob: "pull", carol: "triage"}, nil)
+
+			resource := reconcileRepository()
+
+			Expect(collaborators()).To(Equal(map[string]string{bob: "pull", carol: "triage"}))
+			Expect(resource.Status.Plan).To(ConsistOf(
+				"remove repository "+testOrganization+"/"+collaboratorsRepoName+" collaborator "+carol,
+				"update repository "+testOrganization+"/"+collaboratorsRepoName+" collaborator "+bob+" permission from pull to admin",
+				"add repository "+testOrganization+"/"+collaboratorsRepoName+" collaborator "+alice+" with permission push",
+			))
+		})
+	})
+
+	Context("When a Repository resource manages labels", func() {
+		labelsRepoName := ghTestResourcePrefix + "labels"
+
+		controllerReconciler := &RepositoryReconciler{}
+
+		// createRepository creates the Repository resource and a GitHub repository with the given labels
+		createRepository := func(spec githubv1alpha1.RepositorySpec, labels ...*github.Label) {
+			_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+				Name: &labelsRepoName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			for _, label := range labels {
+				_, err := fakeGHClient.CreateRepositoryLabel(ctx, testOrganization, labelsRepoName, label)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			spec.Owner = testOrganization
+			spec.Name = labelsRepoName
+			Expect(k8sClient.Create(ctx, &githubv1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: spec,
+			})).To(Succeed())
+		}
+
+		reconcileRepository := func() *githubv1alpha1.Repository {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &githubv1alpha1.Repository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource
+		}
+
+		// labels returns the GitHub labels of the repository by name
+		labels := func() map[string]*github.Label {
+			out := map[string]*github.Label{}
+			observed, err := fakeGHClient.GetRepositoryLabels(ctx, testOrganization, labelsRepoName)
+			Expect(err).NotTo(HaveOccurred())
+			for _, label := range observed {
+				out[label.GetName()] = label
+			}
+			return out
+		}
+
+		newLabel := func(name, color string) *github.Label {
+			return &github.Label{Name: github.String(name), Color: github.String(color)}
+		}
+
+		BeforeEach(func() {
+			controllerReconciler = &RepositoryReconciler{
+				Client:       k8sClient,
+				Scheme:       k8sClient.Scheme(),
+				GitHubClient: fakeGHClient,
+				Recorder:     &record.FakeRecorder{},
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance Repository")
+			cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Repository{})
+
+			By("Cleaning up the GitHub repository")
+			Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, labelsRepoName)).To(Succeed())
+		})
+
+		It("should create and update listed labels without deleting unlisted ones", func() {
+			createRepository(githubv1alpha1.RepositorySpec{
+				Labels: []githubv1alpha1.RepositoryLabel{
+					{Name: "bug", Color: "D73A4A", Description: github.String("Something isn't working")},
+					{Name: "enhancement", Color: "a2eeef"},
+				},
+			}, newLabel("Bug", "ffffff"), newLabel("question", "d876e3"))
+
+			resource := reconcileRepository()
+
+			observed := labels()
+			Expect(observed).To(HaveLen(3))
+			Expect(observed).To(HaveKeyWithValue("bug", And(
+				HaveField("Color", HaveValue(Equal("d73a4a"))),
+				HaveField("Description", HaveValue(Equal("Something isn't working"))),
+			)))
+			Expect(observed).To(HaveKeyWithValue("enhancement", HaveField("Color", HaveValue(Equal("a2eeef")))))
+			Expect(observed).To(HaveKey("question"))
+			Expect(resource.Status.Labels).To(Equal([]string{"bug", "enhancement"}))
+		})
+
+		It("should rename a label listed under a previous name", func() {
+			createRepository(githubv1alpha1.RepositorySpec{
+				Labels: []githubv1alpha1.RepositoryLabel{
+					{Name: "defect", Color: "d73a4a", PreviousNames: []string{"issue", "bug"}},
+				},
+			}, newLabel("bug", "d73a4a"))
+			bug := labels()["bug"]
+
+			resource := reconcileRepository()
+
+			observed := labels()
+			Expect(observed).To(HaveLen(1))
+			Expect(observed).To(HaveKeyWithValue("defect", HaveField("ID", Equal(bug.ID))))
+			Expect(resource.Status.Labels).To(Equal([]string{"defect"}))
+		})
+
+		It("should not rename a label whose previous name is listed itself", func() {
+			createRepository(githubv1alpha1.RepositorySpec{
+				Labels: []githubv1alpha1.RepositoryLabel{
+					{Name: "defect", Color: "d73a4a", PreviousNames: []string{"bug"}},
+					{Name: "bug", Color: "d73a4a"},
+				},
+			}, newLabel("bug", "d73a4a"))
+			bug := labels()["bug"]
+
+			resource := reconcileRepository()
+
+			observed := labels()
+			Expect(observed).To(HaveLen(2))
+			Expect(observed).To(HaveKeyWithValue("bug", HaveField("ID", Equal(bug.ID))))
+			Expect(observed).To(HaveKeyWithValue("defect", HaveField("ID", Not(Equal(bug.ID)))))
+			Expect(resource.Status.Labels).To(Equal([]string{"bug", "defect"}))
+		})
+
+		It("should delete unlisted labels if pruned", func() {
+			createRepository(githubv1alpha1.RepositorySpec{
+				Labels: []githubv1alpha1.RepositoryLabel{
+					{Name: "defect", Color: "d73a4a", PreviousNames: []string{"bug"}},
+				},
+				PruneLabels: github.Bool(true),
+			}, newLabel("bug", "d73a4a"), newLabel("question", "d876e3"))
+
+			resource := reconcileRepository()
+
+			Expect(labels()).To(ConsistOf(HaveField("Name", HaveValue(Equal("defect")))))
+			Expect(resource.Status.Labels).To(Equal([]string{"defect"}))
+		})
+
+		It("should delete all labels if pruned without labels", func() {
+			createRepository(githubv1alpha1.RepositorySpec{
+				PruneLabels: github.Bool(true),
+			}, newLabel("bug", "d73a4a"), newLabel("question", "d876e3"))
+
+			resource := reconcileRepository()
+
+			Expect(labels()).To(BeEmpty())
+			Expect(resource.Status.Labels).To(BeEmpty())
+		})
+
+		It("should only plan label changes under the Observe management policy", func() {
+			observe := githubv1alpha1.ManagementPolicyObserve
+			createRepository(githubv1alpha1.RepositorySpec{
+				Labels: []githubv1alpha1.RepositoryLabel{
+					{Name: "defect", Color: "d73a4a", PreviousNames: []string{"bug"}},
+					{Name: "enhancement", Color: "a2eeef"},
+				},
+				PruneLabels:      github.Bool(true),
+				ManagementPolicy: &observe,
+			}, newLabel("bug", "d73a4a"), newLabel("question", "d876e3"))
+
+			resource := reconcileRepository()
+
+			Expect(labels()).To(HaveLen(2))
+			Expect(resource.Status.Plan).To(ConsistOf(
+				"update repository "+testOrganization+"/"+labelsRepoName+" label bug",
+				"create repository "+testOrganization+"/"+labelsRepoName+" label enhancement",
+				"delete repository "+testOrganization+"/"+labelsRepoName+" label question",
+			))
+		})
+	})
+
+	Context("When the GitHub repository drifts", func() {
+		driftRepoName := ghTestResourcePrefix + "drift"
+
+		controllerReconciler := &RepositoryReconciler{}
+
+		reconcileRepository := func() *githubv1alpha1.Repository {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &githubv1alpha1.Repository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource
+		}
+
+		drifted := func(resource *githubv1alpha1.Repository) *metav1.Condition {
+			return meta.FindStatusCondition(resource.Status.Conditions, githubv1alpha1.ConditionTypeDrifted)
+		}
+
+		BeforeEach(func() {
+			_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+				Name:        &driftRepoName,
+				Description: github.String("managed"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, &githubv1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: githubv1alpha1.RepositorySpec{
+					Owner:       testOrganization,
+					Name:        driftRepoName,
+					Description: github.String("managed"),
+				},
+			})).To(Succeed())
+			controllerReconciler = &RepositoryReconciler{
+				Client:       k8sClient,
+				Scheme:       k8sClient.Scheme(),
+				GitHubClient: fakeGHClient,
+				Recorder:     &record.FakeRecorder{},
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance Repository")
+			cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Repository{})
+
+			By("Cleaning up the GitHub repository")
+			Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, driftRepoName)).To(Succeed())
+		})
+
+		It("should report drift until the GitHub repository matches the spec again", func() {
+			resource := reconcileRepository()
+			Expect(resource.Status.Drift).To(BeEmpty())
+			Expect(drifted(resource)).To(HaveField("Status", metav1.ConditionFalse))
+
+			By("Changing the GitHub repository outside of the operator")
+			_, err := fakeGHClient.UpdateRepositoryByName(ctx, testOrganization, driftRepoName, &github.Repository{
+				Description: github.String("changed"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource = reconcileRepository()
+			Expect(resource.Status.Drift).To(ConsistOf(And(
+				HaveField("Field", "description"),
+				HaveField("Desired", "managed"),
+				HaveField("Observed", "changed"),
+			)))
+			Expect(drifted(resource)).To(And(
+				HaveField("Status", metav1.ConditionTrue),
+				HaveField("Reason", githubv1alpha1.ConditionReasonDriftDetected),
+			))
+			ghRepo, err := fakeGHClient.GetRepositoryByName(ctx, testOrganization, driftRepoName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghRepo.GetDescription()).To(Equal("managed"))
+
+			By("Reconciling the corrected GitHub repository")
+			resource = reconcileRepository()
+			Expect(resource.Status.Drift).To(BeEmpty())
+			Expect(drifted(resource)).To(And(
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", githubv1alpha1.ConditionReasonNoDrift),
+			))
+		})
+
+		It("should keep reporting drift it doesn't correct under the Observe management policy", func() {
+			resource := reconcileRepository()
+			observe := githubv1alpha1.ManagementPolicyObserve
+			resource.Spec.ManagementPolicy = &observe
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			By("Changing the GitHub repository outside of the operator")
+			_, err := fakeGHClient.UpdateRepositoryByName(ctx, testOrganization, driftRepoName, &github.Repository{
+				Description: github.String("changed"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource = reconcileRepository()
+			Expect(resource.Status.Drift).To(ConsistOf(HaveField("Field", "description")))
+			detectedAt := resource.Status.Drift[0].DetectedAt
+
+			resource = reconcileRepository()
+			Expect(resource.Status.Drift).To(ConsistOf(And(
+				HaveField("Field", "description"),
+				HaveField("DetectedAt", detectedAt),
+			)))
+			Expect(drifted(resource)).To(HaveField("Status", metav1.ConditionTrue))
+			ghRepo, err := fakeGHClient.GetRepositoryByName(ctx, testOrganization, driftRepoName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghRepo.GetDescription()).To(Equal("changed"))
+		})
+	})
+
+	Context("When a Repository resource changes owner", func() {
+		transferRepoName := ghTestResourcePrefix + "transfer"
+		transferredRepoName := ghTestResourcePrefix + "transferred"
+		otherOrganization := testOrganization + "-transfer"
+
+		controllerReconciler := &RepositoryReconciler{}
+		var nodeId string
+
+		reconcileRepository := func() (*githubv1alpha1.Repository, error) {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			resource := &githubv1alpha1.Repository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource, err
+		}
+
+		// changeOwner points the observed Repository resource at the other organization under a new name
+		changeOwner := func(transferPolicy githubv1alpha1.TransferPolicy, managementPolicy githubv1alpha1.ManagementPolicy) {
+			resource := &githubv1alpha1.Repository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Owner = otherOrganization
+			resource.Spec.Name = transferredRepoName
+			resource.Spec.TransferPolicy = &transferPolicy
+			resource.Spec.ManagementPolicy = &managementPolicy
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		}
+
+		ghRepository := func() *github.Repository {
+			ghRepo, err := fakeGHClient.GetRepositoryByNodeId(ctx, nodeId)
+			Expect(err).NotTo(HaveOccurred())
+			return ghRepo
+		}
+
+		BeforeEach(func() {
+			if _, err := fakeGHClient.GetOrganization(ctx, otherOrganization); err != nil {
+				fakeServer.AddOrganization(otherOrganization)
+			}
+			ghRepo, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+				Name: &transferRepoName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			nodeId = ghRepo.GetNodeID()
+
+			Expect(k8sClient.Create(ctx, &githubv1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: githubv1alpha1.RepositorySpec{
+					Owner: testOrganization,
+					Name:  transferRepoName,
+				},
+			})).To(Succeed())
+			controllerReconciler = &RepositoryReconciler{
+				Client:       k8sClient,
+				Scheme:       k8sClient.Scheme(),
+				GitHubClient: fakeGHClient,
+				Recorder:     &record.FakeRecorder{},
+			}
+
+			By("Observing the GitHub repository under its current owner")
+			resource, err := reconcileRepository()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resource.Status.NodeId).To(HaveValue(Equal(nodeId)))
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance Repository")
+			cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Repository{})
+
+			By("Cleaning up the GitHub repository")
+			ghRepo := ghRepository()
+			Expect(fakeGHClient.DeleteRepositoryByName(ctx, ghRepo.GetOwner().GetLogin(), ghRepo.GetName())).To(Succeed())
+		})
+
+		It("should not transfer the repository without the Allow transfer policy", func() {
+			changeOwner(githubv1alpha1.TransferPolicyDeny, githubv1alpha1.ManagementPolicyFull)
+
+			_, err := reconcileRepository()
+			Expect(err).To(MatchError(ContainSubstring("transferPolicy is not Allow")))
+
+			Expect(ghRepository().GetFullName()).To(Equal(testOrganization + "/" + transferRepoName))
+		})
+
+		It("should transfer and rename the repository with the Allow transfer policy", func() {
+			changeOwner(githubv1alpha1.TransferPolicyAllow, githubv1alpha1.ManagementPolicyFull)
+
+			_, err := reconcileRepository()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghRepository().GetFullName()).To(Equal(otherOrganization + "/" + transferredRepoName))
+
+			By("Observing the GitHub repository under its new owner")
+			resource, err := reconcileRepository()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resource.Status.NodeId).To(HaveValue(Equal(nodeId)))
+			Expect(resource.Status.OwnerLogin).To(HaveValue(Equal(otherOrganization)))
+			Expect(resource.Status.Name).To(HaveValue(Equal(transferredRepoName)))
+			Expect(resource.Status.Drift).To(BeEmpty())
+		})
+
+		It("should only plan the transfer under the Observe management policy", func() {
+			changeOwner(githubv1alpha1.TransferPolicyAllow, githubv1alpha1.ManagementPolicyObserve)
+
+			resource, err := reconcileRepository()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ghRepository().GetFullName()).To(Equal(testOrganization + "/" + transferRepoName))
+			Expect(resource.Status.Plan).To(ConsistOf(
+				"transfer repository " + testOrganization + "/" + transferRepoName + " to " + otherOrganization + "/" + transferredRepoName,
+			))
+			Expect(resource.Status.Drift).To(ConsistOf(And(
+				HaveField("Field", "owner"),
+				HaveField("Desired", otherOrganization),
+				HaveField("Observed", testOrganization),
+			)))
+		})
+	})
 })
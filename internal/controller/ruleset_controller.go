@@ -0,0 +1,578 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+	"github.com/google/go-github/v60/github"
+)
+
+var (
+	rulesetFinalizerName = "github.github-operator.eczy.io/ruleset-finalizer"
+)
+
+type RulesetRequester interface {
+	GetRepositoryRuleset(ctx context.Context, owner, repo string, id int64) (*github.Ruleset, error)
+	GetRepositoryRulesetByName(ctx context.Context, owner, repo, name string) (*github.Ruleset, error)
+	CreateRepositoryRuleset(ctx context.Context, owner, repo string, ruleset *github.Ruleset) (*github.Ruleset, error)
+	UpdateRepositoryRuleset(ctx context.Context, owner, repo string, id int64, ruleset *github.Ruleset) (*github.Ruleset, error)
+	DeleteRepositoryRuleset(ctx context.Context, owner, repo string, id int64) error
+
+	GetOrganizationRuleset(ctx context.Context, org string, id int64) (*github.Ruleset, error)
+	GetOrganizationRulesetByName(ctx context.Context, org, name string) (*github.Ruleset, error)
+	CreateOrganizationRuleset(ctx context.Context, org string, ruleset *github.Ruleset) (*github.Ruleset, error)
+	UpdateOrganizationRuleset(ctx context.Context, org string, id int64, ruleset *github.Ruleset) (*github.Ruleset, error)
+	DeleteOrganizationRuleset(ctx context.Context, org string, id int64) error
+}
+
+// RulesetReconciler reconciles a Ruleset object
+type RulesetReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	GitHubClient             RulesetRequester
+	Recorder                 record.EventRecorder
+	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
+	RequeueInterval          time.Duration
+}
+
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=rulesets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=rulesets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=rulesets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=teams,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
+func (r *RulesetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := log.FromContext(ctx)
+
+	// fetch resource
+	ruleset := &githubv1alpha1.Ruleset{}
+	if err := r.Get(ctx, req.NamespacedName, ruleset); err != nil {
+		log.Error(err, "error fetching Ruleset resource")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub
+	managementPolicy := resolveManagementPolicy(ruleset.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(ruleset, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, ruleset, &ruleset.Status.Conditions, &ruleset.Status.ObservedGeneration, &ruleset.Status.Drift, &ruleset.Status.Plan, err)
+		// referenced resources are watched, so there is no need to retry while waiting for them
+		err = ignoreDependencyNotReady(err)
+	}()
+
+	// the desired state is the spec with references to other resources resolved
+	spec, err := r.resolveReferences(ctx, ruleset)
+	if err != nil && ruleset.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, err
+	} else if err != nil {
+		// deleting the ruleset doesn't depend on its references
+		spec, err = ruleset.Spec.DeepCopy(), nil
+	}
+
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, ruleset.Spec.ProviderRef, ruleset.Spec.Owner, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
+	// try to fetch external resource
+	observed, err := r.observeRuleset(ctx, ruleset)
+	if err != nil {
+		log.Error(err, "error fetching GitHub ruleset")
+		return ctrl.Result{}, err
+	}
+
+	// if external resource does't exist and we aren't deleting the resource, create external resource
+	if observed == nil && ruleset.DeletionTimestamp.IsZero() {
+		if managementPolicy != githubv1alpha1.ManagementPolicyFull {
+			// only the Full management policy creates missing GitHub resources
+			skipMutation(ctx, "create ruleset %s", ruleset.Spec.Name)
+		} else if shouldMutate(ctx, "create ruleset %s", ruleset.Spec.Name) {
+			ghRuleset, err := r.createRuleset(ctx, spec)
+			if err != nil {
+				log.Error(err, "error creating GitHub ruleset")
+				return ctrl.Result{}, err
+			}
+			observed = ghRuleset
+			r.Recorder.Event(ruleset, corev1.EventTypeNormal, eventReasonCreated, "Created GitHub ruleset")
+		}
+	}
+
+	// handle finalizer
+	deletionPolicy := resolveDeletionPolicy(ruleset.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion)
+	if ruleset.DeletionTimestamp.IsZero() {
+		// not being deleted
+		if deletionPolicy != githubv1alpha1.DeletionPolicyOrphan && !controllerutil.ContainsFinalizer(ruleset, rulesetFinalizerName) {
+			controllerutil.AddFinalizer(ruleset, rulesetFinalizerName)
+			if err := r.Update(ctx, ruleset); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if deletionPolicy == githubv1alpha1.DeletionPolicyOrphan && controllerutil.ContainsFinalizer(ruleset, rulesetFinalizerName) {
+			// the GitHub resource is left in place, so there is nothing to clean up
+			controllerutil.RemoveFinalizer(ruleset, rulesetFinalizerName)
+			if err := r.Update(ctx, ruleset); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else if controllerutil.ContainsFinalizer(ruleset, rulesetFinalizerName) {
+		// being deleted
+		if deletionPolicy == githubv1alpha1.DeletionPolicyDelete && ruleset.Status.Id != nil && observed != nil {
+			// if we have never resolved this resource before, don't
+			// touch external state
+			if !shouldMutate(ctx, "delete ruleset %s", ruleset.Spec.Name) {
+				// keep the finalizer so the deletion is applied once the resource leaves dry-run mode
+				return ctrl.Result{}, nil
+			}
+			if err := r.deleteRuleset(ctx, ruleset); err != nil {
+				log.Error(err, "error deleting ruleset")
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(ruleset, corev1.EventTypeNormal, eventReasonDeleted, "Deleted GitHub ruleset")
+		}
+
+		controllerutil.RemoveFinalizer(ruleset, rulesetFinalizerName)
+		if err := r.Update(ctx, ruleset); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// external resource is gone or wasn't created under the management policy
+	if observed == nil {
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+	}
+
+	// update external resource
+	err = r.updateRuleset(ctx, ruleset, spec, observed)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RulesetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&githubv1alpha1.Ruleset{}).
+		// rulesets are reconciled again when the teams their bypass actors reference change
+		Watches(&githubv1alpha1.Team{}, handler.EnqueueRequestsFromMapFunc(r.rulesetsForTeam), builder.WithPredicates(referencedTeamChanged)).
+		Complete(r)
+}
+
+// maps a Team to the rulesets in its namespace that reference it as a bypass actor
+func (r *RulesetReconciler) rulesetsForTeam(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	rulesets := &githubv1alpha1.RulesetList{}
+	if err := r.List(ctx, rulesets, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "error listing Ruleset resources")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, ruleset := range rulesets.Items {
+		for _, actor := range ruleset.Spec.BypassActors {
+			if actor.TeamRef != nil && actor.TeamRef.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: ruleset.Namespace, Name: ruleset.Name},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// resolveReferences returns a copy of the ruleset spec with the fields given as references to
+// other resources filled in. Resolved fields take precedence over the ones set directly.
+func (r *RulesetReconciler) resolveReferences(ctx context.Context, ruleset *githubv1alpha1.Ruleset) (*githubv1alpha1.RulesetSpec, error) {
+	spec := ruleset.Spec.DeepCopy()
+	for i, actor := range spec.BypassActors {
+		if actor.TeamRef == nil {
+			continue
+		}
+		if actor.ActorType != githubv1alpha1.RulesetActorTypeTeam {
+			return nil, fmt.Errorf("bypass actor of type %s sets teamRef, which only applies to Team actors", actor.ActorType)
+		}
+		team, err := getReferencedTeam(ctx, r.Client, ruleset.Namespace, *actor.TeamRef)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(*team.Status.OrganizationLogin, spec.Owner) {
+			return nil, fmt.Errorf("referenced Team '%s' belongs to organization '%s', not '%s'", actor.TeamRef.Name, *team.Status.OrganizationLogin, spec.Owner)
+		}
+		spec.BypassActors[i].ActorId = team.Status.Id
+	}
+	return spec, nil
+}
+
+// observeRuleset fetches the GitHub ruleset of a Ruleset resource, by its ID once it has been observed
+// and by its name before that. It returns nil if the ruleset doesn't exist.
+func (r *RulesetReconciler) observeRuleset(ctx context.Context, ruleset *githubv1alpha1.Ruleset) (*github.Ruleset, error) {
+	log := log.FromContext(ctx)
+
+	var ghRuleset *github.Ruleset
+	var err error
+	if ruleset.Status.Id != nil {
+		ghRuleset, err = r.getRuleset(ctx, ruleset, *ruleset.Status.Id)
+	} else {
+		ghRuleset, err = r.getRulesetByName(ctx, ruleset)
+	}
+	if _, ok := err.(*gh.RulesetNotFoundError); ok {
+		log.Info(err.Error())
+		return nil, nil
+	}
+	return ghRuleset, err
+}
+
+func (r *RulesetReconciler) getRuleset(ctx context.Context, ruleset *githubv1alpha1.Ruleset, id int64) (*github.Ruleset, error) {
+	if ruleset.Spec.RepositoryName != nil {
+		return r.GitHubClient.GetRepositoryRuleset(ctx, ruleset.Spec.Owner, *ruleset.Spec.RepositoryName, id)
+	}
+	return r.GitHubClient.GetOrganizationRuleset(ctx, ruleset.Spec.Owner, id)
+}
+
+func (r *RulesetReconciler) getRulesetByName(ctx context.Context, ruleset *githubv1alpha1.Ruleset) (*github.Ruleset, error) {
+	if ruleset.Spec.RepositoryName != nil {
+		return r.GitHubClient.GetRepositoryRulesetByName(ctx, ruleset.Spec.Owner, *ruleset.Spec.RepositoryName, ruleset.Spec.Name)
+	}
+	return r.GitHubClient.GetOrganizationRulesetByName(ctx, ruleset.Spec.Owner, ruleset.Spec.Name)
+}
+
+func (r *RulesetReconciler) createRuleset(ctx context.Context, spec *githubv1alpha1.RulesetSpec) (*github.Ruleset, error) {
+	create := rulesetToGitHubRuleset(spec)
+	if spec.RepositoryName != nil {
+		return r.GitHubClient.CreateRepositoryRuleset(ctx, spec.Owner, *spec.RepositoryName, create)
+	}
+	return r.GitHubClient.CreateOrganizationRuleset(ctx, spec.Owner, create)
+}
+
+func (r *RulesetReconciler) updateRuleset(ctx context.Context, ruleset *githubv1alpha1.Ruleset, spec *githubv1alpha1.RulesetSpec, ghRuleset *github.Ruleset) error {
+	log := log.FromContext(ctx)
+
+	desired := rulesetToGitHubRuleset(spec)
+	needsUpdate := false
+
+	// Name
+	if desired.Name != ghRuleset.Name {
+		log.Info("ruleset Name update", "from", ghRuleset.Name, "to", desired.Name)
+		recordDrift(ctx, "name", desired.Name, ghRuleset.Name)
+		needsUpdate = true
+	}
+	// Target
+	if ptrNonNilAndNotEqualTo(ruleset.Spec.Target, githubv1alpha1.RulesetTarget(ghRuleset.GetTarget())) {
+		log.Info("ruleset Target update", "from", ghRuleset.GetTarget(), "to", ruleset.Spec.Target)
+		recordDrift(ctx, "target", ruleset.Spec.Target, ghRuleset.GetTarget())
+		needsUpdate = true
+	}
+	// Enforcement
+	if desired.Enforcement != ghRuleset.Enforcement {
+		log.Info("ruleset Enforcement update", "from", ghRuleset.Enforcement, "to", desired.Enforcement)
+		recordDrift(ctx, "enforcement", desired.Enforcement, ghRuleset.Enforcement)
+		needsUpdate = true
+	}
+	// BypassActors
+	desiredActors := bypassActorKeys(desired.BypassActors)
+	observedActors := bypassActorKeys(ghRuleset.BypassActors)
+	if !cmpSlices(desiredActors, observedActors) {
+		log.Info("ruleset BypassActors update", "from", observedActors, "to", desiredActors)
+		recordDrift(ctx, "bypassActors", desiredActors, observedActors)
+		needsUpdate = true
+	}
+	// Conditions
+	observedConditions := ghRuleset.GetConditions()
+	if observedConditions == nil {
+		observedConditions = &github.RulesetConditions{}
+	}
+	if desired.Conditions.RefName != nil {
+		observedRefName := observedConditions.RefName
+		if observedRefName == nil {
+			observedRefName = &github.RulesetRefConditionParameters{}
+		}
+		if !cmpSlices(desired.Conditions.RefName.Include, observedRefName.Include) {
+			log.Info("ruleset IncludeRefs update", "from", observedRefName.Include, "to", desired.Conditions.RefName.Include)
+			recordDrift(ctx, "includeRefs", desired.Conditions.RefName.Include, observedRefName.Include)
+			needsUpdate = true
+		}
+		if !cmpSlices(desired.Conditions.RefName.Exclude, observedRefName.Exclude) {
+			log.Info("ruleset ExcludeRefs update", "from", observedRefName.Exclude, "to", desired.Conditions.RefName.Exclude)
+			recordDrift(ctx, "excludeRefs", desired.Conditions.RefName.Exclude, observedRefName.Exclude)
+			needsUpdate = true
+		}
+	}
+	if desired.Conditions.RepositoryName != nil {
+		observedRepositoryName := observedConditions.RepositoryName
+		if observedRepositoryName == nil {
+			observedRepositoryName = &github.RulesetRepositoryNamesConditionParameters{}
+		}
+		if !cmpSlices(desired.Conditions.RepositoryName.Include, observedRepositoryName.Include) {
+			log.Info("ruleset IncludeRepositories update", "from", observedRepositoryName.Include, "to", desired.Conditions.RepositoryName.Include)
+			recordDrift(ctx, "includeRepositories", desired.Conditions.RepositoryName.Include, observedRepositoryName.Include)
+			needsUpdate = true
+		}
+		if !cmpSlices(desired.Conditions.RepositoryName.Exclude, observedRepositoryName.Exclude) {
+			log.Info("ruleset ExcludeRepositories update", "from", observedRepositoryName.Exclude, "to", desired.Conditions.RepositoryName.Exclude)
+			recordDrift(ctx, "excludeRepositories", desired.Conditions.RepositoryName.Exclude, observedRepositoryName.Exclude)
+			needsUpdate = true
+		}
+	}
+	// Rules
+	desiredRules := ruleKeys(desired.Rules)
+	observedRules := ruleKeys(ghRuleset.Rules)
+	if !cmpSlices(desiredRules, observedRules) {
+		log.Info("ruleset Rules update", "from", observedRules, "to", desiredRules)
+		recordDrift(ctx, "rules", desiredRules, observedRules)
+		needsUpdate = true
+	}
+
+	// perform update if necessary
+	if needsUpdate && shouldMutate(ctx, "update ruleset %s", ruleset.Spec.Name) {
+		log.Info("updating ruleset", "name", ruleset.Spec.Name)
+
+		var updated *github.Ruleset
+		var err error
+		if ruleset.Spec.RepositoryName != nil {
+			updated, err = r.GitHubClient.UpdateRepositoryRuleset(ctx, ruleset.Spec.Owner, *ruleset.Spec.RepositoryName, ghRuleset.GetID(), desired)
+		} else {
+			updated, err = r.GitHubClient.UpdateOrganizationRuleset(ctx, ruleset.Spec.Owner, ghRuleset.GetID(), desired)
+		}
+		if err != nil {
+			log.Error(err, "error updating ruleset", "name", ruleset.Spec.Name)
+			return err
+		}
+		r.Recorder.Event(ruleset, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub ruleset")
+		ghRuleset = updated
+	}
+
+	// populate status from the updated ruleset, or the observed ruleset if it wasn't updated. A
+	// ruleset recreated after it was deleted outside of the operator has a new ID.
+	if needsUpdate || ruleset.Status.LastUpdateTimestamp == nil || !ptrEqual(ghRuleset.ID, ruleset.Status.Id) {
+		updated := ghRuleset
+		now := v1.Now()
+		ruleset.Status = githubv1alpha1.RulesetStatus{
+			LastUpdateTimestamp: &now,
+			Id:                  updated.ID,
+			NodeId:              updated.NodeID,
+			Name:                github.String(updated.Name),
+			Owner:               github.String(ruleset.Spec.Owner),
+			RepositoryName:      ruleset.Spec.RepositoryName,
+			SourceType:          updated.SourceType,
+			Target:              (*githubv1alpha1.RulesetTarget)(updated.Target),
+			Enforcement:         (*githubv1alpha1.RulesetEnforcement)(github.String(updated.Enforcement)),
+			Conditions:          ruleset.Status.Conditions,
+			ObservedGeneration:  ruleset.Status.ObservedGeneration,
+			Drift:               ruleset.Status.Drift,
+			Plan:                ruleset.Status.Plan,
+		}
+
+		// update status
+		if err := r.Status().Update(ctx, ruleset); err != nil {
+			log.Error(err, "error updating Ruleset status", "name", ruleset.Spec.Name)
+		}
+	}
+	return nil
+}
+
+func (r *RulesetReconciler) deleteRuleset(ctx context.Context, ruleset *githubv1alpha1.Ruleset) error {
+	if ruleset.Status.Id == nil {
+		return fmt.Errorf("ruleset Id is nil")
+	}
+	if ruleset.Spec.RepositoryName != nil {
+		return r.GitHubClient.DeleteRepositoryRuleset(ctx, ruleset.Spec.Owner, *ruleset.Spec.RepositoryName, *ruleset.Status.Id)
+	}
+	return r.GitHubClient.DeleteOrganizationRuleset(ctx, ruleset.Spec.Owner, *ruleset.Status.Id)
+}
+
+// returns a comparable representation of each bypass actor
+func bypassActorKeys(actors []*github.BypassActor) []string {
+	out := []string{}
+	for _, actor := range actors {
+		mode := actor.GetBypassMode()
+		if mode == "" {
+			mode = string(githubv1alpha1.RulesetBypassModeAlways)
+		}
+		out = append(out, fmt.Sprintf("%s/%d/%s", actor.GetActorType(), actor.GetActorID(), mode))
+	}
+	return out
+}
+
+// returns a comparable representation of each rule. Rule parameters returned by GitHub
+// are normalized by go-github, so they can be compared to locally constructed rules directly.
+func ruleKeys(rules []*github.RepositoryRule) []string {
+	out := []string{}
+	for _, rule := range rules {
+		key := rule.Type
+		if rule.Parameters != nil {
+			key += string(*rule.Parameters)
+		}
+		out = append(out, key)
+	}
+	return out
+}
+
+func rulesetToGitHubRuleset(spec *githubv1alpha1.RulesetSpec) *github.Ruleset {
+	ghRuleset := &github.Ruleset{
+		Name:         spec.Name,
+		Target:       (*string)(spec.Target),
+		Enforcement:  string(spec.Enforcement),
+		BypassActors: []*github.BypassActor{},
+		Conditions:   &github.RulesetConditions{},
+		Rules:        []*github.RepositoryRule{},
+	}
+
+	for _, actor := range spec.BypassActors {
+		id := actor.ActorId
+		// GitHub expects an ID of 1 for organization admins
+		if actor.ActorType == githubv1alpha1.RulesetActorTypeOrganizationAdmin {
+			id = github.Int64(1)
+		}
+		mode := githubv1alpha1.RulesetBypassModeAlways
+		if actor.BypassMode != nil {
+			mode = *actor.BypassMode
+		}
+		ghRuleset.BypassActors = append(ghRuleset.BypassActors, &github.BypassActor{
+			ActorID:    id,
+			ActorType:  github.String(string(actor.ActorType)),
+			BypassMode: github.String(string(mode)),
+		})
+	}
+
+	// push rulesets apply to all refs
+	if spec.Target == nil || *spec.Target != githubv1alpha1.RulesetTargetPush {
+		ghRuleset.Conditions.RefName = &github.RulesetRefConditionParameters{
+			Include: append([]string{}, spec.IncludeRefs...),
+			Exclude: append([]string{}, spec.ExcludeRefs...),
+		}
+	}
+	if spec.RepositoryName == nil {
+		ghRuleset.Conditions.RepositoryName = &github.RulesetRepositoryNamesConditionParameters{
+			Include: append([]string{}, spec.IncludeRepositories...),
+			Exclude: append([]string{}, spec.ExcludeRepositories...),
+		}
+	}
+
+	rules := spec.Rules
+	if rules == nil {
+		return ghRuleset
+	}
+	if rules.Creation != nil && *rules.Creation {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewCreationRule())
+	}
+	if rules.Update != nil && *rules.Update {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewUpdateRule(&github.UpdateAllowsFetchAndMergeRuleParameters{
+			UpdateAllowsFetchAndMerge: rules.UpdateAllowsFetchAndMerge != nil && *rules.UpdateAllowsFetchAndMerge,
+		}))
+	}
+	if rules.Deletion != nil && *rules.Deletion {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewDeletionRule())
+	}
+	if rules.RequiredLinearHistory != nil && *rules.RequiredLinearHistory {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewRequiredLinearHistoryRule())
+	}
+	if rules.RequiredSignatures != nil && *rules.RequiredSignatures {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewRequiredSignaturesRule())
+	}
+	if rules.NonFastForward != nil && *rules.NonFastForward {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewNonFastForwardRule())
+	}
+	if len(rules.RequiredDeploymentEnvironments) > 0 {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewRequiredDeploymentsRule(&github.RequiredDeploymentEnvironmentsRuleParameters{
+			RequiredDeploymentEnvironments: rules.RequiredDeploymentEnvironments,
+		}))
+	}
+	if rules.PullRequest != nil {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewPullRequestRule(&github.PullRequestRuleParameters{
+			DismissStaleReviewsOnPush:      rules.PullRequest.DismissStaleReviewsOnPush,
+			RequireCodeOwnerReview:         rules.PullRequest.RequireCodeOwnerReview,
+			RequireLastPushApproval:        rules.PullRequest.RequireLastPushApproval,
+			RequiredApprovingReviewCount:   rules.PullRequest.RequiredApprovingReviewCount,
+			RequiredReviewThreadResolution: rules.PullRequest.RequiredReviewThreadResolution,
+		}))
+	}
+	if rules.RequiredStatusChecks != nil {
+		checks := []github.RuleRequiredStatusChecks{}
+		for _, check := range rules.RequiredStatusChecks.RequiredStatusChecks {
+			checks = append(checks, github.RuleRequiredStatusChecks{
+				Context:       check.Context,
+				IntegrationID: check.IntegrationId,
+			})
+		}
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewRequiredStatusChecksRule(&github.RequiredStatusChecksRuleParameters{
+			RequiredStatusChecks:             checks,
+			StrictRequiredStatusChecksPolicy: rules.RequiredStatusChecks.StrictRequiredStatusChecksPolicy,
+		}))
+	}
+	if rules.CommitMessagePattern != nil {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewCommitMessagePatternRule(rulePatternParameters(rules.CommitMessagePattern)))
+	}
+	if rules.CommitAuthorEmailPattern != nil {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewCommitAuthorEmailPatternRule(rulePatternParameters(rules.CommitAuthorEmailPattern)))
+	}
+	if rules.CommitterEmailPattern != nil {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewCommitterEmailPatternRule(rulePatternParameters(rules.CommitterEmailPattern)))
+	}
+	if rules.BranchNamePattern != nil {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewBranchNamePatternRule(rulePatternParameters(rules.BranchNamePattern)))
+	}
+	if rules.TagNamePattern != nil {
+		ghRuleset.Rules = append(ghRuleset.Rules, github.NewTagNamePatternRule(rulePatternParameters(rules.TagNamePattern)))
+	}
+
+	return ghRuleset
+}
+
+func rulePatternParameters(pattern *githubv1alpha1.RulesetPatternRule) *github.RulePatternParameters {
+	return &github.RulePatternParameters{
+		Name:     pattern.Name,
+		Negate:   pattern.Negate,
+		Operator: string(pattern.Operator),
+		Pattern:  pattern.Pattern,
+	}
+}
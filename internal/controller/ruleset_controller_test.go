@@ -0,0 +1,342 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+var _ = Describe("Ruleset Controller", func() {
+	const resourceName = "test-resource"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	testRepoName := ghTestResourcePrefix + "ruleset-test-repo"
+	testRulesetName := ghTestResourcePrefix + "ruleset"
+
+	reconcileRuleset := func(deleteOnResourceDeletion bool) {
+		controllerReconciler := &RulesetReconciler{
+			Client:                   k8sClient,
+			Scheme:                   k8sClient.Scheme(),
+			GitHubClient:             fakeGHClient,
+			Recorder:                 &record.FakeRecorder{},
+			DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		By("Creating a test repository")
+		_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+			Name: &testRepoName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Creating the custom resource for the Kind Ruleset")
+		resource := &githubv1alpha1.Ruleset{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: githubv1alpha1.RulesetSpec{
+				Name:           testRulesetName,
+				Owner:          testOrganization,
+				RepositoryName: &testRepoName,
+				Enforcement:    githubv1alpha1.RulesetEnforcementActive,
+				IncludeRefs:    []string{"~DEFAULT_BRANCH"},
+				Rules: &githubv1alpha1.RulesetRules{
+					Deletion:              github.Bool(true),
+					RequiredLinearHistory: github.Bool(true),
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the specific resource instance Ruleset")
+		cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Ruleset{})
+
+		By("Cleaning up the test repository")
+		Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+	})
+
+	Context("When creating a Ruleset resource", func() {
+		It("should create a GitHub ruleset", func() {
+			By("Reconciling the resource")
+			reconcileRuleset(false)
+
+			By("Checking the Ruleset status")
+			resource := &githubv1alpha1.Ruleset{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Id).NotTo(BeNil())
+
+			By("Checking the GitHub ruleset")
+			ghRuleset, err := fakeGHClient.GetRepositoryRuleset(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghRuleset.Name).To(Equal(testRulesetName))
+			Expect(ghRuleset.Enforcement).To(Equal("active"))
+			Expect(ghRuleset.GetConditions().GetRefName().Include).To(ConsistOf("~DEFAULT_BRANCH"))
+			Expect(ruleKeys(ghRuleset.Rules)).To(ConsistOf("deletion", "required_linear_history"))
+		})
+	})
+
+	Context("When the GitHub ruleset drifts", func() {
+		It("should correct drift of the GitHub ruleset", func() {
+			By("Reconciling the resource")
+			reconcileRuleset(false)
+			resource := &githubv1alpha1.Ruleset{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+
+			By("Changing the GitHub ruleset outside of the operator")
+			_, err := fakeGHClient.UpdateRepositoryRuleset(ctx, testOrganization, testRepoName, *resource.Status.Id, &github.Ruleset{
+				Name:        testRulesetName,
+				Enforcement: "disabled",
+				Rules:       []*github.RepositoryRule{github.NewCreationRule()},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Reconciling the resource")
+			reconcileRuleset(false)
+
+			By("Checking the GitHub ruleset")
+			ghRuleset, err := fakeGHClient.GetRepositoryRuleset(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghRuleset.Enforcement).To(Equal("active"))
+			Expect(ruleKeys(ghRuleset.Rules)).To(ConsistOf("deletion", "required_linear_history"))
+
+			By("Checking the drift is reported")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "enforcement")))
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "rules")))
+		})
+		It("should recreate a GitHub ruleset deleted outside of the operator", func() {
+			By("Reconciling the resource")
+			reconcileRuleset(false)
+			resource := &githubv1alpha1.Ruleset{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			oldId := *resource.Status.Id
+
+			By("Deleting the GitHub ruleset outside of the operator")
+			Expect(fakeGHClient.DeleteRepositoryRuleset(ctx, testOrganization, testRepoName, oldId)).To(Succeed())
+
+			By("Reconciling the resource")
+			reconcileRuleset(false)
+
+			By("Checking the Ruleset status follows the new GitHub ruleset")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(*resource.Status.Id).NotTo(Equal(oldId))
+			ghRuleset, err := fakeGHClient.GetRepositoryRuleset(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghRuleset.Name).To(Equal(testRulesetName))
+		})
+	})
+
+	Context("When a bypass actor references a Team resource", func() {
+		teamNamespacedName := types.NamespacedName{
+			Name:      "test-ruleset-team",
+			Namespace: "default",
+		}
+		testTeamName := ghTestResourcePrefix + "ruleset-team"
+
+		setBypassTeamRef := func() {
+			resource := &githubv1alpha1.Ruleset{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.BypassActors = []githubv1alpha1.RulesetBypassActor{{
+				ActorType: githubv1alpha1.RulesetActorTypeTeam,
+				TeamRef:   &githubv1alpha1.ResourceReference{Name: teamNamespacedName.Name},
+			}}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		}
+
+		AfterEach(func() {
+			By("Cleanup the referenced Team")
+			cleanUpResource(ctx, teamNamespacedName, &githubv1alpha1.Team{})
+			if _, err := fakeGHClient.GetTeamBySlug(ctx, testOrganization, testTeamName); err == nil {
+				Expect(fakeGHClient.DeleteTeamBySlug(ctx, testOrganization, testTeamName)).To(Succeed())
+			}
+		})
+
+		It("should wait for the referenced Team", func() {
+			setBypassTeamRef()
+
+			By("Reconciling the resource")
+			reconcileRuleset(false)
+
+			By("Checking the Ruleset waits without creating a GitHub ruleset")
+			resource := &githubv1alpha1.Ruleset{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Id).To(BeNil())
+			Expect(resource.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", githubv1alpha1.ConditionTypeReady),
+				HaveField("Reason", githubv1alpha1.ConditionReasonDependencyNotReady),
+			)))
+		})
+
+		It("should use the ID of the referenced team", func() {
+			By("Creating and reconciling the referenced Team")
+			team := &githubv1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      teamNamespacedName.Name,
+					Namespace: teamNamespacedName.Namespace,
+				},
+				Spec: githubv1alpha1.TeamSpec{
+					Organization: testOrganization,
+					Name:         testTeamName,
+				},
+			}
+			Expect(k8sClient.Create(ctx, team)).To(Succeed())
+			teamReconciler := &TeamReconciler{
+				Client:       k8sClient,
+				Scheme:       k8sClient.Scheme(),
+				GitHubClient: fakeGHClient,
+				Recorder:     &record.FakeRecorder{},
+			}
+			_, err := teamReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: teamNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, teamNamespacedName, team)).To(Succeed())
+			Expect(team.Status.Id).NotTo(BeNil())
+
+			setBypassTeamRef()
+
+			By("Reconciling the resource")
+			reconcileRuleset(false)
+
+			By("Checking the GitHub ruleset")
+			resource := &githubv1alpha1.Ruleset{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Id).NotTo(BeNil())
+			ghRuleset, err := fakeGHClient.GetRepositoryRuleset(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghRuleset.BypassActors).To(ConsistOf(And(
+				HaveField("ActorType", HaveValue(Equal("Team"))),
+				HaveField("ActorID", HaveValue(Equal(*team.Status.Id))),
+			)))
+		})
+	})
+
+	Context("When deleting a Ruleset resource", func() {
+		It("should delete the GitHub ruleset with the resource", func() {
+			By("Reconciling the resource")
+			reconcileRuleset(true)
+			resource := &githubv1alpha1.Ruleset{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(rulesetFinalizerName))
+			id := *resource.Status.Id
+
+			By("Deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource, &client.DeleteOptions{
+				GracePeriodSeconds: &deletionGracePeriod,
+			})).To(Succeed())
+			reconcileRuleset(true)
+
+			By("Checking the resource is gone")
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking the GitHub ruleset is gone")
+			_, err = fakeGHClient.GetRepositoryRuleset(ctx, testOrganization, testRepoName, id)
+			Expect(err).To(BeAssignableToTypeOf(&gh.RulesetNotFoundError{}))
+		})
+
+		It("should leave the GitHub ruleset in place when deleting the resource by default", func() {
+			By("Reconciling the resource")
+			reconcileRuleset(false)
+			resource := &githubv1alpha1.Ruleset{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).NotTo(ContainElement(rulesetFinalizerName))
+			id := *resource.Status.Id
+
+			By("Deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			By("Checking the GitHub ruleset still exists")
+			_, err := fakeGHClient.GetRepositoryRuleset(ctx, testOrganization, testRepoName, id)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("ruleKeys", func() {
+	It("should identify a rule by its type when it has no parameters", func() {
+		Expect(ruleKeys([]*github.RepositoryRule{
+			github.NewDeletionRule(),
+			github.NewNonFastForwardRule(),
+		})).To(Equal([]string{"deletion", "non_fast_forward"}))
+	})
+
+	It("should match rules returned by GitHub to the same rules built from a spec", func() {
+		ruleset := &githubv1alpha1.Ruleset{
+			Spec: githubv1alpha1.RulesetSpec{
+				Rules: &githubv1alpha1.RulesetRules{
+					Update:                    github.Bool(true),
+					UpdateAllowsFetchAndMerge: github.Bool(true),
+					PullRequest: &githubv1alpha1.RulesetPullRequestRule{
+						RequiredApprovingReviewCount: 2,
+					},
+				},
+			},
+		}
+		desired := rulesetToGitHubRuleset(&ruleset.Spec)
+
+		By("Decoding the rules as GitHub formats them")
+		observed := []*github.RepositoryRule{}
+		Expect(json.Unmarshal([]byte(`[
+			{"type": "update", "parameters": {"update_allows_fetch_and_merge": true}},
+			{
+				"type": "pull_request",
+				"parameters": {
+					"required_review_thread_resolution": false,
+					"required_approving_review_count": 2,
+					"require_last_push_approval": false,
+					"require_code_owner_review": false,
+					"dismiss_stale_reviews_on_push": false
+				}
+			}
+		]`), &observed)).To(Succeed())
+
+		Expect(ruleKeys(observed)).To(Equal(ruleKeys(desired.Rules)))
+	})
+
+	It("should tell rules of the same type apart by their parameters", func() {
+		twoReviews := github.NewPullRequestRule(&github.PullRequestRuleParameters{RequiredApprovingReviewCount: 2})
+		oneReview := github.NewPullRequestRule(&github.PullRequestRuleParameters{RequiredApprovingReviewCount: 1})
+		Expect(ruleKeys([]*github.RepositoryRule{twoReviews})).NotTo(Equal(ruleKeys([]*github.RepositoryRule{oneReview})))
+	})
+})
@@ -31,6 +31,8 @@ import (
 	. "github.com/onsi/gomega"
 	"gopkg.in/dnaeon/go-vcr.v3/recorder"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,6 +42,7 @@ import (
 
 	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1" //+kubebuilder:scaffold:imports
 	gh "github.com/eczy/github-operator/internal/github"
+	"github.com/eczy/github-operator/internal/github/fake"
 	"github.com/eczy/github-operator/internal/utils"
 
 	testutils "github.com/eczy/github-operator/test/utils"
@@ -59,6 +62,8 @@ var (
 	testUser             string = "testuser"
 	ghTestResourcePrefix string = "github-operator-test-"
 	vcrRecorder          *recorder.Recorder
+	fakeServer           *fake.Server
+	fakeGHClient         *gh.Client // client of fakeServer, used by specs whatever the recorder mode
 )
 
 func TestControllers(t *testing.T) {
@@ -125,12 +130,27 @@ var _ = BeforeSuite(func() {
 			recorderMode = recorder.ModeRecordOnce
 		case "mode-passthrough":
 			recorderMode = recorder.ModePassthrough
+		case "fake":
+			// handled below, without a recorder
 		default:
 			err := fmt.Errorf("invalid value for recorder mode: %s", mode)
 			Expect(err).NotTo(HaveOccurred())
 		}
 	}
 
+	// specs whose requests aren't recorded in the cassette always run against an in-memory GitHub API
+	fakeServer = fake.NewServer()
+	fakeServer.AddOrganization(testOrganization)
+	fakeServer.AddUser(testUser)
+	fakeGHClient, err = fakeServer.Client()
+	Expect(err).NotTo(HaveOccurred())
+
+	if lowerMode == "fake" {
+		// run every spec against the in-memory GitHub API instead of recorded or live responses
+		ghClient = fakeGHClient
+		return
+	}
+
 	cassetteName := "fixtures/test-github-operator-controller"
 	cassettePath := path.Dir(cassetteName)
 	if _, err := os.Stat(cassettePath); os.IsNotExist(err) {
@@ -148,7 +168,7 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 	vcrRecorder = rec
-	c, err := utils.GitHubClientFromEnv(ctx, vcrRecorder)
+	c, err := utils.GitHubClientFromEnv(ctx, vcrRecorder, utils.GitHubServer{})
 	if err != nil {
 		if lowerMode == "replay-only" {
 			// continue in replay mode
@@ -167,6 +187,23 @@ var _ = AfterSuite(func() {
 	By("Tearing down the test environment")
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
-	err = vcrRecorder.Stop()
-	Expect(err).NotTo(HaveOccurred())
+	if vcrRecorder != nil {
+		err = vcrRecorder.Stop()
+		Expect(err).NotTo(HaveOccurred())
+	}
+	fakeServer.Close()
 })
+
+// cleanUpResource deletes a resource left behind by a spec, releasing its finalizers first
+func cleanUpResource(ctx context.Context, key types.NamespacedName, obj client.Object) {
+	err := k8sClient.Get(ctx, key, obj)
+	if errors.IsNotFound(err) {
+		return
+	}
+	Expect(err).NotTo(HaveOccurred())
+	if len(obj.GetFinalizers()) > 0 {
+		obj.SetFinalizers(nil)
+		Expect(k8sClient.Update(ctx, obj)).To(Succeed())
+	}
+	Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, obj))).To(Succeed())
+}
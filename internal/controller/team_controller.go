@@ -19,14 +19,25 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"sort"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
 	gh "github.com/eczy/github-operator/internal/github"
@@ -37,6 +48,11 @@ var (
 	teamFinalizerName = "github.github-operator.eczy.io/team-finalizer"
 )
 
+const (
+	teamRoleMember     = "member"
+	teamRoleMaintainer = "maintainer"
+)
+
 type TeamRequester interface {
 	GetTeamBySlug(ctx context.Context, org, slug string) (*github.Team, error)
 	GetTeamById(ctx context.Context, org, teamId int64) (*github.Team, error)
@@ -52,6 +68,11 @@ type TeamRequester interface {
 	GetTeamRepositoryPermissions(ctx context.Context, org, slug string) ([]*gh.TeamRepositoryPermission, error)
 	UpdateTeamRepositoryPermissions(ctx context.Context, org, slug string, repoName, permission string) error
 	RemoveTeamRepositoryPermissions(ctx context.Context, org, slug string, repoName string) error
+
+	GetTeamMemberships(ctx context.Context, org, slug string) ([]*gh.TeamMembership, error)
+	GetTeamPendingInvitations(ctx context.Context, org, slug string) ([]*github.Invitation, error)
+	UpdateTeamMembership(ctx context.Context, org, slug, login, role string) (*github.Membership, error)
+	RemoveTeamMembership(ctx context.Context, org, slug, login string) error
 }
 
 // TeamReconciler reconciles a Team object
@@ -59,13 +80,21 @@ type TeamReconciler struct {
 	client.Client
 	Scheme                   *runtime.Scheme
 	GitHubClient             TeamRequester
+	Recorder                 record.EventRecorder
 	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
 	RequeueInterval          time.Duration
+	// GitHubEvents optionally triggers reconciles for resources changed on GitHub,
+	// see GitHubEventReceiver.
+	GitHubEvents <-chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=teams,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=teams/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=teams/finalizers,verbs=update
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=repositories,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -76,13 +105,9 @@ type TeamReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
-func (r *TeamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *TeamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := log.FromContext(ctx)
 
-	if r.GitHubClient == nil {
-		return ctrl.Result{}, fmt.Errorf("nil GitHub client")
-	}
-
 	// fetch resource
 	team := &githubv1alpha1.Team{}
 	if err := r.Get(ctx, req.NamespacedName, team); err != nil {
@@ -90,71 +115,107 @@ func (r *TeamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	var observed *github.Team
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub
+	managementPolicy := resolveManagementPolicy(team.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(team, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, team, &team.Status.Conditions, &team.Status.ObservedGeneration, &team.Status.Drift, &team.Status.Plan, err)
+		// referenced resources are watched, so there is no need to retry while waiting for them
+		err = ignoreDependencyNotReady(err)
+	}()
+
+	// the desired state is the spec with references to other resources resolved
+	spec, err := r.resolveReferences(ctx, team)
+	if err != nil && team.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, err
+	} else if err != nil {
+		// deleting the team doesn't depend on its references
+		spec, err = team.Spec.DeepCopy(), nil
+	}
+
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, team.Spec.ProviderRef, team.Spec.Organization, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
 	// try to fetch external resource
-	if team.Status.NodeId != nil {
-		ghTeam, err := r.GitHubClient.GetTeamByNodeId(ctx, *team.Status.NodeId)
-		if _, ok := err.(*gh.TeamNotFoundError); ok {
-			log.Info(err.Error())
-		} else if err != nil {
-			log.Error(err, "error fetching GitHub team")
-			return ctrl.Result{}, err
-		}
-		observed = ghTeam
-	} else {
-		ghTeam, err := r.GitHubClient.GetTeamBySlug(ctx, team.Spec.Organization, team.Spec.Name)
-		if _, ok := err.(*gh.TeamNotFoundError); ok {
-			log.Info(err.Error())
-		} else if err != nil {
-			log.Error(err, "error fetching GitHub team")
-			return ctrl.Result{}, err
-		}
-		observed = ghTeam
+	observed, err := r.getTeam(ctx, team)
+	if err != nil {
+		log.Error(err, "error fetching GitHub team")
+		return ctrl.Result{}, err
 	}
 
 	// if external resource does't exist and we aren't deleting the resource, create external resource
 	if observed == nil && team.DeletionTimestamp.IsZero() {
-		log.Info("creating team", "name", team.Spec.Name)
-		ghTeam, err := r.createTeam(ctx, team)
-		if err != nil {
-			log.Error(err, "error creating GitHub team")
-			return ctrl.Result{}, err
+		if managementPolicy != githubv1alpha1.ManagementPolicyFull {
+			// only the Full management policy creates missing GitHub resources
+			skipMutation(ctx, "create team %s", team.Spec.Name)
+		} else if shouldMutate(ctx, "create team %s", team.Spec.Name) {
+			log.Info("creating team", "name", team.Spec.Name)
+			ghTeam, err := r.createTeam(ctx, spec)
+			if err != nil {
+				log.Error(err, "error creating GitHub team")
+				return ctrl.Result{}, err
+			}
+			observed = ghTeam
+			r.Recorder.Event(team, corev1.EventTypeNormal, eventReasonCreated, "Created GitHub team")
 		}
-		observed = ghTeam
 	}
 
 	// handle finalizer
-	if r.DeleteOnResourceDeletion {
-		if team.DeletionTimestamp.IsZero() {
-			// not being deleted
-			if !controllerutil.ContainsFinalizer(team, teamFinalizerName) {
-				controllerutil.AddFinalizer(team, teamFinalizerName)
-				if err := r.Update(ctx, team); err != nil {
-					return ctrl.Result{}, err
-				}
-			}
-		} else {
-			// being deleted
-			if team.Status.LastUpdateTimestamp != nil {
-				// if we have never resolved this resource before, don't
-				// touch external state
-				if err := r.deleteTeam(ctx, team); err != nil {
-					log.Error(err, "unable to delete team")
-					return ctrl.Result{}, err
-				}
+	deletionPolicy := resolveDeletionPolicy(team.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion)
+	if team.DeletionTimestamp.IsZero() {
+		// not being deleted
+		if deletionPolicy != githubv1alpha1.DeletionPolicyOrphan && !controllerutil.ContainsFinalizer(team, teamFinalizerName) {
+			controllerutil.AddFinalizer(team, teamFinalizerName)
+			if err := r.Update(ctx, team); err != nil {
+				return ctrl.Result{}, err
 			}
-
+		} else if deletionPolicy == githubv1alpha1.DeletionPolicyOrphan && controllerutil.ContainsFinalizer(team, teamFinalizerName) {
+			// the GitHub resource is left in place, so there is nothing to clean up
 			controllerutil.RemoveFinalizer(team, teamFinalizerName)
 			if err := r.Update(ctx, team); err != nil {
 				return ctrl.Result{}, err
 			}
+		}
+	} else if controllerutil.ContainsFinalizer(team, teamFinalizerName) {
+		// being deleted
+		if deletionPolicy == githubv1alpha1.DeletionPolicyDelete && team.Status.LastUpdateTimestamp != nil {
+			// if we have never resolved this resource before, don't
+			// touch external state
+			if !shouldMutate(ctx, "delete team %s", team.Spec.Name) {
+				// keep the finalizer so the deletion is applied once the resource leaves dry-run mode
+				return ctrl.Result{}, nil
+			}
+			if err := r.deleteTeam(ctx, team); err != nil {
+				log.Error(err, "unable to delete team")
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(team, corev1.EventTypeNormal, eventReasonDeleted, "Deleted GitHub team")
+		}
 
-			return ctrl.Result{}, nil
+		controllerutil.RemoveFinalizer(team, teamFinalizerName)
+		if err := r.Update(ctx, team); err != nil {
+			return ctrl.Result{}, err
 		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// external resource is gone or wasn't created under the management policy
+	if observed == nil {
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
 	}
 
 	// update external resource
-	err := r.updateTeam(ctx, team, observed)
+	err = r.updateTeam(ctx, team, spec, observed)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -164,22 +225,117 @@ func (r *TeamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *TeamReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&githubv1alpha1.Team{}).
-		Complete(r)
+		// teams are reconciled again when the resources they reference change
+		Watches(&githubv1alpha1.Team{}, handler.EnqueueRequestsFromMapFunc(r.teamsForParentTeam), builder.WithPredicates(referencedTeamChanged)).
+		Watches(&githubv1alpha1.Repository{}, handler.EnqueueRequestsFromMapFunc(r.teamsForRepository), builder.WithPredicates(referencedRepositoryChanged))
+	if r.GitHubEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.GitHubEvents, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
 
-func (r *TeamReconciler) createTeam(ctx context.Context, team *githubv1alpha1.Team) (*github.Team, error) {
-	newTeam := teamResourceToNewTeam(team)
-	created, err := r.GitHubClient.CreateTeam(ctx, team.Spec.Organization, newTeam)
+// maps a Team to the teams in its namespace that reference it as their parent
+func (r *TeamReconciler) teamsForParentTeam(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	teams := &githubv1alpha1.TeamList{}
+	if err := r.List(ctx, teams, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "error listing Team resources")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, team := range teams.Items {
+		if team.Spec.ParentTeamRef != nil && team.Spec.ParentTeamRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: team.Namespace, Name: team.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// maps a Repository to the teams in its namespace that reference it in their repository permissions
+func (r *TeamReconciler) teamsForRepository(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	teams := &githubv1alpha1.TeamList{}
+	if err := r.List(ctx, teams, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "error listing Team resources")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, team := range teams.Items {
+		if _, ok := team.Spec.RepositoryRefs[obj.GetName()]; ok {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: team.Namespace, Name: team.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// resolveReferences returns a copy of the team spec with the fields given as references to
+// other resources filled in. Resolved fields take precedence over the ones set directly.
+func (r *TeamReconciler) resolveReferences(ctx context.Context, team *githubv1alpha1.Team) (*githubv1alpha1.TeamSpec, error) {
+	spec := team.Spec.DeepCopy()
+	if spec.ParentTeamRef != nil {
+		parent, err := getReferencedTeam(ctx, r.Client, team.Namespace, *spec.ParentTeamRef)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(*parent.Status.OrganizationLogin, spec.Organization) {
+			return nil, fmt.Errorf("parent Team '%s' belongs to organization '%s', not '%s'", parent.Name, *parent.Status.OrganizationLogin, spec.Organization)
+		}
+		spec.ParentTeamId = parent.Status.Id
+	}
+	if len(spec.RepositoryRefs) > 0 && spec.Repositories == nil {
+		spec.Repositories = map[string]githubv1alpha1.RepositoryPermission{}
+	}
+	for name, permission := range spec.RepositoryRefs {
+		repo, err := getReferencedRepository(ctx, r.Client, team.Namespace, githubv1alpha1.ResourceReference{Name: name})
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(*repo.Status.OwnerLogin, spec.Organization) {
+			return nil, fmt.Errorf("referenced Repository '%s' belongs to '%s', not to organization '%s'", name, *repo.Status.OwnerLogin, spec.Organization)
+		}
+		spec.Repositories[*repo.Status.Name] = permission
+	}
+	return spec, nil
+}
+
+// getTeam fetches the GitHub team of a Team resource, by its node ID once it has been observed
+// and by its slug before that. It returns nil if the team doesn't exist.
+func (r *TeamReconciler) getTeam(ctx context.Context, team *githubv1alpha1.Team) (*github.Team, error) {
+	log := log.FromContext(ctx)
+
+	var ghTeam *github.Team
+	var err error
+	if team.Status.NodeId != nil {
+		ghTeam, err = r.GitHubClient.GetTeamByNodeId(ctx, *team.Status.NodeId)
+	} else {
+		ghTeam, err = r.GitHubClient.GetTeamBySlug(ctx, team.Spec.Organization, team.Spec.Name)
+	}
+	if _, ok := err.(*gh.TeamNotFoundError); ok {
+		log.Info(err.Error())
+		return nil, nil
+	}
+	return ghTeam, err
+}
+
+func (r *TeamReconciler) createTeam(ctx context.Context, spec *githubv1alpha1.TeamSpec) (*github.Team, error) {
+	newTeam := teamResourceToNewTeam(spec)
+	created, err := r.GitHubClient.CreateTeam(ctx, spec.Organization, newTeam)
 	if err != nil {
 		return nil, fmt.Errorf("creating GitHub Team: %w", err)
 	}
 	return created, nil
 }
 
-// modifies team and ghTeam in place
-func (r *TeamReconciler) updateTeam(ctx context.Context, team *githubv1alpha1.Team, ghTeam *github.Team) error {
+// updates ghTeam to match spec, the resolved spec of team. Modifies team and ghTeam in place
+func (r *TeamReconciler) updateTeam(ctx context.Context, team *githubv1alpha1.Team, spec *githubv1alpha1.TeamSpec, ghTeam *github.Team) error {
 	log := log.FromContext(ctx)
 
 	updateTeam := github.NewTeam{}
@@ -187,62 +343,70 @@ func (r *TeamReconciler) updateTeam(ctx context.Context, team *githubv1alpha1.Te
 
 	// resolve name
 	// name can never be blank
-	updateTeam.Name = team.Spec.Name
-	if team.Spec.Name != ghTeam.GetName() {
-		log.Info("team name update", "from", ghTeam.GetName(), "to", team.Spec.Name)
+	updateTeam.Name = spec.Name
+	if spec.Name != ghTeam.GetName() {
+		log.Info("team name update", "from", ghTeam.GetName(), "to", spec.Name)
+		recordDrift(ctx, "name", spec.Name, ghTeam.GetName())
 		needsUpdate = true
 	}
-	if team.Spec.Name != team.GetObjectMeta().GetName() {
-		log.Info("team spec Name does not match metadata Name", "spec", team.Spec.Name, "metadata", team.GetObjectMeta().GetName())
+	if spec.Name != team.GetObjectMeta().GetName() {
+		log.Info("team spec Name does not match metadata Name", "spec", spec.Name, "metadata", team.GetObjectMeta().GetName())
 	}
 
 	// resolve description
-	if ptrNonNilAndNotEqualTo(team.Spec.Description, ghTeam.GetDescription()) {
-		updateTeam.Description = team.Spec.Description
+	if ptrNonNilAndNotEqualTo(spec.Description, ghTeam.GetDescription()) {
+		updateTeam.Description = spec.Description
 		needsUpdate = true
-		log.Info("team description update", "from", ghTeam.GetDescription(), "to", *team.Spec.Description, "name", team.Spec.Name)
+		log.Info("team description update", "from", ghTeam.GetDescription(), "to", *spec.Description, "name", spec.Name)
+		recordDrift(ctx, "description", *spec.Description, ghTeam.GetDescription())
 
 	}
 
 	// resolve privacy
-	if ptrNonNilAndNotEqualTo(team.Spec.Privacy, githubv1alpha1.Privacy(ghTeam.GetPrivacy())) {
-		updateTeam.Privacy = (*string)(team.Spec.Privacy)
+	if ptrNonNilAndNotEqualTo(spec.Privacy, githubv1alpha1.Privacy(ghTeam.GetPrivacy())) {
+		updateTeam.Privacy = (*string)(spec.Privacy)
 		needsUpdate = true
-		log.Info("team privacy update", "from", githubv1alpha1.Privacy(ghTeam.GetPrivacy()), "to", *team.Spec.Privacy, "name", team.Spec.Name)
+		log.Info("team privacy update", "from", githubv1alpha1.Privacy(ghTeam.GetPrivacy()), "to", *spec.Privacy, "name", spec.Name)
+		recordDrift(ctx, "privacy", *spec.Privacy, githubv1alpha1.Privacy(ghTeam.GetPrivacy()))
 
 	}
 
 	// resolve parent
 	parent := ghTeam.GetParent()
 	if parent != nil {
-		if ptrNonNilAndNotEqualTo(team.Spec.ParentTeamId, parent.GetID()) {
-			updateTeam.ParentTeamID = team.Spec.ParentTeamId
+		if ptrNonNilAndNotEqualTo(spec.ParentTeamId, parent.GetID()) {
+			updateTeam.ParentTeamID = spec.ParentTeamId
 			needsUpdate = true
-			log.Info("team parent update", "from", parent.GetID(), "to", team.Spec.ParentTeamId, "name", team.Spec.Name)
+			log.Info("team parent update", "from", parent.GetID(), "to", spec.ParentTeamId, "name", spec.Name)
+			recordDrift(ctx, "parentTeamId", spec.ParentTeamId, parent.GetID())
 
-		} else if team.Spec.ParentTeamId == nil {
+		} else if spec.ParentTeamId == nil {
 			updateTeam.ParentTeamID = nil
 			needsUpdate = true
-			log.Info("team parent update", "from", parent.GetID(), "to", team.Spec.ParentTeamId, "name", team.Spec.Name)
+			log.Info("team parent update", "from", parent.GetID(), "to", spec.ParentTeamId, "name", spec.Name)
+			recordDrift(ctx, "parentTeamId", spec.ParentTeamId, parent.GetID())
 		}
-	} else if team.Spec.ParentTeamId != nil {
-		updateTeam.ParentTeamID = team.Spec.ParentTeamId
+	} else if spec.ParentTeamId != nil {
+		updateTeam.ParentTeamID = spec.ParentTeamId
 		needsUpdate = true
-		log.Info("team parent update", "from", nil, "to", team.Spec.ParentTeamId, "name", team.Spec.Name)
+		log.Info("team parent update", "from", nil, "to", spec.ParentTeamId, "name", spec.Name)
+		recordDrift(ctx, "parentTeamId", spec.ParentTeamId, nil)
 	}
 
-	// TODO: team members and maintainers
-
 	// perform update if necessary
-	if needsUpdate || team.Status.LastUpdateTimestamp == nil {
-		log.Info("updating team", "name", team.Spec.Name)
+	if needsUpdate && shouldMutate(ctx, "update team %s", spec.Name) {
+		log.Info("updating team", "name", spec.Name)
 		updated, err := r.GitHubClient.UpdateTeamById(ctx, *ghTeam.Organization.ID, *ghTeam.ID, updateTeam)
 		if err != nil {
-			log.Error(err, "error updating team", "name", team.Spec.Name)
+			log.Error(err, "error updating team", "name", spec.Name)
 			return err
 		}
+		r.Recorder.Event(team, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub team")
 		ghTeam = updated
+	}
 
+	// populate status from the updated team, or the observed team if it wasn't updated
+	if needsUpdate || team.Status.LastUpdateTimestamp == nil {
 		now := v1.Now()
 		parent := ghTeam.GetParent()
 		var parentId *int64
@@ -260,23 +424,43 @@ func (r *TeamReconciler) updateTeam(ctx context.Context, team *githubv1alpha1.Te
 			OrganizationId:      github.Int64(ghTeam.GetOrganization().GetID()),
 			Name:                ghTeam.Name,
 			Description:         ghTeam.Description,
-			// TODO
-			// Members:             []string{},
-			// Maintainers:         []string{},
-			Privacy: (*githubv1alpha1.Privacy)(ghTeam.Privacy),
+			Privacy:             (*githubv1alpha1.Privacy)(ghTeam.Privacy),
 			// TODO
 			// NotificationSetting: &"",
-			ParentTeamId:   parentId,
-			ParentTeamSlug: parentSlug,
+			ParentTeamId:       parentId,
+			ParentTeamSlug:     parentSlug,
+			Conditions:         team.Status.Conditions,
+			ObservedGeneration: team.Status.ObservedGeneration,
+			Drift:              team.Status.Drift,
+			Plan:               team.Status.Plan,
 		}
 
 		// update status
 		if err := r.Status().Update(ctx, team); err != nil {
-			log.Error(err, "error updating Team status", "name", team.Spec.Name)
+			log.Error(err, "error updating Team status", "name", spec.Name)
 		}
 	}
 
 	// Repositories
+	if err := r.updateTeamRepositoryPermissions(ctx, team, spec, ghTeam); err != nil {
+		return err
+	}
+
+	// Members and maintainers. Without either or pruning, the roster is left alone.
+	if len(spec.Members) > 0 || len(spec.Maintainers) > 0 || (spec.PruneMembers != nil && *spec.PruneMembers) {
+		if err := r.updateTeamMemberships(ctx, team, ghTeam); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// grants, changes and removes the repository permissions of a team so that they match the Team spec.
+// Modifies team status in place.
+func (r *TeamReconciler) updateTeamRepositoryPermissions(ctx context.Context, team *githubv1alpha1.Team, spec *githubv1alpha1.TeamSpec, ghTeam *github.Team) error {
+	log := log.FromContext(ctx)
+
 	log.Info("updating team repository permissions")
 	trps, err := r.GitHubClient.GetTeamRepositoryPermissions(ctx, ghTeam.GetOrganization().GetLogin(), ghTeam.GetSlug())
 	if err != nil {
@@ -285,46 +469,137 @@ func (r *TeamReconciler) updateTeam(ctx context.Context, team *githubv1alpha1.Te
 	}
 
 	statusRepoPermissions := map[string]githubv1alpha1.RepositoryPermission{}
-	needsUpdate = false
 
 	for _, trp := range trps {
-		if permission, ok := team.Spec.Repositories[trp.RepositoryName]; ok {
-			if permission != githubv1alpha1.RepositoryPermission(trp.Permission) {
+		observedPermission := teamRepositoryPermission(trp.Permission)
+		if permission, ok := spec.Repositories[trp.RepositoryName]; ok {
+			if permission != observedPermission && shouldMutate(ctx, "update team %s permission on repository %s from %s to %s", spec.Name, trp.RepositoryName, observedPermission, permission) {
 				log.Info("updating team repository permission", "team", team.GetName(), "repository", trp.RepositoryName, "permission", permission)
 				err := r.GitHubClient.UpdateTeamRepositoryPermissions(ctx, ghTeam.GetOrganization().GetLogin(), ghTeam.GetSlug(), trp.RepositoryName, string(permission))
 				if err != nil {
 					log.Error(err, "error updating team repository permissions")
 					return err
 				}
-				statusRepoPermissions[trp.RepositoryName] = permission
-				needsUpdate = true
+				observedPermission = permission
 			}
-		} else {
+			statusRepoPermissions[trp.RepositoryName] = observedPermission
+		} else if shouldMutate(ctx, "remove team %s permission on repository %s", spec.Name, trp.RepositoryName) {
 			log.Info("removing team repository permission", "team", team.GetName(), "repository", trp.RepositoryName)
 			err := r.GitHubClient.RemoveTeamRepositoryPermissions(ctx, ghTeam.GetOrganization().GetLogin(), ghTeam.GetSlug(), trp.RepositoryName)
 			if err != nil {
 				log.Error(err, "error removing team repository permissions")
 				return err
 			}
-			needsUpdate = true
+		} else {
+			statusRepoPermissions[trp.RepositoryName] = observedPermission
 		}
 	}
 
-	for repository, permission := range team.Spec.Repositories {
-		if _, ok := statusRepoPermissions[repository]; !ok {
+	for repository, permission := range spec.Repositories {
+		if _, ok := statusRepoPermissions[repository]; !ok && shouldMutate(ctx, "add team %s permission %s on repository %s", spec.Name, permission, repository) {
 			err := r.GitHubClient.UpdateTeamRepositoryPermissions(ctx, ghTeam.GetOrganization().GetLogin(), ghTeam.GetSlug(), repository, string(permission))
 			if err != nil {
 				log.Error(err, "error updating team repository permissions")
 				return err
 			}
 			statusRepoPermissions[repository] = permission
-			needsUpdate = true
 		}
 	}
 
-	if needsUpdate {
+	if !maps.Equal(statusRepoPermissions, team.Status.Repositories) {
 		team.Status.Repositories = statusRepoPermissions
 		// update status
+		if err := r.Status().Update(ctx, team); err != nil {
+			log.Error(err, "error updating Team status", "name", spec.Name)
+		}
+	}
+
+	return nil
+}
+
+// adds, promotes and demotes team members so that the team roster matches the Team spec, and removes
+// members not listed in the spec if it prunes them. Modifies team status in place.
+func (r *TeamReconciler) updateTeamMemberships(ctx context.Context, team *githubv1alpha1.Team, ghTeam *github.Team) error {
+	log := log.FromContext(ctx)
+
+	org := ghTeam.GetOrganization().GetLogin()
+	slug := ghTeam.GetSlug()
+	prune := team.Spec.PruneMembers != nil && *team.Spec.PruneMembers
+
+	// logins are not case sensitive, so key everything on the lowercase login
+	specLogins := map[string]string{}
+	specRoles := map[string]string{}
+	for _, login := range team.Spec.Members {
+		specLogins[strings.ToLower(login)] = login
+		specRoles[strings.ToLower(login)] = teamRoleMember
+	}
+	for _, login := range team.Spec.Maintainers {
+		specLogins[strings.ToLower(login)] = login
+		specRoles[strings.ToLower(login)] = teamRoleMaintainer
+	}
+
+	memberships, err := r.GitHubClient.GetTeamMemberships(ctx, org, slug)
+	if err != nil {
+		log.Error(err, "error getting team memberships")
+		return err
+	}
+	invitations, err := r.GitHubClient.GetTeamPendingInvitations(ctx, org, slug)
+	if err != nil {
+		log.Error(err, "error getting team pending invitations")
+		return err
+	}
+
+	members, maintainers, observed, err := r.updateTeamMembers(ctx, team, org, slug, memberships, specRoles, prune)
+	if err != nil {
+		return err
+	}
+	pending, err := r.updateTeamInvitations(ctx, team, org, slug, invitations, specRoles, prune)
+	if err != nil {
+		return err
+	}
+
+	for key, role := range specRoles {
+		if _, ok := observed[key]; ok {
+			continue
+		}
+		// don't resend invitations to users who haven't accepted yet
+		if _, ok := pending[key]; ok {
+			continue
+		}
+
+		login := specLogins[key]
+		if !shouldMutate(ctx, "add team %s %s %s", team.Spec.Name, role, login) {
+			continue
+		}
+		log.Info("adding team member", "team", team.GetName(), "login", login, "role", role)
+		membership, err := r.GitHubClient.UpdateTeamMembership(ctx, org, slug, login, role)
+		if err != nil {
+			log.Error(err, "error adding team member")
+			return err
+		}
+
+		if membership.GetState() == "pending" {
+			pending[key] = login
+		} else if role == teamRoleMaintainer {
+			maintainers = append(maintainers, login)
+		} else {
+			members = append(members, login)
+		}
+	}
+
+	pendingLogins := []string{}
+	for _, login := range pending {
+		pendingLogins = append(pendingLogins, login)
+	}
+	sort.Strings(members)
+	sort.Strings(maintainers)
+	sort.Strings(pendingLogins)
+
+	if !cmpSlices(members, team.Status.Members) || !cmpSlices(maintainers, team.Status.Maintainers) || !cmpSlices(pendingLogins, team.Status.PendingInvitations) {
+		team.Status.Members = members
+		team.Status.Maintainers = maintainers
+		team.Status.PendingInvitations = pendingLogins
+		// update status
 		if err := r.Status().Update(ctx, team); err != nil {
 			log.Error(err, "error updating Team status", "name", team.Spec.Name)
 		}
@@ -333,6 +608,79 @@ func (r *TeamReconciler) updateTeam(ctx context.Context, team *githubv1alpha1.Te
 	return nil
 }
 
+// updateTeamMembers promotes and demotes the current members of a team to their roles in specRoles, keyed
+// by lowercase login, and removes members missing from it if prune is set. It returns the resulting members
+// and maintainers, and the lowercase logins of all current members.
+func (r *TeamReconciler) updateTeamMembers(ctx context.Context, team *githubv1alpha1.Team, org, slug string, memberships []*gh.TeamMembership, specRoles map[string]string, prune bool) (members, maintainers []string, observed map[string]struct{}, err error) {
+	log := log.FromContext(ctx)
+
+	members = []string{}
+	maintainers = []string{}
+	observed = map[string]struct{}{}
+	for _, membership := range memberships {
+		key := strings.ToLower(membership.Login)
+		observed[key] = struct{}{}
+
+		role, ok := specRoles[key]
+		if !ok {
+			if prune && shouldMutate(ctx, "remove team %s member %s", team.Spec.Name, membership.Login) {
+				log.Info("removing team member", "team", team.GetName(), "login", membership.Login)
+				if err := r.GitHubClient.RemoveTeamMembership(ctx, org, slug, membership.Login); err != nil {
+					log.Error(err, "error removing team member")
+					return nil, nil, nil, err
+				}
+				continue
+			}
+			role = membership.Role
+		}
+
+		if role != membership.Role {
+			if shouldMutate(ctx, "update team %s member %s role from %s to %s", team.Spec.Name, membership.Login, membership.Role, role) {
+				log.Info("updating team member role", "team", team.GetName(), "login", membership.Login, "from", membership.Role, "to", role)
+				if _, err := r.GitHubClient.UpdateTeamMembership(ctx, org, slug, membership.Login, role); err != nil {
+					log.Error(err, "error updating team member role")
+					return nil, nil, nil, err
+				}
+			} else {
+				role = membership.Role
+			}
+		}
+
+		if role == teamRoleMaintainer {
+			maintainers = append(maintainers, membership.Login)
+		} else {
+			members = append(members, membership.Login)
+		}
+	}
+	return members, maintainers, observed, nil
+}
+
+// updateTeamInvitations cancels the pending invitations of users missing from specRoles if prune is set.
+// It returns the logins of the invitations left pending, keyed by lowercase login.
+func (r *TeamReconciler) updateTeamInvitations(ctx context.Context, team *githubv1alpha1.Team, org, slug string, invitations []*github.Invitation, specRoles map[string]string, prune bool) (map[string]string, error) {
+	log := log.FromContext(ctx)
+
+	pending := map[string]string{}
+	for _, invitation := range invitations {
+		// invitations sent by email have no login
+		if invitation.Login == nil {
+			continue
+		}
+		key := strings.ToLower(invitation.GetLogin())
+		if _, ok := specRoles[key]; !ok && prune && shouldMutate(ctx, "cancel team %s invitation for %s", team.Spec.Name, invitation.GetLogin()) {
+			// removing a pending membership cancels the invitation to the team
+			log.Info("cancelling team invitation", "team", team.GetName(), "login", invitation.GetLogin())
+			if err := r.GitHubClient.RemoveTeamMembership(ctx, org, slug, invitation.GetLogin()); err != nil {
+				log.Error(err, "error cancelling team invitation")
+				return nil, err
+			}
+			continue
+		}
+		pending[key] = invitation.GetLogin()
+	}
+	return pending, nil
+}
+
 func (r *TeamReconciler) deleteTeam(ctx context.Context, team *githubv1alpha1.Team) error {
 	if team.Status.OrganizationLogin == nil {
 		return fmt.Errorf("team OrganizationLogin nil")
@@ -343,17 +691,30 @@ func (r *TeamReconciler) deleteTeam(ctx context.Context, team *githubv1alpha1.Te
 	return r.GitHubClient.DeleteTeamBySlug(ctx, *team.Status.OrganizationLogin, *team.Status.Slug)
 }
 
-// teamResourceToNewTeam creates a github.NewTeam instance from a Team resource
-func teamResourceToNewTeam(team *githubv1alpha1.Team) github.NewTeam {
+// teamRepositoryPermission converts a GraphQL repository permission to the
+// equivalent REST permission used in Team specs
+func teamRepositoryPermission(permission string) githubv1alpha1.RepositoryPermission {
+	switch strings.ToUpper(permission) {
+	case "READ":
+		return githubv1alpha1.Pull
+	case "WRITE":
+		return githubv1alpha1.Push
+	default:
+		return githubv1alpha1.RepositoryPermission(strings.ToLower(permission))
+	}
+}
+
+// teamResourceToNewTeam creates a github.NewTeam instance from a resolved Team spec
+func teamResourceToNewTeam(spec *githubv1alpha1.TeamSpec) github.NewTeam {
 	var privacy *string
-	if team.Spec.Privacy != nil {
-		tmp := string(*team.Spec.Privacy)
+	if spec.Privacy != nil {
+		tmp := string(*spec.Privacy)
 		privacy = &tmp
 	}
 	newTeam := github.NewTeam{
-		Name:         team.Spec.Name,
-		Description:  team.Spec.Description,
-		ParentTeamID: team.Spec.ParentTeamId,
+		Name:         spec.Name,
+		Description:  spec.Description,
+		ParentTeamID: spec.ParentTeamId,
 		Privacy:      privacy,
 	}
 	return newTeam
@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -84,6 +85,7 @@ var _ = Describe("Team Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -121,6 +123,7 @@ var _ = Describe("Team Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -164,6 +167,7 @@ var _ = Describe("Team Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -195,6 +199,7 @@ var _ = Describe("Team Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -228,6 +233,7 @@ var _ = Describe("Team Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -273,6 +279,7 @@ var _ = Describe("Team Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -334,6 +341,7 @@ var _ = Describe("Team Controller", func() {
 				Client:                   k8sClient,
 				Scheme:                   k8sClient.Scheme(),
 				GitHubClient:             ghClient,
+				Recorder:                 &record.FakeRecorder{},
 				DeleteOnResourceDeletion: true,
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -379,6 +387,7 @@ var _ = Describe("Team Controller", func() {
 				Client:                   k8sClient,
 				Scheme:                   k8sClient.Scheme(),
 				GitHubClient:             ghClient,
+				Recorder:                 &record.FakeRecorder{},
 				DeleteOnResourceDeletion: true,
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -415,6 +424,7 @@ var _ = Describe("Team Controller", func() {
 				Client:                   k8sClient,
 				Scheme:                   k8sClient.Scheme(),
 				GitHubClient:             ghClient,
+				Recorder:                 &record.FakeRecorder{},
 				DeleteOnResourceDeletion: true,
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -435,6 +445,7 @@ var _ = Describe("Team Controller", func() {
 				Client:       k8sClient,
 				Scheme:       k8sClient.Scheme(),
 				GitHubClient: ghClient,
+				Recorder:     &record.FakeRecorder{},
 			}
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
@@ -461,4 +472,248 @@ var _ = Describe("Team Controller", func() {
 			Expect(ghClient.DeleteTeamById(ctx, ghTeam.GetOrganization().GetID(), ghTeam.GetID())).To(Succeed())
 		})
 	})
+
+	Context("When GitHub reports team repository permissions", func() {
+		testRepoName := ghTestResourcePrefix + "team-permission-test"
+		permissionsTeamName := ghTestResourcePrefix + "team-permissions"
+
+		BeforeEach(func() {
+			By("Creating a test repository")
+			_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+				Name: &testRepoName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance Team")
+			cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Team{})
+
+			By("Cleaning up the GitHub team and test repository")
+			Expect(fakeGHClient.DeleteTeamBySlug(ctx, testOrganization, permissionsTeamName)).To(Succeed())
+			Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+		})
+
+		It("should not plan changes to permissions that already match the spec", func() {
+			By("Creating the custom resource for the Kind Team")
+			resource := &githubv1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: githubv1alpha1.TeamSpec{
+					Organization: testOrganization,
+					Name:         permissionsTeamName,
+					Repositories: map[string]githubv1alpha1.RepositoryPermission{
+						testRepoName: githubv1alpha1.Pull,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &TeamReconciler{
+				Client:       k8sClient,
+				Scheme:       k8sClient.Scheme(),
+				GitHubClient: fakeGHClient,
+				Recorder:     &record.FakeRecorder{},
+			}
+			By("Reconciling the resource")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Observing the GitHub team")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			observe := githubv1alpha1.ManagementPolicyObserve
+			resource.Spec.ManagementPolicy = &observe
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the Team resource Status")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Repositories).To(HaveKeyWithValue(testRepoName, githubv1alpha1.Pull))
+			Expect(resource.Status.Plan).To(BeEmpty())
+		})
+	})
+
+	Context("When a Team resource manages the team roster", func() {
+		rosterTeamName := ghTestResourcePrefix + "team-roster"
+		alice, bob, carol := "team-roster-alice", "team-roster-bob", "team-roster-carol"
+		// users that are never added to the organization, so that they are invited
+		dave, erin := "team-roster-dave", "team-roster-erin"
+
+		controllerReconciler := &TeamReconciler{}
+
+		// createTeam creates the Team resource and a GitHub team with the given members and maintainers
+		createTeam := func(spec githubv1alpha1.TeamSpec, members, maintainers []string) {
+			_, err := fakeGHClient.CreateTeam(ctx, testOrganization, github.NewTeam{Name: rosterTeamName})
+			Expect(err).NotTo(HaveOccurred())
+			for _, login := range members {
+				_, err := fakeGHClient.UpdateTeamMembership(ctx, testOrganization, rosterTeamName, login, teamRoleMember)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			for _, login := range maintainers {
+				_, err := fakeGHClient.UpdateTeamMembership(ctx, testOrganization, rosterTeamName, login, teamRoleMaintainer)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			spec.Organization = testOrganization
+			spec.Name = rosterTeamName
+			Expect(k8sClient.Create(ctx, &githubv1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: spec,
+			})).To(Succeed())
+		}
+
+		reconcileTeam := func() *githubv1alpha1.Team {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			resource := &githubv1alpha1.Team{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource
+		}
+
+		roster := func() map[string]string {
+			memberships, err := fakeGHClient.GetTeamMemberships(ctx, testOrganization, rosterTeamName)
+			Expect(err).NotTo(HaveOccurred())
+			out := map[string]string{}
+			for _, membership := range memberships {
+				out[membership.Login] = membership.Role
+			}
+			return out
+		}
+
+		BeforeEach(func() {
+			for _, login := range []string{alice, bob, carol, dave, erin} {
+				if _, err := fakeGHClient.GetUser(ctx, login); err != nil {
+					fakeServer.AddUser(login)
+				}
+			}
+			controllerReconciler = &TeamReconciler{
+				Client:       k8sClient,
+				Scheme:       k8sClient.Scheme(),
+				GitHubClient: fakeGHClient,
+				Recorder:     &record.FakeRecorder{},
+			}
+		})
+
+		AfterEach(func() {
+			By("Cleanup the specific resource instance Team")
+			cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Team{})
+
+			By("Cleaning up the GitHub team")
+			Expect(fakeGHClient.DeleteTeamBySlug(ctx, testOrganization, rosterTeamName)).To(Succeed())
+			fakeServer.SetInvitations(false)
+		})
+
+		It("should add, promote and demote members without removing unlisted ones", func() {
+			createTeam(githubv1alpha1.TeamSpec{
+				Members:     []string{alice},
+				Maintainers: []string{bob},
+			}, []string{bob}, []string{alice, carol})
+
+			resource := reconcileTeam()
+
+			Expect(roster()).To(Equal(map[string]string{
+				alice: teamRoleMember,
+				bob:   teamRoleMaintainer,
+				carol: teamRoleMaintainer,
+			}))
+			Expect(resource.Status.Members).To(Equal([]string{alice}))
+			Expect(resource.Status.Maintainers).To(Equal([]string{bob, carol}))
+		})
+
+		It("should remove unlisted members when pruning", func() {
+			createTeam(githubv1alpha1.TeamSpec{
+				Maintainers:  []string{alice},
+				PruneMembers: github.Bool(true),
+			}, []string{bob}, []string{alice, carol})
+
+			resource := reconcileTeam()
+
+			Expect(roster()).To(Equal(map[string]string{alice: teamRoleMaintainer}))
+			Expect(resource.Status.Members).To(BeEmpty())
+			Expect(resource.Status.Maintainers).To(Equal([]string{alice}))
+		})
+
+		It("should remove everyone when pruning without members or maintainers", func() {
+			createTeam(githubv1alpha1.TeamSpec{
+				PruneMembers: github.Bool(true),
+			}, []string{alice, bob}, nil)
+
+			reconcileTeam()
+
+			Expect(roster()).To(BeEmpty())
+		})
+
+		It("should leave the roster alone without members, maintainers or pruning", func() {
+			createTeam(githubv1alpha1.TeamSpec{}, []string{alice}, []string{bob})
+
+			reconcileTeam()
+
+			Expect(roster()).To(Equal(map[string]string{alice: teamRoleMember, bob: teamRoleMaintainer}))
+		})
+
+		It("should invite users and cancel unlisted invitations when pruning", func() {
+			fakeServer.SetInvitations(true)
+			createTeam(githubv1alpha1.TeamSpec{
+				Members: []string{dave},
+			}, nil, nil)
+
+			By("Inviting a listed user")
+			resource := reconcileTeam()
+			Expect(resource.Status.PendingInvitations).To(Equal([]string{dave}))
+			Expect(roster()).To(BeEmpty())
+
+			By("Not inviting the user again")
+			resource = reconcileTeam()
+			Expect(resource.Status.PendingInvitations).To(Equal([]string{dave}))
+
+			By("Cancelling the invitation once the user is unlisted")
+			resource.Spec.Members = []string{erin}
+			resource.Spec.PruneMembers = github.Bool(true)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			resource = reconcileTeam()
+			Expect(resource.Status.PendingInvitations).To(Equal([]string{erin}))
+			invitations, err := fakeGHClient.GetTeamPendingInvitations(ctx, testOrganization, rosterTeamName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(invitations).To(ConsistOf(HaveField("Login", HaveValue(Equal(erin)))))
+		})
+
+		It("should only plan roster changes under the Observe management policy", func() {
+			observe := githubv1alpha1.ManagementPolicyObserve
+			createTeam(githubv1alpha1.TeamSpec{
+				Members:          []string{alice},
+				Maintainers:      []string{bob},
+				PruneMembers:     github.Bool(true),
+				ManagementPolicy: &observe,
+			}, []string{bob, carol}, nil)
+
+			resource := reconcileTeam()
+
+			Expect(roster()).To(Equal(map[string]string{bob: teamRoleMember, carol: teamRoleMember}))
+			Expect(resource.Status.Plan).To(ConsistOf(
+				"remove team "+rosterTeamName+" member "+carol,
+				"update team "+rosterTeamName+" member "+bob+" role from member to maintainer",
+				"add team "+rosterTeamName+" member "+alice,
+			))
+		})
+	})
+})
+
+var _ = Describe("teamRepositoryPermission", func() {
+	DescribeTable("should convert a GraphQL repository permission to the REST permission of a Team spec",
+		func(permission string, expected githubv1alpha1.RepositoryPermission) {
+			Expect(teamRepositoryPermission(permission)).To(Equal(expected))
+		},
+		Entry("read", "READ", githubv1alpha1.Pull),
+		Entry("write", "WRITE", githubv1alpha1.Push),
+		Entry("triage", "TRIAGE", githubv1alpha1.Triage),
+		Entry("maintain", "MAINTAIN", githubv1alpha1.Maintain),
+		Entry("admin", "ADMIN", githubv1alpha1.Admin),
+		Entry("a REST permission", "pull", githubv1alpha1.Pull),
+	)
 })
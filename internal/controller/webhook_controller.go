@@ -0,0 +1,521 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+	"github.com/google/go-github/v60/github"
+)
+
+var (
+	webhookFinalizerName = "github.github-operator.eczy.io/webhook-finalizer"
+
+	// GitHub never returns webhook secrets, so the hash of the last applied
+	// secret is tracked on the resource to detect rotation.
+	webhookSecretHashAnnotation = "github.github-operator.eczy.io/webhook-secret-hash"
+)
+
+type WebhookRequester interface {
+	GetRepositoryWebhook(ctx context.Context, owner, repo string, id int64) (*github.Hook, error)
+	GetRepositoryWebhooks(ctx context.Context, owner, repo string) ([]*github.Hook, error)
+	CreateRepositoryWebhook(ctx context.Context, owner, repo string, hook *github.Hook) (*github.Hook, error)
+	UpdateRepositoryWebhook(ctx context.Context, owner, repo string, id int64, hook *github.Hook) (*github.Hook, error)
+	DeleteRepositoryWebhook(ctx context.Context, owner, repo string, id int64) error
+	GetRepositoryWebhookLastDelivery(ctx context.Context, owner, repo string, id int64) (*github.HookDelivery, error)
+	GetOrganizationWebhook(ctx context.Context, org string, id int64) (*github.Hook, error)
+	GetOrganizationWebhooks(ctx context.Context, org string) ([]*github.Hook, error)
+	CreateOrganizationWebhook(ctx context.Context, org string, hook *github.Hook) (*github.Hook, error)
+	UpdateOrganizationWebhook(ctx context.Context, org string, id int64, hook *github.Hook) (*github.Hook, error)
+	DeleteOrganizationWebhook(ctx context.Context, org string, id int64) error
+	GetOrganizationWebhookLastDelivery(ctx context.Context, org string, id int64) (*github.HookDelivery, error)
+}
+
+// WebhookReconciler reconciles a Webhook object
+type WebhookReconciler struct {
+	client.Client
+	Scheme                   *runtime.Scheme
+	GitHubClient             WebhookRequester
+	Recorder                 record.EventRecorder
+	DeleteOnResourceDeletion bool
+	DryRun                   bool
+	Providers                *GitHubProviderClients
+	RequeueInterval          time.Duration
+}
+
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=webhooks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=webhooks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=github.github-operator.eczy.io,resources=webhooks/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.0/pkg/reconcile
+func (r *WebhookReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := log.FromContext(ctx)
+
+	// fetch resource
+	webhook := &githubv1alpha1.Webhook{}
+	if err := r.Get(ctx, req.NamespacedName, webhook); err != nil {
+		log.Error(err, "error fetching Webhook resource")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// under the Observe management policy or in dry-run mode, mutations are recorded but never sent to GitHub
+	managementPolicy := resolveManagementPolicy(webhook.Spec.ManagementPolicy)
+	dryRun := resolveDryRun(webhook, r.DryRun)
+	ctx = withMutationRecorder(ctx, managementPolicy != githubv1alpha1.ManagementPolicyObserve && !dryRun)
+	ctx = withDriftRecorder(ctx)
+
+	// record the outcome of this reconcile in status conditions and events
+	defer func() {
+		reportReconcileStatus(ctx, r.Client, r.Recorder, webhook, &webhook.Status.Conditions, &webhook.Status.ObservedGeneration, &webhook.Status.Drift, &webhook.Status.Plan, err)
+	}()
+
+	ghClient, result, err := resolveGitHubClient(ctx, r.Providers, webhook.Spec.ProviderRef, webhook.Spec.Owner, r.GitHubClient)
+	if err != nil {
+		return result, err
+	}
+	withClient := *r
+	withClient.GitHubClient = ghClient
+	r = &withClient
+
+	// try to fetch external resource
+	var observed *github.Hook
+	if webhook.Status.Id != nil {
+		log.Info("getting webhook by id", "id", *webhook.Status.Id)
+		ghHook, err := r.getWebhook(ctx, webhook, *webhook.Status.Id)
+		if _, ok := err.(*gh.WebhookNotFoundError); ok {
+			log.Info(err.Error())
+		} else if err != nil {
+			log.Error(err, "error fetching GitHub webhook")
+			return ctrl.Result{}, err
+		}
+		observed = ghHook
+	} else {
+		log.Info("getting webhook by url", "url", webhook.Spec.URL)
+		ghHook, err := r.getWebhookByURL(ctx, webhook)
+		if err != nil {
+			log.Error(err, "error fetching GitHub webhooks")
+			return ctrl.Result{}, err
+		}
+		observed = ghHook
+	}
+
+	// if external resource does't exist and we aren't deleting the resource, create external resource
+	if observed == nil && webhook.DeletionTimestamp.IsZero() {
+		if managementPolicy != githubv1alpha1.ManagementPolicyFull {
+			// only the Full management policy creates missing GitHub resources
+			skipMutation(ctx, "create webhook %s", webhook.Spec.URL)
+		} else if shouldMutate(ctx, "create webhook %s", webhook.Spec.URL) {
+			ghHook, err := r.createWebhook(ctx, webhook)
+			if err != nil {
+				log.Error(err, "error creating GitHub webhook")
+				return ctrl.Result{}, err
+			}
+			observed = ghHook
+			r.Recorder.Event(webhook, corev1.EventTypeNormal, eventReasonCreated, "Created GitHub webhook")
+		}
+	}
+
+	// handle finalizer
+	deletionPolicy := resolveDeletionPolicy(webhook.Spec.DeletionPolicy, managementPolicy, r.DeleteOnResourceDeletion)
+	if webhook.DeletionTimestamp.IsZero() {
+		// not being deleted
+		if deletionPolicy != githubv1alpha1.DeletionPolicyOrphan && !controllerutil.ContainsFinalizer(webhook, webhookFinalizerName) {
+			controllerutil.AddFinalizer(webhook, webhookFinalizerName)
+			if err := r.Update(ctx, webhook); err != nil {
+				return ctrl.Result{}, err
+			}
+		} else if deletionPolicy == githubv1alpha1.DeletionPolicyOrphan && controllerutil.ContainsFinalizer(webhook, webhookFinalizerName) {
+			// the GitHub resource is left in place, so there is nothing to clean up
+			controllerutil.RemoveFinalizer(webhook, webhookFinalizerName)
+			if err := r.Update(ctx, webhook); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	} else if controllerutil.ContainsFinalizer(webhook, webhookFinalizerName) {
+		// being deleted
+		if deletionPolicy == githubv1alpha1.DeletionPolicyDelete && webhook.Status.Id != nil && observed != nil {
+			// if we have never resolved this resource before, don't
+			// touch external state
+			if !shouldMutate(ctx, "delete webhook %s", webhook.Spec.URL) {
+				// keep the finalizer so the deletion is applied once the resource leaves dry-run mode
+				return ctrl.Result{}, nil
+			}
+			if err := r.deleteWebhook(ctx, webhook); err != nil {
+				log.Error(err, "error deleting webhook")
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(webhook, corev1.EventTypeNormal, eventReasonDeleted, "Deleted GitHub webhook")
+		}
+
+		controllerutil.RemoveFinalizer(webhook, webhookFinalizerName)
+		if err := r.Update(ctx, webhook); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// external resource is gone or wasn't created under the management policy
+	if observed == nil {
+		return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+	}
+
+	// update external resource
+	err = r.updateWebhook(ctx, webhook, observed)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.RequeueInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *WebhookReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&githubv1alpha1.Webhook{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.webhooksForSecret)).
+		Complete(r)
+}
+
+// maps a Kubernetes Secret to the Webhooks in the same namespace that reference it,
+// so that rotating the Secret pushes the new webhook secret without waiting for the requeue interval.
+func (r *WebhookReconciler) webhooksForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := log.FromContext(ctx)
+
+	webhooks := &githubv1alpha1.WebhookList{}
+	if err := r.List(ctx, webhooks, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "error listing Webhook resources")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, webhook := range webhooks.Items {
+		if webhook.Spec.SecretKeyRef != nil && webhook.Spec.SecretKeyRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: webhook.Namespace, Name: webhook.Name},
+			})
+		}
+	}
+	return requests
+}
+
+func (r *WebhookReconciler) updateWebhook(ctx context.Context, webhook *githubv1alpha1.Webhook, ghHook *github.Hook) error {
+	log := log.FromContext(ctx)
+
+	secret, secretHash, err := r.webhookSecret(ctx, webhook)
+	if err != nil {
+		log.Error(err, "error reading webhook secret")
+		return err
+	}
+	update, needsUpdate := webhookUpdate(ctx, webhook, ghHook)
+	// a managed secret is always sent, since it can't be read back
+	update.Config.Secret = secret
+
+	// Secret
+	secretChanged := secret != nil && webhook.GetAnnotations()[webhookSecretHashAnnotation] != secretHash
+	if secretChanged {
+		log.Info("webhook Secret update", "url", webhook.Spec.URL)
+		// the secret is write-only, so only its hash is reported
+		recordDrift(ctx, "secret", secretHash, webhook.GetAnnotations()[webhookSecretHashAnnotation])
+		needsUpdate = true
+	}
+
+	// perform update if necessary
+	if needsUpdate && shouldMutate(ctx, "update webhook %s", webhook.Spec.URL) {
+		log.Info("updating webhook", "id", ghHook.GetID())
+		var updated *github.Hook
+		if webhook.Spec.RepositoryName == nil {
+			updated, err = r.GitHubClient.UpdateOrganizationWebhook(ctx, webhook.Spec.Owner, ghHook.GetID(), update)
+		} else {
+			updated, err = r.GitHubClient.UpdateRepositoryWebhook(ctx, webhook.Spec.Owner, *webhook.Spec.RepositoryName, ghHook.GetID(), update)
+		}
+		if err != nil {
+			log.Error(err, "error updating webhook", "id", ghHook.GetID())
+			return err
+		}
+		r.Recorder.Event(webhook, corev1.EventTypeNormal, eventReasonUpdated, "Updated GitHub webhook")
+		ghHook = updated
+
+		if secretChanged {
+			if err := r.setWebhookSecretHash(ctx, webhook, secretHash); err != nil {
+				log.Error(err, "error updating Webhook secret hash annotation")
+				return err
+			}
+		}
+	}
+
+	lastDelivery, err := r.getWebhookLastDelivery(ctx, webhook, ghHook)
+	if err != nil {
+		return err
+	}
+	deliveryChanged := lastDelivery != nil &&
+		(webhook.Status.LastDelivery == nil || webhook.Status.LastDelivery.Id == nil || *webhook.Status.LastDelivery.Id != *lastDelivery.Id)
+
+	// a webhook recreated after it was deleted outside of the operator has a new ID
+	idChanged := !ptrEqual(ghHook.ID, webhook.Status.Id)
+	if needsUpdate || deliveryChanged || idChanged || webhook.Status.LastUpdateTimestamp == nil {
+		config := ghHook.GetConfig()
+		contentType := githubv1alpha1.WebhookContentType(config.GetContentType())
+		now := v1.Now()
+		webhook.Status = githubv1alpha1.WebhookStatus{
+			LastUpdateTimestamp: &now,
+			Id:                  ghHook.ID,
+			Owner:               github.String(webhook.Spec.Owner),
+			RepositoryName:      webhook.Spec.RepositoryName,
+			URL:                 config.URL,
+			ContentType:         &contentType,
+			Events:              ghHook.Events,
+			Active:              ghHook.Active,
+			InsecureSSL:         github.Bool(config.GetInsecureSSL() == "1"),
+			CreatedAt:           (*v1.Time)(ghHook.CreatedAt),
+			UpdatedAt:           (*v1.Time)(ghHook.UpdatedAt),
+			LastDelivery:        lastDelivery,
+			Conditions:          webhook.Status.Conditions,
+			ObservedGeneration:  webhook.Status.ObservedGeneration,
+			Drift:               webhook.Status.Drift,
+			Plan:                webhook.Status.Plan,
+		}
+
+		// update status
+		if err := r.Status().Update(ctx, webhook); err != nil {
+			log.Error(err, "error updating Webhook status", "id", ghHook.GetID())
+		}
+	}
+	return nil
+}
+
+// webhookUpdate compares a webhook spec to the observed webhook, other than its secret, and returns
+// the update that reconciles them and whether anything differs.
+func webhookUpdate(ctx context.Context, webhook *githubv1alpha1.Webhook, ghHook *github.Hook) (*github.Hook, bool) {
+	log := log.FromContext(ctx)
+
+	config := ghHook.GetConfig()
+	observedContentType := githubv1alpha1.WebhookContentType(config.GetContentType())
+	observedInsecureSSL := config.GetInsecureSSL() == "1"
+
+	update := &github.Hook{
+		Config: &github.HookConfig{
+			URL:         config.URL,
+			ContentType: config.ContentType,
+			InsecureSSL: config.InsecureSSL,
+		},
+		Events: ghHook.Events,
+		Active: ghHook.Active,
+	}
+	needsUpdate := false
+
+	// URL
+	if webhook.Spec.URL != config.GetURL() {
+		log.Info("webhook URL update", "from", config.GetURL(), "to", webhook.Spec.URL)
+		recordDrift(ctx, "url", webhook.Spec.URL, config.GetURL())
+		update.Config.URL = github.String(webhook.Spec.URL)
+		needsUpdate = true
+	}
+	// ContentType
+	if ptrNonNilAndNotEqualTo(webhook.Spec.ContentType, observedContentType) {
+		log.Info("webhook ContentType update", "from", observedContentType, "to", webhook.Spec.ContentType)
+		recordDrift(ctx, "contentType", webhook.Spec.ContentType, observedContentType)
+		update.Config.ContentType = github.String(string(*webhook.Spec.ContentType))
+		needsUpdate = true
+	}
+	// InsecureSSL
+	if ptrNonNilAndNotEqualTo(webhook.Spec.InsecureSSL, observedInsecureSSL) {
+		log.Info("webhook InsecureSSL update", "from", observedInsecureSSL, "to", webhook.Spec.InsecureSSL)
+		recordDrift(ctx, "insecureSSL", webhook.Spec.InsecureSSL, observedInsecureSSL)
+		update.Config.InsecureSSL = github.String(webhookInsecureSSL(*webhook.Spec.InsecureSSL))
+		needsUpdate = true
+	}
+	// Events
+	if webhook.Spec.Events != nil && !cmpSlices(webhook.Spec.Events, ghHook.Events) {
+		log.Info("webhook Events update", "from", ghHook.Events, "to", webhook.Spec.Events)
+		recordDrift(ctx, "events", webhook.Spec.Events, ghHook.Events)
+		update.Events = webhook.Spec.Events
+		needsUpdate = true
+	}
+	// Active
+	if ptrNonNilAndNotEqualTo(webhook.Spec.Active, ghHook.GetActive()) {
+		log.Info("webhook Active update", "from", ghHook.GetActive(), "to", webhook.Spec.Active)
+		recordDrift(ctx, "active", webhook.Spec.Active, ghHook.GetActive())
+		update.Active = webhook.Spec.Active
+		needsUpdate = true
+	}
+	return update, needsUpdate
+}
+
+// returns the most recent delivery of a webhook, or nil if it hasn't delivered anything
+func (r *WebhookReconciler) getWebhookLastDelivery(ctx context.Context, webhook *githubv1alpha1.Webhook, ghHook *github.Hook) (*githubv1alpha1.WebhookDelivery, error) {
+	log := log.FromContext(ctx)
+
+	var delivery *github.HookDelivery
+	var err error
+	if webhook.Spec.RepositoryName == nil {
+		delivery, err = r.GitHubClient.GetOrganizationWebhookLastDelivery(ctx, webhook.Spec.Owner, ghHook.GetID())
+	} else {
+		delivery, err = r.GitHubClient.GetRepositoryWebhookLastDelivery(ctx, webhook.Spec.Owner, *webhook.Spec.RepositoryName, ghHook.GetID())
+	}
+	if err != nil {
+		log.Error(err, "error fetching webhook deliveries", "id", ghHook.GetID())
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, nil
+	}
+	return &githubv1alpha1.WebhookDelivery{
+		Id:          delivery.ID,
+		Event:       delivery.Event,
+		Action:      delivery.Action,
+		Status:      delivery.Status,
+		StatusCode:  delivery.StatusCode,
+		DeliveredAt: (*v1.Time)(delivery.DeliveredAt),
+	}, nil
+}
+
+func (r *WebhookReconciler) createWebhook(ctx context.Context, webhook *githubv1alpha1.Webhook) (*github.Hook, error) {
+	secret, secretHash, err := r.webhookSecret(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	create := &github.Hook{
+		Name: github.String("web"),
+		Config: &github.HookConfig{
+			URL:    github.String(webhook.Spec.URL),
+			Secret: secret,
+		},
+		Events: webhook.Spec.Events,
+		Active: webhook.Spec.Active,
+	}
+	if webhook.Spec.ContentType != nil {
+		create.Config.ContentType = github.String(string(*webhook.Spec.ContentType))
+	}
+	if webhook.Spec.InsecureSSL != nil {
+		create.Config.InsecureSSL = github.String(webhookInsecureSSL(*webhook.Spec.InsecureSSL))
+	}
+
+	var ghHook *github.Hook
+	if webhook.Spec.RepositoryName == nil {
+		ghHook, err = r.GitHubClient.CreateOrganizationWebhook(ctx, webhook.Spec.Owner, create)
+	} else {
+		ghHook, err = r.GitHubClient.CreateRepositoryWebhook(ctx, webhook.Spec.Owner, *webhook.Spec.RepositoryName, create)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.setWebhookSecretHash(ctx, webhook, secretHash); err != nil {
+		return nil, err
+	}
+	return ghHook, nil
+}
+
+// returns the desired webhook secret and its hash. The secret is nil and the hash is
+// empty if the webhook's secret isn't managed, so that the secret of an adopted webhook is kept.
+func (r *WebhookReconciler) webhookSecret(ctx context.Context, webhook *githubv1alpha1.Webhook) (*string, string, error) {
+	if webhook.Spec.SecretKeyRef == nil {
+		return nil, "", nil
+	}
+	value, valueHash, err := secretKeyValueAndHash(ctx, r.Client, webhook.Namespace, *webhook.Spec.SecretKeyRef)
+	if err != nil {
+		return nil, "", err
+	}
+	return github.String(string(value)), valueHash, nil
+}
+
+func (r *WebhookReconciler) setWebhookSecretHash(ctx context.Context, webhook *githubv1alpha1.Webhook, secretHash string) error {
+	if webhook.GetAnnotations()[webhookSecretHashAnnotation] == secretHash {
+		return nil
+	}
+	annotations := webhook.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if secretHash == "" {
+		delete(annotations, webhookSecretHashAnnotation)
+	} else {
+		annotations[webhookSecretHashAnnotation] = secretHash
+	}
+	webhook.SetAnnotations(annotations)
+	return r.Update(ctx, webhook)
+}
+
+func (r *WebhookReconciler) getWebhook(ctx context.Context, webhook *githubv1alpha1.Webhook, id int64) (*github.Hook, error) {
+	if webhook.Spec.RepositoryName == nil {
+		return r.GitHubClient.GetOrganizationWebhook(ctx, webhook.Spec.Owner, id)
+	}
+	return r.GitHubClient.GetRepositoryWebhook(ctx, webhook.Spec.Owner, *webhook.Spec.RepositoryName, id)
+}
+
+// finds an existing webhook with the same payload URL, so that webhooks created
+// outside of the operator are adopted rather than duplicated
+func (r *WebhookReconciler) getWebhookByURL(ctx context.Context, webhook *githubv1alpha1.Webhook) (*github.Hook, error) {
+	var hooks []*github.Hook
+	var err error
+	if webhook.Spec.RepositoryName == nil {
+		hooks, err = r.GitHubClient.GetOrganizationWebhooks(ctx, webhook.Spec.Owner)
+	} else {
+		hooks, err = r.GitHubClient.GetRepositoryWebhooks(ctx, webhook.Spec.Owner, *webhook.Spec.RepositoryName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range hooks {
+		if hook.GetConfig().GetURL() == webhook.Spec.URL {
+			return hook, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *WebhookReconciler) deleteWebhook(ctx context.Context, webhook *githubv1alpha1.Webhook) error {
+	if webhook.Status.Owner == nil {
+		return fmt.Errorf("webhook Owner is nil")
+	} else if webhook.Status.Id == nil {
+		return fmt.Errorf("webhook Id is nil")
+	}
+	if webhook.Status.RepositoryName == nil {
+		return r.GitHubClient.DeleteOrganizationWebhook(ctx, *webhook.Status.Owner, *webhook.Status.Id)
+	}
+	return r.GitHubClient.DeleteRepositoryWebhook(ctx, *webhook.Status.Owner, *webhook.Status.RepositoryName, *webhook.Status.Id)
+}
+
+func webhookInsecureSSL(insecure bool) string {
+	if insecure {
+		return "1"
+	}
+	return "0"
+}
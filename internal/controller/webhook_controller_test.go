@@ -0,0 +1,240 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+var _ = Describe("Webhook Controller", func() {
+	const resourceName = "test-resource"
+	const testWebhookURL = "https://example.com/github-operator-test"
+	const testSecretKey = "secret"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	secretNamespacedName := types.NamespacedName{
+		Name:      "test-webhook-secret",
+		Namespace: "default",
+	}
+	testRepoName := ghTestResourcePrefix + "webhook-test-repo"
+
+	reconcileWebhook := func(deleteOnResourceDeletion bool) {
+		controllerReconciler := &WebhookReconciler{
+			Client:                   k8sClient,
+			Scheme:                   k8sClient.Scheme(),
+			GitHubClient:             fakeGHClient,
+			Recorder:                 &record.FakeRecorder{},
+			DeleteOnResourceDeletion: deleteOnResourceDeletion,
+		}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		By("Creating a test repository")
+		_, err := fakeGHClient.CreateRepository(ctx, testOrganization, &github.Repository{
+			Name: &testRepoName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Creating the Secret holding the webhook secret")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretNamespacedName.Name,
+				Namespace: secretNamespacedName.Namespace,
+			},
+			Data: map[string][]byte{testSecretKey: []byte("first-secret")},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+		By("Creating the custom resource for the Kind Webhook")
+		contentType := githubv1alpha1.WebhookContentTypeJson
+		resource := &githubv1alpha1.Webhook{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: githubv1alpha1.WebhookSpec{
+				Owner:          testOrganization,
+				RepositoryName: &testRepoName,
+				URL:            testWebhookURL,
+				ContentType:    &contentType,
+				Events:         []string{"push", "pull_request"},
+				Active:         github.Bool(true),
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secretNamespacedName.Name},
+					Key:                  testSecretKey,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the specific resource instance Webhook")
+		cleanUpResource(ctx, typeNamespacedName, &githubv1alpha1.Webhook{})
+		cleanUpResource(ctx, secretNamespacedName, &corev1.Secret{})
+
+		By("Cleaning up the test repository")
+		Expect(fakeGHClient.DeleteRepositoryByName(ctx, testOrganization, testRepoName)).To(Succeed())
+	})
+
+	Context("When creating a Webhook resource", func() {
+		It("should create a GitHub webhook", func() {
+			By("Reconciling the resource")
+			reconcileWebhook(false)
+
+			By("Checking the Webhook status")
+			resource := &githubv1alpha1.Webhook{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Id).NotTo(BeNil())
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNamespacedName, secret)).To(Succeed())
+			Expect(resource.GetAnnotations()).To(HaveKeyWithValue(webhookSecretHashAnnotation, secretValueHash(secret.UID, []byte("first-secret"))))
+
+			By("Checking the GitHub webhook")
+			ghHook, err := fakeGHClient.GetRepositoryWebhook(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghHook.GetConfig().GetURL()).To(Equal(testWebhookURL))
+			Expect(ghHook.GetConfig().GetContentType()).To(Equal("json"))
+			Expect(ghHook.Events).To(ConsistOf("push", "pull_request"))
+			Expect(ghHook.GetActive()).To(BeTrue())
+			ghSecret, ok := fakeServer.WebhookSecret(testOrganization, testRepoName, *resource.Status.Id)
+			Expect(ok).To(BeTrue())
+			Expect(ghSecret).To(Equal("first-secret"))
+		})
+	})
+
+	Context("When the GitHub webhook drifts", func() {
+		It("should correct drift of the GitHub webhook", func() {
+			By("Reconciling the resource")
+			reconcileWebhook(false)
+			resource := &githubv1alpha1.Webhook{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+
+			By("Changing the GitHub webhook outside of the operator")
+			_, err := fakeGHClient.UpdateRepositoryWebhook(ctx, testOrganization, testRepoName, *resource.Status.Id, &github.Hook{
+				Active: github.Bool(false),
+				Events: []string{"push"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Reconciling the resource")
+			reconcileWebhook(false)
+
+			By("Checking the GitHub webhook")
+			ghHook, err := fakeGHClient.GetRepositoryWebhook(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghHook.Events).To(ConsistOf("push", "pull_request"))
+			Expect(ghHook.GetActive()).To(BeTrue())
+
+			By("Checking the drift is reported")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "active")))
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "events")))
+		})
+
+		It("should rotate the GitHub webhook secret when the referenced secret changes", func() {
+			By("Reconciling the resource")
+			reconcileWebhook(false)
+
+			By("Changing the referenced secret")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, secretNamespacedName, secret)).To(Succeed())
+			secret.Data[testSecretKey] = []byte("second-secret")
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			By("Reconciling the resource")
+			reconcileWebhook(false)
+
+			By("Checking the GitHub webhook secret")
+			resource := &githubv1alpha1.Webhook{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			ghSecret, ok := fakeServer.WebhookSecret(testOrganization, testRepoName, *resource.Status.Id)
+			Expect(ok).To(BeTrue())
+			Expect(ghSecret).To(Equal("second-secret"))
+			Expect(resource.Status.Drift).To(ContainElement(HaveField("Field", "secret")))
+		})
+		It("should recreate a GitHub webhook deleted outside of the operator", func() {
+			By("Reconciling the resource")
+			reconcileWebhook(false)
+			resource := &githubv1alpha1.Webhook{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			oldId := *resource.Status.Id
+
+			By("Deleting the GitHub webhook outside of the operator")
+			Expect(fakeGHClient.DeleteRepositoryWebhook(ctx, testOrganization, testRepoName, oldId)).To(Succeed())
+
+			By("Reconciling the resource")
+			reconcileWebhook(false)
+
+			By("Checking the Webhook status follows the new GitHub webhook")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(*resource.Status.Id).NotTo(Equal(oldId))
+			ghHook, err := fakeGHClient.GetRepositoryWebhook(ctx, testOrganization, testRepoName, *resource.Status.Id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ghHook.GetConfig().GetURL()).To(Equal(testWebhookURL))
+		})
+	})
+
+	Context("When deleting a Webhook resource", func() {
+		It("should delete the GitHub webhook with the resource", func() {
+			By("Reconciling the resource")
+			reconcileWebhook(true)
+			resource := &githubv1alpha1.Webhook{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(webhookFinalizerName))
+			id := *resource.Status.Id
+
+			By("Deleting the resource")
+			Expect(k8sClient.Delete(ctx, resource, &client.DeleteOptions{
+				GracePeriodSeconds: &deletionGracePeriod,
+			})).To(Succeed())
+			reconcileWebhook(true)
+
+			By("Checking the resource is gone")
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("Checking the GitHub webhook is gone")
+			_, err = fakeGHClient.GetRepositoryWebhook(ctx, testOrganization, testRepoName, id)
+			Expect(err).To(BeAssignableToTypeOf(&gh.WebhookNotFoundError{}))
+		})
+	})
+})
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "github_operator_http_cache_requests_total",
+		Help: "GET requests to GitHub by cache result. A hit is a 304 Not Modified served from the cache.",
+	}, []string{"result"})
+	cacheBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "github_operator_http_cache_bytes",
+		Help: "Size of the response bodies held in the GitHub HTTP cache.",
+	})
+	cacheEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "github_operator_http_cache_entries",
+		Help: "Number of responses held in the GitHub HTTP cache.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(cacheRequests, cacheBytes, cacheEntries)
+}
+
+type cacheEntry struct {
+	key          string
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+// HTTPCache holds the GitHub responses of caching transports in a single LRU, so that their total
+// size stays within maxBytes however many clients are created. Responses are evicted least
+// recently used first once their bodies exceed maxBytes.
+type HTTPCache struct {
+	maxBytes int
+
+	mu      sync.Mutex
+	size    int
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+func NewHTTPCache(maxBytes int) *HTTPCache {
+	return &HTTPCache{
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// cachingRoundTripper makes GET requests conditional on the ETag or Last-Modified of the
+// last response for the same URL and serves 304 Not Modified responses from cache.
+// GitHub doesn't count 304 responses against the rate limit.
+type cachingRoundTripper struct {
+	base      http.RoundTripper
+	cache     *HTTPCache
+	partition string
+}
+
+// CachingRoundTripper caches the responses of base in cache. Transports with different credentials
+// must use different partitions, since the responses GitHub returns depend on them.
+func CachingRoundTripper(base http.RoundTripper, cache *HTTPCache, partition string) http.RoundTripper {
+	return &cachingRoundTripper{
+		base:      base,
+		cache:     cache,
+		partition: partition,
+	}
+}
+
+func (c *cachingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// callers making their own conditional requests handle the 304 themselves
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return c.base.RoundTrip(req)
+	}
+	// GitHub varies responses on Accept and credentials
+	key := c.partition + " " + req.URL.String() + " " + req.Header.Get("Accept")
+
+	cached := c.cache.get(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := c.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		cacheRequests.WithLabelValues("hit").Inc()
+		resp.Body.Close()
+		// keep the fresh rate limit headers of the 304
+		header := cached.header.Clone()
+		for k, v := range resp.Header {
+			header[k] = v
+		}
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Header = header
+		resp.Body = io.NopCloser(bytes.NewReader(cached.body))
+		resp.ContentLength = int64(len(cached.body))
+		return resp, nil
+	}
+	cacheRequests.WithLabelValues("miss").Inc()
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		c.cache.remove(key)
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	c.cache.add(&cacheEntry{
+		key:          key,
+		etag:         etag,
+		lastModified: lastModified,
+		header:       resp.Header.Clone(),
+		body:         body,
+	})
+	return resp, nil
+}
+
+func (c *HTTPCache) get(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*cacheEntry)
+}
+
+func (c *HTTPCache) add(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeLocked(entry.key)
+	// a response larger than the whole cache would only evict everything else
+	if len(entry.body) > c.maxBytes {
+		return
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	c.size += len(entry.body)
+	cacheBytes.Add(float64(len(entry.body)))
+	cacheEntries.Inc()
+	for c.size > c.maxBytes {
+		c.removeLocked(c.lru.Back().Value.(*cacheEntry).key)
+	}
+}
+
+func (c *HTTPCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeLocked(key)
+}
+
+func (c *HTTPCache) removeLocked(key string) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	c.lru.Remove(elem)
+	delete(c.entries, key)
+	size := len(elem.Value.(*cacheEntry).body)
+	c.size -= size
+	cacheBytes.Sub(float64(size))
+	cacheEntries.Dec()
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("Caching RoundTripper", func() {
+	var (
+		server *httptest.Server
+		cache  *HTTPCache
+		client *http.Client
+
+		mu sync.Mutex
+		// the conditional request headers the server received, by path
+		conditional map[string][]string
+	)
+
+	// serves a body of 10 bytes per path and Accept header, with an ETag derived from both
+	startServer := func(maxBytes int) {
+		conditional = map[string][]string{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			conditional[r.URL.Path] = append(conditional[r.URL.Path], r.Header.Get("If-None-Match"))
+			mu.Unlock()
+
+			if r.Method != http.MethodGet {
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			etag := `"` + r.URL.Path + r.Header.Get("Accept") + `"`
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Header().Set("X-RateLimit-Remaining", "5000")
+			_, _ = io.WriteString(w, (r.URL.Path + strings.Repeat("-", 10))[:10])
+		}))
+		cache = NewHTTPCache(maxBytes)
+		client = &http.Client{Transport: CachingRoundTripper(http.DefaultTransport, cache, "a")}
+	}
+
+	getWith := func(client *http.Client, path, accept string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		return resp
+	}
+
+	get := func(path, accept string) *http.Response {
+		return getWith(client, path, accept)
+	}
+
+	body := func(resp *http.Response) string {
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	counter := func(result string) float64 {
+		m := &dto.Metric{}
+		Expect(cacheRequests.WithLabelValues(result).Write(m)).To(Succeed())
+		return m.GetCounter().GetValue()
+	}
+
+	gauge := func(g prometheus.Gauge) float64 {
+		m := &dto.Metric{}
+		Expect(g.Write(m)).To(Succeed())
+		return m.GetGauge().GetValue()
+	}
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should serve a 304 Not Modified from the cache", func() {
+		startServer(1024)
+
+		first := get("/repos/a", "")
+		Expect(body(first)).To(Equal("/repos/a--"))
+
+		second := get("/repos/a", "")
+		Expect(second.StatusCode).To(Equal(http.StatusOK))
+		Expect(second.Status).To(Equal("200 OK"))
+		Expect(body(second)).To(Equal("/repos/a--"))
+		Expect(second.Header.Get("ETag")).To(Equal(`"/repos/a"`))
+
+		By("Keeping the rate limit headers of the 304")
+		Expect(second.Header.Get("X-RateLimit-Remaining")).To(Equal("4999"))
+		Expect(conditional["/repos/a"]).To(Equal([]string{"", `"/repos/a"`}))
+	})
+
+	It("should cache responses per Accept header", func() {
+		startServer(1024)
+
+		Expect(body(get("/repos/a", "application/json"))).To(Equal("/repos/a--"))
+		Expect(body(get("/repos/a", "application/vnd.github.raw"))).To(Equal("/repos/a--"))
+		Expect(body(get("/repos/a", "application/json"))).To(Equal("/repos/a--"))
+
+		Expect(conditional["/repos/a"]).To(Equal([]string{"", "", `"/repos/aapplication/json"`}))
+	})
+
+	It("should evict the least recently used response once maxBytes is exceeded", func() {
+		startServer(20)
+
+		body(get("/repos/a", ""))
+		body(get("/repos/b", ""))
+		// a is now used more recently than b
+		body(get("/repos/a", ""))
+		// c doesn't fit next to a and b
+		body(get("/repos/c", ""))
+
+		body(get("/repos/a", ""))
+		body(get("/repos/b", ""))
+
+		Expect(conditional["/repos/a"]).To(Equal([]string{"", `"/repos/a"`, `"/repos/a"`}))
+		Expect(conditional["/repos/b"]).To(Equal([]string{"", ""}))
+	})
+
+	It("should not cache requests other than GET", func() {
+		startServer(1024)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/repos/a", strings.NewReader("{}"))
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		resp.Body.Close()
+
+		body(get("/repos/a", ""))
+
+		Expect(conditional["/repos/a"]).To(Equal([]string{"", ""}))
+	})
+
+	It("should count cache hits and misses", func() {
+		startServer(1024)
+		hits, misses := counter("hit"), counter("miss")
+
+		body(get("/repos/a", ""))
+		body(get("/repos/a", ""))
+		body(get("/repos/b", ""))
+
+		Expect(counter("hit") - hits).To(Equal(1.0))
+		Expect(counter("miss") - misses).To(Equal(2.0))
+	})
+
+	It("should not serve responses across partitions", func() {
+		startServer(1024)
+		other := &http.Client{Transport: CachingRoundTripper(http.DefaultTransport, cache, "b")}
+
+		body(get("/repos/a", ""))
+		body(getWith(other, "/repos/a", ""))
+		body(getWith(other, "/repos/a", ""))
+
+		Expect(conditional["/repos/a"]).To(Equal([]string{"", "", `"/repos/a"`}))
+	})
+
+	It("should bound the responses of all transports sharing the cache", func() {
+		bytes, entries := gauge(cacheBytes), gauge(cacheEntries)
+		startServer(20)
+		other := &http.Client{Transport: CachingRoundTripper(http.DefaultTransport, cache, "b")}
+
+		body(get("/repos/a", ""))
+		body(getWith(other, "/repos/b", ""))
+		// c doesn't fit next to a and b
+		body(getWith(other, "/repos/c", ""))
+
+		body(get("/repos/a", ""))
+		Expect(conditional["/repos/a"]).To(Equal([]string{"", ""}))
+
+		By("Counting the responses held by the cache")
+		Expect(gauge(cacheBytes) - bytes).To(Equal(20.0))
+		Expect(gauge(cacheEntries) - entries).To(Equal(2.0))
+	})
+})
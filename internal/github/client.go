@@ -18,6 +18,7 @@ package github
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/google/go-github/v60/github"
 	"github.com/shurcooL/githubv4"
@@ -26,35 +27,63 @@ import (
 type Client struct {
 	rest    *github.Client
 	graphql *githubv4.Client
+
+	httpClient *http.Client
+	baseURL    string
+	uploadURL  string
+	graphqlURL string
 }
 
 type ClientOption = func(*Client) error
 
 func WithRoundTripper(rt http.RoundTripper) ClientOption {
 	return func(c *Client) error {
-		c.rest = github.NewClient(&http.Client{
-			Transport: rt,
-		})
-		c.graphql = githubv4.NewClient(&http.Client{
+		c.httpClient = &http.Client{
 			Transport: rt,
-		})
+		}
 		return nil
 	}
 }
 
 func WithHttpClient(client *http.Client) ClientOption {
 	return func(c *Client) error {
-		c.rest = github.NewClient(client)
-		c.graphql = githubv4.NewClient(client)
+		c.httpClient = client
 		return nil
 	}
 }
 
-func NewClient(opts ...ClientOption) (*Client, error) {
-	client := &Client{
-		rest:    github.NewClient(nil),
-		graphql: githubv4.NewClient(nil),
+// WithEnterpriseURLs points the client at a GitHub Enterprise Server instance. baseURL may be
+// the root of the instance or its REST API URL. Empty uploadURL and graphqlURL default to
+// the ones of the instance at baseURL.
+func WithEnterpriseURLs(baseURL, uploadURL, graphqlURL string) ClientOption {
+	return func(c *Client) error {
+		c.baseURL, c.uploadURL, c.graphqlURL = enterpriseURLs(baseURL)
+		if uploadURL != "" {
+			c.uploadURL = uploadURL
+		}
+		if graphqlURL != "" {
+			c.graphqlURL = graphqlURL
+		}
+		return nil
 	}
+}
+
+// enterpriseURLs returns the REST API, upload and GraphQL URLs of the GitHub Enterprise Server
+// instance at baseURL, which may be the root of the instance or its REST API URL.
+func enterpriseURLs(baseURL string) (string, string, string) {
+	root := strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/api/v3")
+	return root + "/api/v3/", root + "/api/uploads/", root + "/api/graphql"
+}
+
+// EnterpriseAPIURL returns the REST API URL of the GitHub Enterprise Server instance at baseURL,
+// without a trailing slash.
+func EnterpriseAPIURL(baseURL string) string {
+	api, _, _ := enterpriseURLs(baseURL)
+	return strings.TrimSuffix(api, "/")
+}
+
+func NewClient(opts ...ClientOption) (*Client, error) {
+	client := &Client{}
 
 	for _, opt := range opts {
 		err := opt(client)
@@ -63,5 +92,16 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		}
 	}
 
+	client.rest = github.NewClient(client.httpClient)
+	client.graphql = githubv4.NewClient(client.httpClient)
+	if client.baseURL != "" {
+		rest, err := client.rest.WithEnterpriseURLs(client.baseURL, client.uploadURL)
+		if err != nil {
+			return nil, err
+		}
+		client.rest = rest
+		client.graphql = githubv4.NewEnterpriseClient(client.graphqlURL, client.httpClient)
+	}
+
 	return client, nil
 }
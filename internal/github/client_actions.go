@@ -0,0 +1,295 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// Actions public keys
+
+func (c *Client) GetRepositoryActionsPublicKey(ctx context.Context, owner, repo string) (*github.PublicKey, error) {
+	key, _, err := c.rest.Actions.GetRepoPublicKey(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("getting GitHub repository public key: %w", err)
+	}
+	return key, nil
+}
+
+func (c *Client) GetOrganizationActionsPublicKey(ctx context.Context, org string) (*github.PublicKey, error) {
+	key, _, err := c.rest.Actions.GetOrgPublicKey(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("getting GitHub organization public key: %w", err)
+	}
+	return key, nil
+}
+
+func (c *Client) GetEnvironmentActionsPublicKey(ctx context.Context, repoId int64, env string) (*github.PublicKey, error) {
+	key, _, err := c.rest.Actions.GetEnvPublicKey(ctx, int(repoId), env)
+	if err != nil {
+		return nil, fmt.Errorf("getting GitHub environment public key: %w", err)
+	}
+	return key, nil
+}
+
+// EncryptActionsSecret encrypts value for the given public key using a libsodium sealed box,
+// as required by the GitHub Actions secrets API.
+func EncryptActionsSecret(publicKey *github.PublicKey, name string, value []byte) (*github.EncryptedSecret, error) {
+	decoded, err := base64.StdEncoding.DecodeString(publicKey.GetKey())
+	if err != nil {
+		return nil, fmt.Errorf("decoding public key: %w", err)
+	}
+	if len(decoded) != 32 {
+		return nil, fmt.Errorf("public key has length %d, expected 32", len(decoded))
+	}
+	var key [32]byte
+	copy(key[:], decoded)
+
+	sealed, err := box.SealAnonymous(nil, value, &key, rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting secret: %w", err)
+	}
+	return &github.EncryptedSecret{
+		Name:           name,
+		KeyID:          publicKey.GetKeyID(),
+		EncryptedValue: base64.StdEncoding.EncodeToString(sealed),
+	}, nil
+}
+
+// Actions secrets
+
+func (c *Client) GetRepositoryActionsSecret(ctx context.Context, owner, repo, name string) (*github.Secret, error) {
+	secret, resp, err := c.rest.Actions.GetRepoSecret(ctx, owner, repo, name)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &ActionsSecretNotFoundError{Owner: &owner, RepositoryName: &repo, Name: &name}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub repository secret: %w", err)
+	}
+	return secret, nil
+}
+
+func (c *Client) GetOrganizationActionsSecret(ctx context.Context, org, name string) (*github.Secret, error) {
+	secret, resp, err := c.rest.Actions.GetOrgSecret(ctx, org, name)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &ActionsSecretNotFoundError{Owner: &org, Name: &name}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub organization secret: %w", err)
+	}
+	return secret, nil
+}
+
+func (c *Client) GetEnvironmentActionsSecret(ctx context.Context, repoId int64, env, name string) (*github.Secret, error) {
+	secret, resp, err := c.rest.Actions.GetEnvSecret(ctx, int(repoId), env, name)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &ActionsSecretNotFoundError{EnvironmentName: &env, Name: &name}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub environment secret: %w", err)
+	}
+	return secret, nil
+}
+
+func (c *Client) CreateOrUpdateRepositoryActionsSecret(ctx context.Context, owner, repo string, secret *github.EncryptedSecret) error {
+	_, err := c.rest.Actions.CreateOrUpdateRepoSecret(ctx, owner, repo, secret)
+	if err != nil {
+		return fmt.Errorf("updating GitHub repository secret: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) CreateOrUpdateOrganizationActionsSecret(ctx context.Context, org string, secret *github.EncryptedSecret) error {
+	_, err := c.rest.Actions.CreateOrUpdateOrgSecret(ctx, org, secret)
+	if err != nil {
+		return fmt.Errorf("updating GitHub organization secret: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) CreateOrUpdateEnvironmentActionsSecret(ctx context.Context, repoId int64, env string, secret *github.EncryptedSecret) error {
+	_, err := c.rest.Actions.CreateOrUpdateEnvSecret(ctx, int(repoId), env, secret)
+	if err != nil {
+		return fmt.Errorf("updating GitHub environment secret: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteRepositoryActionsSecret(ctx context.Context, owner, repo, name string) error {
+	_, err := c.rest.Actions.DeleteRepoSecret(ctx, owner, repo, name)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub repository secret: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteOrganizationActionsSecret(ctx context.Context, org, name string) error {
+	_, err := c.rest.Actions.DeleteOrgSecret(ctx, org, name)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub organization secret: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteEnvironmentActionsSecret(ctx context.Context, repoId int64, env, name string) error {
+	_, err := c.rest.Actions.DeleteEnvSecret(ctx, int(repoId), env, name)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub environment secret: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) GetOrganizationActionsSecretRepositories(ctx context.Context, org, name string) ([]*github.Repository, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Repository{}
+	for {
+		repos, resp, err := c.rest.Actions.ListSelectedReposForOrgSecret(ctx, org, name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub organization secret repositories: %w", err)
+		}
+		out = append(out, repos.Repositories...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+// Actions variables
+
+func (c *Client) GetRepositoryActionsVariable(ctx context.Context, owner, repo, name string) (*github.ActionsVariable, error) {
+	variable, resp, err := c.rest.Actions.GetRepoVariable(ctx, owner, repo, name)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &ActionsVariableNotFoundError{Owner: &owner, RepositoryName: &repo, Name: &name}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub repository variable: %w", err)
+	}
+	return variable, nil
+}
+
+func (c *Client) GetOrganizationActionsVariable(ctx context.Context, org, name string) (*github.ActionsVariable, error) {
+	variable, resp, err := c.rest.Actions.GetOrgVariable(ctx, org, name)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &ActionsVariableNotFoundError{Owner: &org, Name: &name}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub organization variable: %w", err)
+	}
+	return variable, nil
+}
+
+func (c *Client) GetEnvironmentActionsVariable(ctx context.Context, repoId int64, env, name string) (*github.ActionsVariable, error) {
+	variable, resp, err := c.rest.Actions.GetEnvVariable(ctx, int(repoId), env, name)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &ActionsVariableNotFoundError{EnvironmentName: &env, Name: &name}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub environment variable: %w", err)
+	}
+	return variable, nil
+}
+
+func (c *Client) CreateRepositoryActionsVariable(ctx context.Context, owner, repo string, variable *github.ActionsVariable) error {
+	_, err := c.rest.Actions.CreateRepoVariable(ctx, owner, repo, variable)
+	if err != nil {
+		return fmt.Errorf("creating GitHub repository variable: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) CreateOrganizationActionsVariable(ctx context.Context, org string, variable *github.ActionsVariable) error {
+	_, err := c.rest.Actions.CreateOrgVariable(ctx, org, variable)
+	if err != nil {
+		return fmt.Errorf("creating GitHub organization variable: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) CreateEnvironmentActionsVariable(ctx context.Context, repoId int64, env string, variable *github.ActionsVariable) error {
+	_, err := c.rest.Actions.CreateEnvVariable(ctx, int(repoId), env, variable)
+	if err != nil {
+		return fmt.Errorf("creating GitHub environment variable: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) UpdateRepositoryActionsVariable(ctx context.Context, owner, repo string, variable *github.ActionsVariable) error {
+	_, err := c.rest.Actions.UpdateRepoVariable(ctx, owner, repo, variable)
+	if err != nil {
+		return fmt.Errorf("updating GitHub repository variable: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) UpdateOrganizationActionsVariable(ctx context.Context, org string, variable *github.ActionsVariable) error {
+	_, err := c.rest.Actions.UpdateOrgVariable(ctx, org, variable)
+	if err != nil {
+		return fmt.Errorf("updating GitHub organization variable: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) UpdateEnvironmentActionsVariable(ctx context.Context, repoId int64, env string, variable *github.ActionsVariable) error {
+	_, err := c.rest.Actions.UpdateEnvVariable(ctx, int(repoId), env, variable)
+	if err != nil {
+		return fmt.Errorf("updating GitHub environment variable: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteRepositoryActionsVariable(ctx context.Context, owner, repo, name string) error {
+	_, err := c.rest.Actions.DeleteRepoVariable(ctx, owner, repo, name)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub repository variable: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteOrganizationActionsVariable(ctx context.Context, org, name string) error {
+	_, err := c.rest.Actions.DeleteOrgVariable(ctx, org, name)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub organization variable: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteEnvironmentActionsVariable(ctx context.Context, repoId int64, env, name string) error {
+	_, err := c.rest.Actions.DeleteEnvVariable(ctx, int(repoId), env, name)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub environment variable: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) GetOrganizationActionsVariableRepositories(ctx context.Context, org, name string) ([]*github.Repository, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Repository{}
+	for {
+		repos, resp, err := c.rest.Actions.ListSelectedReposForOrgVariable(ctx, org, name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub organization variable repositories: %w", err)
+		}
+		out = append(out, repos.Repositories...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
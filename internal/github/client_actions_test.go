@@ -0,0 +1,59 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/nacl/box"
+)
+
+var _ = Describe("EncryptActionsSecret", func() {
+	It("should seal the value so that only the holder of the private key can open it", func() {
+		publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		encrypted, err := EncryptActionsSecret(&github.PublicKey{
+			KeyID: github.String("key-id"),
+			Key:   github.String(base64.StdEncoding.EncodeToString(publicKey[:])),
+		}, "DEPLOY_TOKEN", []byte("hunter2"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(encrypted.Name).To(Equal("DEPLOY_TOKEN"))
+		Expect(encrypted.KeyID).To(Equal("key-id"))
+
+		sealed, err := base64.StdEncoding.DecodeString(encrypted.EncryptedValue)
+		Expect(err).NotTo(HaveOccurred())
+		opened, ok := box.OpenAnonymous(nil, sealed, publicKey, privateKey)
+		Expect(ok).To(BeTrue())
+		Expect(string(opened)).To(Equal("hunter2"))
+	})
+
+	It("should reject a public key that isn't base64", func() {
+		_, err := EncryptActionsSecret(&github.PublicKey{Key: github.String("not base64!")}, "DEPLOY_TOKEN", []byte("hunter2"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject a public key of the wrong length", func() {
+		short := base64.StdEncoding.EncodeToString([]byte("too short"))
+		_, err := EncryptActionsSecret(&github.PublicKey{Key: github.String(short)}, "DEPLOY_TOKEN", []byte("hunter2"))
+		Expect(err).To(MatchError(ContainSubstring("expected 32")))
+	})
+})
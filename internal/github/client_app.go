@@ -0,0 +1,45 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// Apps
+
+// ListInstallations lists the installations of the GitHub App the client authenticates as.
+// The client must use a transport from AppRoundTripper.
+func (c *Client) ListInstallations(ctx context.Context) ([]*github.Installation, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Installation{}
+	for {
+		installations, resp, err := c.rest.Apps.ListInstallations(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub App installations: %w", err)
+		}
+		out = append(out, installations...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
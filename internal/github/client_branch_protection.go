@@ -262,7 +262,9 @@ func (c *Client) GetBranchProtection(ctx context.Context, nodeId string) (*Branc
 	}
 
 	err := c.graphql.Query(ctx, &q, variables)
-	if err != nil {
+	if isNodeNotFoundError(err) {
+		return nil, &BranchProtectionNotFoundError{NodeId: &nodeId}
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -448,6 +450,47 @@ func (c *Client) GetBranchProtectionByOwnerRepoPattern(ctx context.Context, repo
 	return nil, fmt.Errorf("no branch protection rule with pattern '%s' found for repo '%s' with owner '%s'", pattern, repositoryName, repositoryOwner)
 }
 
+// returns every branch protection rule of a repository
+func (c *Client) GetRepositoryBranchProtections(ctx context.Context, repositoryOwner, repositoryName string) ([]*BranchProtection, error) {
+	var q struct {
+		Repository struct {
+			BranchProtectionRuleConnection struct {
+				Nodes []struct {
+					// Branch protection rule
+					Id string
+				}
+				PageInfo PageInfo
+			} `graphql:"branchProtectionRules(first: 100, after: $branchProtectionRuleCursor)"`
+		} `graphql:"repository(owner: $repositoryOwner, name: $repositoryName)"`
+	}
+
+	variables := map[string]interface{}{
+		"repositoryOwner":            githubv4.String(repositoryOwner),
+		"repositoryName":             githubv4.String(repositoryName),
+		"branchProtectionRuleCursor": (*githubv4.String)(nil),
+	}
+
+	out := []*BranchProtection{}
+	for {
+		err := c.graphql.Query(ctx, &q, variables)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range q.Repository.BranchProtectionRuleConnection.Nodes {
+			bp, err := c.GetBranchProtection(ctx, node.Id)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, bp)
+		}
+		if !q.Repository.BranchProtectionRuleConnection.PageInfo.HasNextPage {
+			break
+		}
+		variables["branchProtectionRuleCursor"] = q.Repository.BranchProtectionRuleConnection.PageInfo.EndCursor
+	}
+	return out, nil
+}
+
 func (c *Client) CreateBranchProtection(ctx context.Context, input *githubv4.CreateBranchProtectionRuleInput) (*BranchProtection, error) {
 	var m struct {
 		CreateBranchProtectionRule struct {
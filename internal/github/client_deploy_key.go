@@ -0,0 +1,67 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+)
+
+func (c *Client) GetDeployKey(ctx context.Context, owner, repo string, id int64) (*github.Key, error) {
+	key, resp, err := c.rest.Repositories.GetKey(ctx, owner, repo, id)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &DeployKeyNotFoundError{RepositoryOwner: &owner, RepositoryName: &repo, Id: &id}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub deploy key: %w", err)
+	}
+	return key, nil
+}
+
+func (c *Client) GetDeployKeys(ctx context.Context, owner, repo string) ([]*github.Key, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Key{}
+	for {
+		keys, resp, err := c.rest.Repositories.ListKeys(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub deploy keys: %w", err)
+		}
+		out = append(out, keys...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+func (c *Client) CreateDeployKey(ctx context.Context, owner, repo string, key *github.Key) (*github.Key, error) {
+	key, _, err := c.rest.Repositories.CreateKey(ctx, owner, repo, key)
+	if err != nil {
+		return nil, fmt.Errorf("creating GitHub deploy key: %w", err)
+	}
+	return key, nil
+}
+
+func (c *Client) DeleteDeployKey(ctx context.Context, owner, repo string, id int64) error {
+	_, err := c.rest.Repositories.DeleteKey(ctx, owner, repo, id)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub deploy key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// Environments
+
+func (c *Client) GetEnvironment(ctx context.Context, owner, repo, name string) (*github.Environment, error) {
+	environment, resp, err := c.rest.Repositories.GetEnvironment(ctx, owner, repo, name)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &EnvironmentNotFoundError{
+			RepositoryOwner: github.String(owner),
+			RepositoryName:  github.String(repo),
+			Name:            github.String(name),
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub environment: %w", err)
+	}
+	return environment, nil
+}
+
+// Creates the environment if it does not exist. Fields left nil in update are cleared.
+func (c *Client) CreateUpdateEnvironment(ctx context.Context, owner, repo, name string, update *github.CreateUpdateEnvironment) (*github.Environment, error) {
+	environment, _, err := c.rest.Repositories.CreateUpdateEnvironment(ctx, owner, repo, name, update)
+	if err != nil {
+		return nil, fmt.Errorf("updating GitHub environment: %w", err)
+	}
+	return environment, nil
+}
+
+func (c *Client) DeleteEnvironment(ctx context.Context, owner, repo, name string) error {
+	_, err := c.rest.Repositories.DeleteEnvironment(ctx, owner, repo, name)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub environment: %w", err)
+	}
+	return nil
+}
+
+// Deployment branch policies
+
+func (c *Client) GetDeploymentBranchPolicies(ctx context.Context, owner, repo, environment string) ([]*github.DeploymentBranchPolicy, error) {
+	policies, _, err := c.rest.Repositories.ListDeploymentBranchPolicies(ctx, owner, repo, environment)
+	if err != nil {
+		return nil, fmt.Errorf("listing GitHub deployment branch policies: %w", err)
+	}
+	return policies.BranchPolicies, nil
+}
+
+// policyType should be one of "branch" or "tag".
+func (c *Client) CreateDeploymentBranchPolicy(ctx context.Context, owner, repo, environment, pattern, policyType string) (*github.DeploymentBranchPolicy, error) {
+	policy, _, err := c.rest.Repositories.CreateDeploymentBranchPolicy(ctx, owner, repo, environment, &github.DeploymentBranchPolicyRequest{
+		Name: github.String(pattern),
+		Type: github.String(policyType),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating GitHub deployment branch policy: %w", err)
+	}
+	return policy, nil
+}
+
+func (c *Client) DeleteDeploymentBranchPolicy(ctx context.Context, owner, repo, environment string, id int64) error {
+	_, err := c.rest.Repositories.DeleteDeploymentBranchPolicy(ctx, owner, repo, environment, id)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub deployment branch policy: %w", err)
+	}
+	return nil
+}
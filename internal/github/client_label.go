@@ -0,0 +1,80 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// RepositoryLabelUpdate is the request body for updating a repository label.
+// go-github v60 sends the new name of a label as "name", so renames are sent
+// as "new_name" here.
+type RepositoryLabelUpdate struct {
+	NewName     *string `json:"new_name,omitempty"`
+	Color       *string `json:"color,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+func (c *Client) GetRepositoryLabels(ctx context.Context, owner, repo string) ([]*github.Label, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Label{}
+	for {
+		labels, resp, err := c.rest.Issues.ListLabels(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub repository labels: %w", err)
+		}
+		out = append(out, labels...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+func (c *Client) CreateRepositoryLabel(ctx context.Context, owner, repo string, label *github.Label) (*github.Label, error) {
+	label, _, err := c.rest.Issues.CreateLabel(ctx, owner, repo, label)
+	if err != nil {
+		return nil, fmt.Errorf("creating GitHub repository label: %w", err)
+	}
+	return label, nil
+}
+
+func (c *Client) UpdateRepositoryLabel(ctx context.Context, owner, repo, name string, update *RepositoryLabelUpdate) (*github.Label, error) {
+	req, err := c.rest.NewRequest("PATCH", fmt.Sprintf("repos/%v/%v/labels/%v", owner, repo, url.PathEscape(name)), update)
+	if err != nil {
+		return nil, err
+	}
+	label := &github.Label{}
+	_, err = c.rest.Do(ctx, req, label)
+	if err != nil {
+		return nil, fmt.Errorf("updating GitHub repository label: %w", err)
+	}
+	return label, nil
+}
+
+func (c *Client) DeleteRepositoryLabel(ctx context.Context, owner, repo, name string) error {
+	_, err := c.rest.Issues.DeleteLabel(ctx, owner, repo, url.PathEscape(name))
+	if err != nil {
+		return fmt.Errorf("deleting GitHub repository label: %w", err)
+	}
+	return nil
+}
@@ -18,8 +18,11 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/google/go-github/v60/github"
+	"github.com/shurcooL/githubv4"
 )
 
 func (c *Client) GetOrganization(ctx context.Context, login string) (*github.Organization, error) {
@@ -78,3 +81,126 @@ func (c *Client) DeleteOrganization(ctx context.Context, login string) error {
 	_, err := c.rest.Organizations.Delete(ctx, login)
 	return err
 }
+
+// Organization memberships
+
+type OrganizationMembership struct {
+	OrganizationLogin string
+	Login             string
+	UserId            string
+	Role              string
+}
+
+func (c *Client) GetOrganizationMemberships(ctx context.Context, login string) ([]*OrganizationMembership, error) {
+	var q struct {
+		Organization struct {
+			MembersWithRole struct {
+				Edges []struct {
+					Role string
+					Node struct {
+						Id    string
+						Login string
+					}
+				}
+				PageInfo PageInfo
+			} `graphql:"membersWithRole(first: 100, after: $cursor)"`
+		} `graphql:"organization(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(login),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	out := []*OrganizationMembership{}
+	for {
+		err := c.graphql.Query(ctx, &q, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, edge := range q.Organization.MembersWithRole.Edges {
+			out = append(out, &OrganizationMembership{
+				OrganizationLogin: login,
+				Login:             edge.Node.Login,
+				UserId:            edge.Node.Id,
+				// GraphQL returns MEMBER/ADMIN, REST expects member/admin
+				Role: strings.ToLower(edge.Role),
+			})
+		}
+
+		if !q.Organization.MembersWithRole.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(githubv4.String(q.Organization.MembersWithRole.PageInfo.EndCursor))
+	}
+
+	return out, nil
+}
+
+func (c *Client) GetOrganizationInvitations(ctx context.Context, login string) ([]*github.Invitation, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Invitation{}
+	for {
+		invitations, resp, err := c.rest.Organizations.ListPendingOrgInvitations(ctx, login, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub organization invitations: %w", err)
+		}
+		out = append(out, invitations...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+func (c *Client) GetOrganizationFailedInvitations(ctx context.Context, login string) ([]*github.Invitation, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Invitation{}
+	for {
+		invitations, resp, err := c.rest.Organizations.ListFailedOrgInvitations(ctx, login, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub organization failed invitations: %w", err)
+		}
+		out = append(out, invitations...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+// role should be one of "member" or "admin". If the user is not a member of
+// the organization, GitHub sends an invitation and the returned membership is pending.
+func (c *Client) UpdateOrganizationMembership(ctx context.Context, login, user, role string) (*github.Membership, error) {
+	membership, _, err := c.rest.Organizations.EditOrgMembership(ctx, user, login, &github.Membership{
+		Role: github.String(role),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("updating GitHub organization membership: %w", err)
+	}
+	return membership, nil
+}
+
+func (c *Client) RemoveOrganizationMembership(ctx context.Context, login, user string) error {
+	_, err := c.rest.Organizations.RemoveOrgMembership(ctx, user, login)
+	if err != nil {
+		return fmt.Errorf("removing GitHub organization membership: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) CancelOrganizationInvitation(ctx context.Context, login string, invitationId int64) error {
+	// not supported by go-github v60
+	req, err := c.rest.NewRequest("DELETE", fmt.Sprintf("orgs/%v/invitations/%v", login, invitationId), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.rest.Do(ctx, req, nil)
+	if err != nil {
+		return fmt.Errorf("cancelling GitHub organization invitation: %w", err)
+	}
+	return nil
+}
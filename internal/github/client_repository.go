@@ -18,6 +18,8 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/google/go-github/v60/github"
 )
@@ -66,12 +68,32 @@ func (c *Client) GetRepositoryByNodeId(ctx context.Context, nodeId string) (*git
 	}
 
 	err := c.graphql.Query(ctx, &q, variables)
-	if err != nil {
+	if isNodeNotFoundError(err) {
+		return nil, &RepositoryNotFoundError{NodeId: &nodeId}
+	} else if err != nil {
 		return nil, err
 	}
 	return c.GetRepositoryByDatabaseId(ctx, q.Node.Repository.DatabaseId)
 }
 
+// returns every repository owned by an organization
+func (c *Client) GetOrganizationRepositories(ctx context.Context, org string) ([]*github.Repository, error) {
+	opts := &github.RepositoryListByOrgOptions{Type: "all", ListOptions: github.ListOptions{PerPage: 100}}
+	out := []*github.Repository{}
+	for {
+		repos, resp, err := c.rest.Repositories.ListByOrg(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub organization repositories: %w", err)
+		}
+		out = append(out, repos...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
 func (c *Client) UpdateRepositoryByName(ctx context.Context, owner, name string, update *github.Repository) (*github.Repository, error) {
 	repo, _, err := c.rest.Repositories.Edit(ctx, owner, name, update)
 	if err != nil {
@@ -80,6 +102,26 @@ func (c *Client) UpdateRepositoryByName(ctx context.Context, owner, name string,
 	return repo, nil
 }
 
+// Transfers a repository to newOwner, renaming it to newName if it is non-empty. GitHub may
+// schedule the transfer in the background, in which case the repository is returned as accepted.
+func (c *Client) TransferRepository(ctx context.Context, owner, name, newOwner, newName string) (*github.Repository, error) {
+	req := github.TransferRequest{NewOwner: newOwner}
+	if newName != "" {
+		req.NewName = github.String(newName)
+	}
+	repo, _, err := c.rest.Repositories.Transfer(ctx, owner, name, req)
+	if accepted, ok := err.(*github.AcceptedError); ok {
+		repo = &github.Repository{}
+		if err := json.Unmarshal(accepted.Raw, repo); err != nil {
+			return nil, err
+		}
+		return repo, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
 // Pass empty string as org to create a user-owned repo
 func (c *Client) CreateRepository(ctx context.Context, org string, create *github.Repository) (*github.Repository, error) {
 	repo, _, err := c.rest.Repositories.Create(ctx, org, create)
@@ -110,3 +152,133 @@ func (c *Client) DeleteRepositoryByName(ctx context.Context, owner, name string)
 	_, err := c.rest.Repositories.Delete(ctx, owner, name)
 	return err
 }
+
+// Repository collaborators
+
+type RepositoryCollaborator struct {
+	RepositoryOwner string
+	RepositoryName  string
+	Login           string
+	Permission      string
+}
+
+type RepositoryCollaboratorInvitation struct {
+	RepositoryOwner string
+	RepositoryName  string
+	Id              int64
+	Login           string
+	Permission      string
+}
+
+// invitations report legacy permission names - for local use
+var invitationPermissions = map[string]string{
+	"read":  "pull",
+	"write": "push",
+}
+
+// affiliation should be one of "outside", "direct" or "all".
+func (c *Client) GetRepositoryCollaborators(ctx context.Context, owner, repo, affiliation string) ([]*RepositoryCollaborator, error) {
+	opts := &github.ListCollaboratorsOptions{
+		Affiliation: affiliation,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	out := []*RepositoryCollaborator{}
+	for {
+		users, resp, err := c.rest.Repositories.ListCollaborators(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub repository collaborators: %w", err)
+		}
+		for _, user := range users {
+			permission, err := maxPermissionFromMap(user.GetPermissions())
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, &RepositoryCollaborator{
+				RepositoryOwner: owner,
+				RepositoryName:  repo,
+				Login:           user.GetLogin(),
+				Permission:      permission,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+func (c *Client) GetRepositoryInvitations(ctx context.Context, owner, repo string) ([]*RepositoryCollaboratorInvitation, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*RepositoryCollaboratorInvitation{}
+	for {
+		invitations, resp, err := c.rest.Repositories.ListInvitations(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub repository invitations: %w", err)
+		}
+		for _, invitation := range invitations {
+			permission := invitation.GetPermissions()
+			if p, ok := invitationPermissions[permission]; ok {
+				permission = p
+			}
+			out = append(out, &RepositoryCollaboratorInvitation{
+				RepositoryOwner: owner,
+				RepositoryName:  repo,
+				Id:              invitation.GetID(),
+				Login:           invitation.GetInvitee().GetLogin(),
+				Permission:      permission,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+// Adds the user as a collaborator or updates their permission. Users that are
+// not organization members are sent an invitation, which is returned; otherwise
+// the returned invitation is nil.
+func (c *Client) UpdateRepositoryCollaborator(ctx context.Context, owner, repo, user, permission string) (*github.CollaboratorInvitation, error) {
+	invitation, resp, err := c.rest.Repositories.AddCollaborator(ctx, owner, repo, user, &github.RepositoryAddCollaboratorOptions{
+		Permission: permission,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("updating GitHub repository collaborator: %w", err)
+	}
+	// 204 No Content when the user was added directly or already a collaborator
+	if resp.StatusCode != 201 {
+		return nil, nil
+	}
+	return invitation, nil
+}
+
+func (c *Client) RemoveRepositoryCollaborator(ctx context.Context, owner, repo, user string) error {
+	_, err := c.rest.Repositories.RemoveCollaborator(ctx, owner, repo, user)
+	if err != nil {
+		return fmt.Errorf("removing GitHub repository collaborator: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) UpdateRepositoryInvitation(ctx context.Context, owner, repo string, invitationId int64, permission string) error {
+	for legacy, p := range invitationPermissions {
+		if p == permission {
+			permission = legacy
+		}
+	}
+	_, _, err := c.rest.Repositories.UpdateInvitation(ctx, owner, repo, invitationId, permission)
+	if err != nil {
+		return fmt.Errorf("updating GitHub repository invitation: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteRepositoryInvitation(ctx context.Context, owner, repo string, invitationId int64) error {
+	_, err := c.rest.Repositories.DeleteInvitation(ctx, owner, repo, invitationId)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub repository invitation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// Repository rulesets
+
+func (c *Client) GetRepositoryRuleset(ctx context.Context, owner, repo string, id int64) (*github.Ruleset, error) {
+	ruleset, resp, err := c.rest.Repositories.GetRuleset(ctx, owner, repo, id, false)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &RulesetNotFoundError{
+			Owner:          github.String(owner),
+			RepositoryName: github.String(repo),
+			Id:             github.Int64(id),
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub repository ruleset: %w", err)
+	}
+	return ruleset, nil
+}
+
+func (c *Client) GetRepositoryRulesetByName(ctx context.Context, owner, repo, name string) (*github.Ruleset, error) {
+	rulesets, _, err := c.rest.Repositories.GetAllRulesets(ctx, owner, repo, false)
+	if err != nil {
+		return nil, fmt.Errorf("listing GitHub repository rulesets: %w", err)
+	}
+	for _, ruleset := range rulesets {
+		if ruleset.Name == name {
+			// the list endpoint omits conditions and rules
+			return c.GetRepositoryRuleset(ctx, owner, repo, ruleset.GetID())
+		}
+	}
+	return nil, &RulesetNotFoundError{
+		Owner:          github.String(owner),
+		RepositoryName: github.String(repo),
+		Name:           github.String(name),
+	}
+}
+
+func (c *Client) CreateRepositoryRuleset(ctx context.Context, owner, repo string, ruleset *github.Ruleset) (*github.Ruleset, error) {
+	created, _, err := c.rest.Repositories.CreateRuleset(ctx, owner, repo, ruleset)
+	if err != nil {
+		return nil, fmt.Errorf("creating GitHub repository ruleset: %w", err)
+	}
+	return created, nil
+}
+
+func (c *Client) UpdateRepositoryRuleset(ctx context.Context, owner, repo string, id int64, ruleset *github.Ruleset) (*github.Ruleset, error) {
+	updated, _, err := c.rest.Repositories.UpdateRuleset(ctx, owner, repo, id, ruleset)
+	if err != nil {
+		return nil, fmt.Errorf("updating GitHub repository ruleset: %w", err)
+	}
+	return updated, nil
+}
+
+func (c *Client) DeleteRepositoryRuleset(ctx context.Context, owner, repo string, id int64) error {
+	_, err := c.rest.Repositories.DeleteRuleset(ctx, owner, repo, id)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub repository ruleset: %w", err)
+	}
+	return nil
+}
+
+// Organization rulesets
+
+func (c *Client) GetOrganizationRuleset(ctx context.Context, org string, id int64) (*github.Ruleset, error) {
+	ruleset, resp, err := c.rest.Organizations.GetOrganizationRuleset(ctx, org, id)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &RulesetNotFoundError{
+			Owner: github.String(org),
+			Id:    github.Int64(id),
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub organization ruleset: %w", err)
+	}
+	return ruleset, nil
+}
+
+func (c *Client) GetOrganizationRulesetByName(ctx context.Context, org, name string) (*github.Ruleset, error) {
+	rulesets, _, err := c.rest.Organizations.GetAllOrganizationRulesets(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("listing GitHub organization rulesets: %w", err)
+	}
+	for _, ruleset := range rulesets {
+		if ruleset.Name == name {
+			// the list endpoint omits conditions and rules
+			return c.GetOrganizationRuleset(ctx, org, ruleset.GetID())
+		}
+	}
+	return nil, &RulesetNotFoundError{
+		Owner: github.String(org),
+		Name:  github.String(name),
+	}
+}
+
+func (c *Client) CreateOrganizationRuleset(ctx context.Context, org string, ruleset *github.Ruleset) (*github.Ruleset, error) {
+	created, _, err := c.rest.Organizations.CreateOrganizationRuleset(ctx, org, ruleset)
+	if err != nil {
+		return nil, fmt.Errorf("creating GitHub organization ruleset: %w", err)
+	}
+	return created, nil
+}
+
+func (c *Client) UpdateOrganizationRuleset(ctx context.Context, org string, id int64, ruleset *github.Ruleset) (*github.Ruleset, error) {
+	updated, _, err := c.rest.Organizations.UpdateOrganizationRuleset(ctx, org, id, ruleset)
+	if err != nil {
+		return nil, fmt.Errorf("updating GitHub organization ruleset: %w", err)
+	}
+	return updated, nil
+}
+
+func (c *Client) DeleteOrganizationRuleset(ctx context.Context, org string, id int64) error {
+	_, err := c.rest.Organizations.DeleteOrganizationRuleset(ctx, org, id)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub organization ruleset: %w", err)
+	}
+	return nil
+}
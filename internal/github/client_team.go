@@ -19,6 +19,7 @@ package github
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/go-github/v60/github"
 	"github.com/shurcooL/githubv4"
@@ -78,6 +79,24 @@ func (c *Client) GetTeamByNodeId(ctx context.Context, nodeId string) (*github.Te
 	return c.GetTeamById(ctx, q.Node.Team.Organization.DatabaseId, q.Node.Team.DatabaseId)
 }
 
+// returns every team in an organization, including child teams
+func (c *Client) GetOrganizationTeams(ctx context.Context, org string) ([]*github.Team, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Team{}
+	for {
+		teams, resp, err := c.rest.Teams.ListTeams(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub organization teams: %w", err)
+		}
+		out = append(out, teams...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
 func (c *Client) CreateTeam(ctx context.Context, org string, newTeam github.NewTeam) (*github.Team, error) {
 	team, _, err := c.rest.Teams.CreateTeam(ctx, org, newTeam)
 	if err != nil {
@@ -226,3 +245,112 @@ func (c *Client) RemoveTeamRepositoryPermissions(ctx context.Context, org, slug
 	_, err := c.rest.Teams.RemoveTeamRepoBySlug(ctx, org, slug, org, repoName)
 	return err
 }
+
+// Team memberships
+
+type TeamMembership struct {
+	OrganizationLogin string
+	TeamSlug          string
+	Login             string
+	UserId            string
+	Role              string
+}
+
+// only returns direct members of the team; members inherited from child teams are omitted
+func (c *Client) GetTeamMemberships(ctx context.Context, org, slug string) ([]*TeamMembership, error) {
+	var q struct {
+		Organization struct {
+			Team struct {
+				Members struct {
+					Edges []struct {
+						Role string
+						Node struct {
+							Id    string
+							Login string
+						}
+					}
+					PageInfo PageInfo
+				} `graphql:"members(first: 100, after: $cursor, membership: IMMEDIATE)"`
+			} `graphql:"team(slug: $slug)"`
+		} `graphql:"organization(login: $login)"`
+	}
+
+	variables := map[string]interface{}{
+		"login":  githubv4.String(org),
+		"slug":   githubv4.String(slug),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	out := []*TeamMembership{}
+	for {
+		err := c.graphql.Query(ctx, &q, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, edge := range q.Organization.Team.Members.Edges {
+			out = append(out, &TeamMembership{
+				OrganizationLogin: org,
+				TeamSlug:          slug,
+				Login:             edge.Node.Login,
+				UserId:            edge.Node.Id,
+				// GraphQL returns MEMBER/MAINTAINER, REST expects member/maintainer
+				Role: strings.ToLower(edge.Role),
+			})
+		}
+
+		if !q.Organization.Team.Members.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(githubv4.String(q.Organization.Team.Members.PageInfo.EndCursor))
+	}
+
+	return out, nil
+}
+
+func (c *Client) GetTeamPendingInvitations(ctx context.Context, org, slug string) ([]*github.Invitation, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Invitation{}
+	for {
+		invitations, resp, err := c.rest.Teams.ListPendingTeamInvitationsBySlug(ctx, org, slug, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub team invitations: %w", err)
+		}
+		out = append(out, invitations...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+// returns the membership of a user in a team, which is pending if the user hasn't accepted the
+// invitation to the team yet. Unlike team invitations, the membership has the user's team role.
+func (c *Client) GetTeamMembership(ctx context.Context, org, slug, login string) (*github.Membership, error) {
+	membership, _, err := c.rest.Teams.GetTeamMembershipBySlug(ctx, org, slug, login)
+	if err != nil {
+		return nil, fmt.Errorf("getting GitHub team membership: %w", err)
+	}
+	return membership, nil
+}
+
+// role should be one of "member" or "maintainer". If the user is not a member of
+// the organization, GitHub sends an invitation and the returned membership is pending.
+func (c *Client) UpdateTeamMembership(ctx context.Context, org, slug, login, role string) (*github.Membership, error) {
+	membership, _, err := c.rest.Teams.AddTeamMembershipBySlug(ctx, org, slug, login, &github.TeamAddTeamMembershipOptions{
+		Role: role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("updating GitHub team membership: %w", err)
+	}
+	return membership, nil
+}
+
+func (c *Client) RemoveTeamMembership(ctx context.Context, org, slug, login string) error {
+	_, err := c.rest.Teams.RemoveTeamMembershipBySlug(ctx, org, slug, login)
+	if err != nil {
+		return fmt.Errorf("removing GitHub team membership: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// Users
+
+func (c *Client) GetUser(ctx context.Context, login string) (*github.User, error) {
+	user, resp, err := c.rest.Users.Get(ctx, login)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &UserNotFoundError{Login: &login}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub user: %w", err)
+	}
+	return user, nil
+}
+
+// returns the login of the user, or the bot of a GitHub App installation, that the client authenticates as
+func (c *Client) GetViewerLogin(ctx context.Context) (string, error) {
+	var q struct {
+		Viewer struct {
+			Login string
+		}
+	}
+	if err := c.graphql.Query(ctx, &q, nil); err != nil {
+		return "", fmt.Errorf("getting GitHub viewer: %w", err)
+	}
+	return q.Viewer.Login, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetViewerLogin", func() {
+	var server *httptest.Server
+
+	// newClient returns a client of a GitHub Enterprise Server instance answering GraphQL queries with response
+	newClient := func(response string) *Client {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/api/graphql" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, response)
+		}))
+		client, err := NewClient(WithRoundTripper(http.DefaultTransport), WithEnterpriseURLs(server.URL, "", ""))
+		Expect(err).NotTo(HaveOccurred())
+		return client
+	}
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should return the login the client authenticates as", func() {
+		client := newClient(`{"data":{"viewer":{"login":"github-operator[bot]"}}}`)
+		login, err := client.GetViewerLogin(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(login).To(Equal("github-operator[bot]"))
+	})
+
+	It("should return GraphQL errors", func() {
+		client := newClient(`{"data":null,"errors":[{"message":"Bad credentials"}]}`)
+		_, err := client.GetViewerLogin(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("getting GitHub viewer: Bad credentials")))
+	})
+})
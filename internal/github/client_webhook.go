@@ -0,0 +1,156 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v60/github"
+)
+
+// Repository webhooks
+
+func (c *Client) GetRepositoryWebhook(ctx context.Context, owner, repo string, id int64) (*github.Hook, error) {
+	hook, resp, err := c.rest.Repositories.GetHook(ctx, owner, repo, id)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &WebhookNotFoundError{Owner: &owner, RepositoryName: &repo, Id: &id}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub repository webhook: %w", err)
+	}
+	return hook, nil
+}
+
+func (c *Client) GetRepositoryWebhooks(ctx context.Context, owner, repo string) ([]*github.Hook, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Hook{}
+	for {
+		hooks, resp, err := c.rest.Repositories.ListHooks(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub repository webhooks: %w", err)
+		}
+		out = append(out, hooks...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+func (c *Client) CreateRepositoryWebhook(ctx context.Context, owner, repo string, hook *github.Hook) (*github.Hook, error) {
+	hook, _, err := c.rest.Repositories.CreateHook(ctx, owner, repo, hook)
+	if err != nil {
+		return nil, fmt.Errorf("creating GitHub repository webhook: %w", err)
+	}
+	return hook, nil
+}
+
+func (c *Client) UpdateRepositoryWebhook(ctx context.Context, owner, repo string, id int64, hook *github.Hook) (*github.Hook, error) {
+	hook, _, err := c.rest.Repositories.EditHook(ctx, owner, repo, id, hook)
+	if err != nil {
+		return nil, fmt.Errorf("updating GitHub repository webhook: %w", err)
+	}
+	return hook, nil
+}
+
+func (c *Client) DeleteRepositoryWebhook(ctx context.Context, owner, repo string, id int64) error {
+	_, err := c.rest.Repositories.DeleteHook(ctx, owner, repo, id)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub repository webhook: %w", err)
+	}
+	return nil
+}
+
+// GetRepositoryWebhookLastDelivery returns the most recent delivery of a repository webhook,
+// or nil if the webhook has never been delivered.
+func (c *Client) GetRepositoryWebhookLastDelivery(ctx context.Context, owner, repo string, id int64) (*github.HookDelivery, error) {
+	deliveries, _, err := c.rest.Repositories.ListHookDeliveries(ctx, owner, repo, id, &github.ListCursorOptions{PerPage: 1})
+	if err != nil {
+		return nil, fmt.Errorf("listing GitHub repository webhook deliveries: %w", err)
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+	return deliveries[0], nil
+}
+
+// Organization webhooks
+
+func (c *Client) GetOrganizationWebhook(ctx context.Context, org string, id int64) (*github.Hook, error) {
+	hook, resp, err := c.rest.Organizations.GetHook(ctx, org, id)
+	if resp != nil && resp.StatusCode == 404 {
+		return nil, &WebhookNotFoundError{Owner: &org, Id: &id}
+	} else if err != nil {
+		return nil, fmt.Errorf("getting GitHub organization webhook: %w", err)
+	}
+	return hook, nil
+}
+
+func (c *Client) GetOrganizationWebhooks(ctx context.Context, org string) ([]*github.Hook, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	out := []*github.Hook{}
+	for {
+		hooks, resp, err := c.rest.Organizations.ListHooks(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing GitHub organization webhooks: %w", err)
+		}
+		out = append(out, hooks...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return out, nil
+}
+
+func (c *Client) CreateOrganizationWebhook(ctx context.Context, org string, hook *github.Hook) (*github.Hook, error) {
+	hook, _, err := c.rest.Organizations.CreateHook(ctx, org, hook)
+	if err != nil {
+		return nil, fmt.Errorf("creating GitHub organization webhook: %w", err)
+	}
+	return hook, nil
+}
+
+func (c *Client) UpdateOrganizationWebhook(ctx context.Context, org string, id int64, hook *github.Hook) (*github.Hook, error) {
+	hook, _, err := c.rest.Organizations.EditHook(ctx, org, id, hook)
+	if err != nil {
+		return nil, fmt.Errorf("updating GitHub organization webhook: %w", err)
+	}
+	return hook, nil
+}
+
+func (c *Client) DeleteOrganizationWebhook(ctx context.Context, org string, id int64) error {
+	_, err := c.rest.Organizations.DeleteHook(ctx, org, id)
+	if err != nil {
+		return fmt.Errorf("deleting GitHub organization webhook: %w", err)
+	}
+	return nil
+}
+
+// GetOrganizationWebhookLastDelivery returns the most recent delivery of an organization webhook,
+// or nil if the webhook has never been delivered.
+func (c *Client) GetOrganizationWebhookLastDelivery(ctx context.Context, org string, id int64) (*github.HookDelivery, error) {
+	deliveries, _, err := c.rest.Organizations.ListHookDeliveries(ctx, org, id, &github.ListCursorOptions{PerPage: 1})
+	if err != nil {
+		return nil, fmt.Errorf("listing GitHub organization webhook deliveries: %w", err)
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+	return deliveries[0], nil
+}
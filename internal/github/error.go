@@ -16,7 +16,16 @@ limitations under the License.
 
 package github
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// isNodeNotFoundError returns true if err is the GraphQL error GitHub returns when
+// a node ID doesn't resolve to an object, e.g. because it was deleted.
+func isNodeNotFoundError(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "Could not resolve to a node")
+}
 
 type TeamNotFoundError struct {
 	OrgSlug  *string
@@ -55,6 +64,7 @@ type RepositoryNotFoundError struct {
 	OwnerId    *int64
 	Slug       *string
 	Id         *int64
+	NodeId     *string
 }
 
 func (e *RepositoryNotFoundError) Error() string {
@@ -62,7 +72,145 @@ func (e *RepositoryNotFoundError) Error() string {
 		return fmt.Sprintf("repository '%s' not found for owner '%s'", *e.Slug, *e.OwnerLogin)
 	} else if e.OwnerId != nil && e.Id != nil {
 		return fmt.Sprintf("repository %d not found for owner %d", *e.Id, *e.OwnerId)
+	} else if e.NodeId != nil {
+		return fmt.Sprintf("repository '%s' not found", *e.NodeId)
 	} else {
 		return "repository not found"
 	}
 }
+
+type BranchProtectionNotFoundError struct {
+	NodeId *string
+}
+
+func (e *BranchProtectionNotFoundError) Error() string {
+	if e.NodeId != nil {
+		return fmt.Sprintf("branch protection rule '%s' not found", *e.NodeId)
+	}
+	return "branch protection rule not found"
+}
+
+type RulesetNotFoundError struct {
+	Owner          *string
+	RepositoryName *string
+	Name           *string
+	Id             *int64
+}
+
+func (e *RulesetNotFoundError) Error() string {
+	var scope string
+	if e.Owner != nil && e.RepositoryName != nil {
+		scope = fmt.Sprintf(" for repository '%s' with owner '%s'", *e.RepositoryName, *e.Owner)
+	} else if e.Owner != nil {
+		scope = fmt.Sprintf(" for organization '%s'", *e.Owner)
+	}
+	if e.Name != nil {
+		return fmt.Sprintf("ruleset '%s' not found%s", *e.Name, scope)
+	} else if e.Id != nil {
+		return fmt.Sprintf("ruleset %d not found%s", *e.Id, scope)
+	} else {
+		return "ruleset not found" + scope
+	}
+}
+
+type EnvironmentNotFoundError struct {
+	RepositoryOwner *string
+	RepositoryName  *string
+	Name            *string
+}
+
+func (e *EnvironmentNotFoundError) Error() string {
+	if e.RepositoryOwner != nil && e.RepositoryName != nil && e.Name != nil {
+		return fmt.Sprintf("environment '%s' not found for repository '%s' with owner '%s'", *e.Name, *e.RepositoryName, *e.RepositoryOwner)
+	} else {
+		return "environment not found"
+	}
+}
+
+type UserNotFoundError struct {
+	Login *string
+}
+
+func (e *UserNotFoundError) Error() string {
+	if e.Login != nil {
+		return fmt.Sprintf("user '%s' not found", *e.Login)
+	} else {
+		return "user not found"
+	}
+}
+
+type ActionsSecretNotFoundError struct {
+	Owner           *string
+	RepositoryName  *string
+	EnvironmentName *string
+	Name            *string
+}
+
+func (e *ActionsSecretNotFoundError) Error() string {
+	if e.Name == nil {
+		return "actions secret not found"
+	} else if e.EnvironmentName != nil {
+		return fmt.Sprintf("actions secret '%s' not found for environment '%s'", *e.Name, *e.EnvironmentName)
+	} else if e.Owner != nil && e.RepositoryName != nil {
+		return fmt.Sprintf("actions secret '%s' not found for repository '%s' with owner '%s'", *e.Name, *e.RepositoryName, *e.Owner)
+	} else if e.Owner != nil {
+		return fmt.Sprintf("actions secret '%s' not found for organization '%s'", *e.Name, *e.Owner)
+	} else {
+		return fmt.Sprintf("actions secret '%s' not found", *e.Name)
+	}
+}
+
+type ActionsVariableNotFoundError struct {
+	Owner           *string
+	RepositoryName  *string
+	EnvironmentName *string
+	Name            *string
+}
+
+func (e *ActionsVariableNotFoundError) Error() string {
+	if e.Name == nil {
+		return "actions variable not found"
+	} else if e.EnvironmentName != nil {
+		return fmt.Sprintf("actions variable '%s' not found for environment '%s'", *e.Name, *e.EnvironmentName)
+	} else if e.Owner != nil && e.RepositoryName != nil {
+		return fmt.Sprintf("actions variable '%s' not found for repository '%s' with owner '%s'", *e.Name, *e.RepositoryName, *e.Owner)
+	} else if e.Owner != nil {
+		return fmt.Sprintf("actions variable '%s' not found for organization '%s'", *e.Name, *e.Owner)
+	} else {
+		return fmt.Sprintf("actions variable '%s' not found", *e.Name)
+	}
+}
+
+type WebhookNotFoundError struct {
+	Owner          *string
+	RepositoryName *string
+	Id             *int64
+}
+
+func (e *WebhookNotFoundError) Error() string {
+	if e.Id == nil {
+		return "webhook not found"
+	} else if e.Owner != nil && e.RepositoryName != nil {
+		return fmt.Sprintf("webhook with id '%d' not found for repository '%s' with owner '%s'", *e.Id, *e.RepositoryName, *e.Owner)
+	} else if e.Owner != nil {
+		return fmt.Sprintf("webhook with id '%d' not found for organization '%s'", *e.Id, *e.Owner)
+	} else {
+		return fmt.Sprintf("webhook with id '%d' not found", *e.Id)
+	}
+}
+
+type DeployKeyNotFoundError struct {
+	RepositoryOwner *string
+	RepositoryName  *string
+	Id              *int64
+}
+
+func (e *DeployKeyNotFoundError) Error() string {
+	if e.Id == nil {
+		return "deploy key not found"
+	} else if e.RepositoryOwner != nil && e.RepositoryName != nil {
+		return fmt.Sprintf("deploy key with id '%d' not found for repository '%s' with owner '%s'", *e.Id, *e.RepositoryName, *e.RepositoryOwner)
+	} else {
+		return fmt.Sprintf("deploy key with id '%d' not found", *e.Id)
+	}
+}
@@ -0,0 +1,450 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The GraphQL endpoint executes the queries built by githubv4 against views of the in-memory
+// store. Objects are maps from field name to value, where fields that take arguments are resolvers.
+
+type object = map[string]any
+
+type resolver func(args map[string]any) (any, error)
+
+// graphqlError is an error in the "errors" array of a GraphQL response
+type graphqlError struct {
+	Type    string   `json:"type,omitempty"`
+	Path    []string `json:"path,omitempty"`
+	Message string   `json:"message"`
+}
+
+func (e *graphqlError) Error() string {
+	return e.Message
+}
+
+func errorf(format string, args ...any) *graphqlError {
+	return &graphqlError{Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(message string) *graphqlError {
+	return &graphqlError{Type: "NOT_FOUND", Message: message}
+}
+
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	op, err := parseQuery(req.Query)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{"errors": []*graphqlError{{Message: err.Error()}}})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	root := s.queryRoot()
+	if op.mutation {
+		root = s.mutationRoot()
+	}
+	e := &executor{variables: req.Variables}
+	data := e.selectFields(root, op.selections, nil)
+	resp := map[string]any{"data": data}
+	if len(e.errors) > 0 {
+		resp["errors"] = e.errors
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Parsing
+
+type operation struct {
+	mutation   bool
+	selections []selection
+}
+
+// selection is either a field or an inline fragment on a type
+type selection struct {
+	alias      string
+	name       string
+	args       map[string]any
+	onType     string
+	selections []selection
+}
+
+// variable is a reference to a query variable in an argument
+type variable string
+
+type parser struct {
+	src string
+	pos int
+}
+
+func parseQuery(src string) (*operation, error) {
+	p := &parser{src: src}
+	op := &operation{}
+	switch p.peekName() {
+	case "mutation":
+		op.mutation = true
+		p.name()
+	case "query":
+		p.name()
+	}
+	if p.peekName() != "" {
+		p.name()
+	}
+	if p.peek() == '(' {
+		// variable types aren't checked
+		depth := 0
+		for p.pos < len(p.src) {
+			c := p.src[p.pos]
+			p.pos++
+			if c == '(' {
+				depth++
+			} else if c == ')' {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+	}
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+	return op, nil
+}
+
+// skip skips whitespace and commas, which are insignificant in GraphQL
+func (p *parser) skip() {
+	for p.pos < len(p.src) && (unicode.IsSpace(rune(p.src[p.pos])) || p.src[p.pos] == ',') {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() byte {
+	p.skip()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) expect(c byte) error {
+	if p.peek() != c {
+		return fmt.Errorf("parse error: expected '%c' at position %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func isNameChar(c byte, first bool) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || (!first && '0' <= c && c <= '9')
+}
+
+func (p *parser) peekName() string {
+	p.skip()
+	end := p.pos
+	for end < len(p.src) && isNameChar(p.src[end], end == p.pos) {
+		end++
+	}
+	return p.src[p.pos:end]
+}
+
+func (p *parser) name() string {
+	name := p.peekName()
+	p.pos += len(name)
+	return name
+}
+
+func (p *parser) selectionSet() ([]selection, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	selections := []selection{}
+	for p.peek() != '}' {
+		if p.pos >= len(p.src) {
+			return nil, fmt.Errorf("parse error: unterminated selection set")
+		}
+		sel := selection{}
+		if strings.HasPrefix(p.src[p.pos:], "...") {
+			p.pos += 3
+			if p.name() != "on" {
+				return nil, fmt.Errorf("parse error: only inline fragments are supported")
+			}
+			sel.onType = p.name()
+		} else {
+			sel.name = p.name()
+			if sel.name == "" {
+				return nil, fmt.Errorf("parse error: expected field at position %d", p.pos)
+			}
+			sel.alias = sel.name
+			if p.peek() == ':' {
+				p.pos++
+				sel.name = p.name()
+			}
+			if p.peek() == '(' {
+				args, err := p.arguments()
+				if err != nil {
+					return nil, err
+				}
+				sel.args = args
+			}
+		}
+		if p.peek() == '{' {
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			sel.selections = selections
+		}
+		selections = append(selections, sel)
+	}
+	p.pos++
+	return selections, nil
+}
+
+func (p *parser) arguments() (map[string]any, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	args := map[string]any{}
+	for p.peek() != ')' {
+		name := p.name()
+		if name == "" {
+			return nil, fmt.Errorf("parse error: expected argument at position %d", p.pos)
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = value
+	}
+	p.pos++
+	return args, nil
+}
+
+func (p *parser) value() (any, error) {
+	switch c := p.peek(); {
+	case c == '$':
+		p.pos++
+		return variable(p.name()), nil
+	case c == '"':
+		end := p.pos + 1
+		for end < len(p.src) && p.src[end] != '"' {
+			if p.src[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		s, err := strconv.Unquote(p.src[p.pos : end+1])
+		p.pos = end + 1
+		return s, err
+	case c == '-' || ('0' <= c && c <= '9'):
+		end := p.pos + 1
+		for end < len(p.src) && strings.ContainsRune("0123456789.eE-", rune(p.src[end])) {
+			end++
+		}
+		n, err := strconv.ParseFloat(p.src[p.pos:end], 64)
+		p.pos = end
+		return n, err
+	case c == '[':
+		p.pos++
+		list := []any{}
+		for p.peek() != ']' {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		p.pos++
+		return list, nil
+	case c == '{':
+		return p.arguments()
+	default:
+		switch name := p.name(); name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		case "":
+			return nil, fmt.Errorf("parse error: unexpected '%c' at position %d", c, p.pos)
+		default:
+			// enum value
+			return name, nil
+		}
+	}
+}
+
+// Execution
+
+type executor struct {
+	variables map[string]any
+	errors    []*graphqlError
+}
+
+func (e *executor) resolveArg(v any) any {
+	switch v := v.(type) {
+	case variable:
+		return e.variables[string(v)]
+	case []any:
+		out := []any{}
+		for _, item := range v {
+			out = append(out, e.resolveArg(item))
+		}
+		return out
+	case map[string]any:
+		out := map[string]any{}
+		for k, item := range v {
+			out[k] = e.resolveArg(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// selectFields executes a selection set against a value, which is an object, a list or a scalar
+func (e *executor) selectFields(v any, selections []selection, path []string) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []object:
+		out := []any{}
+		for _, item := range v {
+			out = append(out, e.selectFields(item, selections, path))
+		}
+		return out
+	case object:
+		out := map[string]any{}
+		e.selectInto(out, v, selections, path)
+		return out
+	default:
+		return v
+	}
+}
+
+func (e *executor) selectInto(out map[string]any, obj object, selections []selection, path []string) {
+	for _, sel := range selections {
+		if sel.onType != "" {
+			if obj["__typename"] == sel.onType {
+				e.selectInto(out, obj, sel.selections, path)
+			}
+			continue
+		}
+		fieldPath := append(append([]string{}, path...), sel.alias)
+		value := obj[sel.name]
+		if r, ok := value.(resolver); ok {
+			args := map[string]any{}
+			for k, v := range sel.args {
+				args[k] = e.resolveArg(v)
+			}
+			resolved, err := r(args)
+			if err != nil {
+				gqlErr, ok := err.(*graphqlError)
+				if !ok {
+					gqlErr = &graphqlError{Message: err.Error()}
+				}
+				gqlErr.Path = fieldPath
+				e.errors = append(e.errors, gqlErr)
+				out[sel.alias] = nil
+				continue
+			}
+			value = resolved
+		}
+		out[sel.alias] = e.selectFields(value, sel.selections, fieldPath)
+	}
+}
+
+func cursor(i int) string {
+	return base64.StdEncoding.EncodeToString([]byte("cursor:" + strconv.Itoa(i)))
+}
+
+func cursorIndex(c string) (int, error) {
+	b, err := base64.StdEncoding.DecodeString(c)
+	if err != nil || !strings.HasPrefix(string(b), "cursor:") {
+		return 0, errorf("`%s` does not appear to be a valid cursor.", c)
+	}
+	return strconv.Atoi(strings.TrimPrefix(string(b), "cursor:"))
+}
+
+// connection pages through nodes with the first and after arguments. edge returns the fields
+// of the edge to a node other than node and cursor.
+func connection(nodes []object, edge func(i int) object) resolver {
+	return func(args map[string]any) (any, error) {
+		start := 0
+		if after, ok := args["after"].(string); ok && after != "" {
+			i, err := cursorIndex(after)
+			if err != nil {
+				return nil, err
+			}
+			start = i + 1
+		}
+		first := 100
+		if f, ok := args["first"].(float64); ok {
+			first = int(f)
+		}
+		if first < 1 || first > 100 {
+			return nil, errorf("Requesting %d records on the connection exceeds the `first` limit of 100 records.", first)
+		}
+		start = min(start, len(nodes))
+		end := min(start+first, len(nodes))
+		edges := []object{}
+		for i := start; i < end; i++ {
+			e := object{"node": nodes[i], "cursor": cursor(i)}
+			if edge != nil {
+				for k, v := range edge(i) {
+					e[k] = v
+				}
+			}
+			edges = append(edges, e)
+		}
+		var endCursor any
+		if end > start {
+			endCursor = cursor(end - 1)
+		}
+		return object{
+			"nodes":      nodes[start:end],
+			"edges":      edges,
+			"totalCount": len(nodes),
+			"pageInfo": object{
+				"endCursor":   endCursor,
+				"hasNextPage": end < len(nodes),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,2119 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"golang.org/x/crypto/nacl/box"
+
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+const restPrefix = "/api/v3"
+
+func (s *Server) registerRESTHandlers(mux *http.ServeMux) {
+	handle := func(pattern string, h func(w http.ResponseWriter, r *http.Request)) {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.HandleFunc(method+" "+restPrefix+path, func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			h(w, r)
+		})
+	}
+
+	// organizations
+	handle("GET /orgs/{org}", s.getOrganization)
+	handle("PATCH /orgs/{org}", s.editOrganization)
+	handle("DELETE /orgs/{org}", s.deleteOrganization)
+	handle("GET /organizations/{orgId}", s.getOrganizationById)
+	handle("PUT /orgs/{org}/memberships/{user}", s.editOrganizationMembership)
+	handle("DELETE /orgs/{org}/memberships/{user}", s.removeOrganizationMembership)
+	handle("GET /orgs/{org}/invitations", s.listOrganizationInvitations)
+	handle("DELETE /orgs/{org}/invitations/{invitationId}", s.cancelOrganizationInvitation)
+	handle("GET /orgs/{org}/failed_invitations", s.listOrganizationFailedInvitations)
+	handle("GET /users/{user}", s.getUser)
+
+	// teams
+	handle("GET /orgs/{org}/teams", s.listTeams)
+	handle("POST /orgs/{org}/teams", s.createTeam)
+	handle("GET /orgs/{org}/teams/{slug}", s.getTeam)
+	handle("PATCH /orgs/{org}/teams/{slug}", s.editTeam)
+	handle("DELETE /orgs/{org}/teams/{slug}", s.deleteTeam)
+	handle("GET /organizations/{orgId}/team/{teamId}", s.getTeam)
+	handle("PATCH /organizations/{orgId}/team/{teamId}", s.editTeam)
+	handle("DELETE /organizations/{orgId}/team/{teamId}", s.deleteTeam)
+	handle("GET /orgs/{org}/teams/{slug}/repos/{owner}/{repo}", s.getTeamRepository)
+	handle("PUT /orgs/{org}/teams/{slug}/repos/{owner}/{repo}", s.addTeamRepository)
+	handle("DELETE /orgs/{org}/teams/{slug}/repos/{owner}/{repo}", s.removeTeamRepository)
+	handle("GET /orgs/{org}/teams/{slug}/memberships/{user}", s.getTeamMembership)
+	handle("PUT /orgs/{org}/teams/{slug}/memberships/{user}", s.addTeamMembership)
+	handle("DELETE /orgs/{org}/teams/{slug}/memberships/{user}", s.removeTeamMembership)
+	handle("GET /orgs/{org}/teams/{slug}/invitations", s.listTeamInvitations)
+
+	// repositories
+	handle("GET /orgs/{org}/repos", s.listRepositories)
+	handle("POST /orgs/{org}/repos", s.createRepository)
+	handle("GET /repos/{owner}/{repo}", s.getRepository)
+	handle("PATCH /repos/{owner}/{repo}", s.editRepository)
+	handle("DELETE /repos/{owner}/{repo}", s.deleteRepository)
+	handle("GET /repositories/{repoId}", s.getRepository)
+	handle("POST /repos/{owner}/{repo}/generate", s.createRepositoryFromTemplate)
+	handle("PUT /repos/{owner}/{repo}/topics", s.replaceTopics)
+	handle("POST /repos/{owner}/{repo}/transfer", s.transferRepository)
+
+	// collaborators
+	handle("GET /repos/{owner}/{repo}/collaborators", s.listCollaborators)
+	handle("PUT /repos/{owner}/{repo}/collaborators/{user}", s.addCollaborator)
+	handle("DELETE /repos/{owner}/{repo}/collaborators/{user}", s.removeCollaborator)
+	handle("GET /repos/{owner}/{repo}/invitations", s.listRepositoryInvitations)
+	handle("PATCH /repos/{owner}/{repo}/invitations/{invitationId}", s.updateRepositoryInvitation)
+	handle("DELETE /repos/{owner}/{repo}/invitations/{invitationId}", s.deleteRepositoryInvitation)
+
+	// labels
+	handle("GET /repos/{owner}/{repo}/labels", s.listLabels)
+	handle("POST /repos/{owner}/{repo}/labels", s.createLabel)
+	handle("PATCH /repos/{owner}/{repo}/labels/{name}", s.updateLabel)
+	handle("DELETE /repos/{owner}/{repo}/labels/{name}", s.deleteLabel)
+
+	// rulesets
+	handle("GET /repos/{owner}/{repo}/rulesets", s.listRulesets)
+	handle("POST /repos/{owner}/{repo}/rulesets", s.createRuleset)
+	handle("GET /repos/{owner}/{repo}/rulesets/{rulesetId}", s.getRuleset)
+	handle("PUT /repos/{owner}/{repo}/rulesets/{rulesetId}", s.updateRuleset)
+	handle("DELETE /repos/{owner}/{repo}/rulesets/{rulesetId}", s.deleteRuleset)
+
+	// environments
+	handle("GET /repos/{owner}/{repo}/environments/{env}", s.getEnvironment)
+	handle("PUT /repos/{owner}/{repo}/environments/{env}", s.createUpdateEnvironment)
+	handle("DELETE /repos/{owner}/{repo}/environments/{env}", s.deleteEnvironment)
+	handle("GET /repos/{owner}/{repo}/environments/{env}/deployment-branch-policies", s.listDeploymentBranchPolicies)
+	handle("POST /repos/{owner}/{repo}/environments/{env}/deployment-branch-policies", s.createDeploymentBranchPolicy)
+	handle("DELETE /repos/{owner}/{repo}/environments/{env}/deployment-branch-policies/{policyId}", s.deleteDeploymentBranchPolicy)
+
+	// actions secrets and variables of repositories and environments
+	handle("GET /repos/{owner}/{repo}/actions/secrets/public-key", s.getActionsPublicKey)
+	handle("GET /repos/{owner}/{repo}/actions/secrets/{name}", s.getActionsSecret)
+	handle("PUT /repos/{owner}/{repo}/actions/secrets/{name}", s.createOrUpdateActionsSecret)
+	handle("DELETE /repos/{owner}/{repo}/actions/secrets/{name}", s.deleteActionsSecret)
+	handle("GET /repositories/{repoId}/environments/{env}/secrets/public-key", s.getActionsPublicKey)
+	handle("GET /repositories/{repoId}/environments/{env}/secrets/{name}", s.getActionsSecret)
+	handle("PUT /repositories/{repoId}/environments/{env}/secrets/{name}", s.createOrUpdateActionsSecret)
+	handle("DELETE /repositories/{repoId}/environments/{env}/secrets/{name}", s.deleteActionsSecret)
+	handle("POST /repos/{owner}/{repo}/actions/variables", s.createActionsVariable)
+	handle("GET /repos/{owner}/{repo}/actions/variables/{name}", s.getActionsVariable)
+	handle("PATCH /repos/{owner}/{repo}/actions/variables/{name}", s.updateActionsVariable)
+	handle("DELETE /repos/{owner}/{repo}/actions/variables/{name}", s.deleteActionsVariable)
+	handle("POST /repositories/{repoId}/environments/{env}/variables", s.createActionsVariable)
+	handle("GET /repositories/{repoId}/environments/{env}/variables/{name}", s.getActionsVariable)
+	handle("PATCH /repositories/{repoId}/environments/{env}/variables/{name}", s.updateActionsVariable)
+	handle("DELETE /repositories/{repoId}/environments/{env}/variables/{name}", s.deleteActionsVariable)
+
+	// webhooks
+	handle("GET /repos/{owner}/{repo}/hooks", s.listHooks)
+	handle("POST /repos/{owner}/{repo}/hooks", s.createHook)
+	handle("GET /repos/{owner}/{repo}/hooks/{hookId}", s.getHook)
+	handle("PATCH /repos/{owner}/{repo}/hooks/{hookId}", s.editHook)
+	handle("DELETE /repos/{owner}/{repo}/hooks/{hookId}", s.deleteHook)
+	handle("GET /repos/{owner}/{repo}/hooks/{hookId}/deliveries", s.listHookDeliveries)
+
+	// deploy keys
+	handle("GET /repos/{owner}/{repo}/keys", s.listDeployKeys)
+	handle("POST /repos/{owner}/{repo}/keys", s.createDeployKey)
+	handle("GET /repos/{owner}/{repo}/keys/{keyId}", s.getDeployKey)
+	handle("DELETE /repos/{owner}/{repo}/keys/{keyId}", s.deleteDeployKey)
+}
+
+// Helpers
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+// writeError writes an error in the format of the GitHub REST API
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+// writeValidationError writes a 422 for a field that failed validation
+func writeValidationError(w http.ResponseWriter, message, resource, field, detail string) {
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+		"message": message,
+		"errors": []map[string]any{{
+			"resource": resource,
+			"code":     "custom",
+			"field":    field,
+			"message":  detail,
+		}},
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+func decodeBody(r *http.Request, v any) error {
+	if r.Body == nil || r.ContentLength == 0 {
+		return nil
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// merge overlays the fields present in patch onto dst, the way PATCH endpoints leave absent fields unchanged
+func merge(dst any, patch map[string]any) error {
+	b, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+	current := map[string]any{}
+	if err := json.Unmarshal(b, &current); err != nil {
+		return err
+	}
+	for k, v := range patch {
+		current[k] = v
+	}
+	b, err = json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+// paginate writes the page of items requested with the page and per_page parameters, with a Link
+// header pointing at the next page
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 30
+	}
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	if end < len(items) {
+		next := *r.URL
+		q := next.Query()
+		q.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = q.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	}
+	writeJSON(w, http.StatusOK, items[start:end])
+}
+
+func now() *github.Timestamp {
+	return &github.Timestamp{Time: time.Now().UTC().Truncate(time.Second)}
+}
+
+// Organizations
+
+func (s *Server) organization(w http.ResponseWriter, r *http.Request) *github.Organization {
+	var org *github.Organization
+	if id, err := strconv.ParseInt(r.PathValue("orgId"), 10, 64); err == nil {
+		org = s.orgs[id]
+	} else {
+		org = s.orgByLogin(r.PathValue("org"))
+	}
+	if org == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return org
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, r *http.Request) {
+	if org := s.organization(w, r); org != nil {
+		writeJSON(w, http.StatusOK, org)
+	}
+}
+
+func (s *Server) getOrganizationById(w http.ResponseWriter, r *http.Request) {
+	s.getOrganization(w, r)
+}
+
+func (s *Server) editOrganization(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	patch := map[string]any{}
+	if err := decodeBody(r, &patch); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	// the login and IDs of an organization can't be changed through this endpoint
+	delete(patch, "login")
+	delete(patch, "id")
+	delete(patch, "node_id")
+	if err := merge(org, patch); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	org.UpdatedAt = now()
+	writeJSON(w, http.StatusOK, org)
+}
+
+func (s *Server) deleteOrganization(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	for id, repo := range s.repos {
+		if repo.GetOwner().GetID() == org.GetID() {
+			s.removeRepository(id)
+		}
+	}
+	for id, t := range s.teams {
+		if t.orgId == org.GetID() {
+			delete(s.teams, id)
+		}
+	}
+	delete(s.orgs, org.GetID())
+	delete(s.orgMembers, org.GetID())
+	// GitHub deletes organizations in the background
+	writeJSON(w, http.StatusAccepted, map[string]any{})
+}
+
+func (s *Server) editOrganizationMembership(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	user := s.userByLogin(r.PathValue("user"))
+	if user == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var body struct {
+		Role string `json:"role"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if body.Role == "" {
+		body.Role = "member"
+	}
+	if body.Role != "member" && body.Role != "admin" {
+		writeValidationError(w, "Validation Failed", "Membership", "role", "role is not included in the list")
+		return
+	}
+	// users that aren't members are invited, or join immediately if invitations are disabled
+	if _, ok := s.orgMembers[org.GetID()][user.GetLogin()]; !ok && s.invitations {
+		role := "direct_member"
+		if body.Role == "admin" {
+			role = "admin"
+		}
+		s.orgInvitations[org.GetID()][user.GetLogin()] = &orgInvitation{id: s.newId(), role: role}
+		writeJSON(w, http.StatusOK, &github.Membership{
+			State:        github.String("pending"),
+			Role:         github.String(body.Role),
+			Organization: org,
+			User:         user,
+		})
+		return
+	}
+	s.orgMembers[org.GetID()][user.GetLogin()] = body.Role
+	writeJSON(w, http.StatusOK, &github.Membership{
+		State:        github.String("active"),
+		Role:         github.String(body.Role),
+		Organization: org,
+		User:         user,
+	})
+}
+
+func (s *Server) removeOrganizationMembership(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	user := s.userByLogin(r.PathValue("user"))
+	if user == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if _, ok := s.orgMembers[org.GetID()][user.GetLogin()]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	delete(s.orgMembers[org.GetID()], user.GetLogin())
+	for _, t := range s.teams {
+		if t.orgId == org.GetID() {
+			delete(t.members, user.GetLogin())
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listOrganizationInvitations(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	invitations := []*github.Invitation{}
+	for _, login := range sortedKeys(s.orgInvitations[org.GetID()]) {
+		invitation := s.orgInvitations[org.GetID()][login]
+		invitations = append(invitations, &github.Invitation{
+			ID:    github.Int64(invitation.id),
+			Login: github.String(login),
+			Role:  github.String(invitation.role),
+		})
+	}
+	paginate(w, r, invitations)
+}
+
+func (s *Server) cancelOrganizationInvitation(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	id, _ := strconv.ParseInt(r.PathValue("invitationId"), 10, 64)
+	for login, invitation := range s.orgInvitations[org.GetID()] {
+		if invitation.id == id {
+			delete(s.orgInvitations[org.GetID()], login)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// invitations never fail in the fake
+func (s *Server) listOrganizationFailedInvitations(w http.ResponseWriter, r *http.Request) {
+	if org := s.organization(w, r); org != nil {
+		paginate(w, r, []*github.Invitation{})
+	}
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	if user := s.userByLogin(r.PathValue("user")); user != nil {
+		writeJSON(w, http.StatusOK, user)
+	} else if org := s.orgByLogin(r.PathValue("user")); org != nil {
+		writeJSON(w, http.StatusOK, s.account(org.GetLogin()))
+	} else {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// Teams
+
+// teamJSON returns a team with its organization and parent filled in
+func (s *Server) teamJSON(t *team) *github.Team {
+	out := *t.Team
+	out.Organization = s.orgs[t.orgId]
+	out.ReposCount = github.Int(len(t.repos))
+	out.MembersCount = github.Int(len(t.members))
+	if out.Parent != nil {
+		if parent, ok := s.teams[out.Parent.GetID()]; ok {
+			p := *parent.Team
+			out.Parent = &p
+		}
+	}
+	return &out
+}
+
+func (s *Server) team(w http.ResponseWriter, r *http.Request) *team {
+	org := s.organization(w, r)
+	if org == nil {
+		return nil
+	}
+	var t *team
+	if id, err := strconv.ParseInt(r.PathValue("teamId"), 10, 64); err == nil {
+		if found, ok := s.teams[id]; ok && found.orgId == org.GetID() {
+			t = found
+		}
+	} else {
+		t = s.teamBySlug(org.GetID(), r.PathValue("slug"))
+	}
+	if t == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return t
+}
+
+func (s *Server) listTeams(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	teams := []*github.Team{}
+	for _, t := range s.teams {
+		if t.orgId == org.GetID() {
+			teams = append(teams, s.teamJSON(t))
+		}
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].GetID() < teams[j].GetID() })
+	paginate(w, r, teams)
+}
+
+// applyTeamUpdate applies the fields of a create or edit team request
+func (s *Server) applyTeamUpdate(w http.ResponseWriter, t *team, body map[string]any) bool {
+	if name, ok := body["name"].(string); ok {
+		if existing := s.teamBySlug(t.orgId, slug(name)); existing != nil && existing.GetID() != t.GetID() {
+			writeValidationError(w, "Validation Failed", "Team", "name", "Name must be unique for this org")
+			return false
+		}
+		t.Name = github.String(name)
+		t.Slug = github.String(slug(name))
+	}
+	if description, ok := body["description"]; ok {
+		if description == nil {
+			t.Description = nil
+		} else {
+			t.Description = github.String(fmt.Sprint(description))
+		}
+	}
+	if privacy, ok := body["privacy"].(string); ok {
+		if privacy != "secret" && privacy != "closed" {
+			writeValidationError(w, "Validation Failed", "Team", "privacy", "privacy is not included in the list")
+			return false
+		}
+		t.Privacy = github.String(privacy)
+	}
+	if parentId, ok := body["parent_team_id"]; ok {
+		if parentId == nil {
+			t.Parent = nil
+		} else {
+			id, _ := parentId.(float64)
+			parent, found := s.teams[int64(id)]
+			if !found || parent.orgId != t.orgId || parent.GetID() == t.GetID() {
+				writeValidationError(w, "Validation Failed", "Team", "parent_team_id", "Parent team must be a team in this organization")
+				return false
+			}
+			p := *parent.Team
+			t.Parent = &p
+		}
+	}
+	// nested teams can't be secret
+	if t.Parent != nil && t.GetPrivacy() == "secret" {
+		if _, ok := body["privacy"]; ok {
+			writeValidationError(w, "Validation Failed", "Team", "privacy", "A team with a parent can't be secret")
+			return false
+		}
+		t.Privacy = github.String("closed")
+	}
+	return true
+}
+
+func (s *Server) createTeam(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	body := map[string]any{}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if name, _ := body["name"].(string); name == "" {
+		writeValidationError(w, "Validation Failed", "Team", "name", "name can't be blank")
+		return
+	}
+	id := s.newId()
+	t := &team{
+		Team: &github.Team{
+			ID:         github.Int64(id),
+			NodeID:     github.String(nodeId("T", id)),
+			Permission: github.String("pull"),
+			Privacy:    github.String("secret"),
+		},
+		orgId:       org.GetID(),
+		repos:       map[int64]string{},
+		members:     map[string]string{},
+		invitations: map[string]*teamInvitation{},
+	}
+	if !s.applyTeamUpdate(w, t, body) {
+		return
+	}
+	s.teams[id] = t
+	writeJSON(w, http.StatusCreated, s.teamJSON(t))
+}
+
+func (s *Server) getTeam(w http.ResponseWriter, r *http.Request) {
+	if t := s.team(w, r); t != nil {
+		writeJSON(w, http.StatusOK, s.teamJSON(t))
+	}
+}
+
+func (s *Server) editTeam(w http.ResponseWriter, r *http.Request) {
+	t := s.team(w, r)
+	if t == nil {
+		return
+	}
+	body := map[string]any{}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if !s.applyTeamUpdate(w, t, body) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.teamJSON(t))
+}
+
+func (s *Server) deleteTeam(w http.ResponseWriter, r *http.Request) {
+	t := s.team(w, r)
+	if t == nil {
+		return
+	}
+	// child teams are deleted with their parent
+	var remove func(id int64)
+	remove = func(id int64) {
+		delete(s.teams, id)
+		for childId, child := range s.teams {
+			if child.Parent != nil && child.Parent.GetID() == id {
+				remove(childId)
+			}
+		}
+	}
+	remove(t.GetID())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getTeamRepository(w http.ResponseWriter, r *http.Request) {
+	t := s.team(w, r)
+	if t == nil {
+		return
+	}
+	repo := s.repoByName(r.PathValue("owner"), r.PathValue("repo"))
+	if repo == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	permission, ok := t.repos[repo.GetID()]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	out := *repo
+	out.Permissions = permissionsMap(permission)
+	writeJSON(w, http.StatusOK, &out)
+}
+
+var repositoryPermissions = []string{"pull", "triage", "push", "maintain", "admin"}
+
+// permissionsMap returns the permissions implied by a permission, in the format of repository permissions
+func permissionsMap(permission string) map[string]bool {
+	level := slices.Index(repositoryPermissions, permission)
+	out := map[string]bool{}
+	for i, p := range repositoryPermissions {
+		out[p] = i <= level
+	}
+	return out
+}
+
+func (s *Server) addTeamRepository(w http.ResponseWriter, r *http.Request) {
+	t := s.team(w, r)
+	if t == nil {
+		return
+	}
+	repo := s.repoByName(r.PathValue("owner"), r.PathValue("repo"))
+	if repo == nil || repo.GetOwner().GetID() != t.orgId {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var body struct {
+		Permission string `json:"permission"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if body.Permission == "" {
+		body.Permission = "push"
+	}
+	if !slices.Contains(repositoryPermissions, body.Permission) {
+		writeValidationError(w, "Validation Failed", "TeamRepository", "permission", "permission is not included in the list")
+		return
+	}
+	t.repos[repo.GetID()] = body.Permission
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeTeamRepository(w http.ResponseWriter, r *http.Request) {
+	t := s.team(w, r)
+	if t == nil {
+		return
+	}
+	repo := s.repoByName(r.PathValue("owner"), r.PathValue("repo"))
+	if repo == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	delete(t.repos, repo.GetID())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addTeamMembership(w http.ResponseWriter, r *http.Request) {
+	t := s.team(w, r)
+	if t == nil {
+		return
+	}
+	user := s.userByLogin(r.PathValue("user"))
+	if user == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var body struct {
+		Role string `json:"role"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if body.Role == "" {
+		body.Role = "member"
+	}
+	if body.Role != "member" && body.Role != "maintainer" {
+		writeValidationError(w, "Validation Failed", "TeamMembership", "role", "role is not included in the list")
+		return
+	}
+	// users that aren't organization members are invited, or join it if invitations are disabled
+	if _, ok := s.orgMembers[t.orgId][user.GetLogin()]; !ok {
+		if s.invitations {
+			if invitation, ok := t.invitations[user.GetLogin()]; ok {
+				invitation.role = body.Role
+			} else {
+				t.invitations[user.GetLogin()] = &teamInvitation{id: s.newId(), role: body.Role}
+			}
+			writeJSON(w, http.StatusOK, &github.Membership{
+				State: github.String("pending"),
+				Role:  github.String(body.Role),
+				User:  user,
+			})
+			return
+		}
+		s.orgMembers[t.orgId][user.GetLogin()] = "member"
+	}
+	t.members[user.GetLogin()] = body.Role
+	writeJSON(w, http.StatusOK, &github.Membership{
+		State: github.String("active"),
+		Role:  github.String(body.Role),
+		User:  user,
+	})
+}
+
+func (s *Server) getTeamMembership(w http.ResponseWriter, r *http.Request) {
+	t := s.team(w, r)
+	if t == nil {
+		return
+	}
+	user := s.userByLogin(r.PathValue("user"))
+	if user == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if role, ok := t.members[user.GetLogin()]; ok {
+		writeJSON(w, http.StatusOK, &github.Membership{
+			State: github.String("active"),
+			Role:  github.String(role),
+			User:  user,
+		})
+	} else if invitation, ok := t.invitations[user.GetLogin()]; ok {
+		writeJSON(w, http.StatusOK, &github.Membership{
+			State: github.String("pending"),
+			Role:  github.String(invitation.role),
+			User:  user,
+		})
+	} else {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) removeTeamMembership(w http.ResponseWriter, r *http.Request) {
+	t := s.team(w, r)
+	if t == nil {
+		return
+	}
+	user := s.userByLogin(r.PathValue("user"))
+	if user == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	_, member := t.members[user.GetLogin()]
+	_, invited := t.invitations[user.GetLogin()]
+	if !member && !invited {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	// removing a pending membership cancels the invitation
+	delete(t.members, user.GetLogin())
+	delete(t.invitations, user.GetLogin())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listTeamInvitations(w http.ResponseWriter, r *http.Request) {
+	t := s.team(w, r)
+	if t == nil {
+		return
+	}
+	invitations := []*github.Invitation{}
+	for _, login := range sortedKeys(t.invitations) {
+		// team invitations carry the organization role of the invitee, not the team role
+		invitations = append(invitations, &github.Invitation{
+			ID:    github.Int64(t.invitations[login].id),
+			Login: github.String(login),
+			Role:  github.String("direct_member"),
+		})
+	}
+	paginate(w, r, invitations)
+}
+
+// Repositories
+
+func (s *Server) repository(w http.ResponseWriter, r *http.Request) *github.Repository {
+	var repo *github.Repository
+	if id, err := strconv.ParseInt(r.PathValue("repoId"), 10, 64); err == nil {
+		repo = s.repos[id]
+	} else {
+		owner, name := r.PathValue("owner"), r.PathValue("repo")
+		repo = s.repoByName(owner, name)
+		if repo == nil && s.redirectRepository(w, r, owner, name) {
+			return nil
+		}
+	}
+	if repo == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return repo
+}
+
+// redirectRepository redirects a request for the former name of a repository to its current
+// name the way GitHub does, and reports whether it did
+func (s *Server) redirectRepository(w http.ResponseWriter, r *http.Request, owner, name string) bool {
+	repo, ok := s.repos[s.repoRedirects[strings.ToLower(owner+"/"+name)]]
+	if !ok {
+		return false
+	}
+	prefix := restPrefix + "/repos/" + owner + "/" + name
+	location := restPrefix + "/repos/" + repo.GetFullName() + strings.TrimPrefix(r.URL.Path, prefix)
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+	status := http.StatusTemporaryRedirect
+	if r.Method == http.MethodGet {
+		status = http.StatusMovedPermanently
+	}
+	w.Header().Set("Location", location)
+	writeJSON(w, status, map[string]string{"message": "Moved Permanently", "url": location})
+	return true
+}
+
+// moveRepository redirects the former full name of a repository that was renamed or transferred
+func (s *Server) moveRepository(repo *github.Repository, formerName string) {
+	if !strings.EqualFold(formerName, repo.GetFullName()) {
+		s.repoRedirects[strings.ToLower(formerName)] = repo.GetID()
+	}
+}
+
+func (s *Server) listRepositories(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	repos := []*github.Repository{}
+	for _, repo := range s.repos {
+		if repo.GetOwner().GetID() == org.GetID() {
+			repos = append(repos, repo)
+		}
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].GetID() < repos[j].GetID() })
+	paginate(w, r, repos)
+}
+
+// newRepository creates a repository with GitHub's defaults for the fields absent from create
+func (s *Server) newRepository(w http.ResponseWriter, owner *github.User, create *github.Repository) *github.Repository {
+	if create.GetName() == "" {
+		writeValidationError(w, "Repository creation failed.", "Repository", "name", "name can't be blank")
+		return nil
+	}
+	if s.repoByName(owner.GetLogin(), create.GetName()) != nil {
+		writeValidationError(w, "Repository creation failed.", "Repository", "name", "name already exists on this account")
+		return nil
+	}
+	id := s.newId()
+	repo := *create
+	repo.ID = github.Int64(id)
+	repo.NodeID = github.String(nodeId("R", id))
+	repo.Owner = owner
+	repo.FullName = github.String(owner.GetLogin() + "/" + create.GetName())
+	if owner.GetType() == "Organization" {
+		repo.Organization = s.orgs[owner.GetID()]
+	}
+	if repo.Visibility == nil {
+		repo.Visibility = github.String("public")
+		if repo.GetPrivate() {
+			repo.Visibility = github.String("private")
+		}
+	}
+	repo.Private = github.Bool(repo.GetVisibility() != "public")
+	defaults := map[**bool]bool{
+		&repo.HasIssues:                true,
+		&repo.HasProjects:              true,
+		&repo.HasWiki:                  true,
+		&repo.HasDownloads:             true,
+		&repo.HasDiscussions:           false,
+		&repo.AllowMergeCommit:         true,
+		&repo.AllowSquashMerge:         true,
+		&repo.AllowRebaseMerge:         true,
+		&repo.AllowAutoMerge:           false,
+		&repo.AllowUpdateBranch:        false,
+		&repo.AllowForking:             true,
+		&repo.DeleteBranchOnMerge:      false,
+		&repo.WebCommitSignoffRequired: false,
+		&repo.Archived:                 false,
+		&repo.Disabled:                 false,
+		&repo.IsTemplate:               false,
+		&repo.Fork:                     false,
+	}
+	for field, value := range defaults {
+		if *field == nil {
+			*field = github.Bool(value)
+		}
+	}
+	if repo.DefaultBranch == nil {
+		repo.DefaultBranch = github.String("main")
+	}
+	if repo.SquashMergeCommitTitle == nil {
+		repo.SquashMergeCommitTitle = github.String("COMMIT_OR_PR_TITLE")
+	}
+	if repo.SquashMergeCommitMessage == nil {
+		repo.SquashMergeCommitMessage = github.String("COMMIT_MESSAGES")
+	}
+	if repo.MergeCommitTitle == nil {
+		repo.MergeCommitTitle = github.String("MERGE_MESSAGE")
+	}
+	if repo.MergeCommitMessage == nil {
+		repo.MergeCommitMessage = github.String("PR_TITLE")
+	}
+	repo.Topics = []string{}
+	repo.CreatedAt = now()
+	repo.UpdatedAt = repo.CreatedAt
+	repo.PushedAt = repo.CreatedAt
+	s.repos[id] = &repo
+	s.collaborators[id] = map[string]string{}
+	s.repoInvitations[id] = map[string]*repoInvitation{}
+	s.labels[id] = map[string]*github.Label{}
+	return &repo
+}
+
+func (s *Server) createRepository(w http.ResponseWriter, r *http.Request) {
+	org := s.organization(w, r)
+	if org == nil {
+		return
+	}
+	create := &github.Repository{}
+	if err := decodeBody(r, create); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if repo := s.newRepository(w, s.account(org.GetLogin()), create); repo != nil {
+		writeJSON(w, http.StatusCreated, repo)
+	}
+}
+
+func (s *Server) createRepositoryFromTemplate(w http.ResponseWriter, r *http.Request) {
+	template := s.repository(w, r)
+	if template == nil {
+		return
+	}
+	if !template.GetIsTemplate() {
+		writeValidationError(w, "Validation Failed", "Repository", "template", "repository is not a template")
+		return
+	}
+	req := &github.TemplateRepoRequest{}
+	if err := decodeBody(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	owner := s.account(req.GetOwner())
+	if req.Owner == nil {
+		owner = template.Owner
+	}
+	if owner == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	create := &github.Repository{
+		Name:               req.Name,
+		Description:        req.Description,
+		Private:            req.Private,
+		TemplateRepository: template,
+	}
+	if repo := s.newRepository(w, owner, create); repo != nil {
+		writeJSON(w, http.StatusCreated, repo)
+	}
+}
+
+func (s *Server) getRepository(w http.ResponseWriter, r *http.Request) {
+	if repo := s.repository(w, r); repo != nil {
+		writeJSON(w, http.StatusOK, repo)
+	}
+}
+
+func (s *Server) editRepository(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	patch := map[string]any{}
+	if err := decodeBody(r, &patch); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if archived, ok := patch["archived"].(bool); repo.GetArchived() && (!ok || archived) {
+		writeError(w, http.StatusForbidden, "Repository was archived so is read-only.")
+		return
+	}
+	if name, ok := patch["name"].(string); ok && !strings.EqualFold(name, repo.GetName()) {
+		if s.repoByName(repo.GetOwner().GetLogin(), name) != nil {
+			writeValidationError(w, "Validation Failed", "Repository", "name", "name already exists on this account")
+			return
+		}
+	}
+	for _, field := range []string{"id", "node_id", "owner", "organization", "full_name"} {
+		delete(patch, field)
+	}
+	formerName := repo.GetFullName()
+	if err := merge(repo, patch); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if visibility, ok := patch["visibility"].(string); ok {
+		repo.Private = github.Bool(visibility != "public")
+	} else if private, ok := patch["private"].(bool); ok {
+		repo.Visibility = github.String("public")
+		if private {
+			repo.Visibility = github.String("private")
+		}
+	}
+	repo.FullName = github.String(repo.GetOwner().GetLogin() + "/" + repo.GetName())
+	repo.UpdatedAt = now()
+	s.moveRepository(repo, formerName)
+	writeJSON(w, http.StatusOK, repo)
+}
+
+// removeRepository deletes a repository along with its branch protection rules, team permissions
+// and everything else that belongs to it
+func (s *Server) removeRepository(id int64) {
+	delete(s.repos, id)
+	delete(s.collaborators, id)
+	delete(s.repoInvitations, id)
+	delete(s.labels, id)
+	for formerName, repoId := range s.repoRedirects {
+		if repoId == id {
+			delete(s.repoRedirects, formerName)
+		}
+	}
+	for bpId, bp := range s.bps {
+		if bp.repoId == id {
+			delete(s.bps, bpId)
+		}
+	}
+	for _, t := range s.teams {
+		delete(t.repos, id)
+	}
+	for rsId, rs := range s.rulesets {
+		if rs.repoId == id {
+			delete(s.rulesets, rsId)
+		}
+	}
+	for envId, env := range s.environments {
+		if env.repoId == id {
+			delete(s.environments, envId)
+		}
+	}
+	for hookId, h := range s.hooks {
+		if h.repoId == id {
+			delete(s.hooks, hookId)
+		}
+	}
+	for keyId, key := range s.deployKeys {
+		if key.repoId == id {
+			delete(s.deployKeys, keyId)
+		}
+	}
+	for scope := range s.actionsSecrets {
+		if scope.repoId == id {
+			delete(s.actionsSecrets, scope)
+		}
+	}
+	for scope := range s.actionsVariables {
+		if scope.repoId == id {
+			delete(s.actionsVariables, scope)
+		}
+	}
+}
+
+func (s *Server) deleteRepository(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	s.removeRepository(repo.GetID())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) replaceTopics(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	if repo.GetArchived() {
+		writeError(w, http.StatusForbidden, "Repository was archived so is read-only.")
+		return
+	}
+	var body struct {
+		Names []string `json:"names"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	topics := []string{}
+	for _, name := range body.Names {
+		topics = append(topics, strings.ToLower(name))
+	}
+	repo.Topics = topics
+	writeJSON(w, http.StatusOK, map[string]any{"names": topics})
+}
+
+func (s *Server) transferRepository(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	var body struct {
+		NewOwner string  `json:"new_owner"`
+		NewName  *string `json:"new_name"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	owner := s.account(body.NewOwner)
+	if owner == nil {
+		writeValidationError(w, "Validation Failed", "Repository", "new_owner", "new_owner must be a valid user or organization")
+		return
+	}
+	name := repo.GetName()
+	if body.NewName != nil {
+		name = *body.NewName
+	}
+	if existing := s.repoByName(owner.GetLogin(), name); existing != nil && existing.GetID() != repo.GetID() {
+		writeValidationError(w, "Validation Failed", "Repository", "name", "name already exists on this account")
+		return
+	}
+	formerName := repo.GetFullName()
+	// team permissions don't follow a repository to another owner
+	if owner.GetID() != repo.GetOwner().GetID() {
+		for _, t := range s.teams {
+			delete(t.repos, repo.GetID())
+		}
+	}
+	repo.Owner = owner
+	repo.Organization = nil
+	if owner.GetType() == "Organization" {
+		repo.Organization = s.orgs[owner.GetID()]
+	}
+	repo.Name = github.String(name)
+	repo.FullName = github.String(owner.GetLogin() + "/" + name)
+	repo.UpdatedAt = now()
+	s.moveRepository(repo, formerName)
+	// GitHub schedules transfers in the background, although the fake completes them immediately
+	writeJSON(w, http.StatusAccepted, repo)
+}
+
+// Collaborators
+
+// invitationPermissions maps permissions to the legacy names repository invitations report
+var invitationPermissions = map[string]string{
+	"pull": "read",
+	"push": "write",
+}
+
+func (s *Server) listCollaborators(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	permissions := map[string]string{}
+	maps.Copy(permissions, s.collaborators[repo.GetID()])
+	// all collaborators include the members of teams with access to the repository
+	if r.URL.Query().Get("affiliation") != "direct" {
+		for _, t := range s.teams {
+			permission, ok := t.repos[repo.GetID()]
+			if !ok {
+				continue
+			}
+			for login := range t.members {
+				if slices.Index(repositoryPermissions, permission) > slices.Index(repositoryPermissions, permissions[login]) {
+					permissions[login] = permission
+				}
+			}
+		}
+	}
+	users := []*github.User{}
+	for _, login := range sortedKeys(permissions) {
+		user := *s.userByLogin(login)
+		user.Permissions = permissionsMap(permissions[login])
+		users = append(users, &user)
+	}
+	paginate(w, r, users)
+}
+
+func (s *Server) addCollaborator(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	user := s.userByLogin(r.PathValue("user"))
+	if user == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var body struct {
+		Permission string `json:"permission"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if body.Permission == "" {
+		body.Permission = "push"
+	}
+	if !slices.Contains(repositoryPermissions, body.Permission) {
+		writeValidationError(w, "Validation Failed", "Repository", "permission", "permission is not included in the list")
+		return
+	}
+	login := user.GetLogin()
+	_, collaborator := s.collaborators[repo.GetID()][login]
+	_, member := s.orgMembers[repo.GetOwner().GetID()][login]
+	// users that aren't organization members are invited unless they already collaborate
+	if s.invitations && !collaborator && !member {
+		invitations := s.repoInvitations[repo.GetID()]
+		invitation, ok := invitations[login]
+		if !ok {
+			invitation = &repoInvitation{id: s.newId()}
+			invitations[login] = invitation
+		}
+		invitation.permission = body.Permission
+		writeJSON(w, http.StatusCreated, s.repositoryInvitation(repo, login, invitation))
+		return
+	}
+	s.collaborators[repo.GetID()][login] = body.Permission
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) removeCollaborator(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	for login := range s.collaborators[repo.GetID()] {
+		if strings.EqualFold(login, r.PathValue("user")) {
+			delete(s.collaborators[repo.GetID()], login)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) repositoryInvitation(repo *github.Repository, login string, invitation *repoInvitation) *github.CollaboratorInvitation {
+	permission := invitation.permission
+	if legacy, ok := invitationPermissions[permission]; ok {
+		permission = legacy
+	}
+	return &github.CollaboratorInvitation{
+		ID:          github.Int64(invitation.id),
+		Repo:        &github.Repository{ID: repo.ID, Name: repo.Name, FullName: repo.FullName},
+		Invitee:     s.userByLogin(login),
+		Permissions: github.String(permission),
+	}
+}
+
+func (s *Server) listRepositoryInvitations(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	invitations := []*github.CollaboratorInvitation{}
+	for _, login := range sortedKeys(s.repoInvitations[repo.GetID()]) {
+		invitations = append(invitations, s.repositoryInvitation(repo, login, s.repoInvitations[repo.GetID()][login]))
+	}
+	paginate(w, r, invitations)
+}
+
+// repositoryInvitationLogin returns the login of the invitee of the invitation in the request path, or
+// writes an error and returns an empty login if there is no such invitation
+func (s *Server) repositoryInvitationLogin(w http.ResponseWriter, r *http.Request, repo *github.Repository) string {
+	id, _ := strconv.ParseInt(r.PathValue("invitationId"), 10, 64)
+	for login, invitation := range s.repoInvitations[repo.GetID()] {
+		if invitation.id == id {
+			return login
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+	return ""
+}
+
+func (s *Server) updateRepositoryInvitation(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	login := s.repositoryInvitationLogin(w, r, repo)
+	if login == "" {
+		return
+	}
+	var body struct {
+		Permissions string `json:"permissions"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	permission := body.Permissions
+	for p, legacy := range invitationPermissions {
+		if legacy == permission {
+			permission = p
+		}
+	}
+	if !slices.Contains(repositoryPermissions, permission) {
+		writeValidationError(w, "Validation Failed", "RepositoryInvitation", "permissions", "permissions is not included in the list")
+		return
+	}
+	invitation := s.repoInvitations[repo.GetID()][login]
+	invitation.permission = permission
+	writeJSON(w, http.StatusOK, s.repositoryInvitation(repo, login, invitation))
+}
+
+func (s *Server) deleteRepositoryInvitation(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	login := s.repositoryInvitationLogin(w, r, repo)
+	if login == "" {
+		return
+	}
+	delete(s.repoInvitations[repo.GetID()], login)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Labels
+
+func (s *Server) listLabels(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	labels := []*github.Label{}
+	for _, key := range sortedKeys(s.labels[repo.GetID()]) {
+		labels = append(labels, s.labels[repo.GetID()][key])
+	}
+	paginate(w, r, labels)
+}
+
+func (s *Server) createLabel(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	label := &github.Label{}
+	if err := decodeBody(r, label); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	key := strings.ToLower(label.GetName())
+	if _, ok := s.labels[repo.GetID()][key]; ok {
+		writeValidationError(w, "Validation Failed", "Label", "name", "already_exists")
+		return
+	}
+	id := s.newId()
+	label.ID = github.Int64(id)
+	label.NodeID = github.String(nodeId("LA", id))
+	if label.Description == nil {
+		label.Description = github.String("")
+	}
+	label.Default = github.Bool(false)
+	s.labels[repo.GetID()][key] = label
+	writeJSON(w, http.StatusCreated, label)
+}
+
+func (s *Server) updateLabel(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	key := strings.ToLower(r.PathValue("name"))
+	label, ok := s.labels[repo.GetID()][key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	update := &gh.RepositoryLabelUpdate{}
+	if err := decodeBody(r, update); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if update.NewName != nil {
+		newKey := strings.ToLower(*update.NewName)
+		if _, ok := s.labels[repo.GetID()][newKey]; ok && newKey != key {
+			writeValidationError(w, "Validation Failed", "Label", "name", "already_exists")
+			return
+		}
+		delete(s.labels[repo.GetID()], key)
+		s.labels[repo.GetID()][newKey] = label
+		label.Name = update.NewName
+	}
+	if update.Color != nil {
+		label.Color = update.Color
+	}
+	if update.Description != nil {
+		label.Description = update.Description
+	}
+	writeJSON(w, http.StatusOK, label)
+}
+
+func (s *Server) deleteLabel(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	key := strings.ToLower(r.PathValue("name"))
+	if _, ok := s.labels[repo.GetID()][key]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	delete(s.labels[repo.GetID()], key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Rulesets
+
+func (s *Server) ruleset(w http.ResponseWriter, r *http.Request) *ruleset {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return nil
+	}
+	id, _ := strconv.ParseInt(r.PathValue("rulesetId"), 10, 64)
+	rs, ok := s.rulesets[id]
+	if !ok || rs.repoId != repo.GetID() {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil
+	}
+	return rs
+}
+
+// validRuleset writes a 422 if a ruleset can't be saved to a repository
+func (s *Server) validRuleset(w http.ResponseWriter, repoId int64, rs *github.Ruleset) bool {
+	if rs.Name == "" {
+		writeValidationError(w, "Validation Failed", "Ruleset", "name", "name can't be blank")
+		return false
+	}
+	if !slices.Contains([]string{"disabled", "active", "evaluate"}, rs.Enforcement) {
+		writeValidationError(w, "Validation Failed", "Ruleset", "enforcement", "enforcement is not included in the list")
+		return false
+	}
+	for _, other := range s.rulesets {
+		if other.repoId == repoId && other.GetID() != rs.GetID() && strings.EqualFold(other.Name, rs.Name) {
+			writeValidationError(w, "Validation Failed", "Ruleset", "name", "Name must be unique")
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) listRulesets(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	rulesets := []*github.Ruleset{}
+	for _, rs := range s.rulesets {
+		if rs.repoId == repo.GetID() {
+			rulesets = append(rulesets, rs.Ruleset)
+		}
+	}
+	sort.Slice(rulesets, func(i, j int) bool { return rulesets[i].GetID() < rulesets[j].GetID() })
+	writeJSON(w, http.StatusOK, rulesets)
+}
+
+func (s *Server) createRuleset(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	create := &github.Ruleset{}
+	if err := decodeBody(r, create); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if !s.validRuleset(w, repo.GetID(), create) {
+		return
+	}
+	id := s.newId()
+	create.ID = github.Int64(id)
+	create.NodeID = github.String(nodeId("RRS", id))
+	create.Source = repo.GetFullName()
+	create.SourceType = github.String("Repository")
+	if create.Target == nil {
+		create.Target = github.String("branch")
+	}
+	if create.BypassActors == nil {
+		create.BypassActors = []*github.BypassActor{}
+	}
+	if create.Rules == nil {
+		create.Rules = []*github.RepositoryRule{}
+	}
+	s.rulesets[id] = &ruleset{Ruleset: create, repoId: repo.GetID()}
+	writeJSON(w, http.StatusCreated, create)
+}
+
+func (s *Server) getRuleset(w http.ResponseWriter, r *http.Request) {
+	if rs := s.ruleset(w, r); rs != nil {
+		writeJSON(w, http.StatusOK, rs.Ruleset)
+	}
+}
+
+func (s *Server) updateRuleset(w http.ResponseWriter, r *http.Request) {
+	rs := s.ruleset(w, r)
+	if rs == nil {
+		return
+	}
+	patch := map[string]any{}
+	if err := decodeBody(r, &patch); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	for _, field := range []string{"id", "node_id", "source", "source_type", "_links"} {
+		delete(patch, field)
+	}
+	updated := *rs.Ruleset
+	if err := merge(&updated, patch); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if !s.validRuleset(w, rs.repoId, &updated) {
+		return
+	}
+	rs.Ruleset = &updated
+	writeJSON(w, http.StatusOK, rs.Ruleset)
+}
+
+func (s *Server) deleteRuleset(w http.ResponseWriter, r *http.Request) {
+	if rs := s.ruleset(w, r); rs != nil {
+		delete(s.rulesets, rs.GetID())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Environments
+
+func (s *Server) environment(w http.ResponseWriter, r *http.Request) *environment {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return nil
+	}
+	env := s.environmentByName(repo.GetID(), r.PathValue("env"))
+	if env == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return env
+}
+
+// environmentJSON reports the settings of an environment as protection rules, which GitHub only
+// includes when they are enabled
+func (s *Server) environmentJSON(env *environment) *github.Environment {
+	rules := []*github.ProtectionRule{}
+	if env.waitTimer > 0 {
+		rules = append(rules, &github.ProtectionRule{
+			Type:      github.String("wait_timer"),
+			WaitTimer: github.Int(env.waitTimer),
+		})
+	}
+	if len(env.reviewers) > 0 {
+		reviewers := []*github.RequiredReviewer{}
+		for _, reviewer := range env.reviewers {
+			switch reviewer.GetType() {
+			case "User":
+				reviewers = append(reviewers, &github.RequiredReviewer{Type: reviewer.Type, Reviewer: s.users[reviewer.GetID()]})
+			case "Team":
+				reviewers = append(reviewers, &github.RequiredReviewer{Type: reviewer.Type, Reviewer: s.teamJSON(s.teams[reviewer.GetID()])})
+			}
+		}
+		rules = append(rules, &github.ProtectionRule{
+			Type:              github.String("required_reviewers"),
+			PreventSelfReview: github.Bool(env.preventSelfReview),
+			Reviewers:         reviewers,
+		})
+	}
+	return &github.Environment{
+		ID:                     github.Int64(env.id),
+		NodeID:                 github.String(nodeId("EN", env.id)),
+		Name:                   github.String(env.name),
+		CreatedAt:              env.createdAt,
+		UpdatedAt:              env.updatedAt,
+		CanAdminsBypass:        github.Bool(env.canAdminsBypass),
+		ProtectionRules:        rules,
+		DeploymentBranchPolicy: env.branchPolicy,
+	}
+}
+
+func (s *Server) getEnvironment(w http.ResponseWriter, r *http.Request) {
+	if env := s.environment(w, r); env != nil {
+		writeJSON(w, http.StatusOK, s.environmentJSON(env))
+	}
+}
+
+func (s *Server) createUpdateEnvironment(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	update := &github.CreateUpdateEnvironment{}
+	if err := decodeBody(r, update); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if update.GetWaitTimer() < 0 || update.GetWaitTimer() > 43200 {
+		writeValidationError(w, "Validation Failed", "Environment", "wait_timer", "wait_timer must be between 0 and 43200")
+		return
+	}
+	for _, reviewer := range update.Reviewers {
+		_, isUser := s.users[reviewer.GetID()]
+		t, isTeam := s.teams[reviewer.GetID()]
+		if (reviewer.GetType() != "User" || !isUser) && (reviewer.GetType() != "Team" || !isTeam || t.orgId != repo.GetOwner().GetID()) {
+			writeValidationError(w, "Validation Failed", "Environment", "reviewers", "reviewer could not be found")
+			return
+		}
+	}
+	if policy := update.DeploymentBranchPolicy; policy != nil && policy.GetProtectedBranches() == policy.GetCustomBranchPolicies() {
+		writeValidationError(w, "Validation Failed", "Environment", "deployment_branch_policy",
+			"exactly one of protected_branches and custom_branch_policies must be true")
+		return
+	}
+	name := r.PathValue("env")
+	env := s.environmentByName(repo.GetID(), name)
+	if env == nil {
+		id := s.newId()
+		env = &environment{
+			id:        id,
+			repoId:    repo.GetID(),
+			name:      name,
+			policies:  map[int64]*github.DeploymentBranchPolicy{},
+			createdAt: now(),
+		}
+		s.environments[id] = env
+	}
+	env.waitTimer = update.GetWaitTimer()
+	env.preventSelfReview = update.GetPreventSelfReview()
+	env.canAdminsBypass = update.CanAdminsBypass == nil || *update.CanAdminsBypass
+	env.reviewers = update.Reviewers
+	env.branchPolicy = update.DeploymentBranchPolicy
+	// branch and tag policies only apply while custom branch policies are enabled
+	if !env.branchPolicy.GetCustomBranchPolicies() {
+		env.policies = map[int64]*github.DeploymentBranchPolicy{}
+	}
+	env.updatedAt = now()
+	writeJSON(w, http.StatusOK, s.environmentJSON(env))
+}
+
+func (s *Server) deleteEnvironment(w http.ResponseWriter, r *http.Request) {
+	env := s.environment(w, r)
+	if env == nil {
+		return
+	}
+	delete(s.environments, env.id)
+	scope := actionsScope{repoId: env.repoId, environment: env.name}
+	delete(s.actionsSecrets, scope)
+	delete(s.actionsVariables, scope)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listDeploymentBranchPolicies(w http.ResponseWriter, r *http.Request) {
+	env := s.environment(w, r)
+	if env == nil {
+		return
+	}
+	policies := []*github.DeploymentBranchPolicy{}
+	for _, policy := range env.policies {
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].GetID() < policies[j].GetID() })
+	writeJSON(w, http.StatusOK, &github.DeploymentBranchPolicyResponse{
+		TotalCount:     github.Int(len(policies)),
+		BranchPolicies: policies,
+	})
+}
+
+func (s *Server) createDeploymentBranchPolicy(w http.ResponseWriter, r *http.Request) {
+	env := s.environment(w, r)
+	if env == nil {
+		return
+	}
+	if !env.branchPolicy.GetCustomBranchPolicies() {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	req := &github.DeploymentBranchPolicyRequest{}
+	if err := decodeBody(r, req); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if req.Type == nil {
+		req.Type = github.String("branch")
+	}
+	for _, policy := range env.policies {
+		if policy.GetName() == req.GetName() && policy.GetType() == req.GetType() {
+			writeValidationError(w, "Validation Failed", "DeploymentBranchPolicy", "name", "name already exists")
+			return
+		}
+	}
+	id := s.newId()
+	policy := &github.DeploymentBranchPolicy{
+		ID:     github.Int64(id),
+		NodeID: github.String(nodeId("DBP", id)),
+		Name:   req.Name,
+		Type:   req.Type,
+	}
+	env.policies[id] = policy
+	writeJSON(w, http.StatusOK, policy)
+}
+
+func (s *Server) deleteDeploymentBranchPolicy(w http.ResponseWriter, r *http.Request) {
+	env := s.environment(w, r)
+	if env == nil {
+		return
+	}
+	id, _ := strconv.ParseInt(r.PathValue("policyId"), 10, 64)
+	if _, ok := env.policies[id]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	delete(env.policies, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Actions secrets and variables
+
+const actionsPublicKeyId = "fake-key"
+
+// actionsScope resolves the repository, or environment of a repository, that a request for actions
+// secrets or variables is about
+func (s *Server) actionsScope(w http.ResponseWriter, r *http.Request) (actionsScope, bool) {
+	if r.PathValue("env") != "" {
+		env := s.environment(w, r)
+		if env == nil {
+			return actionsScope{}, false
+		}
+		return actionsScope{repoId: env.repoId, environment: env.name}, true
+	}
+	repo := s.repository(w, r)
+	if repo == nil {
+		return actionsScope{}, false
+	}
+	return actionsScope{repoId: repo.GetID()}, true
+}
+
+func (s *Server) getActionsPublicKey(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.actionsScope(w, r); ok {
+		writeJSON(w, http.StatusOK, &github.PublicKey{
+			KeyID: github.String(actionsPublicKeyId),
+			Key:   github.String(base64.StdEncoding.EncodeToString(s.actionsPublicKey[:])),
+		})
+	}
+}
+
+// actionsSecret returns the secret named in the path, writing a 404 if there is none
+func (s *Server) actionsSecret(w http.ResponseWriter, r *http.Request) (actionsScope, *actionsSecret) {
+	scope, ok := s.actionsScope(w, r)
+	if !ok {
+		return scope, nil
+	}
+	secret, ok := s.actionsSecrets[scope][strings.ToUpper(r.PathValue("name"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return scope, nil
+	}
+	return scope, secret
+}
+
+func (s *Server) getActionsSecret(w http.ResponseWriter, r *http.Request) {
+	if _, secret := s.actionsSecret(w, r); secret != nil {
+		writeJSON(w, http.StatusOK, secret.Secret)
+	}
+}
+
+func (s *Server) createOrUpdateActionsSecret(w http.ResponseWriter, r *http.Request) {
+	scope, ok := s.actionsScope(w, r)
+	if !ok {
+		return
+	}
+	body := &github.EncryptedSecret{}
+	if err := decodeBody(r, body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if body.KeyID != actionsPublicKeyId {
+		writeValidationError(w, "Validation Failed", "Secret", "key_id", "key_id is invalid")
+		return
+	}
+	// the value must have been sealed with the public key of the scope
+	sealed, err := base64.StdEncoding.DecodeString(body.EncryptedValue)
+	if _, opened := box.OpenAnonymous(nil, sealed, s.actionsPublicKey, s.actionsPrivateKey); err != nil || !opened {
+		writeValidationError(w, "Validation Failed", "Secret", "encrypted_value", "encrypted_value could not be decrypted")
+		return
+	}
+	name := strings.ToUpper(r.PathValue("name"))
+	if s.actionsSecrets[scope] == nil {
+		s.actionsSecrets[scope] = map[string]*actionsSecret{}
+	}
+	secret, exists := s.actionsSecrets[scope][name]
+	if !exists {
+		created := now()
+		secret = &actionsSecret{Secret: &github.Secret{Name: name, CreatedAt: *created}}
+		s.actionsSecrets[scope][name] = secret
+	}
+	secret.encryptedValue = body.EncryptedValue
+	secret.UpdatedAt = *now()
+	if exists {
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		writeJSON(w, http.StatusCreated, map[string]any{})
+	}
+}
+
+func (s *Server) deleteActionsSecret(w http.ResponseWriter, r *http.Request) {
+	if scope, secret := s.actionsSecret(w, r); secret != nil {
+		delete(s.actionsSecrets[scope], secret.Name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// actionsVariable returns the variable named in the path, writing a 404 if there is none
+func (s *Server) actionsVariable(w http.ResponseWriter, r *http.Request) (actionsScope, *github.ActionsVariable) {
+	scope, ok := s.actionsScope(w, r)
+	if !ok {
+		return scope, nil
+	}
+	variable, ok := s.actionsVariables[scope][strings.ToUpper(r.PathValue("name"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return scope, nil
+	}
+	return scope, variable
+}
+
+func (s *Server) createActionsVariable(w http.ResponseWriter, r *http.Request) {
+	scope, ok := s.actionsScope(w, r)
+	if !ok {
+		return
+	}
+	create := &github.ActionsVariable{}
+	if err := decodeBody(r, create); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if create.Name == "" {
+		writeValidationError(w, "Validation Failed", "Variable", "name", "name can't be blank")
+		return
+	}
+	name := strings.ToUpper(create.Name)
+	if _, exists := s.actionsVariables[scope][name]; exists {
+		writeError(w, http.StatusConflict, "Already exists - Variable already exists")
+		return
+	}
+	if s.actionsVariables[scope] == nil {
+		s.actionsVariables[scope] = map[string]*github.ActionsVariable{}
+	}
+	s.actionsVariables[scope][name] = &github.ActionsVariable{
+		Name:      name,
+		Value:     create.Value,
+		CreatedAt: now(),
+		UpdatedAt: now(),
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{})
+}
+
+func (s *Server) getActionsVariable(w http.ResponseWriter, r *http.Request) {
+	if _, variable := s.actionsVariable(w, r); variable != nil {
+		writeJSON(w, http.StatusOK, variable)
+	}
+}
+
+func (s *Server) updateActionsVariable(w http.ResponseWriter, r *http.Request) {
+	_, variable := s.actionsVariable(w, r)
+	if variable == nil {
+		return
+	}
+	patch := map[string]any{}
+	if err := decodeBody(r, &patch); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if value, ok := patch["value"].(string); ok {
+		variable.Value = value
+	}
+	variable.UpdatedAt = now()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteActionsVariable(w http.ResponseWriter, r *http.Request) {
+	if scope, variable := s.actionsVariable(w, r); variable != nil {
+		delete(s.actionsVariables[scope], variable.Name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Webhooks
+
+func (s *Server) hook(w http.ResponseWriter, r *http.Request) *hook {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return nil
+	}
+	id, _ := strconv.ParseInt(r.PathValue("hookId"), 10, 64)
+	h, ok := s.hooks[id]
+	if !ok || h.repoId != repo.GetID() {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil
+	}
+	return h
+}
+
+// hookJSON returns a webhook with its secret obfuscated the way GitHub does
+func hookJSON(h *hook) *github.Hook {
+	out := *h.Hook
+	config := *h.Config
+	if h.secret != "" {
+		config.Secret = github.String("********")
+	}
+	out.Config = &config
+	return &out
+}
+
+func (s *Server) listHooks(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	hooks := []*github.Hook{}
+	for _, h := range s.hooks {
+		if h.repoId == repo.GetID() {
+			hooks = append(hooks, hookJSON(h))
+		}
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].GetID() < hooks[j].GetID() })
+	paginate(w, r, hooks)
+}
+
+func (s *Server) createHook(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	create := &github.Hook{}
+	if err := decodeBody(r, create); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if create.GetConfig().GetURL() == "" {
+		writeValidationError(w, "Validation Failed", "Hook", "url", "url can't be blank")
+		return
+	}
+	for _, h := range s.hooks {
+		if h.repoId == repo.GetID() && h.Config.GetURL() == create.Config.GetURL() {
+			writeValidationError(w, "Validation Failed", "Hook", "url", "Hook already exists on this repository")
+			return
+		}
+	}
+	id := s.newId()
+	config := *create.Config
+	secret := config.GetSecret()
+	config.Secret = nil
+	if config.ContentType == nil {
+		config.ContentType = github.String("form")
+	}
+	if config.InsecureSSL == nil {
+		config.InsecureSSL = github.String("0")
+	}
+	if len(create.Events) == 0 {
+		create.Events = []string{"push"}
+	}
+	if create.Active == nil {
+		create.Active = github.Bool(true)
+	}
+	create.ID = github.Int64(id)
+	create.Type = github.String("Repository")
+	create.Name = github.String("web")
+	create.Config = &config
+	create.CreatedAt = now()
+	create.UpdatedAt = create.CreatedAt
+	h := &hook{Hook: create, repoId: repo.GetID(), secret: secret}
+	s.hooks[id] = h
+	writeJSON(w, http.StatusCreated, hookJSON(h))
+}
+
+func (s *Server) getHook(w http.ResponseWriter, r *http.Request) {
+	if h := s.hook(w, r); h != nil {
+		writeJSON(w, http.StatusOK, hookJSON(h))
+	}
+}
+
+func (s *Server) editHook(w http.ResponseWriter, r *http.Request) {
+	h := s.hook(w, r)
+	if h == nil {
+		return
+	}
+	var body struct {
+		Config map[string]any `json:"config"`
+		Events []string       `json:"events"`
+		Active *bool          `json:"active"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	// the secret is kept unless the config sets it
+	if secret, ok := body.Config["secret"].(string); ok {
+		h.secret = secret
+	}
+	delete(body.Config, "secret")
+	if err := merge(h.Config, body.Config); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if body.Events != nil {
+		h.Events = body.Events
+	}
+	if body.Active != nil {
+		h.Active = body.Active
+	}
+	h.UpdatedAt = now()
+	writeJSON(w, http.StatusOK, hookJSON(h))
+}
+
+func (s *Server) deleteHook(w http.ResponseWriter, r *http.Request) {
+	if h := s.hook(w, r); h != nil {
+		delete(s.hooks, h.GetID())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// the fake never delivers webhooks
+func (s *Server) listHookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if h := s.hook(w, r); h != nil {
+		writeJSON(w, http.StatusOK, []*github.HookDelivery{})
+	}
+}
+
+// Deploy keys
+
+func (s *Server) deployKey(w http.ResponseWriter, r *http.Request) *deployKey {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return nil
+	}
+	id, _ := strconv.ParseInt(r.PathValue("keyId"), 10, 64)
+	key, ok := s.deployKeys[id]
+	if !ok || key.repoId != repo.GetID() {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil
+	}
+	return key
+}
+
+func (s *Server) listDeployKeys(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	keys := []*github.Key{}
+	for _, key := range s.deployKeys {
+		if key.repoId == repo.GetID() {
+			keys = append(keys, key.Key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].GetID() < keys[j].GetID() })
+	paginate(w, r, keys)
+}
+
+func (s *Server) createDeployKey(w http.ResponseWriter, r *http.Request) {
+	repo := s.repository(w, r)
+	if repo == nil {
+		return
+	}
+	create := &github.Key{}
+	if err := decodeBody(r, create); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	// GitHub keeps the type and key material of an OpenSSH public key, dropping its comment
+	fields := strings.Fields(create.GetKey())
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ssh-") && !strings.HasPrefix(fields[0], "ecdsa-") {
+		writeValidationError(w, "Validation Failed", "PublicKey", "key",
+			"key is invalid. You must supply a key in OpenSSH public key format")
+		return
+	}
+	material := fields[0] + " " + fields[1]
+	for _, key := range s.deployKeys {
+		if key.GetKey() == material {
+			writeValidationError(w, "Validation Failed", "PublicKey", "key", "key is already in use")
+			return
+		}
+	}
+	id := s.newId()
+	key := &github.Key{
+		ID:        github.Int64(id),
+		Key:       github.String(material),
+		Title:     create.Title,
+		ReadOnly:  github.Bool(create.GetReadOnly()),
+		Verified:  github.Bool(true),
+		CreatedAt: now(),
+		AddedBy:   github.String(s.viewer),
+	}
+	s.deployKeys[id] = &deployKey{Key: key, repoId: repo.GetID()}
+	writeJSON(w, http.StatusCreated, key)
+}
+
+func (s *Server) getDeployKey(w http.ResponseWriter, r *http.Request) {
+	if key := s.deployKey(w, r); key != nil {
+		writeJSON(w, http.StatusOK, key.Key)
+	}
+}
+
+func (s *Server) deleteDeployKey(w http.ResponseWriter, r *http.Request) {
+	if key := s.deployKey(w, r); key != nil {
+		delete(s.deployKeys, key.GetID())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,493 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+)
+
+type branchProtectionRule struct {
+	id     int64
+	nodeId string
+	repoId int64
+	// scalar and list fields keyed by their GraphQL name
+	fields map[string]any
+	// node IDs of the actors of each allowance connection
+	actors map[string][]string
+	// required status checks as context and app node ID
+	statusChecks []statusCheck
+}
+
+type statusCheck struct {
+	context string
+	appId   string
+}
+
+// branchProtectionRuleDefaults are the values of fields omitted when a rule is created
+var branchProtectionRuleDefaults = map[string]any{
+	"allowsDeletions":                false,
+	"allowsForcePushes":              false,
+	"blocksCreations":                false,
+	"dismissesStaleReviews":          false,
+	"isAdminEnforced":                false,
+	"lockAllowsFetchAndMerge":        false,
+	"lockBranch":                     false,
+	"requireLastPushApproval":        false,
+	"requiredApprovingReviewCount":   0,
+	"requiredDeploymentEnvironments": []any{},
+	"requiredStatusCheckContexts":    []any{},
+	"requiresApprovingReviews":       false,
+	"requiresCodeOwnerReviews":       false,
+	"requiresCommitSignatures":       false,
+	"requiresConversationResolution": false,
+	"requiresDeployments":            false,
+	"requiresLinearHistory":          false,
+	"requiresStatusChecks":           false,
+	"requiresStrictStatusChecks":     false,
+	"restrictsPushes":                false,
+	"restrictsReviewDismissals":      false,
+}
+
+// allowanceInputs maps the actor ID inputs of the branch protection rule mutations to their connections
+var allowanceInputs = map[string]string{
+	"bypassForcePushActorIds":   "bypassForcePushAllowances",
+	"bypassPullRequestActorIds": "bypassPullRequestAllowances",
+	"pushActorIds":              "pushAllowances",
+	"reviewDismissalActorIds":   "reviewDismissalAllowances",
+}
+
+// graphqlPermissions maps REST repository permissions to the GraphQL RepositoryPermission enum
+var graphqlPermissions = map[string]string{
+	"pull":     "READ",
+	"triage":   "TRIAGE",
+	"push":     "WRITE",
+	"maintain": "MAINTAIN",
+	"admin":    "ADMIN",
+}
+
+func nodeNotFound(id string) *graphqlError {
+	return notFound(fmt.Sprintf("Could not resolve to a node with the global id of '%s'", id))
+}
+
+// parseNodeId returns the kind and database ID encoded in a node ID
+func parseNodeId(id string) (string, int64, bool) {
+	b, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return "", 0, false
+	}
+	kind, dbId, ok := strings.Cut(string(b), "_")
+	if !ok {
+		return "", 0, false
+	}
+	n, err := strconv.ParseInt(dbId, 10, 64)
+	return kind, n, err == nil
+}
+
+// node returns the view of the object with a node ID, or nil if there is none
+func (s *Server) node(id string) object {
+	kind, dbId, ok := parseNodeId(id)
+	if !ok {
+		return nil
+	}
+	switch kind {
+	case "O":
+		if org, ok := s.orgs[dbId]; ok {
+			return s.organizationView(org)
+		}
+	case "U":
+		if user, ok := s.users[dbId]; ok {
+			return userView(user)
+		}
+	case "A":
+		if a, ok := s.apps[dbId]; ok {
+			return appView(a)
+		}
+	case "T":
+		if t, ok := s.teams[dbId]; ok {
+			return s.teamView(t)
+		}
+	case "R":
+		if repo, ok := s.repos[dbId]; ok {
+			return s.repositoryView(repo)
+		}
+	case "B":
+		if bp, ok := s.bps[dbId]; ok {
+			return s.branchProtectionRuleView(bp)
+		}
+	}
+	return nil
+}
+
+func (s *Server) queryRoot() object {
+	return object{
+		"viewer": object{
+			"__typename": "User",
+			"login":      s.viewer,
+		},
+		"node": resolver(func(args map[string]any) (any, error) {
+			id, _ := args["id"].(string)
+			if node := s.node(id); node != nil {
+				return node, nil
+			}
+			return nil, nodeNotFound(id)
+		}),
+		"organization": resolver(func(args map[string]any) (any, error) {
+			login, _ := args["login"].(string)
+			if org := s.orgByLogin(login); org != nil {
+				return s.organizationView(org), nil
+			}
+			return nil, notFound(fmt.Sprintf("Could not resolve to an Organization with the login of '%s'.", login))
+		}),
+		"repository": resolver(func(args map[string]any) (any, error) {
+			owner, _ := args["owner"].(string)
+			name, _ := args["name"].(string)
+			if repo := s.repoByName(owner, name); repo != nil {
+				return s.repositoryView(repo), nil
+			}
+			return nil, notFound(fmt.Sprintf("Could not resolve to a Repository with the name '%s/%s'.", owner, name))
+		}),
+	}
+}
+
+// Views
+
+func (s *Server) organizationView(org *github.Organization) object {
+	return object{
+		"__typename": "Organization",
+		"id":         org.GetNodeID(),
+		"databaseId": org.GetID(),
+		"login":      org.GetLogin(),
+		"name":       org.GetName(),
+		"team": resolver(func(args map[string]any) (any, error) {
+			slug, _ := args["slug"].(string)
+			if t := s.teamBySlug(org.GetID(), slug); t != nil {
+				return s.teamView(t), nil
+			}
+			return nil, nil
+		}),
+		"membersWithRole": resolver(func(args map[string]any) (any, error) {
+			logins := sortedKeys(s.orgMembers[org.GetID()])
+			nodes := []object{}
+			roles := []string{}
+			for _, login := range logins {
+				if user := s.userByLogin(login); user != nil {
+					nodes = append(nodes, userView(user))
+					roles = append(roles, strings.ToUpper(s.orgMembers[org.GetID()][login]))
+				}
+			}
+			return connection(nodes, func(i int) object {
+				return object{"role": roles[i]}
+			})(args)
+		}),
+	}
+}
+
+func userView(user *github.User) object {
+	return object{
+		"__typename": "User",
+		"id":         user.GetNodeID(),
+		"databaseId": user.GetID(),
+		"login":      user.GetLogin(),
+	}
+}
+
+func appView(a *app) object {
+	return object{
+		"__typename": "App",
+		"id":         a.nodeId,
+		"databaseId": a.id,
+		"slug":       a.slug,
+	}
+}
+
+func (s *Server) teamView(t *team) object {
+	return object{
+		"__typename": "Team",
+		"id":         t.GetNodeID(),
+		"databaseId": t.GetID(),
+		"slug":       t.GetSlug(),
+		"name":       t.GetName(),
+		"organization": resolver(func(map[string]any) (any, error) {
+			return s.organizationView(s.orgs[t.orgId]), nil
+		}),
+		"repositories": resolver(func(args map[string]any) (any, error) {
+			ids := sortedKeys(t.repos)
+			nodes := []object{}
+			permissions := []string{}
+			for _, id := range ids {
+				if repo, ok := s.repos[id]; ok {
+					nodes = append(nodes, s.repositoryView(repo))
+					permissions = append(permissions, graphqlPermissions[t.repos[id]])
+				}
+			}
+			return connection(nodes, func(i int) object {
+				return object{"permission": permissions[i]}
+			})(args)
+		}),
+		"members": resolver(func(args map[string]any) (any, error) {
+			// child teams aren't expanded, so every membership is immediate
+			logins := sortedKeys(t.members)
+			nodes := []object{}
+			roles := []string{}
+			for _, login := range logins {
+				if user := s.userByLogin(login); user != nil {
+					nodes = append(nodes, userView(user))
+					roles = append(roles, strings.ToUpper(t.members[login]))
+				}
+			}
+			return connection(nodes, func(i int) object {
+				return object{"role": roles[i]}
+			})(args)
+		}),
+	}
+}
+
+func (s *Server) repositoryView(repo *github.Repository) object {
+	return object{
+		"__typename":    "Repository",
+		"id":            repo.GetNodeID(),
+		"databaseId":    repo.GetID(),
+		"name":          repo.GetName(),
+		"nameWithOwner": repo.GetFullName(),
+		"isArchived":    repo.GetArchived(),
+		"owner": object{
+			"__typename": repo.GetOwner().GetType(),
+			"id":         repo.GetOwner().GetNodeID(),
+			"login":      repo.GetOwner().GetLogin(),
+		},
+		"branchProtectionRules": resolver(func(args map[string]any) (any, error) {
+			nodes := []object{}
+			for _, id := range sortedKeys(s.bps) {
+				if bp := s.bps[id]; bp.repoId == repo.GetID() {
+					nodes = append(nodes, s.branchProtectionRuleView(bp))
+				}
+			}
+			return connection(nodes, nil)(args)
+		}),
+	}
+}
+
+func (s *Server) branchProtectionRuleView(bp *branchProtectionRule) object {
+	view := object{
+		"__typename": "BranchProtectionRule",
+		"id":         bp.nodeId,
+		"repository": resolver(func(map[string]any) (any, error) {
+			return s.repositoryView(s.repos[bp.repoId]), nil
+		}),
+	}
+	for k, v := range bp.fields {
+		view[k] = v
+	}
+	checks := []object{}
+	for _, check := range bp.statusChecks {
+		var a any
+		if kind, id, _ := parseNodeId(check.appId); kind == "A" && s.apps[id] != nil {
+			a = appView(s.apps[id])
+		}
+		checks = append(checks, object{"context": check.context, "app": a})
+	}
+	view["requiredStatusChecks"] = checks
+	for _, allowance := range allowanceInputs {
+		nodes := []object{}
+		for _, actorId := range bp.actors[allowance] {
+			// actors that have been deleted drop out of the allowance
+			if actor := s.node(actorId); actor != nil {
+				nodes = append(nodes, object{"actor": actor})
+			}
+		}
+		view[allowance] = connection(nodes, nil)
+	}
+	return view
+}
+
+func sortedKeys[K int64 | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// Mutations
+
+func (s *Server) mutationRoot() object {
+	return object{
+		"createBranchProtectionRule": resolver(s.createBranchProtectionRule),
+		"updateBranchProtectionRule": resolver(s.updateBranchProtectionRule),
+		"deleteBranchProtectionRule": resolver(s.deleteBranchProtectionRule),
+	}
+}
+
+func mutationInput(args map[string]any) map[string]any {
+	input, _ := args["input"].(map[string]any)
+	if input == nil {
+		input = map[string]any{}
+	}
+	return input
+}
+
+func (s *Server) createBranchProtectionRule(args map[string]any) (any, error) {
+	input := mutationInput(args)
+	repositoryId, _ := input["repositoryId"].(string)
+	kind, repoId, _ := parseNodeId(repositoryId)
+	repo := s.repos[repoId]
+	if kind != "R" || repo == nil {
+		return nil, nodeNotFound(repositoryId)
+	}
+	if repo.GetArchived() {
+		return nil, errorf("Repository was archived so is read-only.")
+	}
+
+	id := s.newId()
+	bp := &branchProtectionRule{
+		id:     id,
+		nodeId: nodeId("B", id),
+		repoId: repoId,
+		fields: map[string]any{},
+		actors: map[string][]string{},
+	}
+	for k, v := range branchProtectionRuleDefaults {
+		bp.fields[k] = v
+	}
+	if err := s.applyBranchProtectionRuleInput(bp, input); err != nil {
+		return nil, err
+	}
+	if _, ok := input["pattern"].(string); !ok {
+		return nil, errorf("Argument 'pattern' on InputObject 'CreateBranchProtectionRuleInput' is required.")
+	}
+	s.bps[id] = bp
+	return object{
+		"branchProtectionRule": s.branchProtectionRuleView(bp),
+		"clientMutationId":     input["clientMutationId"],
+	}, nil
+}
+
+func (s *Server) branchProtectionRuleInput(input map[string]any) (*branchProtectionRule, error) {
+	id, _ := input["branchProtectionRuleId"].(string)
+	kind, dbId, _ := parseNodeId(id)
+	bp := s.bps[dbId]
+	if kind != "B" || bp == nil {
+		return nil, nodeNotFound(id)
+	}
+	return bp, nil
+}
+
+func (s *Server) updateBranchProtectionRule(args map[string]any) (any, error) {
+	input := mutationInput(args)
+	bp, err := s.branchProtectionRuleInput(input)
+	if err != nil {
+		return nil, err
+	}
+	if s.repos[bp.repoId].GetArchived() {
+		return nil, errorf("Repository was archived so is read-only.")
+	}
+	if err := s.applyBranchProtectionRuleInput(bp, input); err != nil {
+		return nil, err
+	}
+	return object{
+		"branchProtectionRule": s.branchProtectionRuleView(bp),
+		"clientMutationId":     input["clientMutationId"],
+	}, nil
+}
+
+func (s *Server) deleteBranchProtectionRule(args map[string]any) (any, error) {
+	input := mutationInput(args)
+	bp, err := s.branchProtectionRuleInput(input)
+	if err != nil {
+		return nil, err
+	}
+	delete(s.bps, bp.id)
+	return object{"clientMutationId": input["clientMutationId"]}, nil
+}
+
+// applyBranchProtectionRuleInput validates the fields present in a mutation input and sets them on
+// a rule. Nothing is changed if the input is invalid.
+func (s *Server) applyBranchProtectionRuleInput(bp *branchProtectionRule, input map[string]any) error {
+	if pattern, ok := input["pattern"].(string); ok {
+		if pattern == "" {
+			return errorf("Pattern can't be blank")
+		}
+		for _, other := range s.bps {
+			if other.id != bp.id && other.repoId == bp.repoId && other.fields["pattern"] == pattern {
+				return errorf("Name already protected: %s", pattern)
+			}
+		}
+	}
+	actors := map[string][]string{}
+	for inputKey, allowance := range allowanceInputs {
+		ids, ok := input[inputKey].([]any)
+		if !ok {
+			continue
+		}
+		actors[allowance] = []string{}
+		for _, v := range ids {
+			id, _ := v.(string)
+			kind, _, _ := parseNodeId(id)
+			if s.node(id) == nil || (kind != "U" && kind != "T" && kind != "A") {
+				return nodeNotFound(id)
+			}
+			actors[allowance] = append(actors[allowance], id)
+		}
+	}
+	var checks []statusCheck
+	if list, ok := input["requiredStatusChecks"].([]any); ok {
+		checks = []statusCheck{}
+		for _, v := range list {
+			check, _ := v.(map[string]any)
+			context, _ := check["context"].(string)
+			appId, _ := check["appId"].(string)
+			if appId != "" {
+				if kind, id, _ := parseNodeId(appId); kind != "A" || s.apps[id] == nil {
+					return nodeNotFound(appId)
+				}
+			}
+			checks = append(checks, statusCheck{context: context, appId: appId})
+		}
+	}
+
+	for k, v := range input {
+		if _, ok := branchProtectionRuleDefaults[k]; (ok || k == "pattern") && v != nil {
+			bp.fields[k] = v
+		}
+	}
+	for allowance, ids := range actors {
+		bp.actors[allowance] = ids
+	}
+	if checks != nil {
+		bp.statusChecks = checks
+		// as on GitHub, requiredStatusCheckContexts lists the context of every required check
+		contexts := []any{}
+		for _, check := range checks {
+			contexts = append(contexts, check.context)
+		}
+		bp.fields["requiredStatusCheckContexts"] = contexts
+	} else if contexts, ok := input["requiredStatusCheckContexts"].([]any); ok {
+		bp.statusChecks = []statusCheck{}
+		for _, context := range contexts {
+			c, _ := context.(string)
+			bp.statusChecks = append(bp.statusChecks, statusCheck{context: c})
+		}
+	}
+	return nil
+}
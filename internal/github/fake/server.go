@@ -0,0 +1,428 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake implements an in-memory GitHub API server covering the REST and GraphQL endpoints
+// used by internal/github, so that controllers can be tested without GitHub credentials.
+package fake
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v60/github"
+	"golang.org/x/crypto/nacl/box"
+
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+// Server is a fake GitHub API backed by an in-memory store. Organizations, users and apps must be
+// added before use, while teams, repositories and the resources of repositories are created through the API.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	nextId int64
+	// login of the authenticated user, see SetViewer
+	viewer string
+	// whether users that aren't organization members are invited, see SetInvitations
+	invitations bool
+
+	orgs         map[int64]*github.Organization
+	users        map[int64]*github.User
+	apps         map[int64]*app
+	teams        map[int64]*team
+	repos        map[int64]*github.Repository
+	bps          map[int64]*branchProtectionRule
+	rulesets     map[int64]*ruleset
+	environments map[int64]*environment
+	hooks        map[int64]*hook
+	deployKeys   map[int64]*deployKey
+
+	// organization memberships and pending organization invitations keyed by organization ID, then login
+	orgMembers     map[int64]map[string]string
+	orgInvitations map[int64]map[string]*orgInvitation
+	// direct collaborator permissions and pending collaborator invitations keyed by repository ID, then login
+	collaborators   map[int64]map[string]string
+	repoInvitations map[int64]map[string]*repoInvitation
+	// issue labels keyed by repository ID, then lowercase name
+	labels map[int64]map[string]*github.Label
+	// IDs of renamed or transferred repositories keyed by their former lowercase full name
+	repoRedirects map[string]int64
+
+	// actions secrets and variables keyed by scope, then name
+	actionsSecrets   map[actionsScope]map[string]*actionsSecret
+	actionsVariables map[actionsScope]map[string]*github.ActionsVariable
+	// the key pair actions secrets are encrypted with, shared by all scopes
+	actionsPublicKey  *[32]byte
+	actionsPrivateKey *[32]byte
+}
+
+type app struct {
+	id     int64
+	nodeId string
+	slug   string
+}
+
+type team struct {
+	*github.Team
+	orgId int64
+	// repository permissions keyed by repository ID
+	repos map[int64]string
+	// team memberships keyed by login
+	members map[string]string
+	// pending team invitations keyed by login
+	invitations map[string]*teamInvitation
+}
+
+type teamInvitation struct {
+	id   int64
+	role string
+}
+
+type orgInvitation struct {
+	id int64
+	// "direct_member" or "admin", as GitHub reports the role of invitations
+	role string
+}
+
+type repoInvitation struct {
+	id         int64
+	permission string
+}
+
+type ruleset struct {
+	*github.Ruleset
+	repoId int64
+}
+
+type environment struct {
+	id                int64
+	repoId            int64
+	name              string
+	waitTimer         int
+	preventSelfReview bool
+	canAdminsBypass   bool
+	reviewers         []*github.EnvReviewers
+	branchPolicy      *github.BranchPolicy
+	// deployment branch and tag policies keyed by ID
+	policies  map[int64]*github.DeploymentBranchPolicy
+	createdAt *github.Timestamp
+	updatedAt *github.Timestamp
+}
+
+type hook struct {
+	*github.Hook
+	repoId int64
+	// GitHub never returns the secret of a webhook
+	secret string
+}
+
+type deployKey struct {
+	*github.Key
+	repoId int64
+}
+
+// actionsScope is the repository, or environment of a repository, that actions secrets and variables belong to
+type actionsScope struct {
+	repoId      int64
+	environment string
+}
+
+type actionsSecret struct {
+	*github.Secret
+	encryptedValue string
+}
+
+// NewServer starts a fake GitHub API server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		orgs:           map[int64]*github.Organization{},
+		users:          map[int64]*github.User{},
+		apps:           map[int64]*app{},
+		teams:          map[int64]*team{},
+		repos:          map[int64]*github.Repository{},
+		bps:            map[int64]*branchProtectionRule{},
+		orgMembers:     map[int64]map[string]string{},
+		orgInvitations: map[int64]map[string]*orgInvitation{},
+		viewer:         "github-operator[bot]",
+
+		collaborators:   map[int64]map[string]string{},
+		repoInvitations: map[int64]map[string]*repoInvitation{},
+		labels:          map[int64]map[string]*github.Label{},
+
+		rulesets:         map[int64]*ruleset{},
+		environments:     map[int64]*environment{},
+		hooks:            map[int64]*hook{},
+		deployKeys:       map[int64]*deployKey{},
+		actionsSecrets:   map[actionsScope]map[string]*actionsSecret{},
+		actionsVariables: map[actionsScope]map[string]*github.ActionsVariable{},
+		repoRedirects:    map[string]int64{},
+	}
+	publicKey, privateKey, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("generating actions key pair: %v", err))
+	}
+	s.actionsPublicKey, s.actionsPrivateKey = publicKey, privateKey
+	mux := http.NewServeMux()
+	s.registerRESTHandlers(mux)
+	mux.HandleFunc("POST /api/graphql", s.handleGraphQL)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "Not Found")
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Client returns an internal/github client that talks to the server.
+func (s *Server) Client() (*gh.Client, error) {
+	return gh.NewClient(
+		gh.WithHttpClient(s.Server.Client()),
+		gh.WithEnterpriseURLs(s.URL, "", ""),
+	)
+}
+
+// AddOrganization adds an organization and returns it.
+func (s *Server) AddOrganization(login string) *github.Organization {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.newId()
+	org := &github.Organization{
+		ID:                    github.Int64(id),
+		NodeID:                github.String(nodeId("O", id)),
+		Login:                 github.String(login),
+		Type:                  github.String("Organization"),
+		DefaultRepoPermission: github.String("read"),
+	}
+	s.orgs[id] = org
+	s.orgMembers[id] = map[string]string{}
+	s.orgInvitations[id] = map[string]*orgInvitation{}
+	return org
+}
+
+// AddUser adds a user and returns it.
+func (s *Server) AddUser(login string) *github.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.newId()
+	user := &github.User{
+		ID:     github.Int64(id),
+		NodeID: github.String(nodeId("U", id)),
+		Login:  github.String(login),
+		Type:   github.String("User"),
+	}
+	s.users[id] = user
+	return user
+}
+
+// AddApp adds a GitHub App that can be granted branch protection allowances and returns its node ID.
+func (s *Server) AddApp(slug string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.newId()
+	s.apps[id] = &app{id: id, nodeId: nodeId("A", id), slug: slug}
+	return s.apps[id].nodeId
+}
+
+// AddOrganizationMember makes an existing user a member of an organization with role member or admin.
+func (s *Server) AddOrganizationMember(org, login, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.orgByLogin(org)
+	if o == nil || s.userByLogin(login) == nil {
+		return fmt.Errorf("no organization %s or user %s", org, login)
+	}
+	s.orgMembers[o.GetID()][login] = role
+	return nil
+}
+
+// SetViewer sets the login that requests are authenticated as. By default requests are
+// authenticated as a GitHub App bot.
+func (s *Server) SetViewer(login string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.viewer = login
+}
+
+// SetInvitations sets whether adding users that aren't organization members to the organization, a team
+// or as repository collaborators invites them, as GitHub does. By default they join immediately.
+func (s *Server) SetInvitations(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.invitations = enabled
+}
+
+// AcceptInvitations makes the users with pending invitations to the organization with the given login
+// or its teams join the organization and their teams, and the users invited to its repositories
+// their collaborators.
+func (s *Server) AcceptInvitations(org string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.orgByLogin(org)
+	if o == nil {
+		return
+	}
+	for login, invitation := range s.orgInvitations[o.GetID()] {
+		s.orgMembers[o.GetID()][login] = "member"
+		if invitation.role == "admin" {
+			s.orgMembers[o.GetID()][login] = "admin"
+		}
+	}
+	s.orgInvitations[o.GetID()] = map[string]*orgInvitation{}
+	for repoId, invitations := range s.repoInvitations {
+		if s.repos[repoId].GetOwner().GetID() != o.GetID() {
+			continue
+		}
+		for login, invitation := range invitations {
+			s.collaborators[repoId][login] = invitation.permission
+		}
+		s.repoInvitations[repoId] = map[string]*repoInvitation{}
+	}
+	for _, t := range s.teams {
+		if t.orgId != o.GetID() {
+			continue
+		}
+		for login, invitation := range t.invitations {
+			if _, ok := s.orgMembers[o.GetID()][login]; !ok {
+				s.orgMembers[o.GetID()][login] = "member"
+			}
+			t.members[login] = invitation.role
+			delete(t.invitations, login)
+		}
+	}
+}
+
+// ActionsSecret decrypts the value of a repository actions secret, or of an environment secret if
+// env isn't empty. It returns false if there is no such secret.
+func (s *Server) ActionsSecret(owner, repo, env, name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.repoByName(owner, repo)
+	if r == nil {
+		return "", false
+	}
+	scope := actionsScope{repoId: r.GetID()}
+	if env != "" {
+		e := s.environmentByName(r.GetID(), env)
+		if e == nil {
+			return "", false
+		}
+		scope.environment = e.name
+	}
+	secret, ok := s.actionsSecrets[scope][strings.ToUpper(name)]
+	if !ok {
+		return "", false
+	}
+	sealed, err := base64.StdEncoding.DecodeString(secret.encryptedValue)
+	if err != nil {
+		return "", false
+	}
+	value, ok := box.OpenAnonymous(nil, sealed, s.actionsPublicKey, s.actionsPrivateKey)
+	return string(value), ok
+}
+
+// WebhookSecret returns the secret of a repository webhook, which the API never returns. It returns
+// false if there is no such webhook.
+func (s *Server) WebhookSecret(owner, repo string, id int64) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.repoByName(owner, repo)
+	h, ok := s.hooks[id]
+	if r == nil || !ok || h.repoId != r.GetID() {
+		return "", false
+	}
+	return h.secret, true
+}
+
+func (s *Server) newId() int64 {
+	s.nextId++
+	return s.nextId
+}
+
+// nodeId returns a global node ID that encodes the kind and database ID of an object
+func nodeId(kind string, id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s_%d", kind, id)))
+}
+
+func (s *Server) orgByLogin(login string) *github.Organization {
+	for _, org := range s.orgs {
+		if strings.EqualFold(org.GetLogin(), login) {
+			return org
+		}
+	}
+	return nil
+}
+
+func (s *Server) userByLogin(login string) *github.User {
+	for _, user := range s.users {
+		if strings.EqualFold(user.GetLogin(), login) {
+			return user
+		}
+	}
+	return nil
+}
+
+func (s *Server) teamBySlug(orgId int64, slug string) *team {
+	for _, t := range s.teams {
+		if t.orgId == orgId && strings.EqualFold(t.GetSlug(), slug) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Server) repoByName(owner, name string) *github.Repository {
+	for _, repo := range s.repos {
+		if strings.EqualFold(repo.GetOwner().GetLogin(), owner) && strings.EqualFold(repo.GetName(), name) {
+			return repo
+		}
+	}
+	return nil
+}
+
+func (s *Server) environmentByName(repoId int64, name string) *environment {
+	for _, env := range s.environments {
+		if env.repoId == repoId && strings.EqualFold(env.name, name) {
+			return env
+		}
+	}
+	return nil
+}
+
+// account returns the organization or user with a login as a repository owner
+func (s *Server) account(login string) *github.User {
+	if org := s.orgByLogin(login); org != nil {
+		return &github.User{
+			ID:     org.ID,
+			NodeID: org.NodeID,
+			Login:  org.Login,
+			Type:   github.String("Organization"),
+		}
+	}
+	return s.userByLogin(login)
+}
+
+var invalidSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug converts a team name into its slug the way GitHub does
+func slug(name string) string {
+	return strings.Trim(invalidSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
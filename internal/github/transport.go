@@ -18,6 +18,9 @@ package github
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net/http"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
@@ -36,14 +39,51 @@ func AuthRoundTripperFromToken(ctx context.Context, base http.RoundTripper, toke
 	return &tr, nil
 }
 
-func AuthRoundTripperFromAppCredentials(ctx context.Context, base http.RoundTripper, appId, installationId int64, pKey []byte) (http.RoundTripper, error) {
+// AuthRoundTripperFromAppCredentials authenticates as a GitHub App installation. If baseURL is set,
+// installation tokens are requested from the GitHub Enterprise Server instance at baseURL.
+func AuthRoundTripperFromAppCredentials(ctx context.Context, base http.RoundTripper, baseURL string, appId, installationId int64, pKey []byte) (http.RoundTripper, error) {
 	tr, err := ghinstallation.New(base, appId, installationId, pKey)
 	if err != nil {
 		return nil, err
 	}
+	if baseURL != "" {
+		tr.BaseURL = EnterpriseAPIURL(baseURL)
+	}
+	return tr, nil
+}
+
+// AppRoundTripper authenticates as the GitHub App itself rather than one of its installations,
+// which is needed to list the App's installations.
+func AppRoundTripper(ctx context.Context, base http.RoundTripper, appId int64, pKey []byte) (http.RoundTripper, error) {
+	tr, err := ghinstallation.NewAppsTransport(base, appId, pKey)
+	if err != nil {
+		return nil, err
+	}
 	return tr, nil
 }
 
+// CABundleRoundTripper trusts the PEM encoded certificates in caBundle in addition to the
+// system roots, e.g. for a GitHub Enterprise Server instance with a private CA.
+func CABundleRoundTripper(ctx context.Context, base http.RoundTripper, caBundle []byte) (http.RoundTripper, error) {
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("can't add a CA bundle to %T", base)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("CA bundle contains no PEM encoded certificates")
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.RootCAs = pool
+	return transport, nil
+}
+
 func RateLimitRoundTripper(ctx context.Context, base http.RoundTripper, opts ...github_ratelimit.Option) (http.RoundTripper, error) {
 	tr, err := github_ratelimit.NewRateLimitWaiter(base, opts...)
 	if err != nil {
@@ -0,0 +1,442 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importer generates resource manifests from an existing GitHub organization so that it
+// can be brought under management of the operator.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v60/github"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+// GitHubRequester is the read-only subset of internal/github.Client used to enumerate an organization.
+type GitHubRequester interface {
+	GetOrganization(ctx context.Context, login string) (*github.Organization, error)
+	GetOrganizationTeams(ctx context.Context, org string) ([]*github.Team, error)
+	GetTeamRepositoryPermissions(ctx context.Context, org, slug string) ([]*gh.TeamRepositoryPermission, error)
+	GetTeamMemberships(ctx context.Context, org, slug string) ([]*gh.TeamMembership, error)
+	GetTeamPendingInvitations(ctx context.Context, org, slug string) ([]*github.Invitation, error)
+	GetTeamMembership(ctx context.Context, org, slug, login string) (*github.Membership, error)
+	GetOrganizationRepositories(ctx context.Context, org string) ([]*github.Repository, error)
+	GetRepositoryByName(ctx context.Context, owner string, name string) (*github.Repository, error)
+	GetRepositoryBranchProtections(ctx context.Context, repositoryOwner, repositoryName string) ([]*gh.BranchProtection, error)
+}
+
+// Importer enumerates an organization and converts it into Organization, Team, Repository and
+// BranchProtection resources. Only spec fields are populated, so the resources can be applied as-is.
+type Importer struct {
+	GitHubClient GitHubRequester
+	// Namespace of the generated resources. Teams and branch protections reference other
+	// generated resources by name, so they must all be applied to the same namespace.
+	Namespace string
+}
+
+// Import returns the resources for an organization, ordered so that referenced resources come first.
+func (i *Importer) Import(ctx context.Context, org string) ([]client.Object, error) {
+	ghOrg, err := i.GitHubClient.GetOrganization(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("fetching organization %s: %w", org, err)
+	}
+	login := ghOrg.GetLogin()
+	objects := []client.Object{i.organization(ghOrg)}
+
+	ghRepos, err := i.GitHubClient.GetOrganizationRepositories(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ghRepos, func(a, b int) bool { return ghRepos[a].GetName() < ghRepos[b].GetName() })
+	repoNames := newNames()
+	repos := []client.Object{}
+	bps := []client.Object{}
+	bpNames := newNames()
+	for _, listed := range ghRepos {
+		// repositories are listed without their merge settings, so each one is fetched in full
+		ghRepo, err := i.GitHubClient.GetRepositoryByName(ctx, login, listed.GetName())
+		if err != nil {
+			return nil, fmt.Errorf("fetching repository %s/%s: %w", login, listed.GetName(), err)
+		}
+		repo := i.repository(ghRepo, repoNames.unique(ghRepo.GetName()))
+		repos = append(repos, repo)
+
+		ghBps, err := i.GitHubClient.GetRepositoryBranchProtections(ctx, login, ghRepo.GetName())
+		if err != nil {
+			return nil, fmt.Errorf("fetching branch protections of repository %s/%s: %w", login, ghRepo.GetName(), err)
+		}
+		for _, ghBp := range ghBps {
+			bps = append(bps, i.branchProtection(ghBp, repo.Name, bpNames.unique(ghRepo.GetName()+"-"+ghBp.Pattern)))
+		}
+	}
+
+	ghTeams, err := i.GitHubClient.GetOrganizationTeams(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ghTeams, func(a, b int) bool { return ghTeams[a].GetSlug() < ghTeams[b].GetSlug() })
+	teamNames := newNames()
+	teamsBySlug := map[string]string{}
+	for _, ghTeam := range ghTeams {
+		teamsBySlug[ghTeam.GetSlug()] = teamNames.unique(ghTeam.GetSlug())
+	}
+	teams := []client.Object{}
+	for _, ghTeam := range ghTeams {
+		team, err := i.team(ctx, login, ghTeam, teamsBySlug)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	objects = append(objects, teams...)
+	objects = append(objects, repos...)
+	objects = append(objects, bps...)
+	return objects, nil
+}
+
+func (i *Importer) objectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: i.Namespace,
+	}
+}
+
+func (i *Importer) organization(ghOrg *github.Organization) *githubv1alpha1.Organization {
+	return &githubv1alpha1.Organization{
+		TypeMeta:   metav1.TypeMeta{APIVersion: githubv1alpha1.GroupVersion.String(), Kind: "Organization"},
+		ObjectMeta: i.objectMeta(resourceName(ghOrg.GetLogin())),
+		Spec: githubv1alpha1.OrganizationSpec{
+			Login:                                ghOrg.GetLogin(),
+			Name:                                 ghOrg.Name,
+			BillingEmail:                         ghOrg.BillingEmail,
+			Company:                              ghOrg.Company,
+			Email:                                ghOrg.Email,
+			TwitterUsername:                      ghOrg.TwitterUsername,
+			Location:                             ghOrg.Location,
+			Description:                          ghOrg.Description,
+			HasOrganizationProjects:              ghOrg.HasOrganizationProjects,
+			HasRepositoryProjects:                ghOrg.HasRepositoryProjects,
+			DefaultRepositoryPermission:          (*githubv1alpha1.DefaultRepositoryPermission)(ghOrg.DefaultRepoPermission),
+			MembersCanCreateRepositories:         ghOrg.MembersCanCreateRepos,
+			MembersCanCreateInternalRepositories: ghOrg.MembersCanCreateInternalRepos,
+			MembersCanCreatePrivateRepositories:  ghOrg.MembersCanCreatePrivateRepos,
+			MembersCanCreatePublicRepositories:   ghOrg.MembersCanCreatePublicRepos,
+			MembersCanCreatePages:                ghOrg.MembersCanCreatePages,
+			MembersCanCreatePublicPages:          ghOrg.MembersCanCreatePublicPages,
+			MembersCanCreatePrivatePages:         ghOrg.MembersCanCreatePrivatePages,
+			MembersCanForkPrivateRepositories:    ghOrg.MembersCanForkPrivateRepos,
+			WebCommitSignoffRequired:             ghOrg.WebCommitSignoffRequired,
+			Blog:                                 ghOrg.Blog,
+			AdvancedSecurityEnabledForNewRepositories:             ghOrg.AdvancedSecurityEnabledForNewRepos,
+			DependabotAlertsEnabledForNewRepositories:             ghOrg.DependabotAlertsEnabledForNewRepos,
+			DependabotSecurityUpdatesEnabledForNewRepositories:    ghOrg.DependabotSecurityUpdatesEnabledForNewRepos,
+			DependencyGraphEnabledForNewRepositories:              ghOrg.DependencyGraphEnabledForNewRepos,
+			SecretScanningEnabledForNewRepositories:               ghOrg.SecretScanningEnabledForNewRepos,
+			SecretScanningPushProtectionEnabledForNewRepositories: ghOrg.SecretScanningPushProtectionEnabledForNewRepos,
+		},
+	}
+}
+
+func (i *Importer) team(ctx context.Context, org string, ghTeam *github.Team, teamsBySlug map[string]string) (*githubv1alpha1.Team, error) {
+	team := &githubv1alpha1.Team{
+		TypeMeta:   metav1.TypeMeta{APIVersion: githubv1alpha1.GroupVersion.String(), Kind: "Team"},
+		ObjectMeta: i.objectMeta(teamsBySlug[ghTeam.GetSlug()]),
+		Spec: githubv1alpha1.TeamSpec{
+			Organization: org,
+			Name:         ghTeam.GetName(),
+			Description:  ghTeam.Description,
+			Privacy:      (*githubv1alpha1.Privacy)(ghTeam.Privacy),
+		},
+	}
+	// the parent team is referenced by resource rather than by ID, which changes if it is recreated
+	if parent := ghTeam.GetParent(); parent != nil {
+		if name, ok := teamsBySlug[parent.GetSlug()]; ok {
+			team.Spec.ParentTeamRef = &githubv1alpha1.ResourceReference{Name: name}
+		} else {
+			team.Spec.ParentTeamId = parent.ID
+		}
+	}
+
+	trps, err := i.GitHubClient.GetTeamRepositoryPermissions(ctx, org, ghTeam.GetSlug())
+	if err != nil {
+		return nil, fmt.Errorf("fetching repository permissions of team %s: %w", ghTeam.GetSlug(), err)
+	}
+	if len(trps) > 0 {
+		team.Spec.Repositories = map[string]githubv1alpha1.RepositoryPermission{}
+		for _, trp := range trps {
+			team.Spec.Repositories[trp.RepositoryName] = teamRepositoryPermission(trp.Permission)
+		}
+	}
+
+	memberships, err := i.GitHubClient.GetTeamMemberships(ctx, org, ghTeam.GetSlug())
+	if err != nil {
+		return nil, fmt.Errorf("fetching members of team %s: %w", ghTeam.GetSlug(), err)
+	}
+	// the roster is pruned, so an empty team stays empty
+	team.Spec.PruneMembers = github.Bool(true)
+	for _, membership := range memberships {
+		if membership.Role == "maintainer" {
+			team.Spec.Maintainers = append(team.Spec.Maintainers, membership.Login)
+		} else {
+			team.Spec.Members = append(team.Spec.Members, membership.Login)
+		}
+	}
+	// invitations of users missing from the spec would be cancelled
+	invitations, err := i.GitHubClient.GetTeamPendingInvitations(ctx, org, ghTeam.GetSlug())
+	if err != nil {
+		return nil, fmt.Errorf("fetching invitations of team %s: %w", ghTeam.GetSlug(), err)
+	}
+	for _, invitation := range invitations {
+		if invitation.Login == nil {
+			continue
+		}
+		// the role of an invitation is the invitee's organization role, the membership has the team role
+		membership, err := i.GitHubClient.GetTeamMembership(ctx, org, ghTeam.GetSlug(), invitation.GetLogin())
+		if err != nil {
+			return nil, fmt.Errorf("fetching membership of %s in team %s: %w", invitation.GetLogin(), ghTeam.GetSlug(), err)
+		}
+		if membership.GetRole() == "maintainer" {
+			team.Spec.Maintainers = append(team.Spec.Maintainers, invitation.GetLogin())
+		} else {
+			team.Spec.Members = append(team.Spec.Members, invitation.GetLogin())
+		}
+	}
+	return team, nil
+}
+
+func (i *Importer) repository(ghRepo *github.Repository, name string) *githubv1alpha1.Repository {
+	repo := &githubv1alpha1.Repository{
+		TypeMeta:   metav1.TypeMeta{APIVersion: githubv1alpha1.GroupVersion.String(), Kind: "Repository"},
+		ObjectMeta: i.objectMeta(name),
+		Spec: githubv1alpha1.RepositorySpec{
+			Name:                     ghRepo.GetName(),
+			Owner:                    ghRepo.GetOwner().GetLogin(),
+			Description:              ghRepo.Description,
+			Homepage:                 ghRepo.Homepage,
+			DefaultBranch:            ghRepo.DefaultBranch,
+			AllowRebaseMerge:         ghRepo.AllowRebaseMerge,
+			AllowUpdateBranch:        ghRepo.AllowUpdateBranch,
+			AllowSquashMerge:         ghRepo.AllowSquashMerge,
+			AllowMergeCommit:         ghRepo.AllowMergeCommit,
+			AllowAutoMerge:           ghRepo.AllowAutoMerge,
+			AllowForking:             ghRepo.AllowForking,
+			WebCommitSignoffRequired: ghRepo.WebCommitSignoffRequired,
+			DeleteBranchOnMerge:      ghRepo.DeleteBranchOnMerge,
+			SquashMergeCommitTitle:   (*githubv1alpha1.SquashMergeCommitTitle)(ghRepo.SquashMergeCommitTitle),
+			SquashMergeCommitMessage: (*githubv1alpha1.SquashMergeCommitMessage)(ghRepo.SquashMergeCommitMessage),
+			MergeCommitTitle:         (*githubv1alpha1.MergeCommitTitle)(ghRepo.MergeCommitTitle),
+			MergeCommitMessage:       (*githubv1alpha1.MergeCommitMessage)(ghRepo.MergeCommitMessage),
+			Topics:                   ghRepo.Topics,
+			Archived:                 ghRepo.Archived,
+			HasIssues:                ghRepo.HasIssues,
+			HasWiki:                  ghRepo.HasWiki,
+			HasProjects:              ghRepo.HasProjects,
+			HasDownloads:             ghRepo.HasDownloads,
+			HasDiscussions:           ghRepo.HasDiscussions,
+			Visibility:               ghRepo.Visibility,
+		},
+	}
+	if template := ghRepo.GetTemplateRepository(); template != nil {
+		repo.Spec.TemplateOwner = github.String(template.GetOwner().GetLogin())
+		repo.Spec.TemplateRepository = template.Name
+	}
+	return repo
+}
+
+func (i *Importer) branchProtection(ghBp *gh.BranchProtection, repositoryName, name string) *githubv1alpha1.BranchProtection {
+	reviewCount := int(ghBp.RequiredApprovingReviewCount)
+	bp := &githubv1alpha1.BranchProtection{
+		TypeMeta:   metav1.TypeMeta{APIVersion: githubv1alpha1.GroupVersion.String(), Kind: "BranchProtection"},
+		ObjectMeta: i.objectMeta(name),
+		Spec: githubv1alpha1.BranchProtectionSpec{
+			// the rule follows its Repository resource, which also owns it
+			RepositoryRef:                  &githubv1alpha1.ResourceReference{Name: repositoryName},
+			Pattern:                        ghBp.Pattern,
+			AllowsDeletions:                &ghBp.AllowsDeletions,
+			AllowsForcePushes:              &ghBp.AllowsForcePushes,
+			BlocksCreations:                &ghBp.BlocksCreations,
+			DismissesStaleReviews:          &ghBp.DismissesStaleReviews,
+			IsAdminEnforced:                &ghBp.IsAdminEnforced,
+			LockAllowsFetchAndMerge:        &ghBp.LockAllowsFetchAndMerge,
+			LockBranch:                     &ghBp.LockBranch,
+			RequireLastPushApproval:        &ghBp.RequireLastPushApproval,
+			RequiredApprovingReviewCount:   &reviewCount,
+			RequiredDeploymentEnvironments: ghBp.RequiredDeploymentEnvironments,
+			RequiredStatusCheckContexts:    ghBp.RequiredStatusCheckContexts,
+			RequiresApprovingReviews:       &ghBp.RequiresApprovingReviews,
+			RequiresCodeOwnerReviews:       &ghBp.RequiresCodeOwnerReviews,
+			RequiresCommitSignatures:       &ghBp.RequiresCommitSignatures,
+			RequiresConversationResolution: &ghBp.RequiresConversationResolution,
+			RequiresDeployments:            &ghBp.RequiresDeployments,
+			RequiresLinearHistory:          &ghBp.RequiresLinearHistory,
+			RequiresStatusChecks:           &ghBp.RequiresStatusChecks,
+			RequiresStrictStatusChecks:     &ghBp.RequiresStrictStatusChecks,
+			RestrictsPushes:                &ghBp.RestrictsPushes,
+			RestrictsReviewDismissals:      &ghBp.RestrictsReviewDismissals,
+		},
+	}
+	for _, check := range ghBp.RequiredStatusChecks {
+		statusCheck := githubv1alpha1.RequiredStatusCheck{Context: check.Context}
+		if check.App.Id != "" {
+			statusCheck.AppId = github.String(check.App.Id)
+		}
+		bp.Spec.RequiredStatusChecks = append(bp.Spec.RequiredStatusChecks, statusCheck)
+	}
+
+	bypassForcePush := ghBp.GetBypassForcePushAllowances()
+	bp.Spec.BypassForcePushUsers = userLogins(bypassForcePush.Users)
+	bp.Spec.BypassForcePushApps = appSlugs(bypassForcePush.Apps)
+	bp.Spec.BypassForcePushTeams = teamSlugs(bypassForcePush.Teams)
+	bypassPullRequest := ghBp.GetBypassPullRequestAllowances()
+	bp.Spec.BypassPullRequestUsers = userLogins(bypassPullRequest.Users)
+	bp.Spec.BypassPullRequestApps = appSlugs(bypassPullRequest.Apps)
+	bp.Spec.BypassPullRequestTeams = teamSlugs(bypassPullRequest.Teams)
+	push := ghBp.GetPushAllowances()
+	bp.Spec.PushAllowanceUsers = userLogins(push.Users)
+	bp.Spec.PushAllowanceApps = appSlugs(push.Apps)
+	bp.Spec.PushAllowanceTeams = teamSlugs(push.Teams)
+	reviewDismissal := ghBp.GetReviewDismissalAllowances()
+	bp.Spec.ReviewDismissalUsers = userLogins(reviewDismissal.Users)
+	bp.Spec.ReviewDismissalApps = appSlugs(reviewDismissal.Apps)
+	bp.Spec.ReviewDismissalTeams = teamSlugs(reviewDismissal.Teams)
+	return bp
+}
+
+// WriteYAML writes objects as a multi-document YAML stream, leaving out status and other fields
+// that are only set by the API server.
+func WriteYAML(w io.Writer, objects []client.Object) error {
+	for _, obj := range objects {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		delete(u, "status")
+		unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+		removeNulls(u)
+
+		out, err := yaml.Marshal(u)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeNulls removes fields that are null, such as unset lists that aren't omitted when empty
+func removeNulls(u map[string]any) {
+	for k, v := range u {
+		switch v := v.(type) {
+		case nil:
+			delete(u, k)
+		case map[string]any:
+			removeNulls(v)
+		}
+	}
+}
+
+// teamRepositoryPermission converts a GraphQL repository permission to the
+// equivalent REST permission used in Team specs
+func teamRepositoryPermission(permission string) githubv1alpha1.RepositoryPermission {
+	switch strings.ToUpper(permission) {
+	case "READ":
+		return githubv1alpha1.Pull
+	case "WRITE":
+		return githubv1alpha1.Push
+	default:
+		return githubv1alpha1.RepositoryPermission(strings.ToLower(permission))
+	}
+}
+
+func userLogins(users []gh.User) []string {
+	out := []string{}
+	for _, user := range users {
+		out = append(out, user.Login)
+	}
+	return nilIfEmpty(out)
+}
+
+func appSlugs(apps []gh.App) []string {
+	out := []string{}
+	for _, app := range apps {
+		out = append(out, app.Slug)
+	}
+	return nilIfEmpty(out)
+}
+
+func teamSlugs(teams []gh.Team) []string {
+	out := []string{}
+	for _, team := range teams {
+		out = append(out, team.Slug)
+	}
+	return nilIfEmpty(out)
+}
+
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// resourceName converts a GitHub name into a valid resource name
+func resourceName(name string) string {
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	if name == "" {
+		name = "unnamed"
+	}
+	return name
+}
+
+// names hands out resource names that are unique within a kind, since different GitHub names
+// can convert to the same resource name
+type names map[string]bool
+
+func newNames() names {
+	return names{}
+}
+
+func (n names) unique(name string) string {
+	base := resourceName(name)
+	out := base
+	for i := 2; n[out]; i++ {
+		suffix := fmt.Sprintf("-%d", i)
+		out = strings.TrimRight(base[:min(len(base), 63-len(suffix))], "-") + suffix
+	}
+	n[out] = true
+	return out
+}
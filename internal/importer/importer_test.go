@@ -0,0 +1,234 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/google/go-github/v60/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/shurcooL/githubv4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	githubv1alpha1 "github.com/eczy/github-operator/api/v1alpha1"
+	gh "github.com/eczy/github-operator/internal/github"
+	"github.com/eczy/github-operator/internal/github/fake"
+)
+
+var _ = Describe("Importer", func() {
+	const org = "testorg"
+
+	ctx := context.Background()
+
+	var (
+		server   *fake.Server
+		ghClient *gh.Client
+	)
+
+	BeforeEach(func() {
+		By("Populating the fake GitHub API")
+		server = fake.NewServer()
+		DeferCleanup(server.Close)
+		server.AddOrganization(org)
+		server.AddUser("alice")
+		server.AddUser("bob")
+		server.AddUser("carol")
+		Expect(server.AddOrganizationMember(org, "alice", "member")).To(Succeed())
+		Expect(server.AddOrganizationMember(org, "bob", "member")).To(Succeed())
+
+		var err error
+		ghClient, err = server.Client()
+		Expect(err).NotTo(HaveOccurred())
+
+		// both names convert to the resource name my-repo
+		for _, name := range []string{"my-repo", "My_Repo"} {
+			_, err := ghClient.CreateRepository(ctx, org, &github.Repository{
+				Name:        github.String(name),
+				Description: github.String(name + " description"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		repo, err := ghClient.GetRepositoryByName(ctx, org, "my-repo")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = ghClient.CreateBranchProtection(ctx, &githubv4.CreateBranchProtectionRuleInput{
+			RepositoryID:          repo.GetNodeID(),
+			Pattern:               "main",
+			RequiresLinearHistory: githubv4.NewBoolean(true),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		parent, err := ghClient.CreateTeam(ctx, org, github.NewTeam{Name: "Platform"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = ghClient.CreateTeam(ctx, org, github.NewTeam{Name: "Platform SRE", ParentTeamID: parent.ID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ghClient.UpdateTeamRepositoryPermissions(ctx, org, "platform", "my-repo", "push")).To(Succeed())
+		_, err = ghClient.UpdateTeamMembership(ctx, org, "platform", "alice", "maintainer")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = ghClient.UpdateTeamMembership(ctx, org, "platform", "bob", "member")
+		Expect(err).NotTo(HaveOccurred())
+		// carol joins the organization through the team
+		_, err = ghClient.UpdateTeamMembership(ctx, org, "platform", "carol", "member")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	importObjects := func() []client.Object {
+		i := &Importer{GitHubClient: ghClient, Namespace: "github"}
+		objects, err := i.Import(ctx, org)
+		Expect(err).NotTo(HaveOccurred())
+		return objects
+	}
+
+	It("should import the organization with referenced resources first", func() {
+		objects := importObjects()
+
+		kinds := []string{}
+		names := []string{}
+		for _, obj := range objects {
+			Expect(obj.GetNamespace()).To(Equal("github"))
+			kinds = append(kinds, obj.GetObjectKind().GroupVersionKind().Kind)
+			names = append(names, obj.GetName())
+		}
+		Expect(kinds).To(Equal([]string{"Organization", "Team", "Team", "Repository", "Repository", "BranchProtection"}))
+		Expect(names).To(Equal([]string{"testorg", "platform", "platform-sre", "my-repo", "my-repo-2", "my-repo-main"}))
+	})
+
+	It("should import teams with their parents, repositories and rosters", func() {
+		objects := importObjects()
+
+		platform := objects[1].(*githubv1alpha1.Team)
+		Expect(platform.Spec.Organization).To(Equal(org))
+		Expect(platform.Spec.Name).To(Equal("Platform"))
+		Expect(platform.Spec.ParentTeamRef).To(BeNil())
+		Expect(platform.Spec.Repositories).To(Equal(map[string]githubv1alpha1.RepositoryPermission{
+			"my-repo": githubv1alpha1.Push,
+		}))
+		Expect(platform.Spec.Maintainers).To(ConsistOf("alice"))
+		Expect(platform.Spec.Members).To(ConsistOf("bob", "carol"))
+
+		sre := objects[2].(*githubv1alpha1.Team)
+		Expect(sre.Spec.ParentTeamRef).To(Equal(&githubv1alpha1.ResourceReference{Name: "platform"}))
+		Expect(sre.Spec.ParentTeamId).To(BeNil())
+		Expect(sre.Spec.Members).To(BeEmpty())
+		Expect(sre.Spec.PruneMembers).To(Equal(github.Bool(true)))
+	})
+
+	It("should import pending invitations with their team role", func() {
+		server.SetInvitations(true)
+		server.AddUser("dave")
+		server.AddUser("erin")
+		membership, err := ghClient.UpdateTeamMembership(ctx, org, "platform", "dave", "maintainer")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(membership.GetState()).To(Equal("pending"))
+		_, err = ghClient.UpdateTeamMembership(ctx, org, "platform", "erin", "member")
+		Expect(err).NotTo(HaveOccurred())
+
+		objects := importObjects()
+
+		platform := objects[1].(*githubv1alpha1.Team)
+		Expect(platform.Spec.Maintainers).To(ConsistOf("alice", "dave"))
+		Expect(platform.Spec.Members).To(ConsistOf("bob", "carol", "erin"))
+	})
+
+	It("should import repositories and their branch protections", func() {
+		objects := importObjects()
+
+		repo := objects[3].(*githubv1alpha1.Repository)
+		Expect(repo.Spec.Owner).To(Equal(org))
+		Expect(repo.Spec.Name).To(Equal("My_Repo"))
+		Expect(repo.Spec.Description).To(Equal(github.String("My_Repo description")))
+
+		bp := objects[5].(*githubv1alpha1.BranchProtection)
+		Expect(bp.Spec.RepositoryRef).To(Equal(&githubv1alpha1.ResourceReference{Name: "my-repo-2"}))
+		Expect(bp.Spec.Pattern).To(Equal("main"))
+		Expect(bp.Spec.RequiresLinearHistory).To(Equal(github.Bool(true)))
+	})
+
+	It("should write manifests without status or null fields", func() {
+		objects := importObjects()
+
+		out := &bytes.Buffer{}
+		Expect(WriteYAML(out, objects)).To(Succeed())
+		Expect(bytes.Count(out.Bytes(), []byte("---\n"))).To(Equal(len(objects)))
+		Expect(out.String()).To(ContainSubstring("apiVersion: " + githubv1alpha1.GroupVersion.String()))
+		Expect(out.String()).To(ContainSubstring("kind: BranchProtection"))
+		Expect(out.String()).NotTo(ContainSubstring("status:"))
+		Expect(out.String()).NotTo(ContainSubstring("creationTimestamp"))
+		Expect(out.String()).NotTo(ContainSubstring("null"))
+	})
+})
+
+var _ = Describe("WriteYAML", func() {
+	It("should leave out status, server-set metadata and unset lists", func() {
+		team := &githubv1alpha1.Team{
+			TypeMeta: metav1.TypeMeta{APIVersion: githubv1alpha1.GroupVersion.String(), Kind: "Team"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "platform",
+				Namespace: "github",
+			},
+			Spec: githubv1alpha1.TeamSpec{
+				Organization: "testorg",
+				Name:         "Platform",
+				Members:      []string{},
+				PruneMembers: github.Bool(true),
+			},
+			Status: githubv1alpha1.TeamStatus{
+				Slug: github.String("platform"),
+			},
+		}
+
+		out := &bytes.Buffer{}
+		Expect(WriteYAML(out, []client.Object{team})).To(Succeed())
+		Expect(out.String()).To(Equal(`---
+apiVersion: github.github-operator.eczy.io/v1alpha1
+kind: Team
+metadata:
+  name: platform
+  namespace: github
+spec:
+  name: Platform
+  organization: testorg
+  pruneMembers: true
+`))
+	})
+})
+
+var _ = Describe("removeNulls", func() {
+	It("should remove null fields at any depth and keep empty ones", func() {
+		u := map[string]any{
+			"kind": "Team",
+			"spec": map[string]any{
+				"members":     []any{},
+				"maintainers": nil,
+				"parentTeamRef": map[string]any{
+					"name": nil,
+				},
+			},
+			"status": nil,
+		}
+		removeNulls(u)
+		Expect(u).To(Equal(map[string]any{
+			"kind": "Team",
+			"spec": map[string]any{
+				"members":       []any{},
+				"parentTeamRef": map[string]any{},
+			},
+		}))
+	})
+})
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gh "github.com/eczy/github-operator/internal/github"
+)
+
+// installationsRefreshInterval limits how often the installations of a GitHub App are listed
+// when a client is requested for an account the App isn't known to be installed on.
+const installationsRefreshInterval = time.Minute
+
+// GitHubAppClients authenticates as a GitHub App without a fixed installation. It lists the App's
+// installations and returns a client for the installation on a given account, caching a client
+// per installation so that installation tokens are reused.
+type GitHubAppClients struct {
+	base   http.RoundTripper
+	server GitHubServer
+	creds  GitHubCredentials
+	app    *gh.Client
+
+	mu            sync.Mutex
+	refreshed     time.Time
+	installations map[string]int64
+	clients       map[int64]*gh.Client
+}
+
+func NewGitHubAppClients(ctx context.Context, base http.RoundTripper, server GitHubServer, creds GitHubCredentials) (*GitHubAppClients, error) {
+	appBase := base
+	if len(server.CABundle) > 0 {
+		var err error
+		appBase, err = gh.CABundleRoundTripper(ctx, base, server.CABundle)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tr, err := gh.AppRoundTripper(ctx, appBase, creds.AppId, creds.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	opts := []gh.ClientOption{gh.WithRoundTripper(tr)}
+	if server.BaseURL != "" {
+		opts = append(opts, gh.WithEnterpriseURLs(server.BaseURL, server.UploadURL, server.GraphQLURL))
+	}
+	app, err := gh.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GitHubAppClients{
+		base:          base,
+		server:        server,
+		creds:         creds,
+		app:           app,
+		installations: map[string]int64{},
+		clients:       map[int64]*gh.Client{},
+	}, nil
+}
+
+// Refresh lists the installations of the GitHub App.
+func (a *GitHubAppClients) Refresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.refresh(ctx)
+}
+
+func (a *GitHubAppClients) refresh(ctx context.Context) error {
+	installations, err := a.app.ListInstallations(ctx)
+	if err != nil {
+		return err
+	}
+	a.refreshed = time.Now()
+	a.installations = map[string]int64{}
+	ids := map[int64]struct{}{}
+	for _, installation := range installations {
+		// account logins are case-insensitive
+		a.installations[strings.ToLower(installation.GetAccount().GetLogin())] = installation.GetID()
+		ids[installation.GetID()] = struct{}{}
+	}
+	// drop the clients of installations that were removed
+	for id := range a.clients {
+		if _, ok := ids[id]; !ok {
+			delete(a.clients, id)
+		}
+	}
+	return nil
+}
+
+// GitHubClient returns a client for the GitHub App's installation on the account with the given login.
+func (a *GitHubAppClients) GitHubClient(ctx context.Context, login string) (*gh.Client, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	installationId, ok := a.installations[strings.ToLower(login)]
+	if !ok && time.Since(a.refreshed) >= installationsRefreshInterval {
+		if err := a.refresh(ctx); err != nil {
+			return nil, err
+		}
+		installationId, ok = a.installations[strings.ToLower(login)]
+	}
+	if !ok {
+		return nil, fmt.Errorf("GitHub App %d is not installed on '%s'", a.creds.AppId, login)
+	}
+
+	if client, ok := a.clients[installationId]; ok {
+		return client, nil
+	}
+	creds := a.creds
+	creds.InstallationId = installationId
+	client, err := GitHubClientFromCredentials(ctx, a.base, a.server, creds)
+	if err != nil {
+		return nil, err
+	}
+	a.clients[installationId] = client
+	return client, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 Evan Czyzycki
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GitHubAppClients", func() {
+	type installation struct {
+		Id      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+
+	var (
+		server  *httptest.Server
+		clients *GitHubAppClients
+
+		mu sync.Mutex
+		// the installations the server lists and how often they were listed
+		installations []installation
+		listed        int
+	)
+
+	install := func(id int64, login string) {
+		mu.Lock()
+		defer mu.Unlock()
+		i := installation{Id: id}
+		i.Account.Login = login
+		installations = append(installations, i)
+	}
+
+	listCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return listed
+	}
+
+	BeforeEach(func() {
+		installations = nil
+		listed = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodGet || req.URL.Path != "/api/v3/app/installations" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			listed++
+			w.Header().Set("Content-Type", "application/json")
+			Expect(json.NewEncoder(w).Encode(installations)).To(Succeed())
+		}))
+		DeferCleanup(server.Close)
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+		clients, err = NewGitHubAppClients(context.Background(), http.DefaultTransport,
+			GitHubServer{BaseURL: server.URL}, GitHubCredentials{AppId: 1, PrivateKey: privateKey})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return a client for the installation on an account", func() {
+		ctx := context.Background()
+		install(10, "Some-Org")
+
+		client, err := clients.GitHubClient(ctx, "some-org")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+
+		By("Reusing the client of the installation")
+		again, err := clients.GitHubClient(ctx, "SOME-ORG")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(BeIdenticalTo(client))
+		Expect(listCount()).To(Equal(1))
+	})
+
+	It("should list installations at most once a minute for unknown accounts", func() {
+		ctx := context.Background()
+		install(10, "some-org")
+
+		_, err := clients.GitHubClient(ctx, "other-org")
+		Expect(err).To(MatchError("GitHub App 1 is not installed on 'other-org'"))
+		Expect(listCount()).To(Equal(1))
+
+		By("Not listing installations again within a minute")
+		install(11, "other-org")
+		_, err = clients.GitHubClient(ctx, "other-org")
+		Expect(err).To(HaveOccurred())
+		Expect(listCount()).To(Equal(1))
+
+		By("Listing installations again once a minute has passed")
+		clients.refreshed = time.Now().Add(-installationsRefreshInterval)
+		client, err := clients.GitHubClient(ctx, "other-org")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+		Expect(listCount()).To(Equal(2))
+	})
+
+	It("should drop the clients of removed installations on refresh", func() {
+		ctx := context.Background()
+		install(10, "some-org")
+
+		client, err := clients.GitHubClient(ctx, "some-org")
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Reinstalling the GitHub App on the account")
+		mu.Lock()
+		installations = nil
+		mu.Unlock()
+		install(12, "some-org")
+		Expect(clients.Refresh(ctx)).To(Succeed())
+		Expect(clients.clients).NotTo(HaveKey(int64(10)))
+
+		reinstalled, err := clients.GitHubClient(ctx, "some-org")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reinstalled).NotTo(BeIdenticalTo(client))
+		Expect(clients.clients).To(HaveKey(int64(12)))
+	})
+})
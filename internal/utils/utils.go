@@ -2,11 +2,14 @@ package utils
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
 
 	gh "github.com/eczy/github-operator/internal/github"
 )
@@ -27,38 +30,177 @@ func LookupEnvVarsError(names ...string) (map[string]string, error) {
 	return found, nil
 }
 
-func GitHubClientFromEnv(ctx context.Context, base http.RoundTripper) (*gh.Client, error) {
-	appCreds, appErr := LookupEnvVarsError("GITHUB_APP_ID", "GITHUB_INSTALLATION_ID", "GITHUB_PRIVATE_KEY")
+// defaultCacheMaxBytes bounds the GitHub HTTP cache unless GITHUB_CACHE_MAX_BYTES is set.
+const defaultCacheMaxBytes = 64 << 20
+
+// cacheMaxBytes returns the size of the GitHub HTTP cache from GITHUB_CACHE_MAX_BYTES.
+// A size of 0 disables the cache.
+func cacheMaxBytes() (int, error) {
+	v, ok := os.LookupEnv("GITHUB_CACHE_MAX_BYTES")
+	if !ok {
+		return defaultCacheMaxBytes, nil
+	}
+	maxBytes, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("parsing GITHUB_CACHE_MAX_BYTES: %w", err)
+	}
+	return maxBytes, nil
+}
+
+// sharedHTTPCache is the GitHub HTTP cache shared by all clients of the process, so that clients
+// created per provider, installation or rotated credential don't each get a cache of their own.
+// It is nil if the cache is disabled.
+var sharedHTTPCache = sync.OnceValues(func() (*gh.HTTPCache, error) {
+	maxBytes, err := cacheMaxBytes()
+	if err != nil || maxBytes <= 0 {
+		return nil, err
+	}
+	return gh.NewHTTPCache(maxBytes), nil
+})
+
+// cachingRoundTripper wraps a transport authenticated with creds in the GitHub HTTP cache, if enabled.
+func cachingRoundTripper(base http.RoundTripper, creds GitHubCredentials) (http.RoundTripper, error) {
+	cache, err := sharedHTTPCache()
+	if err != nil {
+		return nil, err
+	}
+	if cache == nil {
+		return base, nil
+	}
+	return gh.CachingRoundTripper(base, cache, creds.cachePartition()), nil
+}
+
+// GitHubCredentials authenticate a GitHub client, either with Token or as the GitHub App
+// installation identified by AppId, InstallationId and PrivateKey.
+type GitHubCredentials struct {
+	Token          string
+	AppId          int64
+	InstallationId int64
+	PrivateKey     []byte
+}
+
+// cachePartition identifies the credentials in the GitHub HTTP cache without holding on to a token.
+func (c GitHubCredentials) cachePartition() string {
+	if c.AppId != 0 {
+		return fmt.Sprintf("app/%d/%d", c.AppId, c.InstallationId)
+	}
+	sum := sha256.Sum256([]byte(c.Token))
+	return "token/" + hex.EncodeToString(sum[:])
+}
+
+// DiscoversInstallations returns true for GitHub App credentials without an installation, which
+// select the App's installation per account instead. See GitHubAppClients.
+func (c GitHubCredentials) DiscoversInstallations() bool {
+	return c.AppId != 0 && c.InstallationId == 0
+}
+
+// AuthType returns how the credentials authenticate with GitHub, either "App" or "Token".
+func (c GitHubCredentials) AuthType() string {
+	if c.AppId != 0 {
+		return "App"
+	}
+	return "Token"
+}
+
+// GitHubServer locates the GitHub instance a client talks to. The zero value is github.com.
+type GitHubServer struct {
+	// REST API URL of a GitHub Enterprise Server instance, or its root URL.
+	BaseURL string
+	// Upload URL, defaults to the one of the instance at BaseURL.
+	UploadURL string
+	// GraphQL API URL, defaults to the one of the instance at BaseURL.
+	GraphQLURL string
+	// PEM encoded certificates to trust in addition to the system roots.
+	CABundle []byte
+}
+
+// GitHubServerFromEnv reads the GitHub instance from GITHUB_BASE_URL, GITHUB_UPLOAD_URL,
+// GITHUB_GRAPHQL_URL and GITHUB_CA_BUNDLE_FILE. All of them are optional.
+func GitHubServerFromEnv() (GitHubServer, error) {
+	server := GitHubServer{
+		BaseURL:    os.Getenv("GITHUB_BASE_URL"),
+		UploadURL:  os.Getenv("GITHUB_UPLOAD_URL"),
+		GraphQLURL: os.Getenv("GITHUB_GRAPHQL_URL"),
+	}
+	if path, ok := os.LookupEnv("GITHUB_CA_BUNDLE_FILE"); ok {
+		caBundle, err := os.ReadFile(path)
+		if err != nil {
+			return server, fmt.Errorf("reading GITHUB_CA_BUNDLE_FILE: %w", err)
+		}
+		server.CABundle = caBundle
+	}
+	return server, nil
+}
+
+// GitHubCredentialsFromEnv reads GitHub App credentials from GITHUB_APP_ID, GITHUB_PRIVATE_KEY and
+// the optional GITHUB_INSTALLATION_ID, or a token from GITHUB_TOKEN.
+func GitHubCredentialsFromEnv() (GitHubCredentials, error) {
+	appCreds, appErr := LookupEnvVarsError("GITHUB_APP_ID", "GITHUB_PRIVATE_KEY")
 	oauthCreds, oauthErr := LookupEnvVarsError("GITHUB_TOKEN")
 	if appErr == nil {
 		appId, err := strconv.ParseInt(appCreds["GITHUB_APP_ID"], 10, 64)
 		if err != nil {
-			return nil, err
+			return GitHubCredentials{}, err
 		}
-		instId, err := strconv.ParseInt(appCreds["GITHUB_INSTALLATION_ID"], 10, 64)
-		if err != nil {
-			return nil, err
+		creds := GitHubCredentials{
+			AppId:      appId,
+			PrivateKey: []byte(appCreds["GITHUB_PRIVATE_KEY"]),
 		}
-		tr, err := gh.AuthRoundTripperFromAppCredentials(ctx, base, appId, instId, []byte(appCreds["GITHUB_PRIVATE_KEY"]))
-		if err != nil {
-			return nil, err
+		if v, ok := os.LookupEnv("GITHUB_INSTALLATION_ID"); ok {
+			creds.InstallationId, err = strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return GitHubCredentials{}, err
+			}
 		}
-		tr, err = gh.RateLimitRoundTripper(ctx, tr)
-		if err != nil {
-			return nil, err
-		}
-		return gh.NewClient(gh.WithRoundTripper(tr))
+		return creds, nil
 	} else if oauthErr == nil {
-		tr, err := gh.AuthRoundTripperFromToken(ctx, base, oauthCreds["GITHUB_TOKEN"])
-		if err != nil {
-			return nil, err
-		}
-		tr, err = gh.RateLimitRoundTripper(ctx, tr)
+		return GitHubCredentials{
+			Token: oauthCreds["GITHUB_TOKEN"],
+		}, nil
+	} else {
+		return GitHubCredentials{}, errors.Join(appErr, oauthErr)
+	}
+}
+
+func GitHubClientFromEnv(ctx context.Context, base http.RoundTripper, server GitHubServer) (*gh.Client, error) {
+	creds, err := GitHubCredentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if creds.DiscoversInstallations() {
+		return nil, fmt.Errorf("expected env vars not set: [GITHUB_INSTALLATION_ID]")
+	}
+	return GitHubClientFromCredentials(ctx, base, server, creds)
+}
+
+func GitHubClientFromCredentials(ctx context.Context, base http.RoundTripper, server GitHubServer, creds GitHubCredentials) (*gh.Client, error) {
+	var tr http.RoundTripper
+	var err error
+	if len(server.CABundle) > 0 {
+		base, err = gh.CABundleRoundTripper(ctx, base, server.CABundle)
 		if err != nil {
 			return nil, err
 		}
-		return gh.NewClient(gh.WithRoundTripper(tr))
+	}
+	if creds.AppId != 0 {
+		tr, err = gh.AuthRoundTripperFromAppCredentials(ctx, base, server.BaseURL, creds.AppId, creds.InstallationId, creds.PrivateKey)
 	} else {
-		return nil, errors.Join(appErr, oauthErr)
+		tr, err = gh.AuthRoundTripperFromToken(ctx, base, creds.Token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tr, err = cachingRoundTripper(tr, creds)
+	if err != nil {
+		return nil, err
+	}
+	tr, err = gh.RateLimitRoundTripper(ctx, tr)
+	if err != nil {
+		return nil, err
+	}
+	opts := []gh.ClientOption{gh.WithRoundTripper(tr)}
+	if server.BaseURL != "" {
+		opts = append(opts, gh.WithEnterpriseURLs(server.BaseURL, server.UploadURL, server.GraphQLURL))
 	}
+	return gh.NewClient(opts...)
 }